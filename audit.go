@@ -0,0 +1,33 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// audit passes the statement to Config.AuditSink, with the placeholders
+// replaced by args as with interpolateParams=true. The statement must not be
+// executed if an error is returned, since it could not be recorded.
+func (mc *mysqlConn) audit(query string, args []driver.Value) error {
+	sink := mc.cfg.AuditSink
+	if sink == nil {
+		return nil
+	}
+	if len(args) > 0 {
+		buf, err := mc.appendInterpolatedParams(nil, query, args, 0)
+		if err != nil {
+			return fmt.Errorf("can not record statement for audit: %w", err)
+		}
+		query = string(buf)
+	}
+	sink(query)
+	return nil
+}
@@ -0,0 +1,70 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAuditSink(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	var recorded []string
+	mc.cfg = mc.cfg.Clone()
+	mc.cfg.InterpolateParams = true
+	mc.cfg.AuditSink = func(query string) {
+		recorded = append(recorded, query)
+	}
+	okPacket := mockPacket(1, []byte{iOK, 1, 0, 2, 0, 0, 0})
+	conn.queuedReplies = [][]byte{okPacket, okPacket, okPacket}
+	conn.maxReads = 3
+
+	// text protocol
+	if _, err := mc.Exec("INSERT INTO t VALUES (?, ?)", []driver.Value{"it's", nil}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mc.Exec("DELETE FROM t", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// binary protocol
+	stmt := &mysqlStmt{mc: mc, id: 1, paramCount: 3, sql: "UPDATE t SET a = ?, b = ? WHERE c = ?"}
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if _, err := stmt.Exec([]driver.Value{int64(1), []byte("\x00"), ts}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`INSERT INTO t VALUES ('it\'s', NULL)`,
+		"DELETE FROM t",
+		`UPDATE t SET a = 1, b = _binary'\0' WHERE c = '2026-01-02 03:04:05'`,
+	}
+	if !reflect.DeepEqual(recorded, expected) {
+		t.Errorf("expected %q, got %q", expected, recorded)
+	}
+}
+
+func TestAuditSinkNotRecordable(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg = mc.cfg.Clone()
+	mc.cfg.AuditSink = func(query string) {
+		t.Errorf("unexpected statement: %s", query)
+	}
+
+	// the placeholders do not match the arguments
+	stmt := &mysqlStmt{mc: mc, id: 1, paramCount: 1, sql: "SELECT ?, ?"}
+	if _, err := stmt.Exec([]driver.Value{int64(1)}); err == nil {
+		t.Fatal("expected error")
+	}
+	if conn.writes != 0 {
+		t.Errorf("statement was sent")
+	}
+}
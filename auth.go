@@ -17,16 +17,12 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
-	"sync"
 
 	"filippo.io/edwards25519"
 )
 
 // server pub keys registry
-var (
-	serverPubKeyLock     sync.RWMutex
-	serverPubKeyRegistry map[string]*rsa.PublicKey
-)
+var serverPubKeyRegistry registry[*rsa.PublicKey]
 
 // RegisterServerPubKey registers a server RSA public key which can be used to
 // send data in a secure manner to the server without receiving the public key
@@ -57,30 +53,99 @@ var (
 //		log.Fatal("not a RSA public key")
 //	}
 func RegisterServerPubKey(name string, pubKey *rsa.PublicKey) {
-	serverPubKeyLock.Lock()
-	if serverPubKeyRegistry == nil {
-		serverPubKeyRegistry = make(map[string]*rsa.PublicKey)
-	}
-
-	serverPubKeyRegistry[name] = pubKey
-	serverPubKeyLock.Unlock()
+	serverPubKeyRegistry.set(name, pubKey)
+	parsedDSNs.purge()
 }
 
 // DeregisterServerPubKey removes the public key registered with the given name.
 func DeregisterServerPubKey(name string) {
-	serverPubKeyLock.Lock()
-	if serverPubKeyRegistry != nil {
-		delete(serverPubKeyRegistry, name)
-	}
-	serverPubKeyLock.Unlock()
+	serverPubKeyRegistry.delete(name)
+	parsedDSNs.purge()
+}
+
+// ListServerPubKeys returns the sorted names of the registered public keys.
+func ListServerPubKeys() []string {
+	return serverPubKeyRegistry.names()
+}
+
+// ResetServerPubKeys removes all registered public keys.
+func ResetServerPubKeys() {
+	serverPubKeyRegistry.reset()
+	parsedDSNs.purge()
 }
 
 func getServerPubKey(name string) (pubKey *rsa.PublicKey) {
-	serverPubKeyLock.RLock()
-	if v, ok := serverPubKeyRegistry[name]; ok {
-		pubKey = v
+	pubKey, _ = serverPubKeyRegistry.get(name)
+	return
+}
+
+// AuthPlugin is a client side authentication plugin which can be registered
+// with RegisterAuthPlugin to support authentication methods not implemented
+// by the driver, e.g. PAM, LDAP or Kerberos.
+type AuthPlugin interface {
+	// InitAuth returns the initial authentication response for the auth
+	// plugin data (challenge) sent by the server. cfg must not be modified.
+	//
+	// If the server is expected to continue the exchange with AuthMoreData
+	// packets, InitAuth returns a non-nil AuthMoreDataFunc, which is called
+	// for each of these packets on this connection.
+	InitAuth(authData []byte, cfg *Config) ([]byte, AuthMoreDataFunc, error)
+}
+
+// AuthMoreDataFunc handles an AuthMoreData packet sent by the server during
+// authentication and returns the response which is sent back to the server.
+// data is only valid until the function returns.
+//
+// Once the exchange ended, whether it succeeded or failed, the function is
+// called a last time with nil data so that it can release its resources, e.g.
+// a security context. The results of this call are ignored.
+type AuthMoreDataFunc func(data []byte) ([]byte, error)
+
+// AuthPluginFunc is an adapter to allow the use of ordinary functions as
+// AuthPlugin.
+type AuthPluginFunc func(authData []byte, cfg *Config) ([]byte, AuthMoreDataFunc, error)
+
+// InitAuth calls f(authData, cfg).
+func (f AuthPluginFunc) InitAuth(authData []byte, cfg *Config) ([]byte, AuthMoreDataFunc, error) {
+	return f(authData, cfg)
+}
+
+// auth plugins registry
+var authPluginRegistry registry[AuthPlugin]
+
+// builtinAuthPlugins are the authentication plugins implemented by the driver.
+var builtinAuthPlugins = map[string]bool{
+	"caching_sha2_password": true,
+	"mysql_old_password":    true,
+	"mysql_clear_password":  true,
+	"mysql_native_password": true,
+	"sha256_password":       true,
+	"client_ed25519":        true,
+
+	"authentication_ldap_sasl_client": true,
+}
+
+// RegisterAuthPlugin registers a client side authentication plugin under the
+// name used by the server, e.g. "authentication_pam". The plugin is used when
+// the server requests authentication with it.
+// The authentication plugins implemented by the driver can not be replaced.
+func RegisterAuthPlugin(name string, plugin AuthPlugin) error {
+	if builtinAuthPlugins[name] {
+		return fmt.Errorf("auth plugin '%s' is reserved", name)
 	}
-	serverPubKeyLock.RUnlock()
+
+	authPluginRegistry.set(name, plugin)
+	return nil
+}
+
+// DeregisterAuthPlugin removes the authentication plugin registered with the
+// given name.
+func DeregisterAuthPlugin(name string) {
+	authPluginRegistry.delete(name)
+}
+
+func getAuthPlugin(name string) (plugin AuthPlugin) {
+	plugin, _ = authPluginRegistry.get(name)
 	return
 }
 
@@ -275,6 +340,7 @@ func (mc *mysqlConn) sendEncryptedPassword(seed []byte, pub *rsa.PublicKey) erro
 }
 
 func (mc *mysqlConn) auth(authData []byte, plugin string) ([]byte, error) {
+	mc.endAuth()
 	switch plugin {
 	case "caching_sha2_password":
 		authResp := scrambleSHA256Password(authData, mc.cfg.Passwd)
@@ -321,8 +387,11 @@ func (mc *mysqlConn) auth(authData []byte, plugin string) ([]byte, error) {
 			return append([]byte(mc.cfg.Passwd), 0), nil
 		}
 
-		pubKey := mc.cfg.pubKey
+		pubKey := mc.serverPubKey()
 		if pubKey == nil {
+			if !mc.cfg.AllowServerPubKeyRetrieval {
+				return nil, ErrPubKeyRetrieval
+			}
 			// request public key from server
 			return []byte{1}, nil
 		}
@@ -337,7 +406,15 @@ func (mc *mysqlConn) auth(authData []byte, plugin string) ([]byte, error) {
 		}
 		return authEd25519(authData, mc.cfg.Passwd)
 
+	case "authentication_ldap_sasl_client":
+		return mc.authSASL(authData)
+
 	default:
+		if p := getAuthPlugin(plugin); p != nil {
+			authResp, next, err := p.InitAuth(authData, mc.cfg)
+			mc.authMoreData, mc.authEnd = next, next
+			return authResp, err
+		}
 		mc.log("unknown auth plugin:", plugin)
 		return nil, ErrUnknownPlugin
 	}
@@ -362,6 +439,7 @@ func (mc *mysqlConn) handleAuthResult(oldAuthData []byte, plugin string) error {
 		}
 
 		plugin = newPlugin
+		mc.authMoreData = nil
 
 		authResp, err := mc.auth(authData, plugin)
 		if err != nil {
@@ -383,6 +461,11 @@ func (mc *mysqlConn) handleAuthResult(oldAuthData []byte, plugin string) error {
 		}
 	}
 
+	// continue the exchange of a multi-step auth plugin
+	if mc.authMoreData != nil {
+		return mc.continueAuth(authData)
+	}
+
 	switch plugin {
 
 	// https://dev.mysql.com/blog-archive/preparing-your-community-connector-for-mysql-8-part-2-sha256/
@@ -405,8 +488,11 @@ func (mc *mysqlConn) handleAuthResult(oldAuthData []byte, plugin string) error {
 						return err
 					}
 				} else {
-					pubKey := mc.cfg.pubKey
+					pubKey := mc.serverPubKey()
 					if pubKey == nil {
+						if !mc.cfg.AllowServerPubKeyRetrieval {
+							return ErrPubKeyRetrieval
+						}
 						// request public key from server
 						data, err := mc.buf.takeSmallBuffer(4 + 1)
 						if err != nil {
@@ -436,6 +522,7 @@ func (mc *mysqlConn) handleAuthResult(oldAuthData []byte, plugin string) error {
 							return err
 						}
 						pubKey = pkix.(*rsa.PublicKey)
+						mc.storeServerPubKey(pubKey)
 					}
 
 					// send encrypted password
@@ -467,6 +554,7 @@ func (mc *mysqlConn) handleAuthResult(oldAuthData []byte, plugin string) error {
 			if err != nil {
 				return err
 			}
+			mc.storeServerPubKey(pub.(*rsa.PublicKey))
 
 			// send encrypted password
 			err = mc.sendEncryptedPassword(oldAuthData, pub.(*rsa.PublicKey))
@@ -482,3 +570,70 @@ func (mc *mysqlConn) handleAuthResult(oldAuthData []byte, plugin string) error {
 
 	return err
 }
+
+// serverPubKey returns the RSA public key used to encrypt the password on
+// connections without TLS: the key registered with RegisterServerPubKey and
+// set with serverPubKey, which pins the key, or else the key retrieved from
+// the same server by an earlier connection of the connector. It returns nil
+// if the key must be requested from the server.
+func (mc *mysqlConn) serverPubKey() *rsa.PublicKey {
+	if mc.cfg.pubKey != nil {
+		return mc.cfg.pubKey
+	}
+	if mc.connector == nil || !mc.cfg.AllowServerPubKeyRetrieval {
+		return nil
+	}
+	cached := mc.connector.state.Load().pubKey.Load()
+	if cached == nil || cached.addr != mc.cfg.Net+"/"+mc.cfg.Addr {
+		return nil
+	}
+	mc.cachedPubKeyUsed = true
+	return cached.key
+}
+
+// storeServerPubKey caches the RSA public key retrieved from the server for
+// the next connections of the connector.
+func (mc *mysqlConn) storeServerPubKey(key *rsa.PublicKey) {
+	if mc.connector != nil {
+		mc.connector.state.Load().pubKey.Store(&cachedServerPubKey{
+			addr: mc.cfg.Net + "/" + mc.cfg.Addr,
+			key:  key,
+		})
+	}
+}
+
+// endAuth tells the registered auth plugin of the last call of auth that the
+// exchange ended, see AuthMoreDataFunc.
+func (mc *mysqlConn) endAuth() {
+	if end := mc.authEnd; end != nil {
+		mc.authEnd = nil
+		end(nil)
+	}
+}
+
+// continueAuth handles the AuthMoreData packets of a multi-step auth plugin
+// until the server sends the final OK packet.
+func (mc *mysqlConn) continueAuth(authData []byte) error {
+	next := mc.authMoreData
+	mc.authMoreData = nil
+
+	for authData != nil {
+		authResp, err := next(authData)
+		if err != nil {
+			return err
+		}
+		if err = mc.writeAuthSwitchPacket(authResp); err != nil {
+			return err
+		}
+
+		var newPlugin string
+		authData, newPlugin, err = mc.readAuthResult()
+		if err != nil {
+			return err
+		}
+		if newPlugin != "" {
+			return ErrMalformPkt
+		}
+	}
+	return nil
+}
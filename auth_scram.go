@@ -0,0 +1,182 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+// scramClient implements the client side of the SASL SCRAM mechanisms
+// (RFC 5802, RFC 7677) used by the authentication_ldap_sasl_client plugin.
+type scramClient struct {
+	newHash func() hash.Hash
+	user    string
+	passwd  string
+	nonce   string
+
+	clientFirstBare string
+	serverSignature []byte
+	done            bool
+}
+
+func newScramClient(mechanism, user, passwd string) (*scramClient, error) {
+	c := &scramClient{user: user, passwd: passwd}
+	switch mechanism {
+	case "SCRAM-SHA-1":
+		c.newHash = sha1.New
+	case "SCRAM-SHA-256":
+		c.newHash = sha256.New
+	default:
+		return nil, fmt.Errorf("unsupported SASL mechanism '%s'", mechanism)
+	}
+
+	nonce := make([]byte, 18)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	c.nonce = base64.StdEncoding.EncodeToString(nonce)
+	return c, nil
+}
+
+// authSASL starts the SASL exchange for the mechanism requested by the server
+// and returns the first client message.
+func (mc *mysqlConn) authSASL(authData []byte) ([]byte, error) {
+	mechanism := string(bytes.TrimRight(authData, "\x00"))
+	c, err := newScramClient(mechanism, mc.cfg.User, mc.cfg.Passwd)
+	if err != nil {
+		return nil, err
+	}
+	mc.authMoreData = c.next
+	return c.clientFirst(), nil
+}
+
+// clientFirst returns the client-first-message.
+func (c *scramClient) clientFirst() []byte {
+	user := strings.NewReplacer("=", "=3D", ",", "=2C").Replace(c.user)
+	c.clientFirstBare = "n=" + user + ",r=" + c.nonce
+	return []byte("n,," + c.clientFirstBare)
+}
+
+// next processes a message from the server. It returns the
+// client-final-message for the server-first-message and an empty response
+// after the server signature has been verified.
+func (c *scramClient) next(data []byte) ([]byte, error) {
+	if c.serverSignature != nil {
+		return nil, c.verifyServerFinal(string(data))
+	}
+	if c.done {
+		return nil, ErrMalformPkt
+	}
+	return c.clientFinal(string(data))
+}
+
+func (c *scramClient) clientFinal(serverFirst string) ([]byte, error) {
+	var nonce, salt string
+	var iterations int
+	for _, attr := range strings.Split(serverFirst, ",") {
+		k, v, ok := strings.Cut(attr, "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "r":
+			nonce = v
+		case "s":
+			salt = v
+		case "i":
+			iterations, _ = strconv.Atoi(v)
+		case "e":
+			return nil, fmt.Errorf("SCRAM authentication failed: %s", v)
+		}
+	}
+	if !strings.HasPrefix(nonce, c.nonce) || len(nonce) == len(c.nonce) {
+		return nil, fmt.Errorf("SCRAM: invalid server nonce")
+	}
+	saltBytes, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil || len(saltBytes) == 0 {
+		return nil, fmt.Errorf("SCRAM: invalid salt")
+	}
+	if iterations <= 0 {
+		return nil, fmt.Errorf("SCRAM: invalid iteration count")
+	}
+
+	saltedPassword := pbkdf2(c.newHash, []byte(c.passwd), saltBytes, iterations)
+	clientKey := c.hmac(saltedPassword, []byte("Client Key"))
+	h := c.newHash()
+	h.Write(clientKey)
+	storedKey := h.Sum(nil)
+
+	clientFinalWithoutProof := "c=biws,r=" + nonce
+	authMessage := []byte(c.clientFirstBare + "," + serverFirst + "," + clientFinalWithoutProof)
+
+	proof := c.hmac(storedKey, authMessage)
+	for i := range proof {
+		proof[i] ^= clientKey[i]
+	}
+	serverKey := c.hmac(saltedPassword, []byte("Server Key"))
+	c.serverSignature = c.hmac(serverKey, authMessage)
+
+	return []byte(clientFinalWithoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof)), nil
+}
+
+func (c *scramClient) verifyServerFinal(serverFinal string) error {
+	expected := c.serverSignature
+	c.serverSignature = nil
+	c.done = true
+
+	if msg, ok := strings.CutPrefix(serverFinal, "e="); ok {
+		return fmt.Errorf("SCRAM authentication failed: %s", msg)
+	}
+	v, ok := strings.CutPrefix(serverFinal, "v=")
+	if !ok {
+		return ErrMalformPkt
+	}
+	if i := strings.IndexByte(v, ','); i >= 0 {
+		v = v[:i]
+	}
+	signature, err := base64.StdEncoding.DecodeString(v)
+	if err != nil || !hmac.Equal(signature, expected) {
+		return fmt.Errorf("SCRAM: invalid server signature")
+	}
+	return nil
+}
+
+func (c *scramClient) hmac(key, data []byte) []byte {
+	mac := hmac.New(c.newHash, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// pbkdf2 derives a key with a length of one hash block (RFC 8018), which is
+// the Hi() function of RFC 5802.
+func pbkdf2(newHash func() hash.Hash, passwd, salt []byte, iterations int) []byte {
+	mac := hmac.New(newHash, passwd)
+	mac.Write(salt)
+	mac.Write([]byte{0, 0, 0, 1})
+	u := mac.Sum(nil)
+	result := append([]byte(nil), u...)
+	for i := 1; i < iterations; i++ {
+		mac.Reset()
+		mac.Write(u)
+		u = mac.Sum(u[:0])
+		for j := range result {
+			result[j] ^= u[j]
+		}
+	}
+	return result
+}
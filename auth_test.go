@@ -164,6 +164,7 @@ func TestAuthFastCachingSHA256PasswordEmpty(t *testing.T) {
 
 func TestAuthFastCachingSHA256PasswordFullRSA(t *testing.T) {
 	conn, mc := newRWMockConn(1)
+	mc.cfg.AllowServerPubKeyRetrieval = true
 	mc.cfg.User = "root"
 	mc.cfg.Passwd = "secret"
 
@@ -580,6 +581,7 @@ func TestAuthFastSHA256PasswordEmpty(t *testing.T) {
 
 func TestAuthFastSHA256PasswordRSA(t *testing.T) {
 	conn, mc := newRWMockConn(1)
+	mc.cfg.AllowServerPubKeyRetrieval = true
 	mc.cfg.User = "root"
 	mc.cfg.Passwd = "secret"
 
@@ -773,6 +775,7 @@ func TestAuthSwitchCachingSHA256PasswordEmpty(t *testing.T) {
 
 func TestAuthSwitchCachingSHA256PasswordFullRSA(t *testing.T) {
 	conn, mc := newRWMockConn(2)
+	mc.cfg.AllowServerPubKeyRetrieval = true
 	mc.cfg.Passwd = "secret"
 
 	// auth switch request
@@ -1224,6 +1227,7 @@ func TestAuthSwitchSHA256PasswordEmpty(t *testing.T) {
 
 func TestAuthSwitchSHA256PasswordRSA(t *testing.T) {
 	conn, mc := newRWMockConn(2)
+	mc.cfg.AllowServerPubKeyRetrieval = true
 	mc.cfg.Passwd = "secret"
 
 	// auth switch request
@@ -1379,3 +1383,223 @@ func TestEd25519Auth(t *testing.T) {
 		t.Errorf("got error: %v", err)
 	}
 }
+
+func TestRegisterAuthPluginReserved(t *testing.T) {
+	plugin := AuthPluginFunc(func(authData []byte, cfg *Config) ([]byte, AuthMoreDataFunc, error) {
+		return nil, nil, nil
+	})
+	if err := RegisterAuthPlugin("mysql_native_password", plugin); err == nil {
+		t.Error("expected error when registering a builtin auth plugin")
+	}
+}
+
+func TestAuthSwitchRegisteredPlugin(t *testing.T) {
+	var gotAuthData, gotMoreData string
+	ended := false
+	plugin := AuthPluginFunc(func(authData []byte, cfg *Config) ([]byte, AuthMoreDataFunc, error) {
+		gotAuthData = string(authData)
+		next := func(data []byte) ([]byte, error) {
+			if data == nil {
+				ended = true
+				return nil, nil
+			}
+			gotMoreData = string(data)
+			return []byte("ans:" + string(data)), nil
+		}
+		return []byte(cfg.Passwd), next, nil
+	})
+	if err := RegisterAuthPlugin("dialog", plugin); err != nil {
+		t.Fatal(err)
+	}
+	defer DeregisterAuthPlugin("dialog")
+
+	conn, mc := newRWMockConn(2)
+	mc.cfg.Passwd = "init"
+
+	// auth switch request
+	conn.data = []byte{11, 0, 0, 2, 254, 'd', 'i', 'a', 'l', 'o', 'g', 0, 'a', 'b', 'c'}
+	conn.queuedReplies = [][]byte{
+		// auth more data
+		{3, 0, 0, 4, 1, 'q', '1'},
+		// OK
+		{7, 0, 0, 6, 0, 0, 0, 2, 0, 0, 0},
+	}
+	conn.maxReads = 3
+
+	authData := []byte{123, 87, 15, 84, 20, 58, 37, 121, 91, 117, 51, 24, 19,
+		47, 43, 9, 41, 112, 67, 110}
+	if err := mc.handleAuthResult(authData, "mysql_native_password"); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if gotAuthData != "abc" {
+		t.Errorf("expected auth data %q, got %q", "abc", gotAuthData)
+	}
+	if gotMoreData != "q1" {
+		t.Errorf("expected auth more data %q, got %q", "q1", gotMoreData)
+	}
+	expectedReply := []byte{4, 0, 0, 3, 'i', 'n', 'i', 't', 6, 0, 0, 5, 'a', 'n', 's', ':', 'q', '1'}
+	if !bytes.Equal(conn.written, expectedReply) {
+		t.Errorf("got unexpected data: %v", conn.written)
+	}
+
+	mc.endAuth()
+	if !ended {
+		t.Error("expected the plugin to be notified of the end of the exchange")
+	}
+}
+
+func TestScramClient(t *testing.T) {
+	for _, tc := range []struct {
+		mechanism   string
+		nonce       string
+		clientFirst string
+		serverFirst string
+		clientFinal string
+		serverFinal string
+	}{
+		// RFC 5802, section 5
+		{
+			"SCRAM-SHA-1",
+			"fyko+d2lbbFgONRv9qkxdawL",
+			"n,,n=user,r=fyko+d2lbbFgONRv9qkxdawL",
+			"r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j,s=QSXCR+Q6sek8bf92,i=4096",
+			"c=biws,r=fyko+d2lbbFgONRv9qkxdawL3rfcNHYJY1ZVvWVs7j,p=v0X8v3Bz2T0CJGbJQyF0X+HI4Ts=",
+			"v=rmF9pqV8S7suAoZWja4dJRkFsKQ=",
+		},
+		// RFC 7677, section 3
+		{
+			"SCRAM-SHA-256",
+			"rOprNGfwEbeRWgbNEkqO",
+			"n,,n=user,r=rOprNGfwEbeRWgbNEkqO",
+			"r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096",
+			"c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=",
+			"v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4=",
+		},
+	} {
+		t.Run(tc.mechanism, func(t *testing.T) {
+			c, err := newScramClient(tc.mechanism, "user", "pencil")
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.nonce = tc.nonce
+
+			if got := string(c.clientFirst()); got != tc.clientFirst {
+				t.Errorf("client-first: expected %q, got %q", tc.clientFirst, got)
+			}
+			resp, err := c.next([]byte(tc.serverFirst))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(resp) != tc.clientFinal {
+				t.Errorf("client-final: expected %q, got %q", tc.clientFinal, resp)
+			}
+			resp, err = c.next([]byte(tc.serverFinal))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp) != 0 {
+				t.Errorf("expected empty response, got %q", resp)
+			}
+		})
+	}
+}
+
+func TestScramClientInvalidServer(t *testing.T) {
+	if _, err := newScramClient("GSSAPI", "user", "pencil"); err == nil {
+		t.Error("expected error for unsupported mechanism")
+	}
+
+	c, err := newScramClient("SCRAM-SHA-256", "user", "pencil")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.nonce = "rOprNGfwEbeRWgbNEkqO"
+	c.clientFirst()
+
+	// nonce not extended by the server
+	if _, err := c.next([]byte("r=rOprNGfwEbeRWgbNEkqO,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096")); err == nil {
+		t.Error("expected error for invalid server nonce")
+	}
+
+	if _, err = c.next([]byte("r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.next([]byte("v=rmF9pqV8S7suAoZWja4dJRkFsKQ=")); err == nil {
+		t.Error("expected error for invalid server signature")
+	}
+}
+
+func TestAuthSwitchLDAPSASL(t *testing.T) {
+	conn, mc := newRWMockConn(2)
+	mc.cfg.User = "us=er"
+
+	// auth switch request
+	conn.data = append([]byte{46, 0, 0, 2, 254}, "authentication_ldap_sasl_client\x00SCRAM-SHA-256"...)
+	conn.maxReads = 1
+
+	// the connection fails after the client-first-message has been sent
+	if err := mc.handleAuthResult(nil, "caching_sha2_password"); err == nil {
+		t.Fatal("expected error")
+	}
+	if prefix := []byte("n,,n=us=3Der,r="); !bytes.HasPrefix(conn.written[4:], prefix) {
+		t.Errorf("unexpected client-first-message: %q", conn.written[4:])
+	}
+	if err := RegisterAuthPlugin("authentication_ldap_sasl_client", AuthPluginFunc(nil)); err == nil {
+		t.Error("expected error registering a builtin plugin")
+	}
+}
+
+func TestAuthServerPubKeyRetrievalNotAllowed(t *testing.T) {
+	conn, mc := newRWMockConn(2)
+	mc.cfg.Passwd = "secret"
+	if _, err := mc.auth(make([]byte, 20), "sha256_password"); err != ErrPubKeyRetrieval {
+		t.Errorf("expected ErrPubKeyRetrieval, got %v", err)
+	}
+
+	conn.data = []byte{2, 0, 0, 2, 1, 4} // Perform Full Authentication
+	if err := mc.handleAuthResult(make([]byte, 20), "caching_sha2_password"); err != ErrPubKeyRetrieval {
+		t.Errorf("expected ErrPubKeyRetrieval, got %v", err)
+	}
+	if len(conn.written) != 0 {
+		t.Errorf("public key requested: %v", conn.written)
+	}
+}
+
+func TestAuthServerPubKeyCache(t *testing.T) {
+	conn, mc := newRWMockConn(2)
+	mc.cfg.Passwd = "secret"
+	mc.cfg.AllowServerPubKeyRetrieval = true
+	authData := make([]byte, 20)
+
+	if resp, err := mc.auth(authData, "sha256_password"); err != nil || !bytes.Equal(resp, []byte{1}) {
+		t.Fatalf("expected public key request, got %v, %v", resp, err)
+	}
+	conn.data = append([]byte{byte(1 + len(testPubKey)), 1, 0, 2, 1}, testPubKey...)
+	conn.queuedReplies = [][]byte{{7, 0, 0, 4, 0, 0, 0, 2, 0, 0, 0}}
+	conn.maxReads = 2
+	if err := mc.handleAuthResult(authData, "sha256_password"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the next connection of the connector encrypts the password right away
+	_, mc2 := newRWMockConn(1)
+	mc2.connector = mc.connector
+	mc2.cfg = mc.cfg
+	resp, err := mc2.auth(authData, "sha256_password")
+	if err != nil || len(resp) != testPubKeyRSA.Size() {
+		t.Errorf("expected encrypted password, got %d bytes, %v", len(resp), err)
+	}
+	if !mc2.cachedPubKeyUsed {
+		t.Error("cached key not used")
+	}
+
+	// the cache is per server
+	_, mc3 := newRWMockConn(1)
+	mc3.connector = mc.connector
+	mc3.cfg = mc.cfg.Clone()
+	mc3.cfg.Addr = "other:3306"
+	if resp, _ := mc3.auth(authData, "sha256_password"); !bytes.Equal(resp, []byte{1}) {
+		t.Errorf("key of another server used")
+	}
+}
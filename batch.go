@@ -0,0 +1,189 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+)
+
+// batchExecWindow is the number of statements of a batch which are sent
+// before their results are read. It bounds the results buffered by the
+// server, so that neither the server nor the client block on full socket
+// buffers.
+const batchExecWindow = 128
+
+// BatchError is returned by BatchExec if a statement of the batch failed.
+type BatchError struct {
+	Index int // index of the failed statement in the batch
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("statement %d of batch: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// BatchExec executes the prepared statement stmt once for each argument list
+// of argsBatch. The COM_STMT_EXECUTE packets are pipelined, i.e. up to 128
+// statements are sent before their results are read, which saves a round
+// trip per statement, e.g. for bulk inserts.
+//
+// stmt must be a statement of this driver, which is accessible with
+// sql.Conn.Raw:
+//
+//	err := conn.Raw(func(driverConn any) error {
+//		stmt, err := driverConn.(driver.Conn).Prepare("INSERT INTO t (a, b) VALUES (?, ?)")
+//		if err != nil {
+//			return err
+//		}
+//		defer stmt.Close()
+//		results, err = mysql.BatchExec(stmt, rows)
+//		return err
+//	})
+//
+// The results are returned in the order of argsBatch. If a statement fails, a
+// *BatchError with its index is returned and no further statements are sent.
+// The statements which were already sent after the failed one are executed
+// by the server, too; the results of all executed statements are returned
+// and the results of the other statements are nil. Use a transaction to
+// execute the batch atomically.
+//
+// Statements are not pipelined with compression, collectWarnings, strict or
+// TreatWarningsAsErrors, as the warnings must be fetched after each statement.
+func BatchExec(stmt driver.Stmt, argsBatch [][]driver.Value) ([]driver.Result, error) {
+	s, ok := stmt.(*mysqlStmt)
+	if !ok {
+		return nil, fmt.Errorf("mysql: unexpected driver statement %T", stmt)
+	}
+	mc := s.mc
+	if mc.closed.Load() {
+		return nil, driver.ErrBadConn
+	}
+
+	batch, err := s.convertBatch(argsBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	window := batchExecWindow
+	if mc.compress || mc.showsWarnings() {
+		window = 1
+	}
+	results := make([]driver.Result, len(batch))
+	reprepared := false
+	for start := 0; start < len(batch); {
+		end := min(start+window, len(batch))
+		sent, err := s.sendBatch(batch[start:end])
+		if sent == 0 && err != nil {
+			return results, &BatchError{Index: start, Err: mc.markBadConn(err)}
+		}
+		if err != nil && mc.closed.Load() {
+			// the results of the sent statements can not be read
+			return results, &BatchError{Index: start + sent, Err: err}
+		}
+
+		failed, rerr := s.readBatchResults(results[start : start+sent])
+		if rerr != nil {
+			if needsReprepare(rerr) && !reprepared && noResults(results[start:start+sent]) &&
+				s.reprepare(rerr) == nil {
+				// no statement of the window was executed, send it again
+				reprepared = true
+				continue
+			}
+			return results, &BatchError{Index: start + failed, Err: rerr}
+		}
+		if err != nil {
+			return results, &BatchError{Index: start + sent, Err: err}
+		}
+		start += sent
+	}
+	return results, nil
+}
+
+// convertBatch converts the arguments of all statements of a batch first, so
+// that nothing is sent if an argument is invalid.
+func (stmt *mysqlStmt) convertBatch(argsBatch [][]driver.Value) ([][]driver.Value, error) {
+	batch := make([][]driver.Value, len(argsBatch))
+	conv := stmt.mc.converter()
+	for i, args := range argsBatch {
+		if len(args) != stmt.paramCount {
+			return nil, &BatchError{Index: i, Err: fmt.Errorf(
+				"argument count mismatch (got: %d; has: %d)", len(args), stmt.paramCount)}
+		}
+		batch[i] = make([]driver.Value, len(args))
+		for j, arg := range args {
+			v, err := conv.ConvertValue(arg)
+			if err != nil {
+				return nil, &BatchError{Index: i, Err: err}
+			}
+			batch[i][j] = v
+		}
+	}
+	return batch, nil
+}
+
+// sendBatch sends the COM_STMT_EXECUTE packets of the statements of a batch
+// without reading their results. It returns the number of statements sent.
+func (stmt *mysqlStmt) sendBatch(batch [][]driver.Value) (int, error) {
+	for i, args := range batch {
+		if err := stmt.mc.audit(stmt.sql, args); err != nil {
+			return i, err
+		}
+		if err := stmt.writeExecutePacket(args); err != nil {
+			return i, err
+		}
+	}
+	return len(batch), nil
+}
+
+// readBatchResults reads the results of the statements sent by sendBatch into
+// results. It returns the index and the error of the first failed statement.
+// The results of all statements are read unless the connection is broken.
+func (stmt *mysqlStmt) readBatchResults(results []driver.Result) (int, error) {
+	mc := stmt.mc
+	failed, first := 0, error(nil)
+	for i := range results {
+		mc.sequence = 1 // response to a command with sequence 0
+		err := mc.readExecResult()
+		if err == nil {
+			copied := mc.result
+			err = mc.checkWarnings()
+			if err == nil {
+				copied.warnings = mc.warnings
+				results[i] = &copied
+				continue
+			}
+		}
+		if first == nil {
+			failed, first = i, err
+		}
+		var mysqlErr *MySQLError
+		if !errors.As(err, &mysqlErr) && !errors.As(err, new(MySQLWarnings)) {
+			// the connection is broken, the other results are lost
+			break
+		}
+	}
+	mc.clearResult()
+	mc.resetSequence()
+	return failed, first
+}
+
+func noResults(results []driver.Result) bool {
+	for _, r := range results {
+		if r != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestBatchExec(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	stmt := &mysqlStmt{mc: mc, id: 1, paramCount: 1}
+	var replies []byte
+	for id := byte(1); id <= 3; id++ {
+		replies = append(replies, mockPacket(1, []byte{iOK, 1, id, 2, 0, 0, 0})...)
+	}
+	// all results arrive after the last statement was sent
+	conn.queuedReplies = [][]byte{replies}
+
+	results, err := BatchExec(stmt, [][]driver.Value{{1}, {"b"}, {nil}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conn.writes != 3 {
+		t.Errorf("expected 3 writes, got %d", conn.writes)
+	}
+	if cmds := writtenCommands(conn.written); !bytes.Equal(cmds, []byte{comStmtExecute, comStmtExecute, comStmtExecute}) {
+		t.Errorf("unexpected commands %v", cmds)
+	}
+	for i, res := range results {
+		if id, err := res.LastInsertId(); err != nil || id != int64(i+1) {
+			t.Errorf("result %d: got %d, %v", i, id, err)
+		}
+	}
+}
+
+func TestBatchExecError(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	stmt := &mysqlStmt{mc: mc, id: 1, paramCount: 1}
+	var replies []byte
+	replies = append(replies, okPacket...)
+	replies = append(replies, mockErr(1, 1062, "Duplicate entry")...)
+	replies = append(replies, okPacket...)
+	conn.queuedReplies = [][]byte{replies}
+
+	results, err := BatchExec(stmt, [][]driver.Value{{1}, {1}, {2}})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Index != 1 {
+		t.Fatalf("expected BatchError for statement 1, got %v", err)
+	}
+	var mysqlErr *MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != 1062 {
+		t.Errorf("expected MySQLError 1062, got %v", err)
+	}
+	if results[0] == nil || results[1] != nil || results[2] == nil {
+		t.Errorf("unexpected results %v", results)
+	}
+	if mc.closed.Load() {
+		t.Error("connection closed after a statement error")
+	}
+}
+
+func TestBatchExecArgs(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	stmt := &mysqlStmt{mc: mc, id: 1, paramCount: 2}
+	_, err := BatchExec(stmt, [][]driver.Value{{1, 2}, {3}})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Index != 1 {
+		t.Errorf("expected BatchError for statement 1, got %v", err)
+	}
+	if conn.writes != 0 {
+		t.Errorf("statements sent despite invalid arguments: %d writes", conn.writes)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
+	"io"
 	"math"
 	"runtime"
 	"strings"
@@ -257,6 +258,78 @@ func BenchmarkInterpolation(b *testing.B) {
 	}
 }
 
+// textRowsResponse returns the response to a query of n rows with an INT
+// and a TEXT column of valSize bytes.
+func textRowsResponse(n, valSize int) []byte {
+	resp := mockColumn(2, "id", fieldTypeLong)
+	resp = append(resp, mockColumn(3, "val", fieldTypeBLOB)...)
+	resp = append(resp, mockPacket(4, []byte{iEOF, 0, 0, 2, 0})...)
+	seq := byte(5)
+	for i := 0; i < n; i++ {
+		row := appendLengthEncodedString(nil, fmt.Sprint(100000+i))
+		row = appendLengthEncodedString(row, strings.Repeat("x", valSize))
+		resp = append(resp, mockPacket(seq, row)...)
+		seq++
+	}
+	return append(resp, mockPacket(seq, []byte{iEOF, 0, 0, 2, 0})...)
+}
+
+// readTextRows reads all rows of the response returned by textRowsResponse.
+func readTextRows(conn *mockConn, mc *mysqlConn, resp []byte, dest []driver.Value) error {
+	conn.data = resp
+	mc.sequence = 2
+	rows := &textRows{}
+	rows.mc = mc
+	var err error
+	if rows.rs.columns, err = mc.readColumns(len(dest)); err != nil {
+		return err
+	}
+	for {
+		if err = rows.readRow(dest); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func BenchmarkReadTextRows(b *testing.B) {
+	const n = 1000
+	resp := textRowsResponse(n, 50)
+	conn, mc := newRWMockConn(2)
+	dest := make([]driver.Value, 2)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := readTextRows(conn, mc, resp, dest); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReadTextRows10k measures reading 10000 rows of different sizes.
+// reads/op is the number of reads from the network connection.
+func BenchmarkReadTextRows10k(b *testing.B) {
+	for _, valSize := range []int{50, 1000, 8000} {
+		b.Run(fmt.Sprintf("val=%d", valSize), func(b *testing.B) {
+			resp := textRowsResponse(10000, valSize)
+			conn, mc := newRWMockConn(2)
+			dest := make([]driver.Value, 2)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := readTextRows(conn, mc, resp, dest); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(conn.reads)/float64(b.N), "reads/op")
+		})
+	}
+}
+
 func benchmarkQueryContext(b *testing.B, db *sql.DB, p int) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
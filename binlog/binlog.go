@@ -0,0 +1,317 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package binlog streams the binary log of a MySQL server with the
+// replication protocol of the Go MySQL Driver, e.g. for change data capture.
+//
+// Dump registers the connection as a replica and calls a function for every
+// event of the binary log, starting at a binlog position or after a GTID
+// set. The events which describe changes are decoded: rotations, statements
+// (QueryEvent), table maps and the rows of row-based replication:
+//
+//	conn, err := db.Conn(ctx)
+//	...
+//	err = binlog.Dump(ctx, conn, binlog.DumpOptions{ServerID: 1001, GTIDSet: executed},
+//		func(ev binlog.Event) error {
+//			if rows, ok := ev.(*binlog.RowsEvent); ok {
+//				log.Printf("%s on %s.%s: %v", rows.Header().Type, rows.Table.Schema, rows.Table.Table, rows.Rows)
+//			}
+//			return nil
+//		})
+//
+// The user needs the REPLICATION SLAVE privilege (REPLICATION REPLICA in
+// newer versions). The connection can not be used for other statements
+// while the binlog is streamed, and it is closed if the stream is
+// interrupted. Dumps after a GTID set are supported by MySQL only.
+package binlog
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Commands of the replication protocol.
+const (
+	comBinlogDump     = 0x12
+	comRegisterSlave  = 0x15
+	comBinlogDumpGTID = 0x1e
+)
+
+// Flags of the dump commands.
+const (
+	dumpNonBlock    = 0x01
+	dumpThroughGTID = 0x04
+)
+
+// Semi-synchronous replication: events are preceded by the magic byte and a
+// flag byte, and the replica acknowledges events with the flag set.
+const (
+	semiSyncMagic        = 0xef
+	semiSyncAckRequested = 0x01
+)
+
+// ErrChecksum is returned when the CRC32 checksum of an event does not match.
+var ErrChecksum = errors.New("binlog: event checksum mismatch")
+
+// DumpOptions configure a binlog dump.
+type DumpOptions struct {
+	// ServerID identifies the replica. It must differ from the server IDs of
+	// the source and of all other replicas of the source.
+	ServerID uint32
+
+	// File and Position are the binlog position to start at. Position
+	// defaults to 4, the first event of a file. They are ignored if GTIDSet
+	// is set.
+	File     string
+	Position uint32
+
+	// GTIDSet, if not empty, is the GTID set executed by the replica, e.g.
+	// "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5". The source sends all
+	// transactions which are not part of it. Use "" to start at File and
+	// Position instead.
+	GTIDSet string
+
+	// NonBlock ends the dump at the end of the last binlog instead of
+	// waiting for new events.
+	NonBlock bool
+
+	// HeartbeatPeriod, if positive, makes the source send a heartbeat event
+	// when no events are sent for the period, e.g. to keep the read timeout
+	// of the DSN from expiring while the source is idle.
+	HeartbeatPeriod time.Duration
+
+	// SemiSync makes the replica take part in semi-synchronous replication
+	// if it is enabled on the source (rpl_semi_sync_source_enabled or
+	// rpl_semi_sync_master_enabled): events are acknowledged when the source
+	// requests it, after the function passed to Dump returned nil for them.
+	// A source waiting for ACKs then commits a transaction only once it has
+	// been processed. Like MySQL replicas, the dump silently falls back to
+	// asynchronous replication if semi-synchronous replication is disabled
+	// on the source.
+	SemiSync bool
+
+	// Hostname is the host name reported for the replica by SHOW REPLICAS.
+	// It defaults to the host name of the machine.
+	Hostname string
+}
+
+// Register registers conn as a replica with the given server ID using
+// COM_REGISTER_SLAVE. Dump calls it before the dump.
+func Register(ctx context.Context, conn *sql.Conn, serverID uint32, hostname string) error {
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+	if len(hostname) > 255 {
+		hostname = hostname[:255]
+	}
+	// https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_com_register_slave.html
+	data := binary.LittleEndian.AppendUint32(nil, serverID)
+	data = append(data, byte(len(hostname)))
+	data = append(data, hostname...)
+	data = append(data, 0, 0)                   // user, password
+	data = append(data, 0, 0)                   // port
+	data = append(data, 0, 0, 0, 0, 0, 0, 0, 0) // replication rank, source id
+	_, err := mysql.RawCommand(ctx, conn, comRegisterSlave, data)
+	return err
+}
+
+// Dump streams the binlog of the server conn is connected to and calls f for
+// every event, until f returns an error, ctx is done or, with NonBlock, the
+// end of the binlog is reached. Dump returns the error of f, if any. The
+// event passed to f is not modified by Dump afterwards.
+func Dump(ctx context.Context, conn *sql.Conn, opts DumpOptions, f func(Event) error) error {
+	if opts.ServerID == 0 {
+		return errors.New("binlog: ServerID must not be 0")
+	}
+
+	// announce that checksums are handled; the source refuses to send
+	// events with checksums otherwise
+	var checksum string
+	if err := conn.QueryRowContext(ctx, "SELECT @@GLOBAL.binlog_checksum").Scan(&checksum); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, "SET @master_binlog_checksum = @@GLOBAL.binlog_checksum, @source_binlog_checksum = @@GLOBAL.binlog_checksum"); err != nil {
+		return err
+	}
+	if opts.HeartbeatPeriod > 0 {
+		ns := strconv.FormatInt(int64(opts.HeartbeatPeriod), 10)
+		if _, err := conn.ExecContext(ctx, "SET @master_heartbeat_period = "+ns+", @source_heartbeat_period = "+ns); err != nil {
+			return err
+		}
+	}
+	semiSync := false
+	if opts.SemiSync {
+		var err error
+		if semiSync, err = semiSyncEnabled(ctx, conn); err != nil {
+			return err
+		}
+		if semiSync {
+			if _, err := conn.ExecContext(ctx, "SET @rpl_semi_sync_slave = 1, @rpl_semi_sync_replica = 1"); err != nil {
+				return err
+			}
+		}
+	}
+	if err := Register(ctx, conn, opts.ServerID, opts.Hostname); err != nil {
+		return err
+	}
+
+	cmd, payload, err := dumpCommand(&opts)
+	if err != nil {
+		return err
+	}
+	s := &stream{p: newParser(strings.EqualFold(checksum, "CRC32")), semiSync: semiSync, f: f}
+	s.p.file = opts.File
+	return mysql.StreamCommand(ctx, conn, cmd, payload, s.handle)
+}
+
+// stream handles the packets of a dump.
+type stream struct {
+	p        *parser
+	semiSync bool
+	f        func(Event) error
+}
+
+func (s *stream) handle(packet []byte, reply func([]byte) error) error {
+	// OK byte followed by the event
+	data, needAck := packet[1:], false
+	if s.semiSync {
+		if len(data) < 2 || data[0] != semiSyncMagic {
+			return errors.New("binlog: semi-sync event without magic byte")
+		}
+		data, needAck = data[2:], data[1]&semiSyncAckRequested != 0
+	}
+	ev, err := s.p.parse(data)
+	if err != nil {
+		return err
+	}
+	if err := s.f(ev); err != nil {
+		return err
+	}
+	if needAck {
+		return reply(semiSyncAck(s.p.file, ev.Header().LogPos))
+	}
+	return nil
+}
+
+// semiSyncEnabled reports whether semi-synchronous replication is enabled
+// on the source, by the semisync_source or the older semisync_master plugin.
+func semiSyncEnabled(ctx context.Context, conn *sql.Conn) (bool, error) {
+	rows, err := conn.QueryContext(ctx, "SHOW GLOBAL VARIABLES WHERE Variable_name IN ('rpl_semi_sync_source_enabled', 'rpl_semi_sync_master_enabled')")
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	enabled := false
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return false, err
+		}
+		enabled = enabled || strings.EqualFold(value, "ON")
+	}
+	return enabled, rows.Err()
+}
+
+// semiSyncAck returns the ACK packet of semi-synchronous replication for
+// the event ending at pos in the binlog file.
+func semiSyncAck(file string, pos uint32) []byte {
+	data := []byte{semiSyncMagic}
+	data = binary.LittleEndian.AppendUint64(data, uint64(pos))
+	return append(data, file...)
+}
+
+// dumpCommand returns the dump command for opts and its payload.
+func dumpCommand(opts *DumpOptions) (byte, []byte, error) {
+	var flags uint16
+	if opts.NonBlock {
+		flags |= dumpNonBlock
+	}
+	pos := opts.Position
+	if pos < 4 {
+		pos = 4
+	}
+
+	if opts.GTIDSet == "" {
+		// https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_com_binlog_dump.html
+		data := binary.LittleEndian.AppendUint32(nil, pos)
+		data = binary.LittleEndian.AppendUint16(data, flags)
+		data = binary.LittleEndian.AppendUint32(data, opts.ServerID)
+		data = append(data, opts.File...)
+		return comBinlogDump, data, nil
+	}
+
+	gtids, err := encodeGTIDSet(opts.GTIDSet)
+	if err != nil {
+		return 0, nil, err
+	}
+	// https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_com_binlog_dump_gtid.html
+	flags |= dumpThroughGTID
+	data := binary.LittleEndian.AppendUint16(nil, flags)
+	data = binary.LittleEndian.AppendUint32(data, opts.ServerID)
+	data = binary.LittleEndian.AppendUint32(data, 0) // no file name
+	data = binary.LittleEndian.AppendUint64(data, 4)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(gtids)))
+	data = append(data, gtids...)
+	return comBinlogDumpGTID, data, nil
+}
+
+// encodeGTIDSet encodes a GTID set like "uuid:1-5:7,uuid2:1-3" in the
+// binary format of COM_BINLOG_DUMP_GTID.
+func encodeGTIDSet(s string) ([]byte, error) {
+	var sids [][]string
+	for _, sid := range strings.Split(s, ",") {
+		if sid = strings.TrimSpace(sid); sid != "" {
+			sids = append(sids, strings.Split(sid, ":"))
+		}
+	}
+
+	data := binary.LittleEndian.AppendUint64(nil, uint64(len(sids)))
+	for _, parts := range sids {
+		uuid, err := hex.DecodeString(strings.ReplaceAll(parts[0], "-", ""))
+		if err != nil || len(uuid) != 16 {
+			return nil, fmt.Errorf("binlog: invalid GTID set %q", s)
+		}
+		data = append(data, uuid...)
+		data = binary.LittleEndian.AppendUint64(data, uint64(len(parts)-1))
+		for _, interval := range parts[1:] {
+			first, last, ok := strings.Cut(interval, "-")
+			if !ok {
+				last = first
+			}
+			start, err1 := strconv.ParseUint(first, 10, 63)
+			end, err2 := strconv.ParseUint(last, 10, 63)
+			if err1 != nil || err2 != nil || start == 0 || end < start {
+				return nil, fmt.Errorf("binlog: invalid GTID set %q", s)
+			}
+			// the end of an interval is exclusive
+			data = binary.LittleEndian.AppendUint64(data, start)
+			data = binary.LittleEndian.AppendUint64(data, end+1)
+		}
+	}
+	return data, nil
+}
+
+// verifyChecksum checks the CRC32 checksum at the end of an event.
+func verifyChecksum(data []byte) error {
+	n := len(data) - 4
+	if n < 0 || crc32.ChecksumIEEE(data[:n]) != binary.LittleEndian.Uint32(data[n:]) {
+		return ErrChecksum
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package binlog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"hash/crc32"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// event returns an event of type t with body, followed by a CRC32 checksum.
+func event(t EventType, body []byte) []byte {
+	data := make([]byte, eventHeaderLen, eventHeaderLen+len(body)+4)
+	binary.LittleEndian.PutUint32(data, 1700000000)
+	data[4] = byte(t)
+	binary.LittleEndian.PutUint32(data[5:], 1)
+	binary.LittleEndian.PutUint32(data[9:], uint32(eventHeaderLen+len(body)+4))
+	data = append(data, body...)
+	return binary.LittleEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+}
+
+func formatDescription() []byte {
+	body := binary.LittleEndian.AppendUint16(nil, 4)
+	version := make([]byte, 50)
+	copy(version, "8.0.36")
+	body = append(body, version...)
+	body = append(body, 0, 0, 0, 0, eventHeaderLen)
+	lens := make([]byte, 41)
+	lens[QueryEventType-1] = 13
+	lens[TableMapEventType-1] = 8
+	lens[WriteRowsEventType-1] = 10
+	lens[UpdateRowsEventType-1] = 10
+	body = append(body, lens...)
+	return append(body, 1) // CRC32
+}
+
+func TestParseEvents(t *testing.T) {
+	p := newParser(true)
+	mustParse := func(data []byte) Event {
+		t.Helper()
+		ev, err := p.parse(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ev
+	}
+
+	rotate := mustParse(event(RotateEventType, append([]byte{4, 0, 0, 0, 0, 0, 0, 0}, "binlog.000002"...)))
+	if ev, ok := rotate.(*RotateEvent); !ok || ev.NextFile != "binlog.000002" || ev.Position != 4 {
+		t.Errorf("unexpected rotate event %+v", rotate)
+	}
+
+	fde := mustParse(event(FormatDescriptionEventType, formatDescription()))
+	if ev, ok := fde.(*FormatDescriptionEvent); !ok || ev.ServerVersion != "8.0.36" || ev.ChecksumAlgorithm != 1 {
+		t.Errorf("unexpected format description event %+v", fde)
+	}
+
+	query := []byte{7, 0, 0, 0, 0, 0, 0, 0, 4, 0, 0, 0, 0}
+	query = append(query, "shop\x00BEGIN"...)
+	if ev, ok := mustParse(event(QueryEventType, query)).(*QueryEvent); !ok || ev.Schema != "shop" || ev.Query != "BEGIN" || ev.ThreadID != 7 {
+		t.Errorf("unexpected query event %+v", ev)
+	}
+
+	// table map of shop.t (id INT UNSIGNED, name VARCHAR(20), price DECIMAL(14,4), created DATETIME(3))
+	tableMap := []byte{42, 0, 0, 0, 0, 0, 1, 0, 4}
+	tableMap = append(tableMap, "shop\x00"...)
+	tableMap = append(tableMap, 1)
+	tableMap = append(tableMap, "t\x00"...)
+	tableMap = append(tableMap, 4, typeLong, typeVarChar, typeNewDecimal, typeDateTime2)
+	tableMap = append(tableMap, 5, 20, 0, 14, 4, 3) // metadata
+	tableMap = append(tableMap, 0x0e)               // null bitmap
+	tableMap = append(tableMap, metaSignedness, 1, 0x80)
+	tableMap = append(tableMap, metaColumnName, 22, 2, 'i', 'd', 4, 'n', 'a', 'm', 'e', 5, 'p', 'r', 'i', 'c', 'e', 7, 'c', 'r', 'e', 'a', 't', 'e', 'd')
+	tm := mustParse(event(TableMapEventType, tableMap)).(*TableMapEvent)
+	if tm.TableID != 42 || tm.Schema != "shop" || tm.Table != "t" {
+		t.Errorf("unexpected table map event %+v", tm)
+	}
+	if !reflect.DeepEqual(tm.ColumnNames, []string{"id", "name", "price", "created"}) ||
+		!reflect.DeepEqual(tm.Unsigned, []bool{true, false, false, false}) {
+		t.Errorf("unexpected optional metadata %q %v", tm.ColumnNames, tm.Unsigned)
+	}
+
+	// DATETIME(3) 2024-02-29 13:14:15.123
+	ymd := int64((2024*13+2)<<5 | 29)
+	hms := int64(13<<12 | 14<<6 | 15)
+	dt := make([]byte, 8)
+	binary.BigEndian.PutUint64(dt, uint64(ymd<<17|hms+0x8000000000))
+	created := append(dt[3:], 0x04, 0xce) // 1230 hundreds of microseconds
+
+	price, _ := hex.DecodeString("810dfb38d204d2") // 1234567890.1234
+	row := func(id byte, name string) []byte {
+		r := []byte{0, id, 0, 0, 0x80, byte(len(name))}
+		r = append(r, name...)
+		r = append(r, price...)
+		return append(r, created...)
+	}
+	rows := []byte{42, 0, 0, 0, 0, 0, 1, 0, 2, 0, 4, 0x0f}
+	rows = append(rows, row(0xff, "apple")...)
+	rows = append(rows, 0x02, 2, 0, 0, 0, price[0], price[1], price[2], price[3], price[4], price[5], price[6])
+	rows = append(rows, created...)
+	re := mustParse(event(WriteRowsEventType, rows)).(*RowsEvent)
+	d, _ := mysql.NewDecimal("1234567890.1234")
+	ts := time.Date(2024, 2, 29, 13, 14, 15, 123000000, time.UTC)
+	expected := [][]any{
+		{uint64(0x800000ff), []byte("apple"), d, ts},
+		{uint64(2), nil, d, ts},
+	}
+	if re.Table != tm || !reflect.DeepEqual(re.Rows, expected) || re.Before != nil {
+		t.Errorf("unexpected rows %v, expected %v", re.Rows, expected)
+	}
+
+	update := []byte{42, 0, 0, 0, 0, 0, 1, 0, 2, 0, 4, 0x0f, 0x0f}
+	update = append(update, row(1, "a")...)
+	update = append(update, row(1, "b")...)
+	ue := mustParse(event(UpdateRowsEventType, update)).(*RowsEvent)
+	if len(ue.Before) != 1 || len(ue.Rows) != 1 ||
+		!bytes.Equal(ue.Before[0][1].([]byte), []byte("a")) || !bytes.Equal(ue.Rows[0][1].([]byte), []byte("b")) {
+		t.Errorf("unexpected update %v -> %v", ue.Before, ue.Rows)
+	}
+
+	if ev, ok := mustParse(event(XIDEventType, []byte{9, 0, 0, 0, 0, 0, 0, 0})).(*XIDEvent); !ok || ev.XID != 9 {
+		t.Errorf("unexpected xid event %+v", ev)
+	}
+
+	gtid := append([]byte{1}, make([]byte, 16)...)
+	gtid[1] = 0x3e
+	gtid = binary.LittleEndian.AppendUint64(gtid, 23)
+	ge := mustParse(event(GTIDEventType, gtid)).(*GTIDEvent)
+	if s := ge.GTID(); s != "3e000000-0000-0000-0000-000000000000:23" {
+		t.Errorf("unexpected GTID %q", s)
+	}
+
+	if _, ok := mustParse(event(HeartbeatEventType, []byte("binlog.000002"))).(*GenericEvent); !ok {
+		t.Error("expected a generic event for heartbeats")
+	}
+
+	bad := event(XIDEventType, []byte{9, 0, 0, 0, 0, 0, 0, 0})
+	bad[len(bad)-1] ^= 1
+	if _, err := p.parse(bad); err != ErrChecksum {
+		t.Errorf("expected ErrChecksum, got %v", err)
+	}
+}
+
+func TestDecodeDecimal(t *testing.T) {
+	tests := []struct {
+		hex              string
+		precision, scale int
+		expected         string
+	}{
+		{"810dfb38d204d2", 14, 4, "1234567890.1234"},
+		{"7ef204c72dfb2d", 14, 4, "-1234567890.1234"},
+		{"8000000000", 10, 0, "0"},
+		{"8000010001f4", 10, 5, "1.00500"},
+		{"7ffffefffe0b", 10, 5, "-1.00500"},
+	}
+	for _, test := range tests {
+		b, _ := hex.DecodeString(test.hex)
+		if n := decimalSize(test.precision, test.scale); n != len(b) {
+			t.Errorf("size of DECIMAL(%d,%d): got %d, expected %d", test.precision, test.scale, n, len(b))
+			continue
+		}
+		s, err := decodeDecimal(b, test.precision, test.scale)
+		if err != nil || s != test.expected {
+			t.Errorf("%s: got %q (%v), expected %q", test.hex, s, err, test.expected)
+		}
+	}
+}
+
+func TestDecodeTemporal(t *testing.T) {
+	// TIME(0) -838:59:59 and TIME(2) -00:00:01.50
+	v, _, err := decodeValue([]byte{0x4b, 0x91, 0x05}, typeTime2, 0, false)
+	if err != nil || v != -(838*time.Hour+59*time.Minute+59*time.Second) {
+		t.Errorf("unexpected TIME %v (%v)", v, err)
+	}
+	v, _, err = decodeValue([]byte{0x7f, 0xff, 0xfe, 0xce}, typeTime2, 2, false)
+	if err != nil || v != -1500*time.Millisecond {
+		t.Errorf("unexpected TIME(2) %v (%v)", v, err)
+	}
+
+	v, _, err = decodeValue([]byte{0x1a, 0xab, 0x0f}, typeDate, 0, false)
+	if err != nil || v != time.Date(2005, 8, 26, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("unexpected DATE %v (%v)", v, err)
+	}
+	v, _, err = decodeValue([]byte{0x65, 0x9a, 0x5e, 0x00, 0x00, 0x30, 0x39}, typeTimestamp2, 6, false)
+	if err != nil || v != time.Unix(1704615424, 12345000).UTC() {
+		t.Errorf("unexpected TIMESTAMP(6) %v (%v)", v, err)
+	}
+}
+
+func TestDumpCommand(t *testing.T) {
+	cmd, data, err := dumpCommand(&DumpOptions{ServerID: 7, File: "binlog.000003", Position: 157, NonBlock: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := append([]byte{157, 0, 0, 0, 1, 0, 7, 0, 0, 0}, "binlog.000003"...)
+	if cmd != comBinlogDump || !bytes.Equal(data, expected) {
+		t.Errorf("unexpected COM_BINLOG_DUMP %#x %v", cmd, data)
+	}
+
+	cmd, data, err = dumpCommand(&DumpOptions{ServerID: 7, GTIDSet: "3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5:7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd != comBinlogDumpGTID || binary.LittleEndian.Uint16(data) != dumpThroughGTID {
+		t.Errorf("unexpected COM_BINLOG_DUMP_GTID %#x %v", cmd, data)
+	}
+	sid, _ := hex.DecodeString("3e11fa4771ca11e19e33c80aa9429562")
+	gtids := binary.LittleEndian.AppendUint64(nil, 1)
+	gtids = append(gtids, sid...)
+	for _, v := range []uint64{2, 1, 6, 7, 8} {
+		gtids = binary.LittleEndian.AppendUint64(gtids, v)
+	}
+	if n := binary.LittleEndian.Uint32(data[18:]); n != uint32(len(gtids)) || !bytes.Equal(data[22:], gtids) {
+		t.Errorf("unexpected GTID set %v, expected %v", data[18:], gtids)
+	}
+
+	if _, _, err := dumpCommand(&DumpOptions{ServerID: 7, GTIDSet: "nope:1-5"}); err == nil {
+		t.Error("expected an error for an invalid GTID set")
+	}
+}
+
+func TestSemiSync(t *testing.T) {
+	var events []Event
+	s := &stream{p: newParser(true), semiSync: true, f: func(ev Event) error {
+		events = append(events, ev)
+		return nil
+	}}
+	var acks [][]byte
+	reply := func(payload []byte) error {
+		acks = append(acks, payload)
+		return nil
+	}
+
+	rotate := event(RotateEventType, append([]byte{4, 0, 0, 0, 0, 0, 0, 0}, "binlog.000002"...))
+	if err := s.handle(append([]byte{0, semiSyncMagic, 0}, rotate...), reply); err != nil {
+		t.Fatal(err)
+	}
+	xid := event(XIDEventType, []byte{9, 0, 0, 0, 0, 0, 0, 0})
+	binary.LittleEndian.PutUint32(xid[13:], 1234)
+	binary.LittleEndian.PutUint32(xid[len(xid)-4:], crc32.ChecksumIEEE(xid[:len(xid)-4]))
+	if err := s.handle(append([]byte{0, semiSyncMagic, semiSyncAckRequested}, xid...), reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	expected := append([]byte{semiSyncMagic, 0xd2, 0x04, 0, 0, 0, 0, 0, 0}, "binlog.000002"...)
+	if len(acks) != 1 || !bytes.Equal(acks[0], expected) {
+		t.Errorf("unexpected ACKs %v, expected %v", acks, expected)
+	}
+
+	if err := s.handle(append([]byte{0}, xid...), reply); err == nil {
+		t.Error("expected an error for an event without magic byte")
+	}
+}
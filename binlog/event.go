@@ -0,0 +1,336 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package binlog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EventType is the type of a binlog event.
+type EventType byte
+
+// Event types
+const (
+	QueryEventType             EventType = 2
+	RotateEventType            EventType = 4
+	FormatDescriptionEventType EventType = 15
+	XIDEventType               EventType = 16
+	TableMapEventType          EventType = 19
+	WriteRowsEventV1Type       EventType = 23
+	UpdateRowsEventV1Type      EventType = 24
+	DeleteRowsEventV1Type      EventType = 25
+	HeartbeatEventType         EventType = 27
+	WriteRowsEventType         EventType = 30
+	UpdateRowsEventType        EventType = 31
+	DeleteRowsEventType        EventType = 32
+	GTIDEventType              EventType = 33
+	AnonymousGTIDEventType     EventType = 34
+	PreviousGTIDsEventType     EventType = 35
+)
+
+var eventTypeNames = map[EventType]string{
+	QueryEventType:             "Query",
+	RotateEventType:            "Rotate",
+	FormatDescriptionEventType: "Format_desc",
+	XIDEventType:               "Xid",
+	TableMapEventType:          "Table_map",
+	WriteRowsEventV1Type:       "Write_rows_v1",
+	UpdateRowsEventV1Type:      "Update_rows_v1",
+	DeleteRowsEventV1Type:      "Delete_rows_v1",
+	HeartbeatEventType:         "Heartbeat",
+	WriteRowsEventType:         "Write_rows",
+	UpdateRowsEventType:        "Update_rows",
+	DeleteRowsEventType:        "Delete_rows",
+	GTIDEventType:              "Gtid",
+	AnonymousGTIDEventType:     "Anonymous_Gtid",
+	PreviousGTIDsEventType:     "Previous_gtids",
+}
+
+// String returns the name of the event type as shown by SHOW BINLOG EVENTS.
+func (t EventType) String() string {
+	if name, ok := eventTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("EventType(%d)", byte(t))
+}
+
+const eventHeaderLen = 19
+
+// EventHeader is the common header of all events.
+type EventHeader struct {
+	Timestamp time.Time
+	Type      EventType
+	ServerID  uint32
+	EventSize uint32
+	LogPos    uint32 // position of the next event in the binlog file
+	Flags     uint16
+}
+
+// Header returns the header of the event.
+func (h *EventHeader) Header() *EventHeader {
+	return h
+}
+
+// Event is a binlog event. It is one of *RotateEvent,
+// *FormatDescriptionEvent, *QueryEvent, *XIDEvent, *GTIDEvent,
+// *TableMapEvent, *RowsEvent or *GenericEvent.
+type Event interface {
+	Header() *EventHeader
+}
+
+// GenericEvent is an event which is not decoded, e.g. a heartbeat.
+type GenericEvent struct {
+	EventHeader
+	Data []byte // body of the event
+}
+
+// RotateEvent announces the binlog file of the following events. It is sent
+// at the start of a dump and when the source switches to a new file.
+type RotateEvent struct {
+	EventHeader
+	Position uint64
+	NextFile string
+}
+
+// FormatDescriptionEvent describes the format of the events of a binlog file.
+type FormatDescriptionEvent struct {
+	EventHeader
+	BinlogVersion     uint16
+	ServerVersion     string
+	HeaderLength      uint8
+	ChecksumAlgorithm byte // 0 for none, 1 for CRC32
+	postHeaderLens    []byte
+}
+
+// QueryEvent is a statement, e.g. a DDL statement, BEGIN, or a DML
+// statement with statement-based replication.
+type QueryEvent struct {
+	EventHeader
+	ThreadID      uint32
+	ExecutionTime uint32
+	ErrorCode     uint16
+	Schema        string
+	Query         string
+}
+
+// XIDEvent is the commit of a transaction.
+type XIDEvent struct {
+	EventHeader
+	XID uint64
+}
+
+// GTIDEvent precedes the events of a transaction and carries its GTID.
+type GTIDEvent struct {
+	EventHeader
+	SID [16]byte // UUID of the source
+	GNO int64    // sequence number of the transaction, 0 for anonymous transactions
+}
+
+// GTID returns the GTID in the usual form uuid:gno.
+func (e *GTIDEvent) GTID() string {
+	u := hex.EncodeToString(e.SID[:])
+	return fmt.Sprintf("%s-%s-%s-%s-%s:%d", u[:8], u[8:12], u[12:16], u[16:20], u[20:], e.GNO)
+}
+
+var errShortEvent = errors.New("binlog: event too short")
+
+// parser decodes the events of a dump. It keeps the format of the current
+// binlog file and the table maps needed to decode rows events.
+type parser struct {
+	checksum bool   // events end with a CRC32 checksum
+	file     string // current binlog file, see RotateEvent
+	format   *FormatDescriptionEvent
+	tables   map[uint64]*TableMapEvent
+}
+
+func newParser(checksum bool) *parser {
+	return &parser{checksum: checksum, tables: make(map[uint64]*TableMapEvent)}
+}
+
+// postHeaderLen returns the length of the post header of events of type t,
+// or def if the format description does not specify it.
+func (p *parser) postHeaderLen(t EventType, def int) int {
+	if p.format != nil && int(t) <= len(p.format.postHeaderLens) {
+		return int(p.format.postHeaderLens[t-1])
+	}
+	return def
+}
+
+// parse decodes an event. The returned event does not refer to data.
+func (p *parser) parse(data []byte) (Event, error) {
+	if len(data) < eventHeaderLen {
+		return nil, errShortEvent
+	}
+	h := EventHeader{
+		Timestamp: time.Unix(int64(binary.LittleEndian.Uint32(data)), 0),
+		Type:      EventType(data[4]),
+		ServerID:  binary.LittleEndian.Uint32(data[5:]),
+		EventSize: binary.LittleEndian.Uint32(data[9:]),
+		LogPos:    binary.LittleEndian.Uint32(data[13:]),
+		Flags:     binary.LittleEndian.Uint16(data[17:]),
+	}
+
+	if h.Type == FormatDescriptionEventType {
+		ev, err := parseFormatDescription(h, data)
+		if err != nil {
+			return nil, err
+		}
+		p.checksum = ev.ChecksumAlgorithm == 1
+		if p.checksum {
+			if err := verifyChecksum(data); err != nil {
+				return nil, err
+			}
+		}
+		p.format = ev
+		// table IDs are only valid within a binlog file
+		p.tables = make(map[uint64]*TableMapEvent)
+		return ev, nil
+	}
+
+	if p.checksum {
+		if err := verifyChecksum(data); err != nil {
+			return nil, err
+		}
+		data = data[:len(data)-4]
+	}
+	body := data[eventHeaderLen:]
+
+	switch h.Type {
+	case RotateEventType:
+		if len(body) < 8 {
+			return nil, errShortEvent
+		}
+		ev := &RotateEvent{
+			EventHeader: h,
+			Position:    binary.LittleEndian.Uint64(body),
+			NextFile:    string(body[8:]),
+		}
+		p.file = ev.NextFile
+		return ev, nil
+
+	case QueryEventType:
+		return parseQuery(h, body, p.postHeaderLen(h.Type, 13))
+
+	case XIDEventType:
+		if len(body) < 8 {
+			return nil, errShortEvent
+		}
+		return &XIDEvent{EventHeader: h, XID: binary.LittleEndian.Uint64(body)}, nil
+
+	case GTIDEventType, AnonymousGTIDEventType:
+		if len(body) < 25 {
+			return nil, errShortEvent
+		}
+		ev := &GTIDEvent{EventHeader: h, GNO: int64(binary.LittleEndian.Uint64(body[17:]))}
+		copy(ev.SID[:], body[1:17])
+		return ev, nil
+
+	case TableMapEventType:
+		ev, err := parseTableMap(h, body, p.postHeaderLen(h.Type, 8))
+		if err != nil {
+			return nil, err
+		}
+		p.tables[ev.TableID] = ev
+		return ev, nil
+
+	case WriteRowsEventV1Type, UpdateRowsEventV1Type, DeleteRowsEventV1Type,
+		WriteRowsEventType, UpdateRowsEventType, DeleteRowsEventType:
+		def := 8
+		if h.Type >= WriteRowsEventType {
+			def = 10
+		}
+		return p.parseRows(h, body, p.postHeaderLen(h.Type, def))
+	}
+	return &GenericEvent{EventHeader: h, Data: append([]byte(nil), body...)}, nil
+}
+
+func parseFormatDescription(h EventHeader, data []byte) (*FormatDescriptionEvent, error) {
+	// binlog version [2], server version [50], create timestamp [4],
+	// header length [1], post header lengths, checksum algorithm [1],
+	// checksum [4]
+	body := data[eventHeaderLen:]
+	if len(body) < 57+5 {
+		return nil, errShortEvent
+	}
+	version := body[2:52]
+	if i := bytes.IndexByte(version, 0); i >= 0 {
+		version = version[:i]
+	}
+	return &FormatDescriptionEvent{
+		EventHeader:       h,
+		BinlogVersion:     binary.LittleEndian.Uint16(body),
+		ServerVersion:     string(version),
+		HeaderLength:      body[56],
+		ChecksumAlgorithm: body[len(body)-5],
+		postHeaderLens:    append([]byte(nil), body[57:len(body)-5]...),
+	}, nil
+}
+
+func parseQuery(h EventHeader, body []byte, postHeaderLen int) (*QueryEvent, error) {
+	if len(body) < 13 || postHeaderLen < 13 {
+		return nil, errShortEvent
+	}
+	ev := &QueryEvent{
+		EventHeader:   h,
+		ThreadID:      binary.LittleEndian.Uint32(body),
+		ExecutionTime: binary.LittleEndian.Uint32(body[4:]),
+		ErrorCode:     binary.LittleEndian.Uint16(body[9:]),
+	}
+	schemaLen := int(body[8])
+	statusLen := int(binary.LittleEndian.Uint16(body[11:]))
+	pos := postHeaderLen + statusLen
+	if len(body) < pos+schemaLen+1 {
+		return nil, errShortEvent
+	}
+	ev.Schema = string(body[pos : pos+schemaLen])
+	ev.Query = string(body[pos+schemaLen+1:])
+	return ev, nil
+}
+
+// readLengthEncodedInteger reads an integer of the client/server protocol.
+func readLengthEncodedInteger(b []byte) (uint64, int, error) {
+	if len(b) == 0 {
+		return 0, 0, errShortEvent
+	}
+	n := 1
+	switch b[0] {
+	case 0xfc:
+		n = 3
+	case 0xfd:
+		n = 4
+	case 0xfe:
+		n = 9
+	default:
+		return uint64(b[0]), 1, nil
+	}
+	if len(b) < n {
+		return 0, 0, errShortEvent
+	}
+	var v uint64
+	for i := n - 1; i > 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	return v, n, nil
+}
+
+// readTableID reads the table ID of a table map or rows event, 6 bytes
+// unless the post header is 6 bytes long.
+func readTableID(body []byte, postHeaderLen int) (uint64, int) {
+	if postHeaderLen == 6 {
+		return uint64(binary.LittleEndian.Uint32(body)), 4
+	}
+	var b [8]byte
+	copy(b[:], body[:6])
+	return binary.LittleEndian.Uint64(b[:]), 6
+}
@@ -0,0 +1,645 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package binlog
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Column types of the binlog, see enum_field_types of the server.
+const (
+	typeDecimal    = 0
+	typeTiny       = 1
+	typeShort      = 2
+	typeLong       = 3
+	typeFloat      = 4
+	typeDouble     = 5
+	typeNull       = 6
+	typeTimestamp  = 7
+	typeLongLong   = 8
+	typeInt24      = 9
+	typeDate       = 10
+	typeTime       = 11
+	typeDateTime   = 12
+	typeYear       = 13
+	typeVarChar    = 15
+	typeBit        = 16
+	typeTimestamp2 = 17
+	typeDateTime2  = 18
+	typeTime2      = 19
+	typeVector     = 242
+	typeJSON       = 245
+	typeNewDecimal = 246
+	typeEnum       = 247
+	typeSet        = 248
+	typeTinyBlob   = 249
+	typeMediumBlob = 250
+	typeLongBlob   = 251
+	typeBlob       = 252
+	typeVarString  = 253
+	typeString     = 254
+	typeGeometry   = 255
+)
+
+// Types of the optional metadata of table map events.
+const (
+	metaSignedness = 1
+	metaColumnName = 4
+)
+
+// TableMapEvent describes the table of the following rows events.
+type TableMapEvent struct {
+	EventHeader
+	TableID     uint64
+	Schema      string
+	Table       string
+	ColumnTypes []byte // binlog column types, see enum_field_types of the server
+
+	// ColumnNames and Unsigned are only known if binlog_row_metadata=FULL
+	// (MySQL 8.0.1+). Otherwise ColumnNames is nil and all integers are
+	// treated as signed.
+	ColumnNames []string
+	Unsigned    []bool
+
+	meta []uint16 // type specific metadata of the columns
+}
+
+// RowsEvent holds the rows written, updated or deleted by a statement with
+// row-based replication.
+//
+// The values are nil for NULL, int64 or uint64 for integers and YEAR,
+// float32 and float64, mysql.Decimal, []byte for strings, BLOBs, GEOMETRY
+// and JSON (in the binary JSON format of the server), time.Time in UTC for
+// DATE, DATETIME and TIMESTAMP, time.Duration for TIME, uint64 for BIT and
+// SET, and int64 for the index of ENUM values. Columns missing from the row
+// image, e.g. with binlog_row_image=MINIMAL, are nil as well.
+type RowsEvent struct {
+	EventHeader
+	Table *TableMapEvent
+	Flags uint16
+
+	// Rows are the written or deleted rows, or the rows after an update.
+	Rows [][]any
+
+	// Before are the rows before an update, in the order of Rows.
+	Before [][]any
+}
+
+func parseTableMap(h EventHeader, body []byte, postHeaderLen int) (*TableMapEvent, error) {
+	if len(body) < postHeaderLen+1 {
+		return nil, errShortEvent
+	}
+	ev := &TableMapEvent{EventHeader: h}
+	ev.TableID, _ = readTableID(body, postHeaderLen)
+	pos := postHeaderLen
+
+	// schema and table names, with length prefix and terminating NUL
+	var names [2]string
+	for i := range names {
+		if pos >= len(body) {
+			return nil, errShortEvent
+		}
+		n := int(body[pos])
+		if len(body) < pos+1+n+1 {
+			return nil, errShortEvent
+		}
+		names[i] = string(body[pos+1 : pos+1+n])
+		pos += 1 + n + 1
+	}
+	ev.Schema, ev.Table = names[0], names[1]
+
+	columnCount, n, err := readLengthEncodedInteger(body[pos:])
+	if err != nil {
+		return nil, err
+	}
+	pos += n
+	if uint64(len(body)-pos) < columnCount {
+		return nil, errShortEvent
+	}
+	ev.ColumnTypes = append([]byte(nil), body[pos:pos+int(columnCount)]...)
+	pos += int(columnCount)
+
+	metaLen, n, err := readLengthEncodedInteger(body[pos:])
+	if err != nil {
+		return nil, err
+	}
+	pos += n
+	if uint64(len(body)-pos) < metaLen {
+		return nil, errShortEvent
+	}
+	if ev.meta, err = parseColumnMeta(ev.ColumnTypes, body[pos:pos+int(metaLen)]); err != nil {
+		return nil, err
+	}
+	pos += int(metaLen)
+
+	// null bitmap, followed by the optional metadata
+	pos += (len(ev.ColumnTypes) + 7) / 8
+	if pos > len(body) {
+		return nil, errShortEvent
+	}
+	if err := ev.parseOptionalMeta(body[pos:]); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// parseColumnMeta parses the metadata of the columns, e.g. the maximum
+// length of VARCHAR columns. Two-byte metadata is stored little-endian, so
+// that the first byte is in the lower bits.
+func parseColumnMeta(types []byte, b []byte) ([]uint16, error) {
+	meta := make([]uint16, len(types))
+	pos := 0
+	for i, t := range types {
+		n := 0
+		switch t {
+		case typeFloat, typeDouble, typeBlob, typeGeometry, typeJSON, typeVector,
+			typeTimestamp2, typeDateTime2, typeTime2:
+			n = 1
+		case typeVarChar, typeVarString, typeBit, typeNewDecimal, typeString, typeEnum, typeSet:
+			n = 2
+		}
+		if len(b) < pos+n {
+			return nil, errShortEvent
+		}
+		switch n {
+		case 1:
+			meta[i] = uint16(b[pos])
+		case 2:
+			meta[i] = binary.LittleEndian.Uint16(b[pos:])
+		}
+		pos += n
+	}
+	return meta, nil
+}
+
+func isNumericType(t byte) bool {
+	switch t {
+	case typeTiny, typeShort, typeInt24, typeLong, typeLongLong, typeFloat, typeDouble, typeDecimal, typeNewDecimal:
+		return true
+	}
+	return false
+}
+
+// parseOptionalMeta parses the optional metadata of binlog_row_metadata,
+// fields of type, length and value.
+func (ev *TableMapEvent) parseOptionalMeta(b []byte) error {
+	for len(b) > 0 {
+		typ := b[0]
+		n, m, err := readLengthEncodedInteger(b[1:])
+		if err != nil {
+			return err
+		}
+		b = b[1+m:]
+		if uint64(len(b)) < n {
+			return errShortEvent
+		}
+		value := b[:n]
+		b = b[n:]
+
+		switch typ {
+		case metaSignedness:
+			// one bit per numeric column, most significant bit first
+			ev.Unsigned = make([]bool, len(ev.ColumnTypes))
+			j := 0
+			for i, t := range ev.ColumnTypes {
+				if !isNumericType(t) {
+					continue
+				}
+				if j/8 < len(value) {
+					ev.Unsigned[i] = value[j/8]&(0x80>>(j%8)) != 0
+				}
+				j++
+			}
+		case metaColumnName:
+			var names []string
+			for len(value) > 0 {
+				l, m, err := readLengthEncodedInteger(value)
+				if err != nil {
+					return err
+				}
+				if uint64(len(value)-m) < l {
+					return errShortEvent
+				}
+				names = append(names, string(value[m:m+int(l)]))
+				value = value[m+int(l):]
+			}
+			ev.ColumnNames = names
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseRows(h EventHeader, body []byte, postHeaderLen int) (*RowsEvent, error) {
+	if len(body) < postHeaderLen {
+		return nil, errShortEvent
+	}
+	ev := &RowsEvent{EventHeader: h}
+	tableID, n := readTableID(body, postHeaderLen)
+	ev.Flags = binary.LittleEndian.Uint16(body[n:])
+	pos := postHeaderLen
+	if postHeaderLen == 10 {
+		// v2: extra data, including its length
+		extra := int(binary.LittleEndian.Uint16(body[n+2:]))
+		pos += extra - 2
+	}
+	ev.Table = p.tables[tableID]
+	if ev.Table == nil {
+		return nil, fmt.Errorf("binlog: rows event for unknown table %d", tableID)
+	}
+	if pos > len(body) {
+		return nil, errShortEvent
+	}
+
+	columnCount, n, err := readLengthEncodedInteger(body[pos:])
+	if err != nil {
+		return nil, err
+	}
+	pos += n
+	if columnCount != uint64(len(ev.Table.ColumnTypes)) {
+		return nil, fmt.Errorf("binlog: rows event with %d columns for table %s.%s with %d columns",
+			columnCount, ev.Table.Schema, ev.Table.Table, len(ev.Table.ColumnTypes))
+	}
+	bitmapLen := (int(columnCount) + 7) / 8
+	update := h.Type == UpdateRowsEventV1Type || h.Type == UpdateRowsEventType
+	images := 1
+	if update {
+		images = 2
+	}
+	if len(body) < pos+images*bitmapLen {
+		return nil, errShortEvent
+	}
+	present := body[pos : pos+bitmapLen]
+	presentAfter := present
+	pos += bitmapLen
+	if update {
+		presentAfter = body[pos : pos+bitmapLen]
+		pos += bitmapLen
+	}
+
+	for pos < len(body) {
+		if update {
+			row, n, err := ev.Table.decodeRow(body[pos:], present)
+			if err != nil {
+				return nil, err
+			}
+			ev.Before = append(ev.Before, row)
+			pos += n
+		}
+		row, n, err := ev.Table.decodeRow(body[pos:], presentAfter)
+		if err != nil {
+			return nil, err
+		}
+		ev.Rows = append(ev.Rows, row)
+		pos += n
+	}
+	return ev, nil
+}
+
+func isBitSet(bitmap []byte, i int) bool {
+	return bitmap[i/8]&(1<<(i%8)) != 0
+}
+
+// decodeRow decodes a row image with the columns in the bitmap present and
+// returns the number of bytes read.
+func (t *TableMapEvent) decodeRow(b []byte, present []byte) ([]any, int, error) {
+	count := 0
+	for i := range t.ColumnTypes {
+		if isBitSet(present, i) {
+			count++
+		}
+	}
+	nullBitmapLen := (count + 7) / 8
+	if len(b) < nullBitmapLen {
+		return nil, 0, errShortEvent
+	}
+	nulls := b[:nullBitmapLen]
+	pos := nullBitmapLen
+
+	row := make([]any, len(t.ColumnTypes))
+	j := 0 // index of the present column
+	for i, typ := range t.ColumnTypes {
+		if !isBitSet(present, i) {
+			continue
+		}
+		isNull := isBitSet(nulls, j)
+		j++
+		if isNull {
+			continue
+		}
+		unsigned := i < len(t.Unsigned) && t.Unsigned[i]
+		v, n, err := decodeValue(b[pos:], typ, t.meta[i], unsigned)
+		if err != nil {
+			return nil, 0, fmt.Errorf("binlog: column %d of %s.%s: %w", i, t.Schema, t.Table, err)
+		}
+		row[i] = v
+		pos += n
+	}
+	return row, pos, nil
+}
+
+// readUint reads an unsigned little-endian integer of n bytes.
+func readUint(b []byte, n int) uint64 {
+	var v uint64
+	for i := n - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	return v
+}
+
+// readUintBE reads an unsigned big-endian integer of n bytes.
+func readUintBE(b []byte, n int) uint64 {
+	var v uint64
+	for i := 0; i < n; i++ {
+		v = v<<8 | uint64(b[i])
+	}
+	return v
+}
+
+// decodeValue decodes a non-NULL value of a column of type typ with the
+// metadata meta and returns the number of bytes read.
+func decodeValue(b []byte, typ byte, meta uint16, unsigned bool) (any, int, error) {
+	// length of the fixed size types
+	n := 0
+	switch typ {
+	case typeTiny, typeYear:
+		n = 1
+	case typeShort:
+		n = 2
+	case typeInt24, typeDate, typeTime:
+		n = 3
+	case typeLong, typeFloat, typeTimestamp:
+		n = 4
+	case typeLongLong, typeDouble, typeDateTime:
+		n = 8
+	case typeTimestamp2:
+		n = 4 + (int(meta)+1)/2
+	case typeDateTime2:
+		n = 5 + (int(meta)+1)/2
+	case typeTime2:
+		n = 3 + (int(meta)+1)/2
+	case typeBit:
+		n = int(meta>>8) + (int(meta&0xff)+7)/8
+	case typeNewDecimal:
+		n = decimalSize(int(meta&0xff), int(meta>>8))
+	case typeString, typeEnum, typeSet:
+		realType, length := byte(meta), int(meta>>8)
+		if realType&0x30 != 0x30 {
+			// the length of CHAR columns longer than 255 bytes is in the
+			// upper bits of the type
+			length |= int(realType&0x30^0x30) << 4
+			realType |= 0x30
+		}
+		switch realType {
+		case typeEnum, typeSet:
+			typ, n = realType, length
+		default:
+			return readPrefixed(b, 1+boolInt(length > 255))
+		}
+	case typeVarChar, typeVarString:
+		return readPrefixed(b, 1+boolInt(meta > 255))
+	case typeBlob, typeGeometry, typeJSON, typeVector, typeTinyBlob, typeMediumBlob, typeLongBlob:
+		return readPrefixed(b, int(meta))
+	default:
+		return nil, 0, fmt.Errorf("unsupported column type %d", typ)
+	}
+	if len(b) < n {
+		return nil, 0, errShortEvent
+	}
+	b = b[:n]
+
+	switch typ {
+	case typeTiny, typeShort, typeInt24, typeLong, typeLongLong:
+		u := readUint(b, n)
+		if unsigned {
+			return u, n, nil
+		}
+		// sign extension
+		shift := 64 - 8*n
+		return int64(u<<shift) >> shift, n, nil
+	case typeYear:
+		if b[0] == 0 {
+			return int64(0), n, nil
+		}
+		return int64(b[0]) + 1900, n, nil
+	case typeFloat:
+		return math.Float32frombits(binary.LittleEndian.Uint32(b)), n, nil
+	case typeDouble:
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), n, nil
+	case typeNewDecimal:
+		s, err := decodeDecimal(b, int(meta&0xff), int(meta>>8))
+		if err != nil {
+			return nil, 0, err
+		}
+		d, err := mysql.NewDecimal(s)
+		return d, n, err
+	case typeBit, typeSet:
+		if typ == typeBit {
+			return readUintBE(b, n), n, nil
+		}
+		return readUint(b, n), n, nil
+	case typeEnum:
+		return int64(readUint(b, n)), n, nil
+	case typeDate:
+		v := readUint(b, 3)
+		return makeDate(int(v>>9), int(v>>5&15), int(v&31), 0, 0, 0, 0), n, nil
+	case typeDateTime:
+		v := binary.LittleEndian.Uint64(b)
+		d, t := v/1000000, v%1000000
+		return makeDate(int(d/10000), int(d/100%100), int(d%100), int(t/10000), int(t/100%100), int(t%100), 0), n, nil
+	case typeTime:
+		v := int64(readUint(b, 3)<<40) >> 40
+		sign := time.Duration(1)
+		if v < 0 {
+			sign, v = -1, -v
+		}
+		return sign * (time.Duration(v/10000)*time.Hour + time.Duration(v/100%100)*time.Minute + time.Duration(v%100)*time.Second), n, nil
+	case typeTimestamp:
+		return time.Unix(int64(binary.LittleEndian.Uint32(b)), 0).UTC(), n, nil
+	case typeTimestamp2:
+		sec := int64(binary.BigEndian.Uint32(b))
+		usec := readFraction(b[4:], int(meta))
+		return time.Unix(sec, usec*1000).UTC(), n, nil
+	case typeDateTime2:
+		v := int64(readUintBE(b, 5)) - 0x8000000000
+		ymd, hms := v>>17, v%(1<<17)
+		ym := ymd >> 5
+		usec := readFraction(b[5:], int(meta))
+		return makeDate(int(ym/13), int(ym%13), int(ymd%(1<<5)), int(hms>>12), int(hms>>6%(1<<6)), int(hms%(1<<6)), usec), n, nil
+	case typeTime2:
+		return decodeTime2(b, int(meta)), n, nil
+	}
+	return nil, 0, fmt.Errorf("unsupported column type %d", typ)
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// readPrefixed reads a byte string with a little-endian length prefix of
+// lenSize bytes.
+func readPrefixed(b []byte, lenSize int) (any, int, error) {
+	if lenSize < 1 || lenSize > 4 || len(b) < lenSize {
+		return nil, 0, errShortEvent
+	}
+	n := readUint(b, lenSize)
+	if uint64(len(b)-lenSize) < n {
+		return nil, 0, errShortEvent
+	}
+	end := lenSize + int(n)
+	return append([]byte(nil), b[lenSize:end]...), end, nil
+}
+
+// readFraction reads the fractional seconds of the temporal types in
+// microseconds, stored big-endian in (fsp+1)/2 bytes.
+func readFraction(b []byte, fsp int) int64 {
+	switch (fsp + 1) / 2 {
+	case 1:
+		return int64(b[0]) * 10000
+	case 2:
+		return int64(binary.BigEndian.Uint16(b)) * 100
+	case 3:
+		return int64(readUintBE(b, 3))
+	}
+	return 0
+}
+
+// makeDate returns the time.Time of a DATE or DATETIME value, or the zero
+// time.Time for zero dates like 0000-00-00.
+func makeDate(year, month, day, hour, min, sec int, usec int64) time.Time {
+	if year == 0 || month == 0 || day == 0 {
+		return time.Time{}
+	}
+	return time.Date(year, time.Month(month), day, hour, min, sec, int(usec)*1000, time.UTC)
+}
+
+// decodeTime2 decodes a TIME value with fsp fractional digits. The value is
+// stored as a big-endian integer with an offset, including the fraction.
+func decodeTime2(b []byte, fsp int) time.Duration {
+	var packed int64 // hms << 24 | microseconds
+	switch (fsp + 1) / 2 {
+	case 0:
+		packed = (int64(readUintBE(b, 3)) - 0x800000) << 24
+	case 1, 2:
+		hms := int64(readUintBE(b, 3)) - 0x800000
+		size := (fsp + 1) / 2
+		frac := int64(readUintBE(b[3:], size))
+		if hms < 0 && frac > 0 {
+			hms++
+			frac -= 1 << (8 * size)
+		}
+		if size == 1 {
+			frac *= 10000
+		} else {
+			frac *= 100
+		}
+		packed = hms<<24 + frac
+	case 3:
+		packed = int64(readUintBE(b, 6)) - 0x800000000000
+	}
+	sign := time.Duration(1)
+	if packed < 0 {
+		sign, packed = -1, -packed
+	}
+	hms, usec := packed>>24, packed%(1<<24)
+	d := time.Duration(hms>>12%(1<<10))*time.Hour +
+		time.Duration(hms>>6%(1<<6))*time.Minute +
+		time.Duration(hms%(1<<6))*time.Second +
+		time.Duration(usec)*time.Microsecond
+	return sign * d
+}
+
+// digits per 4 bytes in the binary DECIMAL format, and bytes per number of
+// leftover digits
+const digitsPerInt = 9
+
+var digitsToBytes = [digitsPerInt + 1]int{0, 1, 1, 2, 2, 3, 3, 4, 4, 4}
+
+// decimalSize returns the size of a binary DECIMAL(precision, scale).
+func decimalSize(precision, scale int) int {
+	intg := precision - scale
+	return intg/digitsPerInt*4 + digitsToBytes[intg%digitsPerInt] +
+		scale/digitsPerInt*4 + digitsToBytes[scale%digitsPerInt]
+}
+
+// decodeDecimal decodes a binary DECIMAL(precision, scale): groups of nine
+// digits in 4 bytes big-endian, with fewer bytes for the leftover digits
+// before the integer groups and after the fraction groups. The sign is the
+// inverted highest bit, and negative numbers have all bits inverted.
+func decodeDecimal(b []byte, precision, scale int) (string, error) {
+	if precision < scale || scale > 30 || precision > 65 {
+		return "", fmt.Errorf("invalid DECIMAL(%d,%d)", precision, scale)
+	}
+	buf := append([]byte(nil), b[:decimalSize(precision, scale)]...)
+	negative := buf[0]&0x80 == 0
+	buf[0] ^= 0x80
+	if negative {
+		for i := range buf {
+			buf[i] = ^buf[i]
+		}
+	}
+
+	intg := precision - scale
+	var s strings.Builder
+	if negative {
+		s.WriteByte('-')
+	}
+	pos := 0
+	group := func(digits int) uint64 {
+		n := digitsToBytes[digits]
+		if digits == digitsPerInt {
+			n = 4
+		}
+		v := readUintBE(buf[pos:], n)
+		pos += n
+		return v
+	}
+
+	// integer part, without leading zeros
+	var intPart strings.Builder
+	if lead := intg % digitsPerInt; lead > 0 {
+		if v := group(lead); v > 0 {
+			intPart.WriteString(strconv.FormatUint(v, 10))
+		}
+	}
+	for i := 0; i < intg/digitsPerInt; i++ {
+		v := group(digitsPerInt)
+		if intPart.Len() == 0 {
+			if v > 0 {
+				intPart.WriteString(strconv.FormatUint(v, 10))
+			}
+		} else {
+			fmt.Fprintf(&intPart, "%09d", v)
+		}
+	}
+	if intPart.Len() == 0 {
+		intPart.WriteByte('0')
+	}
+	s.WriteString(intPart.String())
+
+	if scale > 0 {
+		s.WriteByte('.')
+		for i := 0; i < scale/digitsPerInt; i++ {
+			fmt.Fprintf(&s, "%09d", group(digitsPerInt))
+		}
+		if rest := scale % digitsPerInt; rest > 0 {
+			fmt.Fprintf(&s, "%0*d", rest, group(rest))
+		}
+	}
+	return s.String(), nil
+}
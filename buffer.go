@@ -15,11 +15,21 @@ import (
 const defaultBufSize = 4096
 const maxCachedBufSize = 256 * 1024
 
+// readAheadPackets is the number of packets of the average recent size which
+// the read buffer should hold, so that a single read usually returns many rows.
+const readAheadPackets = 16
+
 // readerFunc is a function that compatible with io.Reader.
 // We use this function type instead of io.Reader because we want to
 // just pass mc.readWithTimeout.
+// Like io.Reader, it may read fewer bytes than requested; fill calls it until
+// enough bytes are buffered.
 type readerFunc func([]byte) (int, error)
 
+// maxConsecutiveEmptyReads is the number of reads returning neither data nor
+// an error after which fill fails with io.ErrNoProgress, as bufio.Reader does.
+const maxConsecutiveEmptyReads = 100
+
 // A buffer which is used for both reading and writing.
 // This is possible since communication on each connection is synchronous.
 // In other words, we can't write and read simultaneously on the same connection.
@@ -27,16 +37,34 @@ type readerFunc func([]byte) (int, error)
 // Also highly optimized for this particular use case.
 type buffer struct {
 	buf       []byte // read buffer.
-	cachedBuf []byte // buffer that will be reused. len(cachedBuf) <= maxCachedBufSize.
+	cachedBuf []byte // buffer that will be reused. len(cachedBuf) <= maxSize.
+	maxSize   int    // max size of cachedBuf, see Config.MaxBufferSize.
+	avgPkt    int    // moving average of the sizes of recent packets.
 }
 
 // newBuffer allocates and returns a new buffer.
 func newBuffer() buffer {
 	return buffer{
 		cachedBuf: make([]byte, defaultBufSize),
+		maxSize:   maxCachedBufSize,
 	}
 }
 
+// observe adds the size of a packet read from the server to the moving
+// average of recent packet sizes, which determines the size of the read
+// buffer. Written packets are not observed, as they are not read into it.
+func (b *buffer) observe(pktLen int) {
+	b.avgPkt += (pktLen - b.avgPkt) / 8
+}
+
+// readAheadSize returns the size of the read buffer for the recent packet
+// sizes: large enough for readAheadPackets packets, rounded up to a multiple
+// of defaultBufSize and bounded by defaultBufSize and maxSize.
+func (b *buffer) readAheadSize() int {
+	size := (b.avgPkt*readAheadPackets/defaultBufSize + 1) * defaultBufSize
+	return max(defaultBufSize, min(size, b.maxSize))
+}
+
 // busy returns true if the read buffer is not empty.
 func (b *buffer) busy() bool {
 	return len(b.buf) > 0
@@ -47,14 +75,21 @@ func (b *buffer) fill(need int, r readerFunc) error {
 	// we'll move the contents of the current buffer to dest before filling it.
 	dest := b.cachedBuf
 
-	// grow buffer if necessary to fit the whole packet.
-	if need > len(dest) {
+	// the buffer must fit the whole packet.
+	size := b.readAheadSize()
+	if need > size {
 		// Round up to the next multiple of the default size
-		dest = make([]byte, ((need/defaultBufSize)+1)*defaultBufSize)
+		size = ((need / defaultBufSize) + 1) * defaultBufSize
+	}
+
+	// grow the buffer to read ahead more of large rows, and shrink it after
+	// the rows became much smaller to release the memory.
+	if size > len(dest) || 4*size <= len(dest) {
+		dest = make([]byte, size)
 
 		// if the allocated buffer is not too large, move it to backing storage
 		// to prevent extra allocations on applications that perform large reads
-		if len(dest) <= maxCachedBufSize {
+		if len(dest) <= b.maxSize {
 			b.cachedBuf = dest
 		}
 	}
@@ -63,12 +98,19 @@ func (b *buffer) fill(need int, r readerFunc) error {
 	n := len(b.buf)
 	copy(dest[:n], b.buf)
 
-	for {
+	for empty := 0; ; {
 		nn, err := r(dest[n:])
 		n += nn
 
 		if err == nil && n < need {
-			continue
+			if nn > 0 {
+				empty = 0
+				continue
+			}
+			if empty++; empty < maxConsecutiveEmptyReads {
+				continue
+			}
+			err = io.ErrNoProgress
 		}
 
 		b.buf = dest[:n]
@@ -113,7 +155,7 @@ func (b *buffer) takeBuffer(length int) ([]byte, error) {
 		return b.cachedBuf[:length], nil
 	}
 
-	if length < maxCachedBufSize {
+	if length < b.maxSize {
 		b.cachedBuf = make([]byte, length)
 		return b.cachedBuf, nil
 	}
@@ -145,7 +187,7 @@ func (b *buffer) takeCompleteBuffer() ([]byte, error) {
 
 // store stores buf, an updated buffer, if its suitable to do so.
 func (b *buffer) store(buf []byte) {
-	if cap(buf) <= maxCachedBufSize && cap(buf) > cap(b.cachedBuf) {
+	if cap(buf) <= b.maxSize && cap(buf) > cap(b.cachedBuf) {
 		b.cachedBuf = buf[:cap(buf)]
 	}
 }
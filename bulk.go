@@ -0,0 +1,304 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// ErrBulkUnsupported is returned by BulkExec if the server does not support
+// COM_STMT_BULK_EXECUTE, which requires MariaDB 10.2.7 or later.
+var ErrBulkUnsupported = errors.New("mysql: the server does not support bulk execution")
+
+// BulkExec executes the prepared statement stmt with all rows of parameters
+// using MariaDB's COM_STMT_BULK_EXECUTE, which sends the rows in a single
+// command instead of one command per row. This is the fastest way to insert
+// many rows into MariaDB. The rows are split into several commands if they
+// exceed max_allowed_packet. ErrBulkUnsupported is returned for other
+// servers; BatchExec works with all servers.
+//
+// stmt must be a statement of this driver, which is accessible with
+// sql.Conn.Raw, see BatchExec. The values of a parameter must have the same
+// type in all rows, except for NULL.
+//
+// If the server supports it (MariaDB 11.5.1+), the returned Result contains
+// the generated id and the affected rows of each row in AllLastInsertIds and
+// AllRowsAffected. Otherwise it contains the first generated id and the total
+// affected rows of each command. If a command fails, the rows sent before
+// may have been inserted; use a transaction to insert the rows atomically.
+func BulkExec(stmt driver.Stmt, rows [][]driver.Value) (Result, error) {
+	s, ok := stmt.(*mysqlStmt)
+	if !ok {
+		return nil, fmt.Errorf("mysql: unexpected driver statement %T", stmt)
+	}
+	mc := s.mc
+	if mc.closed.Load() {
+		return nil, driver.ErrBadConn
+	}
+	if mc.mariadbFlags&mariadbClientStmtBulkOperations == 0 {
+		return nil, ErrBulkUnsupported
+	}
+	if s.paramCount == 0 {
+		return nil, errors.New("mysql: bulk execution requires a statement with parameters")
+	}
+
+	batch, err := s.convertBatch(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(batch) == 0 {
+		return &mysqlResult{affectedRows: []int64{0}, insertIds: []int64{0}}, nil
+	}
+	types, err := mc.bulkTypes(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &mysqlResult{}
+	for start := 0; start < len(batch); {
+		sent, err := s.writeBulkExecutePacket(types, batch[start:])
+		if err != nil {
+			return nil, mc.markBadConn(err)
+		}
+		if err := s.readBulkResult(res); err != nil {
+			return nil, err
+		}
+		start += sent
+	}
+	return res, nil
+}
+
+// bulkTypes returns the type and the flags of each parameter, which are taken
+// from the first non-NULL value of the parameter.
+func (mc *mysqlConn) bulkTypes(batch [][]driver.Value) ([]byte, error) {
+	types := make([]byte, 2*len(batch[0]))
+	for i := 0; i < len(types); i += 2 {
+		types[i] = byte(fieldTypeNULL)
+	}
+	for r, row := range batch {
+		for i, arg := range row {
+			typ, flags, ok := bulkType(mc.zeroDateArg(arg))
+			if !ok {
+				return nil, &BatchError{Index: r, Err: fmt.Errorf("cannot convert type: %T", arg)}
+			}
+			switch {
+			case typ == fieldTypeNULL:
+			case types[2*i] == byte(fieldTypeNULL):
+				types[2*i], types[2*i+1] = byte(typ), flags
+			case types[2*i] != byte(typ) || types[2*i+1] != flags:
+				return nil, &BatchError{Index: r, Err: fmt.Errorf(
+					"parameter %d has type %T, but another type in a previous row", i, arg)}
+			}
+		}
+	}
+	return types, nil
+}
+
+// bulkType returns the type and the flags with which arg is sent.
+func bulkType(arg driver.Value) (fieldType, byte, bool) {
+	switch v := arg.(type) {
+	case nil:
+		return fieldTypeNULL, 0, true
+	case int64:
+		return fieldTypeLongLong, 0, true
+	case uint64:
+		return fieldTypeLongLong, 0x80, true // type is unsigned
+	case float64:
+		return fieldTypeDouble, 0, true
+	case float32:
+		return fieldTypeFloat, 0, true
+	case Decimal:
+		return fieldTypeNewDecimal, 0, true
+	case bool:
+		return fieldTypeTiny, 0, true
+	case []byte:
+		if v == nil {
+			return fieldTypeNULL, 0, true
+		}
+		return fieldTypeString, 0, true
+	case string, time.Time, json.RawMessage:
+		return fieldTypeString, 0, true
+	case time.Duration:
+		return fieldTypeTime, 0, true
+	}
+	return 0, 0, false
+}
+
+// appendBulkValue appends the indicator and the value of arg to buf.
+func (mc *mysqlConn) appendBulkValue(buf []byte, arg driver.Value) ([]byte, error) {
+	arg = mc.zeroDateArg(arg)
+	if v, ok := arg.(json.RawMessage); ok {
+		arg = []byte(v)
+	}
+	switch v := arg.(type) {
+	case nil:
+		return append(buf, bulkIndicatorNull), nil
+	case int64:
+		buf = append(buf, bulkIndicatorNone)
+		return binary.LittleEndian.AppendUint64(buf, uint64(v)), nil
+	case uint64:
+		buf = append(buf, bulkIndicatorNone)
+		return binary.LittleEndian.AppendUint64(buf, v), nil
+	case float64:
+		buf = append(buf, bulkIndicatorNone)
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v)), nil
+	case float32:
+		buf = append(buf, bulkIndicatorNone)
+		return binary.LittleEndian.AppendUint32(buf, math.Float32bits(v)), nil
+	case Decimal:
+		buf = append(buf, bulkIndicatorNone)
+		buf = appendLengthEncodedInteger(buf, uint64(len(v.String())))
+		return append(buf, v.String()...), nil
+	case bool:
+		if v {
+			return append(buf, bulkIndicatorNone, 0x01), nil
+		}
+		return append(buf, bulkIndicatorNone, 0x00), nil
+	case []byte:
+		if v == nil {
+			return append(buf, bulkIndicatorNull), nil
+		}
+		buf = append(buf, bulkIndicatorNone)
+		buf = appendLengthEncodedInteger(buf, uint64(len(v)))
+		return append(buf, v...), nil
+	case string:
+		buf = append(buf, bulkIndicatorNone)
+		buf = appendLengthEncodedInteger(buf, uint64(len(v)))
+		return append(buf, v...), nil
+	case time.Time:
+		var a [64]byte
+		b := a[:0]
+		if v.IsZero() {
+			b = append(b, "0000-00-00"...)
+		} else {
+			var err error
+			if b, err = appendDateTime(b, v.In(mc.cfg.Loc), mc.cfg.timeTruncate); err != nil {
+				return nil, err
+			}
+		}
+		buf = append(buf, bulkIndicatorNone)
+		buf = appendLengthEncodedInteger(buf, uint64(len(b)))
+		return append(buf, b...), nil
+	case time.Duration:
+		buf = append(buf, bulkIndicatorNone)
+		return appendBinaryTimeDuration(buf, v), nil
+	}
+	return nil, fmt.Errorf("cannot convert type: %T", arg)
+}
+
+// writeBulkExecutePacket sends a COM_STMT_BULK_EXECUTE command with as many
+// rows of batch as fit into max_allowed_packet, at least one. It returns the
+// number of rows sent.
+// https://mariadb.com/kb/en/com_stmt_bulk_execute/
+func (stmt *mysqlStmt) writeBulkExecutePacket(types []byte, batch [][]driver.Value) (int, error) {
+	mc := stmt.mc
+	if err := mc.flushCoalesced(); err != nil {
+		return 0, err
+	}
+
+	flags := bulkFlagSendTypes
+	if mc.mariadbFlags&mariadbClientBulkUnitResults != 0 {
+		flags |= bulkFlagSendUnitResults
+	}
+	data := make([]byte, 4, 4+1+4+2+len(types))
+	data = append(data, comStmtBulkExecute)
+	data = binary.LittleEndian.AppendUint32(data, stmt.id)
+	data = binary.LittleEndian.AppendUint16(data, flags)
+	data = append(data, types...)
+
+	var row []byte
+	sent := 0
+	for _, args := range batch {
+		row = row[:0]
+		for _, arg := range args {
+			var err error
+			if row, err = mc.appendBulkValue(row, arg); err != nil {
+				return 0, err
+			}
+		}
+		if sent > 0 && len(data)-4+len(row) > mc.maxAllowedPacket {
+			break
+		}
+		if err := mc.audit(stmt.sql, args); err != nil {
+			return 0, err
+		}
+		data = append(data, row...)
+		sent++
+	}
+
+	mc.resetSequence()
+	err := mc.writePacket(data)
+	mc.syncSequence()
+	return sent, err
+}
+
+// readBulkResult reads the result of a COM_STMT_BULK_EXECUTE command into res.
+// The result is an OK packet or, with bulkFlagSendUnitResults, a result set
+// with the generated id and the affected rows of each row.
+func (stmt *mysqlStmt) readBulkResult(res *mysqlResult) error {
+	mc := stmt.mc
+	handleOk := mc.clearResult()
+	resLen, err := handleOk.readResultSetHeaderPacket()
+	if err != nil {
+		return err
+	}
+	if resLen == 0 {
+		res.affectedRows = append(res.affectedRows, mc.result.affectedRows...)
+		res.insertIds = append(res.insertIds, mc.result.insertIds...)
+	} else {
+		rows := new(binaryRows)
+		rows.mc = mc
+		if rows.rs.columns, err = mc.readColumns(resLen); err != nil {
+			return err
+		}
+		dest := make([]driver.Value, resLen)
+		for {
+			err := rows.readRow(dest)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if resLen >= 2 {
+				res.insertIds = append(res.insertIds, bulkInt(dest[0]))
+				res.affectedRows = append(res.affectedRows, bulkInt(dest[1]))
+			}
+		}
+	}
+	if err := handleOk.discardResults(); err != nil {
+		return err
+	}
+	if err := mc.checkWarnings(); err != nil {
+		return err
+	}
+	res.warnings = append(res.warnings, mc.warnings...)
+	return nil
+}
+
+func bulkInt(v driver.Value) int64 {
+	switch v := v.(type) {
+	case int64:
+		return v
+	case uint64:
+		return int64(v)
+	case []byte:
+		n, _ := strconv.ParseInt(string(v), 10, 64)
+		return n
+	}
+	return 0
+}
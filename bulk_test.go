@@ -0,0 +1,127 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBulkExec(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.mariadbFlags = mariadbClientStmtBulkOperations
+	stmt := &mysqlStmt{mc: mc, id: 3, paramCount: 2}
+	conn.queuedReplies = [][]byte{mockPacket(1, []byte{iOK, 2, 10, 2, 0, 0, 0})}
+
+	res, err := BulkExec(stmt, [][]driver.Value{{1, "a"}, {nil, "bc"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte{comStmtBulkExecute, 3, 0, 0, 0, 128, 0,
+		byte(fieldTypeLongLong), 0, byte(fieldTypeString), 0,
+		bulkIndicatorNone, 1, 0, 0, 0, 0, 0, 0, 0, bulkIndicatorNone, 1, 'a',
+		bulkIndicatorNull, bulkIndicatorNone, 2, 'b', 'c',
+	}
+	if want := mockPacket(0, payload); !bytes.Equal(conn.written, want) {
+		t.Errorf("unexpected packet\nexpected %v\ngot      %v", want, conn.written)
+	}
+	if n, _ := res.RowsAffected(); n != 2 {
+		t.Errorf("expected 2 affected rows, got %d", n)
+	}
+	if id, _ := res.LastInsertId(); id != 10 {
+		t.Errorf("expected insert id 10, got %d", id)
+	}
+}
+
+func TestBulkExecUnitResults(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.mariadbFlags = mariadbClientStmtBulkOperations | mariadbClientBulkUnitResults
+	stmt := &mysqlStmt{mc: mc, id: 1, paramCount: 1}
+
+	row := func(seq byte, id, affected uint64) []byte {
+		data := []byte{iOK, 0}
+		data = binary.LittleEndian.AppendUint64(data, id)
+		return mockPacket(seq, binary.LittleEndian.AppendUint64(data, affected))
+	}
+	resp := mockPacket(1, []byte{2})
+	resp = append(resp, mockColumn(2, "Id", fieldTypeLongLong)...)
+	resp = append(resp, mockColumn(3, "Affected_rows", fieldTypeLongLong)...)
+	resp = append(resp, mockPacket(4, []byte{iEOF, 0, 0, 2, 0})...)
+	resp = append(resp, row(5, 7, 1)...)
+	resp = append(resp, row(6, 8, 1)...)
+	resp = append(resp, mockPacket(7, []byte{iEOF, 0, 0, 2, 0})...)
+	conn.queuedReplies = [][]byte{resp}
+
+	res, err := BulkExec(stmt, [][]driver.Value{{"x"}, {"y"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flags := binary.LittleEndian.Uint16(conn.written[9:]); flags != bulkFlagSendTypes|bulkFlagSendUnitResults {
+		t.Errorf("unexpected flags %d", flags)
+	}
+	if ids := res.AllLastInsertIds(); !reflect.DeepEqual(ids, []int64{7, 8}) {
+		t.Errorf("unexpected insert ids %v", ids)
+	}
+	if rows := res.AllRowsAffected(); !reflect.DeepEqual(rows, []int64{1, 1}) {
+		t.Errorf("unexpected affected rows %v", rows)
+	}
+}
+
+func TestBulkExecSplit(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.mariadbFlags = mariadbClientStmtBulkOperations
+	mc.maxAllowedPacket = 30
+	stmt := &mysqlStmt{mc: mc, id: 1, paramCount: 1}
+	conn.queuedReplies = [][]byte{okPacket, okPacket}
+
+	// 7 bytes header, 2 bytes types and 9 bytes per row: two rows fit
+	res, err := BulkExec(stmt, [][]driver.Value{{1}, {2}, {3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conn.writes != 2 || len(res.AllRowsAffected()) != 2 {
+		t.Errorf("expected 2 commands, got %d writes and %d results", conn.writes, len(res.AllRowsAffected()))
+	}
+}
+
+func TestBulkExecErrors(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	stmt := &mysqlStmt{mc: mc, id: 1, paramCount: 1}
+	if _, err := BulkExec(stmt, [][]driver.Value{{1}}); err != ErrBulkUnsupported {
+		t.Errorf("expected ErrBulkUnsupported, got %v", err)
+	}
+
+	mc.mariadbFlags = mariadbClientStmtBulkOperations
+	_, err := BulkExec(stmt, [][]driver.Value{{1}, {nil}, {"a"}})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Index != 2 {
+		t.Errorf("expected BatchError for row 2, got %v", err)
+	}
+	if conn.writes != 0 {
+		t.Errorf("rows sent despite mixed types: %d writes", conn.writes)
+	}
+}
+
+func TestMariaDBCapabilities(t *testing.T) {
+	conn, mc := newRWMockConn(1)
+	mc.mariadbFlags = mariadbClientStmtBulkOperations | 1 // with MARIADB_CLIENT_PROGRESS
+	if err := mc.writeHandshakeResponsePacket(nil, "mysql_native_password"); err != nil {
+		t.Fatal(err)
+	}
+	if flags := clientFlag(binary.LittleEndian.Uint32(conn.written[4:])); flags&clientLongPassword != 0 {
+		t.Error("CLIENT_MYSQL is set")
+	}
+	if ext := mariadbFlag(binary.LittleEndian.Uint32(conn.written[32:])); ext != mariadbClientStmtBulkOperations {
+		t.Errorf("unexpected extended capabilities %b", ext)
+	}
+}
@@ -0,0 +1,167 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+)
+
+// CancelPhase is the phase of an operation which was interrupted because its
+// context was done.
+type CancelPhase int
+
+const (
+	// CancelBeforeSend means the context was done before anything was sent
+	// to the server. The connection is still usable.
+	CancelBeforeSend CancelPhase = iota
+	// CancelConnect means the connection was being established.
+	CancelConnect
+	// CancelSend means a command or the contents of a LOAD DATA LOCAL
+	// INFILE request were being sent. An upload is not ended, so that the
+	// server does not load the contents sent so far.
+	CancelSend
+	// CancelReceive means the response or the rows of a result set were
+	// being read.
+	CancelReceive
+)
+
+func (p CancelPhase) String() string {
+	switch p {
+	case CancelBeforeSend:
+		return "before send"
+	case CancelConnect:
+		return "connect"
+	case CancelSend:
+		return "send"
+	case CancelReceive:
+		return "receive"
+	}
+	return "unknown"
+}
+
+// CancelInfo is returned when an operation fails because its context was
+// canceled or its deadline was exceeded. It wraps the error of the context,
+// so errors.Is(err, context.Canceled) and
+// errors.Is(err, context.DeadlineExceeded) work in every phase:
+//
+//	var ci *mysql.CancelInfo
+//	if errors.As(err, &ci) && ci.Phase != mysql.CancelBeforeSend {
+//		// the statement may have been executed by the server
+//	}
+//
+// Except for CancelBeforeSend the connection is closed.
+type CancelInfo struct {
+	Phase CancelPhase
+	Err   error // context.Canceled or context.DeadlineExceeded
+}
+
+func (ci *CancelInfo) Error() string {
+	return "mysql: interrupted (" + ci.Phase.String() + "): " + ci.Err.Error()
+}
+
+func (ci *CancelInfo) Unwrap() error {
+	return ci.Err
+}
+
+// canceledError returns the cancellation of the current operation, if any.
+func (mc *mysqlConn) canceledError(phase CancelPhase) error {
+	if err := mc.canceled.Value(); err != nil {
+		return &CancelInfo{Phase: phase, Err: err}
+	}
+	return nil
+}
+
+// connectCanceled reports a cancellation while connecting as CancelConnect.
+func connectCanceled(err error) error {
+	var ci *CancelInfo
+	if errors.As(err, &ci) {
+		ci.Phase = CancelConnect
+	}
+	return err
+}
+
+var (
+	errCancelTokenStale  = errors.New("mysql: connection of the CancelToken was closed or reconnected")
+	errCancelTokenServer = errors.New("mysql: CancelToken connected to another server")
+)
+
+// CancelToken cancels the statements of a connection from another
+// connection, independent of their context, see NewCancelToken.
+type CancelToken struct {
+	mc         *mysqlConn
+	connID     uint32
+	serverUUID string // @@server_uuid, empty if the server has none, e.g. MariaDB
+}
+
+// NewCancelToken returns a CancelToken for conn, e.g. to cancel a long
+// running migration of a CLI from an admin endpoint. It must be created
+// before the statement is started, since database/sql does not give access
+// to a connection while it is in use:
+//
+//	token, err := mysql.NewCancelToken(ctx, conn)
+//	...
+//	go func() {
+//		<-stop
+//		token.CancelQuery(context.Background())
+//	}()
+//	_, err = conn.ExecContext(context.Background(), "ALTER TABLE ...")
+func NewCancelToken(ctx context.Context, conn *sql.Conn) (*CancelToken, error) {
+	var token *CancelToken
+	err := rawConn(ctx, conn, func(mc *mysqlConn) error {
+		if mc.connector == nil {
+			return errors.New("mysql: connection was not opened by a connector")
+		}
+		uuid, err := mc.getSystemVar("server_uuid")
+		if _, ok := err.(*MySQLError); ok {
+			uuid = nil
+		} else if err != nil {
+			return err
+		}
+		token = &CancelToken{mc: mc, connID: mc.connID.Load(), serverUUID: string(uuid)}
+		return nil
+	})
+	return token, err
+}
+
+// CancelQuery kills the statement running on the connection of the token
+// with KILL QUERY, which is sent on a new connection of the same connector.
+// The statement fails with ER_QUERY_INTERRUPTED (1317) and the connection
+// remains usable. Nothing happens if no statement is running.
+//
+// CancelQuery returns an error instead if the connection of the token was
+// closed or reconnected, since the server may have given its id to another
+// connection, or if the new connection reaches another server, e.g. behind
+// a load balancer.
+func (t *CancelToken) CancelQuery(ctx context.Context) error {
+	if t.mc.closed.Load() || t.mc.connID.Load() != t.connID {
+		return errCancelTokenStale
+	}
+	mc, err := t.mc.connector.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer mc.Close()
+	if err := mc.watchCancel(ctx); err != nil {
+		return err
+	}
+	defer mc.finish()
+	if t.serverUUID != "" {
+		uuid, err := mc.getSystemVar("server_uuid")
+		if err != nil {
+			return err
+		}
+		if string(uuid) != t.serverUUID {
+			return errCancelTokenServer
+		}
+	}
+	return mc.exec("KILL QUERY " + strconv.FormatUint(uint64(t.connID), 10))
+}
@@ -0,0 +1,70 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCancelInfo(t *testing.T) {
+	check := func(err error, phase CancelPhase) {
+		t.Helper()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected context.DeadlineExceeded, got %v", err)
+		}
+		var ci *CancelInfo
+		if !errors.As(err, &ci) {
+			t.Fatalf("expected *CancelInfo, got %#v", err)
+		}
+		if ci.Phase != phase {
+			t.Errorf("expected phase %v, got %v", phase, ci.Phase)
+		}
+	}
+
+	// the watcher closes the connection when the deadline is exceeded
+	conn, mc := newRWMockConn(0)
+	mc.canceled.Set(context.DeadlineExceeded)
+	conn.closed = true
+
+	check(mc.writeCommandPacketStr(comQuery, "SELECT 1"), CancelSend)
+
+	conn, mc = newRWMockConn(0)
+	mc.canceled.Set(context.DeadlineExceeded)
+	conn.closed = true
+	_, err := mc.readPacket()
+	check(err, CancelReceive)
+	check(mc.error(), CancelReceive)
+
+	check(connectCanceled(&CancelInfo{Phase: CancelReceive, Err: context.DeadlineExceeded}), CancelConnect)
+
+	want := "mysql: interrupted (receive): context deadline exceeded"
+	if s := (&CancelInfo{Phase: CancelReceive, Err: context.DeadlineExceeded}).Error(); s != want {
+		t.Errorf("expected %q, got %q", want, s)
+	}
+}
+
+func TestCancelTokenStale(t *testing.T) {
+	_, mc := newRWMockConn(0)
+	mc.connID.Store(42)
+	token := &CancelToken{mc: mc, connID: 42}
+
+	// reconnected, e.g. by a transparent failover
+	mc.connID.Store(43)
+	if err := token.CancelQuery(context.Background()); err != errCancelTokenStale {
+		t.Errorf("expected errCancelTokenStale, got %v", err)
+	}
+
+	mc.connID.Store(42)
+	mc.Close()
+	if err := token.CancelQuery(context.Background()); err != errCancelTokenStale {
+		t.Errorf("expected errCancelTokenStale, got %v", err)
+	}
+}
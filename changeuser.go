@@ -0,0 +1,114 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+)
+
+var (
+	errChangeUserInTx    = errors.New("mysql: ChangeUser in a transaction")
+	errChangeUserStmts   = errors.New("mysql: ChangeUser with open prepared statements")
+	errChangeUserAuthLen = errors.New("mysql: auth response too long for COM_CHANGE_USER")
+)
+
+// ChangeUser authenticates conn as another user with COM_CHANGE_USER and
+// makes dbname the default database, without opening a new connection, e.g.
+// after rotating credentials or to switch between the users of tenants.
+// The auth plugins are run like for a new connection.
+//
+// The server resets the session like a new connection: temporary tables,
+// user variables and prepared statements are dropped and a transaction is
+// rolled back. The driver then sets up the session as configured in the DSN
+// again. Therefore ChangeUser fails in a transaction or while statements
+// prepared on conn are open. If the authentication fails, conn is closed
+// and the error is returned.
+//
+// When conn is closed, the connection is returned to the pool of the sql.DB
+// and stays authenticated as user. Change back to the user of the DSN before
+// closing conn, or discard the connection by returning driver.ErrBadConn
+// from conn.Raw.
+func ChangeUser(ctx context.Context, conn *sql.Conn, user, passwd, dbname string) error {
+	return rawConn(ctx, conn, func(mc *mysqlConn) error { return mc.changeUser(user, passwd, dbname) })
+}
+
+func (mc *mysqlConn) changeUser(user, passwd, dbname string) error {
+	if mc.closed.Load() {
+		return driver.ErrBadConn
+	}
+	if mc.inTx || mc.status&statusInTrans != 0 || mc.xaState != xaNone {
+		return errChangeUserInTx
+	}
+	if mc.openStmts > 0 {
+		return errChangeUserStmts
+	}
+
+	prev := mc.cfg
+	mc.cfg = prev.Clone()
+	mc.cfg.User, mc.cfg.Passwd, mc.cfg.DBName = user, passwd, dbname
+	defer mc.endAuth()
+	authResp, err := mc.auth(mc.scramble, mc.authPlugin)
+	if err == nil && len(authResp) > 255 {
+		err = errChangeUserAuthLen
+	}
+	if err != nil {
+		mc.cfg = prev
+		return err
+	}
+	collationID, err := mc.handshakeCollationID()
+	if err != nil {
+		mc.cfg = prev
+		return err
+	}
+
+	// https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_com_change_user.html
+	data := make([]byte, 0, len(user)+len(authResp)+len(dbname)+len(mc.authPlugin)+len(mc.connAttrs)+16)
+	data = append(data, user...)
+	data = append(data, 0)
+	data = append(data, byte(len(authResp)))
+	data = append(data, authResp...)
+	data = append(data, dbname...)
+	data = append(data, 0)
+	data = append(data, collationID, 0)
+	data = append(data, mc.authPlugin...)
+	data = append(data, 0)
+	if mc.flags&clientConnectAttrs != 0 {
+		data = appendLengthEncodedInteger(data, uint64(len(mc.connAttrs)))
+		data = append(data, mc.connAttrs...)
+	}
+
+	mc.clearResult()
+	if err := mc.writeCommandPacketStr(comChangeUser, string(data)); err != nil {
+		mc.cfg = prev
+		return mc.markBadConn(err)
+	}
+	if err := mc.handleAuthResult(mc.scramble, mc.authPlugin); err != nil {
+		mc.cleanup()
+		return err
+	}
+
+	// All prepared statements and user variables have been deallocated
+	mc.schema = dbname
+	mc.stmtCache = nil
+	mc.stmtLRU = nil
+	mc.sessionVars = nil
+	mc.waitedGTIDs = ""
+	if err := mc.initSession(); err != nil {
+		mc.Close()
+		return err
+	}
+	if err := mc.prewarmStatements(); err != nil {
+		mc.Close()
+		return err
+	}
+	return nil
+}
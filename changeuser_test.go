@@ -0,0 +1,70 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestChangeUser(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.scramble = []byte("0123456789abcdefghij")
+	mc.authPlugin = "mysql_native_password"
+	mc.stmtCache = map[string]*mysqlStmt{}
+	mc.sessionVars = []sessionVar{{"sql_mode", "''"}}
+	mc.waitedGTIDs = "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5"
+	conn.queuedReplies = [][]byte{mockPacket(1, []byte{iOK, 0, 0, 2, 0, 0, 0})}
+
+	if err := mc.changeUser("bob", "secret", "shop"); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected []byte
+	expected = append(expected, comChangeUser)
+	expected = append(expected, "bob\x00"...)
+	expected = append(expected, 20)
+	expected = append(expected, scramblePassword(mc.scramble, "secret")...)
+	expected = append(expected, "shop\x00"...)
+	expected = append(expected, defaultCollationID, 0)
+	expected = append(expected, "mysql_native_password\x00"...)
+	if n := getUint24(conn.written[:3]); n != len(expected) || !bytes.Equal(conn.written[4:4+n], expected) {
+		t.Errorf("unexpected packet %q, expected %q", conn.written[4:], expected)
+	}
+	if mc.cfg.User != "bob" || mc.schema != "shop" || mc.stmtCache != nil || mc.sessionVars != nil || mc.waitedGTIDs != "" {
+		t.Errorf("unexpected state after ChangeUser: user %q, schema %q", mc.cfg.User, mc.schema)
+	}
+
+	// an authentication failure closes the connection
+	conn.queuedReplies = [][]byte{mockPacket(1, []byte{iERR, 0x15, 0x04, '#', '2', '8', '0', '0', '0', 'd', 'e', 'n', 'i', 'e', 'd'})}
+	var me *MySQLError
+	if err := mc.changeUser("eve", "", ""); !errors.As(err, &me) || me.Number != 1045 {
+		t.Errorf("expected error 1045, got %v", err)
+	}
+	if !mc.closed.Load() {
+		t.Error("connection not closed")
+	}
+}
+
+func TestChangeUserInTx(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.status |= statusInTrans
+	if err := mc.changeUser("bob", "", ""); err != errChangeUserInTx {
+		t.Errorf("expected errChangeUserInTx, got %v", err)
+	}
+	mc.status = 0
+	mc.openStmts = 1
+	if err := mc.changeUser("bob", "", ""); err != errChangeUserStmts {
+		t.Errorf("expected errChangeUserStmts, got %v", err)
+	}
+	if len(conn.written) != 0 {
+		t.Errorf("unexpected packet %v", conn.written)
+	}
+}
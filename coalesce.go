@@ -0,0 +1,218 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// coalesceBufferSize is the size of the buffered commands which are
+	// written immediately instead of waiting for the delay window.
+	coalesceBufferSize = 16 * 1024
+	// coalesceMaxPending is the number of statements whose results are read
+	// before more statements are coalesced, so that neither the server nor
+	// the client block on full socket buffers.
+	coalesceMaxPending = 64
+)
+
+// writeCoalescer buffers the COM_QUERY packets of consecutive statements in a
+// transaction, see Config.coalesceWrites. The buffer is written by the next
+// statement after the delay window expired, when it is full or before the
+// next other command; there is no timer, so the last statements wait for the
+// next command of the transaction however long that takes. The results are read before the next other command or
+// when a result is accessed. Everything happens on the goroutine using the
+// connection, so the buffer is never written concurrently to other commands.
+type writeCoalescer struct {
+	buf     []byte             // commands not written yet
+	since   time.Time          // when the first command of buf was buffered
+	pending []*coalescedResult // results not read yet
+}
+
+// coalescedResult is the driver.Result of a coalesced statement. It is only
+// complete after the result has been read.
+type coalescedResult struct {
+	mc   *mysqlConn
+	done bool
+	res  mysqlResult
+	err  error
+}
+
+// wait reads the results of the coalesced statements up to r.
+func (r *coalescedResult) wait() error {
+	if !r.done {
+		r.mc.flushCoalesced()
+	}
+	return r.err
+}
+
+func (r *coalescedResult) LastInsertId() (int64, error) {
+	if err := r.wait(); err != nil {
+		return 0, err
+	}
+	return r.res.LastInsertId()
+}
+
+func (r *coalescedResult) RowsAffected() (int64, error) {
+	if err := r.wait(); err != nil {
+		return 0, err
+	}
+	return r.res.RowsAffected()
+}
+
+func (r *coalescedResult) AllLastInsertIds() []int64 {
+	if r.wait() != nil {
+		return nil
+	}
+	return r.res.AllLastInsertIds()
+}
+
+func (r *coalescedResult) AllRowsAffected() []int64 {
+	if r.wait() != nil {
+		return nil
+	}
+	return r.res.AllRowsAffected()
+}
+
+// coalesces reports whether the write of query can be coalesced with the
+// following statements. Only plain DML statements in transactions are
+// coalesced, as their results are not needed to issue the next statement.
+// Transactions are detected with the status of the server, so that they are
+// also coalesced in transactions started with a statement like BEGIN.
+func (mc *mysqlConn) coalesces(query string) bool {
+	if mc.cfg.coalesceWrites <= 0 || mc.status&statusInTrans == 0 || mc.compress ||
+		mc.showsWarnings() || len(query) > coalesceBufferSize {
+		return false
+	}
+	keyword, _, _ := strings.Cut(strings.TrimLeft(query, " \t\r\n"), " ")
+	switch strings.ToUpper(keyword) {
+	case "INSERT", "UPDATE", "DELETE", "REPLACE":
+		return true
+	}
+	return false
+}
+
+// execCoalesced buffers the COM_QUERY packet of query and returns a result
+// which is read later.
+func (mc *mysqlConn) execCoalesced(query string) (*coalescedResult, error) {
+	c := mc.coalescer
+	if c == nil {
+		c = &writeCoalescer{}
+		mc.coalescer = c
+	}
+	if len(c.pending) >= coalesceMaxPending {
+		if err := mc.flushCoalesced(); err != nil {
+			return nil, err
+		}
+	}
+
+	var attrs []byte
+	if mc.sendsQueryAttrs() {
+		attrs = appendQueryAttrs(nil, mc.queryAttrs)
+	}
+	pktLen := 1 + len(attrs) + len(query)
+
+	if len(c.buf)+4+pktLen > coalesceBufferSize {
+		if err := mc.writeCoalesced(); err != nil {
+			return nil, mc.failCoalesced(err)
+		}
+	}
+	if len(c.buf) == 0 {
+		c.since = time.Now()
+	}
+	// header with sequence 0, command, query attributes and query
+	c.buf = append(c.buf, byte(pktLen), byte(pktLen>>8), byte(pktLen>>16), 0, comQuery)
+	c.buf = append(c.buf, attrs...)
+	c.buf = append(c.buf, query...)
+	if s := mc.cfg.StatsCollector; s != nil {
+		s.PacketWritten(pktLen)
+	}
+	if mc.trace != nil {
+		mc.traceSentPacket(0, c.buf[len(c.buf)-pktLen:], false)
+	}
+
+	res := &coalescedResult{mc: mc}
+	c.pending = append(c.pending, res)
+	if time.Since(c.since) >= mc.cfg.coalesceWrites {
+		if err := mc.writeCoalesced(); err != nil {
+			return nil, mc.failCoalesced(err)
+		}
+	}
+	return res, nil
+}
+
+// writeCoalesced writes the buffered commands.
+func (mc *mysqlConn) writeCoalesced() error {
+	c := mc.coalescer
+	if len(c.buf) == 0 {
+		return nil
+	}
+	if to := mc.cfg.WriteTimeout; to > 0 {
+		if err := mc.netConn.SetWriteDeadline(time.Now().Add(to)); err != nil {
+			return err
+		}
+	}
+	_, err := mc.netConn.Write(c.buf)
+	c.buf = c.buf[:0]
+	return err
+}
+
+// failCoalesced closes the connection after the buffered commands could not
+// be written and sets err as the error of all pending results.
+func (mc *mysqlConn) failCoalesced(err error) error {
+	c := mc.coalescer
+	mc.cleanup()
+	for _, r := range c.pending {
+		r.done, r.err = true, err
+	}
+	c.pending = nil
+	return err
+}
+
+// flushCoalesced writes the buffered commands and reads the results of all
+// coalesced statements. It must be called before any other command is sent.
+// The first error of the statements is returned.
+func (mc *mysqlConn) flushCoalesced() error {
+	c := mc.coalescer
+	if c == nil || len(c.pending) == 0 {
+		return nil
+	}
+	if err := mc.writeCoalesced(); err != nil {
+		return mc.failCoalesced(err)
+	}
+	pending := c.pending
+	c.pending = nil
+
+	var first error
+	for i, r := range pending {
+		mc.sequence = 1 // response to a command with sequence 0
+		r.err = mc.readExecResult()
+		r.res = mc.result
+		r.done = true
+		if r.err == nil {
+			continue
+		}
+		if first == nil {
+			first = r.err
+		}
+		var mysqlErr *MySQLError
+		if !errors.As(r.err, &mysqlErr) {
+			// the connection is broken, the other results are lost
+			for _, lost := range pending[i+1:] {
+				lost.done, lost.err = true, r.err
+			}
+			break
+		}
+	}
+	mc.clearResult()
+	mc.resetSequence()
+	return first
+}
@@ -0,0 +1,152 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+// txOkPacket is an OK packet with SERVER_STATUS_IN_TRANS set.
+var txOkPacket = []byte{7, 0, 0, 1, 0, 0, 0, 3, 0, 0, 0}
+
+func TestCoalesceWrites(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.coalesceWrites = time.Hour
+	insertOk := mockPacket(1, []byte{iOK, 1, 42, 2, 0, 0, 0})
+	conn.queuedReplies = [][]byte{txOkPacket, append(append([]byte{}, insertOk...), insertOk...), okPacket}
+
+	tx, err := mc.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []any
+	for _, q := range []string{"INSERT INTO t VALUES (1)", "INSERT INTO t VALUES (2)"} {
+		res, err := mc.Exec(q, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, res)
+	}
+	if conn.writes != 1 {
+		t.Fatalf("expected the inserts to be buffered, got %d writes", conn.writes)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if conn.writes != 3 {
+		t.Errorf("expected the inserts to be written at once, got %d writes", conn.writes)
+	}
+
+	var expected []byte
+	expected = append(expected, commandPacket(comQuery, "START TRANSACTION")...)
+	expected = append(expected, commandPacket(comQuery, "INSERT INTO t VALUES (1)")...)
+	expected = append(expected, commandPacket(comQuery, "INSERT INTO t VALUES (2)")...)
+	expected = append(expected, commandPacket(comQuery, "COMMIT")...)
+	if !bytes.Equal(conn.written, expected) {
+		t.Errorf("unexpected packets written:\nexpected %q\ngot      %q", expected, conn.written)
+	}
+	for i, res := range results {
+		id, err := res.(Result).LastInsertId()
+		if err != nil || id != 42 {
+			t.Errorf("result %d: got %d, %v", i, id, err)
+		}
+		if _, ok := ResultInfo(res); !ok {
+			t.Errorf("result %d: no ResultInfo", i)
+		}
+		multi, ok := MultiResults(res)
+		if !ok || len(multi) != 1 || multi[0].AffectedRows != 1 || multi[0].LastInsertId != 42 {
+			t.Errorf("result %d: unexpected MultiResults %+v, %v", i, multi, ok)
+		}
+	}
+}
+
+func TestCoalesceWritesError(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.coalesceWrites = time.Hour
+	replies := append(append([]byte{}, okPacket...), mockErr(1, 1062, "Duplicate entry")...)
+	conn.queuedReplies = [][]byte{txOkPacket, replies, okPacket}
+
+	tx, err := mc.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	res1, err := mc.Exec("INSERT INTO t VALUES (1)", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res2, err := mc.Exec("INSERT INTO t VALUES (1)", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mysqlErr *MySQLError
+	if err := tx.Commit(); !errors.As(err, &mysqlErr) || mysqlErr.Number != 1062 {
+		t.Fatalf("expected error 1062 from Commit, got %v", err)
+	}
+	if _, err := res1.RowsAffected(); err != nil {
+		t.Errorf("first insert: %v", err)
+	}
+	if _, err := res2.RowsAffected(); !errors.As(err, &mysqlErr) {
+		t.Errorf("second insert: expected MySQLError, got %v", err)
+	}
+	if !bytes.HasSuffix(conn.written, commandPacket(comQuery, "ROLLBACK")) {
+		t.Errorf("expected the transaction to be rolled back, got %q", conn.written)
+	}
+}
+
+func TestCoalesceWritesDelay(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.coalesceWrites = time.Millisecond
+	conn.queuedReplies = [][]byte{txOkPacket}
+
+	if _, err := mc.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mc.Exec("UPDATE t SET a = 1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if conn.writes != 1 {
+		t.Fatalf("expected the update to be buffered, got %d writes", conn.writes)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if _, err := mc.Exec("UPDATE t SET a = 2", nil); err != nil {
+		t.Fatal(err)
+	}
+	if conn.writes != 2 {
+		t.Errorf("expected the updates to be written after the delay window, got %d writes", conn.writes)
+	}
+	if len(mc.coalescer.pending) != 2 {
+		t.Errorf("expected 2 pending results, got %d", len(mc.coalescer.pending))
+	}
+}
+
+func TestCoalesces(t *testing.T) {
+	_, mc := newRWMockConn(0)
+	mc.cfg.coalesceWrites = time.Millisecond
+	if mc.coalesces("INSERT INTO t VALUES (1)") {
+		t.Error("statement outside of a transaction is coalesced")
+	}
+	// e.g. after BEGIN executed as statement
+	mc.status |= statusInTrans
+	for query, want := range map[string]bool{
+		"INSERT INTO t VALUES (1)":                true,
+		"  update t SET a = 1":                    true,
+		"DELETE FROM t":                           true,
+		"SELECT * FROM t":                         false,
+		"LOAD DATA LOCAL INFILE 'f' INTO TABLE t": false,
+		"SAVEPOINT a":                             false,
+	} {
+		if got := mc.coalesces(query); got != want {
+			t.Errorf("%q: got %t, want %t", query, got, want)
+		}
+	}
+}
@@ -8,6 +8,11 @@
 
 package mysql
 
+import (
+	"strings"
+	"sync"
+)
+
 const defaultCollationID = 45 // utf8mb4_general_ci
 const binaryCollationID = 63
 
@@ -264,3 +269,37 @@ var unsafeCollations = map[string]bool{
 	"gb18030_bin":            true,
 	"gb18030_unicode_520_ci": true,
 }
+
+// The maximum number of bytes per character of the multibyte charsets, keyed
+// by the charset prefix of the collation names. Other charsets use one byte.
+var charsetMaxLen = map[string]int64{
+	"big5":    2,
+	"cp932":   2,
+	"eucjpms": 3,
+	"euckr":   2,
+	"gb18030": 4,
+	"gb2312":  2,
+	"gbk":     2,
+	"sjis":    2,
+	"ucs2":    2,
+	"ujis":    3,
+	"utf16":   4,
+	"utf16le": 4,
+	"utf32":   4,
+	"utf8":    3,
+	"utf8mb3": 3,
+	"utf8mb4": 4,
+}
+
+// collationMaxLen maps the collation IDs to the maximum number of bytes per
+// character of their charset.
+var collationMaxLen = sync.OnceValue(func() map[byte]int64 {
+	m := make(map[byte]int64, len(collations))
+	for name, id := range collations {
+		charset, _, _ := strings.Cut(name, "_")
+		if n, ok := charsetMaxLen[charset]; ok {
+			m[id] = n
+		}
+	}
+	return m
+})
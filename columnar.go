@@ -0,0 +1,363 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// BatchRows reads a result set in batches of rows stored column by column,
+// e.g. for conversion to Apache Arrow record batches. It avoids converting
+// every value to a driver.Value.
+//
+// This is accessible by executing queries using sql.Conn.Raw() and
+// downcasting the returned rows:
+//
+//	rows, err := rawConn.(driver.QueryerContext).QueryContext(...)
+//	var batch mysql.ColumnBatch
+//	for {
+//		err := rows.(mysql.BatchRows).NextBatch(&batch, 1024)
+//		if err == io.EOF {
+//			break
+//		}
+//		...
+//	}
+//
+// Next and NextBatch may be mixed.
+type BatchRows interface {
+	driver.Rows
+	// NextBatch reads up to n rows of the current result set into batch,
+	// replacing its contents. It returns io.EOF if there are no more rows.
+	// The vectors of batch are reused by the next call.
+	NextBatch(batch *ColumnBatch, n int) error
+}
+
+// ColumnKind is the memory layout of a ColumnVector.
+type ColumnKind uint8
+
+const (
+	// ColumnBinary stores variable-length values in Offsets and Data, like the
+	// Arrow Binary layout. Used for strings, BLOB, DECIMAL, JSON and
+	// temporal types. Temporal values are stored as text even if
+	// parseTime=true.
+	ColumnBinary ColumnKind = iota
+	// ColumnInt64 stores TINYINT to BIGINT and YEAR values in Int64.
+	ColumnInt64
+	// ColumnUint64 stores BIGINT UNSIGNED values in Uint64.
+	ColumnUint64
+	// ColumnFloat64 stores FLOAT and DOUBLE values in Float64.
+	ColumnFloat64
+)
+
+func (k ColumnKind) String() string {
+	switch k {
+	case ColumnBinary:
+		return "Binary"
+	case ColumnInt64:
+		return "Int64"
+	case ColumnUint64:
+		return "Uint64"
+	case ColumnFloat64:
+		return "Float64"
+	}
+	return "ColumnKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+func columnKind(mf *mysqlField) ColumnKind {
+	switch mf.fieldType {
+	case fieldTypeTiny, fieldTypeShort, fieldTypeInt24, fieldTypeYear, fieldTypeLong:
+		return ColumnInt64
+	case fieldTypeLongLong:
+		if mf.flags&flagUnsigned != 0 {
+			return ColumnUint64
+		}
+		return ColumnInt64
+	case fieldTypeFloat, fieldTypeDouble:
+		return ColumnFloat64
+	}
+	return ColumnBinary
+}
+
+// ColumnVector holds the values of one column of a ColumnBatch. Only the
+// slice matching Kind is used. NULL values have a zero value in it.
+type ColumnVector struct {
+	Kind ColumnKind
+
+	// Validity is the validity bitmap in LSB bit order: bit i is set if the
+	// value of row i is not NULL.
+	Validity  []byte
+	NullCount int
+
+	Int64   []int64
+	Uint64  []uint64
+	Float64 []float64
+
+	// Value i of a ColumnBinary vector is Data[Offsets[i]:Offsets[i+1]].
+	Offsets []int32
+	Data    []byte
+}
+
+// IsNull reports whether the value of row i is NULL.
+func (v *ColumnVector) IsNull(i int) bool {
+	return v.Validity[i>>3]&(1<<(i&7)) == 0
+}
+
+// Bytes returns the value of row i of a ColumnBinary vector.
+func (v *ColumnVector) Bytes(i int) []byte {
+	return v.Data[v.Offsets[i]:v.Offsets[i+1]]
+}
+
+func (v *ColumnVector) reset(kind ColumnKind) {
+	v.Kind = kind
+	v.Validity = v.Validity[:0]
+	v.NullCount = 0
+	v.Int64 = v.Int64[:0]
+	v.Uint64 = v.Uint64[:0]
+	v.Float64 = v.Float64[:0]
+	v.Offsets = v.Offsets[:0]
+	v.Data = v.Data[:0]
+	if kind == ColumnBinary {
+		v.Offsets = append(v.Offsets, 0)
+	}
+}
+
+// setValid sets the validity bit of row.
+func (v *ColumnVector) setValid(row int, valid bool) {
+	if row&7 == 0 {
+		v.Validity = append(v.Validity, 0)
+	}
+	if valid {
+		v.Validity[row>>3] |= 1 << (row & 7)
+	} else {
+		v.NullCount++
+	}
+}
+
+func (v *ColumnVector) appendNull(row int) error {
+	v.setValid(row, false)
+	switch v.Kind {
+	case ColumnInt64:
+		v.Int64 = append(v.Int64, 0)
+	case ColumnUint64:
+		v.Uint64 = append(v.Uint64, 0)
+	case ColumnFloat64:
+		v.Float64 = append(v.Float64, 0)
+	default:
+		v.Offsets = append(v.Offsets, int32(len(v.Data)))
+	}
+	return nil
+}
+
+func (v *ColumnVector) appendBytes(row int, b []byte) error {
+	if len(v.Data)+len(b) > math.MaxInt32 {
+		return errors.New("mysql: column batch exceeds 2 GiB, use a smaller batch size")
+	}
+	v.setValid(row, true)
+	v.Data = append(v.Data, b...)
+	v.Offsets = append(v.Offsets, int32(len(v.Data)))
+	return nil
+}
+
+// appendText appends a value in the text protocol representation.
+func (v *ColumnVector) appendText(row int, b []byte, mf *mysqlField) error {
+	switch v.Kind {
+	case ColumnInt64:
+		n, err := strconv.ParseInt(string(b), 10, 64)
+		if err != nil {
+			return err
+		}
+		v.Int64 = append(v.Int64, n)
+	case ColumnUint64:
+		n, err := strconv.ParseUint(string(b), 10, 64)
+		if err != nil {
+			return err
+		}
+		v.Uint64 = append(v.Uint64, n)
+	case ColumnFloat64:
+		if mf.fieldType == fieldTypeFloat {
+			f, err := strconv.ParseFloat(string(b), 32)
+			if err != nil {
+				return err
+			}
+			v.Float64 = append(v.Float64, float64(float32(f)))
+		} else {
+			f, err := strconv.ParseFloat(string(b), 64)
+			if err != nil {
+				return err
+			}
+			v.Float64 = append(v.Float64, f)
+		}
+	default:
+		return v.appendBytes(row, b)
+	}
+	v.setValid(row, true)
+	return nil
+}
+
+// appendValue appends a value returned by binaryRows.readRow.
+func (v *ColumnVector) appendValue(row int, value driver.Value, mf *mysqlField) error {
+	switch x := value.(type) {
+	case nil:
+		return v.appendNull(row)
+	case []byte:
+		return v.appendText(row, x, mf)
+	case time.Time:
+		// temporal values are always ColumnBinary, formatted as by the text
+		// protocol
+		var b [32]byte
+		return v.appendBytes(row, appendTextDateTime(b[:0], x, mf))
+	}
+
+	switch v.Kind {
+	case ColumnInt64:
+		if x, ok := value.(int64); ok {
+			v.Int64 = append(v.Int64, x)
+			v.setValid(row, true)
+			return nil
+		}
+	case ColumnUint64:
+		if x, ok := value.(int64); ok {
+			v.Uint64 = append(v.Uint64, uint64(x))
+			v.setValid(row, true)
+			return nil
+		}
+	case ColumnFloat64:
+		switch x := value.(type) {
+		case float32:
+			v.Float64 = append(v.Float64, float64(x))
+			v.setValid(row, true)
+			return nil
+		case float64:
+			v.Float64 = append(v.Float64, x)
+			v.setValid(row, true)
+			return nil
+		}
+	}
+	return fmt.Errorf("mysql: can not store %T in %s column %q", value, v.Kind, mf.name)
+}
+
+// ColumnBatch is a batch of rows stored column by column, see BatchRows.
+type ColumnBatch struct {
+	// Len is the number of rows in the batch.
+	Len int
+	// Columns holds one vector per column of the result set.
+	Columns []ColumnVector
+}
+
+// reset empties b for the given columns, reusing the allocated vectors.
+func (b *ColumnBatch) reset(columns []mysqlField) {
+	b.Len = 0
+	if cap(b.Columns) < len(columns) {
+		b.Columns = append(b.Columns[:cap(b.Columns)], make([]ColumnVector, len(columns)-cap(b.Columns))...)
+	}
+	b.Columns = b.Columns[:len(columns)]
+	for i := range b.Columns {
+		b.Columns[i].reset(columnKind(&columns[i]))
+	}
+}
+
+func checkBatchSize(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("mysql: invalid batch size %d", n)
+	}
+	return nil
+}
+
+func (rows *textRows) NextBatch(batch *ColumnBatch, n int) error {
+	if err := checkBatchSize(n); err != nil {
+		return err
+	}
+	columns := rows.rs.columns
+	batch.reset(columns)
+
+	for batch.Len < n {
+		mc := rows.mc
+		if mc == nil {
+			break
+		}
+		if err := mc.error(); err != nil {
+			return err
+		}
+
+		data, err := rows.readRowPacket()
+		if rows.mc == nil {
+			// the last result set has been read
+			defer mc.restoreSessionVars()
+		}
+		if err == io.EOF {
+			if rows.mc == nil {
+				if werr := mc.checkWarnings(); werr != nil {
+					return werr
+				}
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		pos := 0
+		for i := range columns {
+			buf, isNull, length, err := readLengthEncodedString(data[pos:])
+			pos += length
+			if err == nil {
+				if isNull {
+					err = batch.Columns[i].appendNull(batch.Len)
+				} else {
+					err = batch.Columns[i].appendText(batch.Len, buf, &columns[i])
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+		batch.Len++
+	}
+
+	if batch.Len == 0 {
+		return io.EOF
+	}
+	return nil
+}
+
+func (rows *binaryRows) NextBatch(batch *ColumnBatch, n int) error {
+	if err := checkBatchSize(n); err != nil {
+		return err
+	}
+	columns := rows.rs.columns
+	batch.reset(columns)
+
+	dest := make([]driver.Value, len(columns))
+	for batch.Len < n && !rows.rs.done {
+		err := rows.Next(dest)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		for i := range columns {
+			if err := batch.Columns[i].appendValue(batch.Len, dest[i], &columns[i]); err != nil {
+				return err
+			}
+		}
+		batch.Len++
+	}
+
+	if batch.Len == 0 {
+		return io.EOF
+	}
+	return nil
+}
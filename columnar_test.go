@@ -0,0 +1,164 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// batchTestRows returns text rows reading a result set with the columns
+// id INT, big BIGINT UNSIGNED, score DOUBLE and name VARCHAR, and 10 rows.
+// Every third row has NULL score and name.
+func batchTestRows(t *testing.T) (*textRows, *mockConn) {
+	conn, mc := newRWMockConn(2)
+	big := mockColumn(3, "big", fieldTypeLongLong)
+	big[len(big)-5] = byte(flagUnsigned)
+	resp := mockColumn(2, "id", fieldTypeLong)
+	resp = append(resp, big...)
+	resp = append(resp, mockColumn(4, "score", fieldTypeDouble)...)
+	resp = append(resp, mockColumn(5, "name", fieldTypeVarChar)...)
+	resp = append(resp, mockPacket(6, []byte{iEOF, 0, 0, 2, 0})...)
+	seq := byte(7)
+	for i := 0; i < 10; i++ {
+		row := appendLengthEncodedString(nil, string(uint64ToString(uint64(i))))
+		row = appendLengthEncodedString(row, "18446744073709551615")
+		if i%3 == 0 {
+			row = append(row, 0xfb, 0xfb) // NULL
+		} else {
+			row = appendLengthEncodedString(row, "1.5")
+			row = appendLengthEncodedString(row, "name")
+		}
+		resp = append(resp, mockPacket(seq, row)...)
+		seq++
+	}
+	conn.data = append(resp, mockPacket(seq, []byte{iEOF, 0, 0, 2, 0})...)
+	conn.maxReads = 1
+	mc.sequence = 2
+
+	rows := &textRows{}
+	rows.mc = mc
+	var err error
+	if rows.rs.columns, err = mc.readColumns(4); err != nil {
+		t.Fatal(err)
+	}
+	return rows, conn
+}
+
+func TestTextRowsNextBatch(t *testing.T) {
+	rows, _ := batchTestRows(t)
+
+	var batch ColumnBatch
+	var lens, nulls []int
+	var ids []int64
+	for {
+		err := rows.NextBatch(&batch, 4)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		lens = append(lens, batch.Len)
+		nulls = append(nulls, batch.Columns[2].NullCount)
+		ids = append(ids, batch.Columns[0].Int64...)
+
+		kinds := []ColumnKind{ColumnInt64, ColumnUint64, ColumnFloat64, ColumnBinary}
+		for i, v := range batch.Columns {
+			if v.Kind != kinds[i] {
+				t.Errorf("column %d: expected %s, got %s", i, kinds[i], v.Kind)
+			}
+		}
+		if batch.Columns[1].Uint64[0] != 18446744073709551615 {
+			t.Errorf("unexpected uint64 value %d", batch.Columns[1].Uint64[0])
+		}
+		for row := 0; row < batch.Len; row++ {
+			id := batch.Columns[0].Int64[row]
+			score, name := &batch.Columns[2], &batch.Columns[3]
+			if null := id%3 == 0; score.IsNull(row) != null || name.IsNull(row) != null {
+				t.Errorf("row %d: unexpected validity", id)
+			} else if !null && (score.Float64[row] != 1.5 || string(name.Bytes(row)) != "name") {
+				t.Errorf("row %d: unexpected values %v, %q", id, score.Float64[row], name.Bytes(row))
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(lens, []int{4, 4, 2}) {
+		t.Errorf("unexpected batch sizes %v", lens)
+	}
+	if !reflect.DeepEqual(ids, []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+		t.Errorf("unexpected ids %v", ids)
+	}
+	if !reflect.DeepEqual(nulls, []int{2, 1, 1}) {
+		t.Errorf("unexpected NULL counts %v", nulls)
+	}
+}
+
+func TestTextRowsNextBatchLayout(t *testing.T) {
+	rows, _ := batchTestRows(t)
+
+	var batch ColumnBatch
+	if err := rows.NextBatch(&batch, 10); err != nil {
+		t.Fatal(err)
+	}
+	name := batch.Columns[3]
+	if name.NullCount != 4 {
+		t.Errorf("expected 4 NULL values, got %d", name.NullCount)
+	}
+	// rows 0, 3, 6 and 9 are NULL
+	if want := []byte{0xb6, 0x01}; !reflect.DeepEqual(name.Validity, want) {
+		t.Errorf("expected validity %x, got %x", want, name.Validity)
+	}
+	wantOffsets := []int32{0, 0, 4, 8, 8, 12, 16, 16, 20, 24, 24}
+	if !reflect.DeepEqual(name.Offsets, wantOffsets) {
+		t.Errorf("expected offsets %v, got %v", wantOffsets, name.Offsets)
+	}
+	if err := rows.NextBatch(&batch, 10); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+	if batch.Len != 0 {
+		t.Errorf("expected empty batch, got %d rows", batch.Len)
+	}
+}
+
+func TestNextBatchInvalidSize(t *testing.T) {
+	rows, _ := batchTestRows(t)
+	if err := rows.NextBatch(&ColumnBatch{}, 0); err == nil {
+		t.Error("expected error for batch size 0")
+	}
+}
+
+func TestColumnVectorAppendTime(t *testing.T) {
+	midnight := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	frac := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC)
+	tests := []struct {
+		field    mysqlField
+		value    time.Time
+		expected string
+	}{
+		{mysqlField{fieldType: fieldTypeDateTime}, midnight, "2024-05-06 00:00:00"},
+		{mysqlField{fieldType: fieldTypeDateTime, decimals: 3}, midnight, "2024-05-06 00:00:00.000"},
+		{mysqlField{fieldType: fieldTypeTimestamp, decimals: 6}, frac, "2024-05-06 07:08:09.123456"},
+		{mysqlField{fieldType: fieldTypeDateTime}, frac, "2024-05-06 07:08:09"},
+		{mysqlField{fieldType: fieldTypeDate}, midnight, "2024-05-06"},
+		{mysqlField{fieldType: fieldTypeDateTime, decimals: 2}, time.Time{}, "0000-00-00 00:00:00.00"},
+	}
+	for _, tt := range tests {
+		var v ColumnVector
+		v.reset(ColumnBinary)
+		if err := v.appendValue(0, tt.value, &tt.field); err != nil {
+			t.Fatal(err)
+		}
+		if got := string(v.Bytes(0)); got != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"io"
+)
+
+type columnKey struct {
+	schema, table, name string
+}
+
+// loadColumnDefaults sets the default values of the table columns of a result
+// set from information_schema, with a single query for all tables.
+func (mc *mysqlConn) loadColumnDefaults(columns []mysqlField) error {
+	query := []byte("SELECT TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME, COLUMN_DEFAULT FROM information_schema.COLUMNS WHERE (TABLE_SCHEMA, TABLE_NAME) IN (")
+	tables := make(map[columnKey]bool)
+	for _, col := range columns {
+		key := columnKey{schema: col.schema, table: col.orgTable}
+		if col.orgTable == "" || col.hasDefault || tables[key] {
+			continue
+		}
+		if len(tables) > 0 {
+			query = append(query, ',')
+		}
+		tables[key] = true
+		query = append(query, '(')
+		query = mc.appendStringLiteral(query, col.schema)
+		query = append(query, ',')
+		query = mc.appendStringLiteral(query, col.orgTable)
+		query = append(query, ')')
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+	query = append(query, ')')
+
+	// Send command
+	handleOk := mc.clearResult()
+	if err := mc.writeCommandPacketStr(comQuery, string(query)); err != nil {
+		return err
+	}
+
+	// Read Result
+	resLen, err := handleOk.readResultSetHeaderPacket()
+	if err != nil {
+		return err
+	}
+	rows := new(textRows)
+	rows.mc = mc
+	if rows.rs.columns, err = mc.readColumns(resLen); err != nil {
+		return err
+	}
+	if resLen != 4 {
+		if err := mc.readUntilEOF(); err != nil {
+			return err
+		}
+		return ErrMalformPkt
+	}
+
+	defaults := make(map[columnKey]string)
+	dest := make([]driver.Value, resLen)
+	for {
+		err := rows.readRow(dest)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		// COLUMN_DEFAULT is NULL for columns without default
+		if value, ok := dest[3].([]byte); ok {
+			schema, _ := dest[0].([]byte)
+			table, _ := dest[1].([]byte)
+			name, _ := dest[2].([]byte)
+			defaults[columnKey{string(schema), string(table), string(name)}] = string(value)
+		}
+	}
+
+	for i := range columns {
+		col := &columns[i]
+		if value, ok := defaults[columnKey{col.schema, col.orgTable, col.orgName}]; ok && !col.hasDefault {
+			col.defaultValue = value
+			col.hasDefault = true
+		}
+	}
+	return nil
+}
+
+// appendStringLiteral appends s as quoted string literal to buf.
+func (mc *mysqlConn) appendStringLiteral(buf []byte, s string) []byte {
+	buf = append(buf, '\'')
+	if mc.status&statusNoBackslashEscapes == 0 {
+		buf = escapeStringBackslash(buf, s)
+	} else {
+		buf = escapeStringQuotes(buf, s)
+	}
+	return append(buf, '\'')
+}
+
+// loadColumnDefaults loads the default values of the result set columns of the
+// statement before it is executed the first time. The defaults stay unknown
+// if the server returns an error, e.g. for missing privileges, so that they do
+// not fail the statement.
+func (stmt *mysqlStmt) loadColumnDefaults() error {
+	if stmt.defaults || len(stmt.columns) == 0 {
+		return nil
+	}
+	mc := stmt.mc
+	stmt.defaults = true
+	if prewarmed := mc.stmtCache[stmt.sql]; prewarmed != nil && prewarmed.id == stmt.id {
+		prewarmed.defaults = true
+	}
+	err := mc.loadColumnDefaults(stmt.columns)
+	if _, ok := err.(*MySQLError); ok {
+		mc.log("loading column defaults failed: ", err)
+		return nil
+	}
+	return err
+}
+
+// copyColumnDefaults copies the default values loaded when the statement was
+// prepared to the columns of its result set.
+func (stmt *mysqlStmt) copyColumnDefaults(columns []mysqlField) {
+	if len(stmt.columns) != len(columns) {
+		return
+	}
+	for i := range columns {
+		if !columns[i].hasDefault {
+			columns[i].defaultValue = stmt.columns[i].defaultValue
+			columns[i].hasDefault = stmt.columns[i].hasDefault
+		}
+	}
+}
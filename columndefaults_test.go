@@ -0,0 +1,134 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"testing"
+)
+
+// mockTableColumn returns a column definition packet of a table column,
+// followed by the default value like in the response to COM_FIELD_LIST if
+// dflt is not nil.
+func mockTableColumn(seq byte, schema, table, name string, dflt []byte) []byte {
+	payload := appendLengthEncodedString(nil, "def")
+	payload = appendLengthEncodedString(payload, schema)
+	payload = appendLengthEncodedString(payload, table)
+	payload = appendLengthEncodedString(payload, table)
+	payload = appendLengthEncodedString(payload, name)
+	payload = appendLengthEncodedString(payload, name)
+	payload = append(payload, 0x0c)  // length of fixed fields
+	payload = append(payload, 33, 0) // charset
+	payload = append(payload, 0, 1, 0, 0)
+	payload = append(payload, byte(fieldTypeVarChar), 0, 0, 0, 0, 0)
+	if dflt != nil {
+		payload = appendLengthEncodedString(payload, string(dflt))
+	}
+	return mockPacket(seq, payload)
+}
+
+func TestReadColumnsFieldListDefault(t *testing.T) {
+	conn, mc := newRWMockConn(1)
+	mc.cfg.columnDefaults = true
+	conn.data = mockTableColumn(1, "db", "users", "name", []byte("anon"))
+	conn.data = append(conn.data, mockTableColumn(2, "db", "users", "id", nil)...)
+	conn.data = append(conn.data, mockPacket(3, []byte{iEOF, 0, 0, 2, 0})...)
+	conn.maxReads = 1
+
+	columns, err := mc.readColumns(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := &textRows{}
+	rows.rs.columns = columns
+	if value, ok := rows.ColumnTypeDefault(0); !ok || value != "anon" {
+		t.Errorf("expected default 'anon', got %q, %v", value, ok)
+	}
+	if _, ok := rows.ColumnTypeDefault(1); ok {
+		t.Error("expected no default")
+	}
+	if c := columns[1]; c.schema != "db" || c.orgTable != "users" || c.orgName != "id" {
+		t.Errorf("unexpected column %+v", c)
+	}
+}
+
+func TestPrepareColumnDefaults(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.columnDefaults = true
+
+	prepare := mockPacket(1, []byte{iOK, 1, 0, 0, 0, 3, 0, 0, 0, 0, 0, 0})
+	prepare = append(prepare, mockTableColumn(2, "db", "users", "id", nil)...)
+	prepare = append(prepare, mockTableColumn(3, "db", "users", "name", nil)...)
+	prepare = append(prepare, mockColumn(4, "1", fieldTypeLongLong)...)
+	prepare = append(prepare, mockPacket(5, []byte{iEOF, 0, 0, 2, 0})...)
+
+	defaults := mockPacket(1, []byte{4})
+	for i, name := range []string{"TABLE_SCHEMA", "TABLE_NAME", "COLUMN_NAME", "COLUMN_DEFAULT"} {
+		defaults = append(defaults, mockColumn(byte(2+i), name, fieldTypeVarChar)...)
+	}
+	defaults = append(defaults, mockPacket(6, []byte{iEOF, 0, 0, 2, 0})...)
+	row := appendLengthEncodedString(nil, "db")
+	row = appendLengthEncodedString(row, "users")
+	row = appendLengthEncodedString(row, "id")
+	defaults = append(defaults, mockPacket(7, append(row, 0xfb))...)
+	row = appendLengthEncodedString(nil, "db")
+	row = appendLengthEncodedString(row, "users")
+	row = appendLengthEncodedString(row, "name")
+	row = appendLengthEncodedString(row, "anon")
+	defaults = append(defaults, mockPacket(8, row)...)
+	defaults = append(defaults, mockPacket(9, []byte{iEOF, 0, 0, 2, 0})...)
+
+	conn.queuedReplies = [][]byte{prepare, defaults}
+	conn.maxReads = 2
+
+	ds, err := mc.Prepare("SELECT id, name, 1 FROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmt := ds.(*mysqlStmt)
+	if bytes.Contains(conn.written, []byte("information_schema")) {
+		t.Fatal("defaults loaded by Prepare")
+	}
+
+	// loaded once, before the first execution
+	if err := stmt.loadColumnDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	if err := stmt.loadColumnDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	columns := stmt.columns
+	if len(columns) != 3 {
+		t.Fatalf("expected 3 columns, got %d", len(columns))
+	}
+	if columns[0].hasDefault || columns[2].hasDefault {
+		t.Errorf("unexpected defaults %+v", columns)
+	}
+	if !columns[1].hasDefault || columns[1].defaultValue != "anon" {
+		t.Errorf("expected default 'anon', got %+v", columns[1])
+	}
+	if !bytes.Contains(conn.written, []byte("IN (('db','users'))")) {
+		t.Errorf("unexpected query %q", conn.written)
+	}
+}
+
+func TestLoadColumnDefaultsError(t *testing.T) {
+	conn, mc := newRWMockConn(1)
+	mc.cfg.columnDefaults = true
+	conn.data = mockErr(1, 1142, "SELECT command denied")
+	conn.maxReads = 1
+
+	stmt := &mysqlStmt{mc: mc, columns: []mysqlField{{schema: "db", orgTable: "users", orgName: "name"}}}
+	if err := stmt.loadColumnDefaults(); err != nil {
+		t.Fatalf("expected the error to be ignored, got %v", err)
+	}
+	if !stmt.defaults || stmt.columns[0].hasDefault {
+		t.Errorf("unexpected statement %+v", stmt)
+	}
+}
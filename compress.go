@@ -150,13 +150,17 @@ const maxPayloadLen = maxPacketSize - 4
 
 // writePackets sends one or some packets with compression.
 // Use this instead of mc.netConn.Write() when mc.compress is true.
+// The server rejects compressed packets whose uncompressed payload exceeds
+// max_allowed_packet, even if the packets in it are smaller, so the payload
+// is limited to mc.maxAllowedPacket, too.
 func (c *compIO) writePackets(packets []byte) (int, error) {
 	totalBytes := len(packets)
 	blankHeader := make([]byte, 7)
 	buf := &c.buff
+	maxLen := min(maxPayloadLen, c.mc.maxAllowedPacket)
 
 	for len(packets) > 0 {
-		payloadLen := min(maxPayloadLen, len(packets))
+		payloadLen := min(maxLen, len(packets))
 		payload := packets[:payloadLen]
 		uncompressedLen := payloadLen
 
@@ -182,11 +186,15 @@ func (c *compIO) writePackets(packets []byte) (int, error) {
 			}
 		}
 
+		comprLen := buf.Len() - 7
 		if n, err := c.writeCompressedPacket(buf.Bytes(), uncompressedLen); err != nil {
 			// To allow returning ErrBadConn when sending really 0 bytes, we sum
 			// up compressed bytes that is returned by underlying Write().
 			return totalBytes - len(packets) + n, err
 		}
+		if s := c.mc.cfg.StatsCollector; s != nil && uncompressedLen > 0 {
+			s.BytesCompressed(uncompressedLen, comprLen)
+		}
 		packets = packets[payloadLen:]
 	}
 
@@ -117,3 +117,27 @@ func TestRoundtrip(t *testing.T) {
 		})
 	}
 }
+
+// TestCompressMaxAllowedPacket tests that the uncompressed payload of a
+// compressed packet does not exceed max_allowed_packet.
+func TestCompressMaxAllowedPacket(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.compress = true
+	mc.compIO = newCompIO(mc)
+	mc.maxAllowedPacket = 1000
+
+	packets := append(mockPacket(0, make([]byte, 900)), mockPacket(1, make([]byte, 900))...)
+	if _, err := mc.compIO.writePackets(packets); err != nil {
+		t.Fatal(err)
+	}
+	if conn.writes != 2 {
+		t.Errorf("expected 2 compressed packets, got %d", conn.writes)
+	}
+	for data := conn.written; len(data) > 0; {
+		comprLen, uncomprLen := getUint24(data), getUint24(data[4:])
+		if uncomprLen > mc.maxAllowedPacket {
+			t.Errorf("uncompressed payload of %d bytes", uncomprLen)
+		}
+		data = data[7+comprLen:]
+	}
+}
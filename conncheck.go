@@ -13,6 +13,7 @@ package mysql
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"syscall"
@@ -20,6 +21,22 @@ import (
 
 var errUnexpectedRead = errors.New("unexpected read from socket")
 
+// unexpectedRead returns the error for data received on an idle connection.
+// Servers and proxies may send an ERR packet before closing the connection,
+// e.g. 1053 (ER_SERVER_SHUTDOWN). Its error is included if data is a
+// complete uncompressed and unencrypted ERR packet.
+func unexpectedRead(data []byte) error {
+	if len(data) > 4 {
+		pktLen := int(uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16)
+		if pktLen == len(data)-4 {
+			if me := parseErrorPacket(data[4:]); me != nil {
+				return fmt.Errorf("%w: %w", errUnexpectedRead, me)
+			}
+		}
+	}
+	return errUnexpectedRead
+}
+
 func connCheck(conn net.Conn) error {
 	var sysErr error
 
@@ -33,13 +50,13 @@ func connCheck(conn net.Conn) error {
 	}
 
 	err = rawConn.Read(func(fd uintptr) bool {
-		var buf [1]byte
+		var buf [1024]byte
 		n, err := syscall.Read(int(fd), buf[:])
 		switch {
 		case n == 0 && err == nil:
 			sysErr = io.EOF
 		case n > 0:
-			sysErr = errUnexpectedRead
+			sysErr = unexpectedRead(buf[:n])
 		case err == syscall.EAGAIN || err == syscall.EWOULDBLOCK:
 			sysErr = nil
 		default:
@@ -12,6 +12,11 @@
 package mysql
 
 import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
 	"testing"
 	"time"
 )
@@ -37,3 +42,79 @@ func TestStaleConnectionChecks(t *testing.T) {
 		}
 	})
 }
+
+func TestUnexpectedRead(t *testing.T) {
+	shutdown := mockErr(0, 1053, "Server shutdown in progress")
+	err := unexpectedRead(shutdown)
+	var me *MySQLError
+	if !errors.Is(err, errUnexpectedRead) || !errors.As(err, &me) || me.Number != 1053 {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	for _, data := range [][]byte{
+		{0x01},
+		shutdown[:len(shutdown)-1],       // incomplete
+		mockPacket(0, []byte{iOK, 0, 0}), // not an ERR packet
+	} {
+		if err := unexpectedRead(data); err != errUnexpectedRead {
+			t.Errorf("%x: unexpected error %v", data, err)
+		}
+	}
+}
+
+type testCloseReasonCollector struct {
+	testStatsCollector
+	reasons []error
+}
+
+func (s *testCloseReasonCollector) ConnClosedReason(reason error) {
+	s.reasons = append(s.reasons, reason)
+}
+
+func TestResetSessionAsyncError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("can not listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write(mockErr(0, 1053, "Server shutdown in progress"))
+		io.Copy(io.Discard, conn)
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := &testCloseReasonCollector{}
+	cfg := NewConfig()
+	cfg.StatsCollector = stats
+	mc := &mysqlConn{
+		buf:     newBuffer(),
+		cfg:     cfg,
+		netConn: conn,
+		rawConn: conn,
+		closech: make(chan struct{}),
+	}
+
+	// wait for the ERR packet
+	time.Sleep(100 * time.Millisecond)
+
+	if err := mc.ResetSession(context.Background()); err != driver.ErrBadConn {
+		t.Fatalf("expected driver.ErrBadConn, got %v", err)
+	}
+	mc.Close()
+
+	if len(stats.reasons) != 1 {
+		t.Fatalf("expected 1 close reason, got %v", stats.reasons)
+	}
+	var me *MySQLError
+	if !errors.As(stats.reasons[0], &me) || me.Number != 1053 {
+		t.Errorf("unexpected close reason %v", stats.reasons[0])
+	}
+}
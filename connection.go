@@ -30,23 +30,62 @@ type mysqlConn struct {
 	result           mysqlResult // managed by clearResult() and handleOkPacket().
 	compIO           *compIO
 	cfg              *Config
+	connAttrs        string // encoded connection attributes
 	connector        *connector
 	maxAllowedPacket int
 	maxWriteSize     int
 	flags            clientFlag
+	mariadbFlags     mariadbFlag // negotiated MariaDB extended capabilities
 	status           statusFlag
 	sequence         uint8
 	compressSequence uint8
 	parseTime        bool
 	compress         bool
+	sessionTrack     bool           // CLIENT_SESSION_TRACK was negotiated, see checkSessionState
+	deprecateEOF     bool           // CLIENT_DEPRECATE_EOF was negotiated, see isEOFPacket
+	deadline         time.Time      // deadline of the current operation, see setDeadline()
+	queryDeadline    time.Time      // deadline of the current query, see startQuery()
+	packetDeadline   time.Time      // deadline of the packet being read, see readPacket()
+	schema           string         // current default database
+	gipkMode         bool           // sql_generate_invisible_primary_key of the server, see fetchGIPKMode
+	info             ConnectionInfo // parsed from the handshake, see ConnInfo
+
+	noResetConnection bool                  // COM_RESET_CONNECTION is not supported by the server
+	authMoreData      AuthMoreDataFunc      // continues the exchange of a registered auth plugin
+	authEnd           AuthMoreDataFunc      // called with nil data once the exchange ended, see endAuth
+	cachedPubKeyUsed  bool                  // the password was encrypted with a cached server key, see serverPubKey
+	scramble          []byte                // auth plugin data of the handshake, see ChangeUser
+	authPlugin        string                // auth plugin of the handshake response
+	stmtCache         map[string]*mysqlStmt // statements prepared in advance, by query
+	stmtLRU           *stmtLRU              // statements prepared for ExecContext and QueryContext, see Config.stmtCacheSize
+	stmtSchema        string                // default database the cached statements were prepared in, see closeStaleStmts
+	xaState           xaState               // state of the XA transaction branch
+	xaID              XID                   // XID of the XA transaction branch
+	openStmts         int                   // statements returned by Prepare and not closed yet
+	failoverArmed     bool                  // the next write may fail over, see Config.transparentFailover
+	queryAttrs        []queryAttr           // query attributes of the current statement, see WithQueryAttrs
+	profile           *Profile              // profile of the current operation, see WithProfile
+	interpolation     *bool                 // overrides InterpolateParams for the current statement, see WithInterpolation
+	sessionVars       []sessionVar          // session variables to restore after the current statement, see WithSessionVars
+	waitedGTIDs       string                // GTID set last waited for, see WithGTIDWait
+	ctx               context.Context       // context of the current statement, passed to LOAD DATA handlers
+	inTx              bool                  // a transaction was started with Begin and not ended yet
+	coalescer         *writeCoalescer       // coalesced statements of the transaction, see Config.coalesceWrites
+	zeroDateNull      bool                  // zero time.Time parameters are sent as NULL, see Config.zeroDate
+	trace             *protocolTrace        // written to a file if the connection fails, see Config.protocolTrace
+	readOnlyChecked   time.Time             // last verification of the read-only mode, see Config.AssertReadOnly
+	warnings          MySQLWarnings         // warnings of the last statement, see Config.collectWarnings
 
 	// for context support (Go 1.8+)
 	watching bool
+	watchCtx context.Context // context being watched, see watchCancel
 	watcher  chan<- context.Context
 	closech  chan struct{}
 	finished chan<- struct{}
-	canceled atomicError // set non-nil if conn is canceled
-	closed   atomic.Bool // set when conn is closed, before closech is closed
+	canceled atomicError   // set non-nil if conn is canceled
+	broken   atomicError   // set non-nil if conn is found broken, see CloseReasonCollector
+	closed   atomic.Bool   // set when conn is closed, before closech is closed
+	connID   atomic.Uint32 // ConnectionID of the current network connection, read by CancelToken
 }
 
 // Helper function to call per-connection logger.
@@ -64,10 +103,31 @@ func (mc *mysqlConn) log(v ...any) {
 	mc.cfg.Logger.Print(v...)
 }
 
-func (mc *mysqlConn) readWithTimeout(b []byte) (int, error) {
-	to := mc.cfg.ReadTimeout
+// ioDeadline returns the deadline for the next I/O operation with the given
+// timeout. The deadline of the current operation takes precedence if it is
+// earlier.
+func (mc *mysqlConn) ioDeadline(to time.Duration) time.Time {
+	var deadline time.Time
 	if to > 0 {
-		if err := mc.netConn.SetReadDeadline(time.Now().Add(to)); err != nil {
+		deadline = time.Now().Add(to)
+	}
+	deadline = earlierDeadline(deadline, mc.deadline)
+	return earlierDeadline(deadline, mc.queryDeadline)
+}
+
+// earlierDeadline returns the earlier of the deadlines a and b, where the zero
+// time means no deadline.
+func earlierDeadline(a, b time.Time) time.Time {
+	if !b.IsZero() && (a.IsZero() || b.Before(a)) {
+		return b
+	}
+	return a
+}
+
+func (mc *mysqlConn) readWithTimeout(b []byte) (int, error) {
+	deadline := earlierDeadline(mc.ioDeadline(mc.readTimeout()), mc.packetDeadline)
+	if !deadline.IsZero() {
+		if err := mc.netConn.SetReadDeadline(deadline); err != nil {
 			return 0, err
 		}
 	}
@@ -75,15 +135,41 @@ func (mc *mysqlConn) readWithTimeout(b []byte) (int, error) {
 }
 
 func (mc *mysqlConn) writeWithTimeout(b []byte) (int, error) {
-	to := mc.cfg.WriteTimeout
-	if to > 0 {
-		if err := mc.netConn.SetWriteDeadline(time.Now().Add(to)); err != nil {
+	if deadline := mc.ioDeadline(mc.writeTimeout()); !deadline.IsZero() {
+		if err := mc.netConn.SetWriteDeadline(deadline); err != nil {
 			return 0, err
 		}
 	}
 	return mc.netConn.Write(b)
 }
 
+// setDeadline sets a deadline for all following I/O operations until it is
+// cleared again by calling setDeadline with the zero time.
+func (mc *mysqlConn) setDeadline(t time.Time) error {
+	mc.deadline = t
+	return mc.netConn.SetDeadline(t)
+}
+
+// startQuery starts the QueryTimeout of a query or exec. It is stopped by
+// finish, i.e. for queries when the rows are closed.
+func (mc *mysqlConn) startQuery() {
+	if to := mc.cfg.QueryTimeout; to > 0 {
+		mc.queryDeadline = time.Now().Add(to)
+	}
+}
+
+// finishQuery stops the QueryTimeout and clears the deadline of the
+// connection, so that it does not affect the following operations.
+func (mc *mysqlConn) finishQuery() {
+	if mc.queryDeadline.IsZero() {
+		return
+	}
+	mc.queryDeadline = time.Time{}
+	if mc.netConn != nil {
+		mc.netConn.SetDeadline(mc.deadline)
+	}
+}
+
 func (mc *mysqlConn) resetSequence() {
 	mc.sequence = 0
 	mc.compressSequence = 0
@@ -101,6 +187,78 @@ func (mc *mysqlConn) syncSequence() {
 	}
 }
 
+// initSession sets up the session state requested by the config after the
+// connection is established.
+func (mc *mysqlConn) initSession() (err error) {
+	// Charset: character_set_connection, character_set_client, character_set_results
+	charsets, auto := mc.cfg.charsets, mc.cfg.Collation == collationAuto
+	if auto {
+		if mc.serverCollationFits() {
+			// negotiated in the handshake
+			charsets = nil
+		} else if len(charsets) == 0 {
+			charsets = []string{mc.autoCharset()}
+		}
+	}
+	if len(charsets) > 0 {
+		var charset string
+		for _, charset = range charsets {
+			// ignore errors here - a charset may not exist
+			if mc.cfg.Collation != "" && !auto {
+				err = mc.exec("SET NAMES " + charset + " COLLATE " + mc.cfg.Collation)
+			} else {
+				err = mc.exec("SET NAMES " + charset)
+			}
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return err
+		}
+		mc.info.Charset, mc.info.ConnectionCollation = charset, ""
+		if auto {
+			collation, err := mc.getSystemVar("collation_connection")
+			if err != nil {
+				return err
+			}
+			mc.info.ConnectionCollation = string(collation)
+		} else if mc.cfg.Collation != "" {
+			mc.info.ConnectionCollation = mc.cfg.Collation
+		}
+	}
+
+	if d := mc.cfg.maxExecutionTime; d > 0 {
+		ms := int64((d + time.Millisecond/2) / time.Millisecond)
+		if ms == 0 {
+			ms = 1
+		}
+		if err = mc.exec("SET SESSION max_execution_time = " + strconv.FormatInt(ms, 10)); err != nil {
+			return err
+		}
+	}
+
+	if mc.cfg.trackGTIDs && mc.sessionTrack {
+		if err = mc.exec("SET SESSION session_track_gtids = OWN_GTID"); err != nil {
+			return err
+		}
+	}
+
+	// Handle DSN Params
+	if err = mc.handleParams(); err != nil {
+		return err
+	}
+	for _, cmd := range mc.cfg.initCommands {
+		if err = mc.exec(cmd); err != nil {
+			return err
+		}
+	}
+	if err = mc.setZeroDatePolicy(); err != nil {
+		return err
+	}
+	return mc.checkReadOnly()
+}
+
 // Handles parameters set in DSN after the connection is established
 func (mc *mysqlConn) handleParams() (err error) {
 	var cmdSet strings.Builder
@@ -127,18 +285,19 @@ func (mc *mysqlConn) handleParams() (err error) {
 
 // markBadConn replaces errBadConnNoWrite with driver.ErrBadConn.
 // This function is used to return driver.ErrBadConn only when safe to retry.
+// If the Config.RetryBudget is exhausted, ErrInvalidConn is returned instead.
 func (mc *mysqlConn) markBadConn(err error) error {
 	if err == errBadConnNoWrite {
-		return driver.ErrBadConn
+		return mc.badConn(ErrInvalidConn)
 	}
 	return err
 }
 
 func (mc *mysqlConn) Begin() (driver.Tx, error) {
-	return mc.begin(false)
+	return mc.begin(false, "")
 }
 
-func (mc *mysqlConn) begin(readOnly bool) (driver.Tx, error) {
+func (mc *mysqlConn) begin(readOnly bool, comment string) (driver.Tx, error) {
 	if mc.closed.Load() {
 		return nil, driver.ErrBadConn
 	}
@@ -148,9 +307,13 @@ func (mc *mysqlConn) begin(readOnly bool) (driver.Tx, error) {
 	} else {
 		q = "START TRANSACTION"
 	}
+	if comment != "" {
+		q += " " + comment
+	}
 	err := mc.exec(q)
 	if err == nil {
-		return &mysqlTx{mc}, err
+		mc.inTx = true
+		return &mysqlTx{mc: mc}, err
 	}
 	return nil, mc.markBadConn(err)
 }
@@ -168,6 +331,9 @@ func (mc *mysqlConn) Close() (err error) {
 func (mc *mysqlConn) close() {
 	mc.cleanup()
 	mc.clearResult()
+	if mc.trace != nil {
+		mc.writeTrace()
+	}
 }
 
 // Closes the network connection and unsets internal variables. Do not call this
@@ -188,6 +354,14 @@ func (mc *mysqlConn) cleanup() {
 	if err := conn.Close(); err != nil {
 		mc.log("closing connection:", err)
 	}
+	if s := mc.cfg.StatsCollector; s != nil {
+		s.ConnClosed()
+		if rc, ok := s.(CloseReasonCollector); ok {
+			if reason := mc.broken.Value(); reason != nil {
+				rc.ConnClosedReason(reason)
+			}
+		}
+	}
 	// This function can be called from multiple goroutines.
 	// So we can not mc.clearResult() here.
 	// Caller should do it if they are in safe goroutine.
@@ -195,7 +369,7 @@ func (mc *mysqlConn) cleanup() {
 
 func (mc *mysqlConn) error() error {
 	if mc.closed.Load() {
-		if err := mc.canceled.Value(); err != nil {
+		if err := mc.canceledError(CancelReceive); err != nil {
 			return err
 		}
 		return ErrInvalidConn
@@ -207,8 +381,22 @@ func (mc *mysqlConn) Prepare(query string) (driver.Stmt, error) {
 	if mc.closed.Load() {
 		return nil, driver.ErrBadConn
 	}
+	if err := mc.assertReadOnly(query); err != nil {
+		return nil, err
+	}
+	if err := mc.closeStaleStmts(); err != nil {
+		return nil, err
+	}
+	query = addQueryHints(query, mc.queryHints())
+
+	// Use the statement prepared in advance, if any
+	if stmt := mc.stmtCache[query]; stmt != nil {
+		mc.openStmts++
+		return &mysqlStmt{mc: mc, id: stmt.id, paramCount: stmt.paramCount, sql: query, cached: true, columns: stmt.columns, defaults: stmt.defaults}, nil
+	}
+
 	// Send command
-	err := mc.writeCommandPacketStr(comStmtPrepare, query)
+	err := mc.writeCommandPacketStr(comStmtPrepare, mc.rewriteQuery(query))
 	if err != nil {
 		// STMT_PREPARE is safe to retry.  So we can return ErrBadConn here.
 		mc.log(err)
@@ -216,29 +404,21 @@ func (mc *mysqlConn) Prepare(query string) (driver.Stmt, error) {
 	}
 
 	stmt := &mysqlStmt{
-		mc: mc,
+		mc:  mc,
+		sql: query,
 	}
 
 	// Read Result
-	columnCount, err := stmt.readPrepareResultPacket()
+	err = stmt.readPrepareResult()
 	if err == nil {
-		if stmt.paramCount > 0 {
-			if err = mc.readUntilEOF(); err != nil {
-				return nil, err
-			}
-		}
-
-		if columnCount > 0 {
-			err = mc.readUntilEOF()
-		}
+		mc.openStmts++
 	}
-
 	return stmt, err
 }
 
 func (mc *mysqlConn) interpolateParams(query string, args []driver.Value) (string, error) {
 	// Number of ? should be same to len(args)
-	if strings.Count(query, "?") != len(args) {
+	if countPlaceholders(query, mc.status&statusNoBackslashEscapes != 0) != len(args) {
 		return "", driver.ErrSkip
 	}
 
@@ -250,19 +430,34 @@ func (mc *mysqlConn) interpolateParams(query string, args []driver.Value) (strin
 		// So its safe to retry.
 		return "", driver.ErrBadConn
 	}
-	buf = buf[:0]
+	buf, err = mc.appendInterpolatedParams(buf[:0], query, args, mc.maxAllowedPacket-4)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// appendInterpolatedParams appends query with the placeholders replaced by
+// the escaped args to buf. driver.ErrSkip is returned if an arg can not be
+// interpolated or if the result is longer than maxLen (if maxLen > 0).
+func (mc *mysqlConn) appendInterpolatedParams(buf []byte, query string, args []driver.Value, maxLen int) ([]byte, error) {
+	var err error
 	argPos := 0
+	noBackslashEscapes := mc.status&statusNoBackslashEscapes != 0
 
 	for i := 0; i < len(query); i++ {
-		q := strings.IndexByte(query[i:], '?')
+		q := nextPlaceholder(query, i, noBackslashEscapes)
 		if q == -1 {
 			buf = append(buf, query[i:]...)
 			break
 		}
-		buf = append(buf, query[i:i+q]...)
-		i += q
+		buf = append(buf, query[i:q]...)
+		i = q
 
-		arg := args[argPos]
+		if argPos == len(args) {
+			return nil, driver.ErrSkip
+		}
+		arg := mc.zeroDateArg(args[argPos])
 		argPos++
 
 		if arg == nil {
@@ -278,6 +473,12 @@ func (mc *mysqlConn) interpolateParams(query string, args []driver.Value) (strin
 			buf = strconv.AppendUint(buf, v, 10)
 		case float64:
 			buf = strconv.AppendFloat(buf, v, 'g', -1, 64)
+		case float32:
+			buf = strconv.AppendFloat(buf, float64(v), 'g', -1, 32)
+		case Decimal:
+			buf = append(buf, v.String()...)
+		case sqlLiteral:
+			buf = append(buf, v...)
 		case bool:
 			if v {
 				buf = append(buf, '1')
@@ -291,10 +492,14 @@ func (mc *mysqlConn) interpolateParams(query string, args []driver.Value) (strin
 				buf = append(buf, '\'')
 				buf, err = appendDateTime(buf, v.In(mc.cfg.Loc), mc.cfg.timeTruncate)
 				if err != nil {
-					return "", err
+					return nil, err
 				}
 				buf = append(buf, '\'')
 			}
+		case time.Duration:
+			buf = append(buf, '\'')
+			buf = appendTimeDuration(buf, v)
+			buf = append(buf, '\'')
 		case json.RawMessage:
 			buf = append(buf, '\'')
 			if mc.status&statusNoBackslashEscapes == 0 {
@@ -324,52 +529,102 @@ func (mc *mysqlConn) interpolateParams(query string, args []driver.Value) (strin
 			}
 			buf = append(buf, '\'')
 		default:
-			return "", driver.ErrSkip
+			return nil, driver.ErrSkip
 		}
 
-		if len(buf)+4 > mc.maxAllowedPacket {
-			return "", driver.ErrSkip
+		if maxLen > 0 && len(buf) > maxLen {
+			return nil, driver.ErrSkip
 		}
 	}
 	if argPos != len(args) {
-		return "", driver.ErrSkip
+		return nil, driver.ErrSkip
 	}
-	return string(buf), nil
+	return buf, nil
+}
+
+// declined is called with the error of interpolateParams or
+// bindQueryAttrParams. If the arguments could not be sent with the query, i.e.
+// err is driver.ErrSkip, the fallback to a prepared statement is reported to
+// the StatsCollector and ErrInterpolation is returned with strictInterpolation.
+func (mc *mysqlConn) declined(err error) error {
+	if err != driver.ErrSkip {
+		return err
+	}
+	if s, ok := mc.cfg.StatsCollector.(FallbackCollector); ok {
+		s.PrepareFallback()
+	}
+	if mc.cfg.strictInterpolation {
+		return ErrInterpolation
+	}
+	return err
 }
 
 func (mc *mysqlConn) Exec(query string, args []driver.Value) (driver.Result, error) {
 	if mc.closed.Load() {
 		return nil, driver.ErrBadConn
 	}
+	if err := mc.assertReadOnly(query); err != nil {
+		return nil, err
+	}
+	query = addQueryHints(query, mc.queryHints())
+	stmt, stmtArgs := query, []driver.Value(nil) // recorded by audit
 	if len(args) != 0 {
-		if !mc.cfg.InterpolateParams {
+		switch {
+		case mc.interpolates():
+			// try to interpolate the parameters to save extra roundtrips for preparing and closing a statement
+			prepared, err := mc.interpolateParams(query, args)
+			if err != nil {
+				return nil, mc.declined(err)
+			}
+			query, stmt = prepared, prepared
+		case mc.bindsQueryAttrParams():
+			bound, attrs, err := mc.bindQueryAttrParams(query, args)
+			if err != nil {
+				return nil, mc.declined(err)
+			}
+			query, stmtArgs = bound, args
+			prev := mc.queryAttrs
+			mc.queryAttrs = attrs
+			defer func() { mc.queryAttrs = prev }()
+		default:
 			return nil, driver.ErrSkip
 		}
-		// try to interpolate the parameters to save extra roundtrips for preparing and closing a statement
-		prepared, err := mc.interpolateParams(query, args)
+	}
+	if err := mc.audit(stmt, stmtArgs); err != nil {
+		return nil, err
+	}
+	if mc.coalesces(query) {
+		res, err := mc.execCoalesced(mc.rewriteQuery(query))
 		if err != nil {
-			return nil, err
+			return nil, mc.markBadConn(err)
 		}
-		query = prepared
+		return res, nil
 	}
 
-	err := mc.exec(query)
+	err := mc.exec(mc.rewriteQuery(query))
 	if err == nil {
 		copied := mc.result
-		return &copied, err
+		if err = mc.checkWarnings(); err == nil {
+			copied.warnings = mc.warnings
+			return &copied, nil
+		}
 	}
 	return nil, mc.markBadConn(err)
 }
 
 // Internal function to execute commands
 func (mc *mysqlConn) exec(query string) error {
-	handleOk := mc.clearResult()
 	// Send command
 	if err := mc.writeCommandPacketStr(comQuery, query); err != nil {
 		return mc.markBadConn(err)
 	}
+	return mc.readExecResult()
+}
 
-	// Read Result
+// readExecResult reads the result of a statement sent with COM_QUERY and
+// discards result sets.
+func (mc *mysqlConn) readExecResult() error {
+	handleOk := mc.clearResult()
 	resLen, err := handleOk.readResultSetHeaderPacket()
 	if err != nil {
 		return err
@@ -377,7 +632,7 @@ func (mc *mysqlConn) exec(query string) error {
 
 	if resLen > 0 {
 		// columns
-		if err := mc.readUntilEOF(); err != nil {
+		if err := mc.skipColumns(resLen); err != nil {
 			return err
 		}
 
@@ -400,16 +655,43 @@ func (mc *mysqlConn) query(query string, args []driver.Value) (*textRows, error)
 	if mc.closed.Load() {
 		return nil, driver.ErrBadConn
 	}
+	if err := mc.assertReadOnly(query); err != nil {
+		return nil, err
+	}
+	query = addQueryHints(query, mc.queryHints())
+	stmt, stmtArgs := query, []driver.Value(nil) // recorded by audit
 	if len(args) != 0 {
-		if !mc.cfg.InterpolateParams {
+		switch {
+		case mc.interpolates():
+			// try client-side prepare to reduce roundtrip
+			prepared, err := mc.interpolateParams(query, args)
+			if err != nil {
+				return nil, mc.declined(err)
+			}
+			query, stmt = prepared, prepared
+		case mc.bindsQueryAttrParams():
+			bound, attrs, err := mc.bindQueryAttrParams(query, args)
+			if err != nil {
+				return nil, mc.declined(err)
+			}
+			query, stmtArgs = bound, args
+			prev := mc.queryAttrs
+			mc.queryAttrs = attrs
+			defer func() { mc.queryAttrs = prev }()
+		default:
 			return nil, driver.ErrSkip
 		}
-		// try client-side prepare to reduce roundtrip
-		prepared, err := mc.interpolateParams(query, args)
-		if err != nil {
-			return nil, err
+	}
+	if err := mc.audit(stmt, stmtArgs); err != nil {
+		return nil, err
+	}
+	query = mc.rewriteQuery(query)
+	if mc.cfg.propagateDeadline && mc.info.isMariaDB(10, 1) {
+		if deadline, ok := mc.context().Deadline(); ok {
+			if d := time.Until(deadline); d > 0 {
+				query = addStatementTime(query, d)
+			}
 		}
-		query = prepared
 	}
 	// Send command
 	err := mc.writeCommandPacketStr(comQuery, query)
@@ -426,6 +708,7 @@ func (mc *mysqlConn) query(query string, args []driver.Value) (*textRows, error)
 
 	rows := new(textRows)
 	rows.mc = mc
+	rows.mapper = mc.cfg.TypeMapper
 
 	if resLen == 0 {
 		rows.rs.done = true
@@ -444,11 +727,16 @@ func (mc *mysqlConn) query(query string, args []driver.Value) (*textRows, error)
 }
 
 // Gets the value of the given MySQL System Variable
-// The returned byte slice is only valid until the next read
 func (mc *mysqlConn) getSystemVar(name string) ([]byte, error) {
+	return mc.queryValue("SELECT @@" + name)
+}
+
+// queryValue returns the value of the first column of the first row of
+// query as text, or nil for NULL.
+func (mc *mysqlConn) queryValue(query string) ([]byte, error) {
 	// Send command
 	handleOk := mc.clearResult()
-	if err := mc.writeCommandPacketStr(comQuery, "SELECT @@"+name); err != nil {
+	if err := mc.writeCommandPacketStr(comQuery, query); err != nil {
 		return nil, err
 	}
 
@@ -461,14 +749,19 @@ func (mc *mysqlConn) getSystemVar(name string) ([]byte, error) {
 
 		if resLen > 0 {
 			// Columns
-			if err := mc.readUntilEOF(); err != nil {
+			if err := mc.skipColumns(resLen); err != nil {
 				return nil, err
 			}
 		}
 
 		dest := make([]driver.Value, resLen)
 		if err = rows.readRow(dest); err == nil {
-			return dest[0].([]byte), mc.readUntilEOF()
+			// copy, since the buffer is reused by readUntilEOF
+			value, _ := dest[0].([]byte)
+			if value != nil {
+				value = append([]byte{}, value...)
+			}
+			return value, mc.readUntilEOF()
 		}
 	}
 	return nil, err
@@ -482,13 +775,24 @@ func (mc *mysqlConn) cancel(err error) {
 
 // finish is called when the query has succeeded.
 func (mc *mysqlConn) finish() {
+	mc.finishQuery()
+	mc.stopWatching()
+}
+
+// stopWatching stops watching the context of the current operation and
+// returns it, or nil if no context is watched.
+func (mc *mysqlConn) stopWatching() context.Context {
 	if !mc.watching || mc.finished == nil {
-		return
+		return nil
 	}
 	select {
 	case mc.finished <- struct{}{}:
 		mc.watching = false
+		ctx := mc.watchCtx
+		mc.watchCtx = nil
+		return ctx
 	case <-mc.closech:
+		return nil
 	}
 }
 
@@ -511,19 +815,34 @@ func (mc *mysqlConn) Ping(ctx context.Context) (err error) {
 	return handleOk.readResultOK()
 }
 
+// pingWithTimeout is like Ping, but waits at most timeout for the response.
+func (mc *mysqlConn) pingWithTimeout(ctx context.Context, timeout time.Duration) (err error) {
+	if err = mc.setDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	err = mc.Ping(ctx)
+	if derr := mc.setDeadline(time.Time{}); err == nil {
+		err = derr
+	}
+	return err
+}
+
 // BeginTx implements driver.ConnBeginTx interface
 func (mc *mysqlConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
 	if mc.closed.Load() {
 		return nil, driver.ErrBadConn
 	}
 
+	if err := mc.setProfile(ctx); err != nil {
+		return nil, err
+	}
 	if err := mc.watchCancel(ctx); err != nil {
 		return nil, err
 	}
 	defer mc.finish()
 
-	if sql.IsolationLevel(opts.Isolation) != sql.LevelDefault {
-		level, err := mapIsolationLevel(opts.Isolation)
+	if isolation := mc.isolation(sql.IsolationLevel(opts.Isolation)); isolation != sql.LevelDefault {
+		level, err := mapIsolationLevel(driver.IsolationLevel(isolation))
 		if err != nil {
 			return nil, err
 		}
@@ -533,7 +852,93 @@ func (mc *mysqlConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver
 		}
 	}
 
-	return mc.begin(opts.ReadOnly)
+	var hints TxHints
+	if onBeginTx := mc.cfg.OnBeginTx; onBeginTx != nil {
+		var err error
+		hints, err = onBeginTx(ctx, sql.TxOptions{
+			Isolation: sql.IsolationLevel(opts.Isolation),
+			ReadOnly:  opts.ReadOnly,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, stmt := range hints.Statements {
+			if err := mc.exec(stmt); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	schema := txSchemaFromContext(ctx)
+	if schema == "" || schema == mc.schema {
+		return mc.begin(opts.ReadOnly, hints.Comment)
+	}
+
+	prev := mc.schema
+	if err := mc.initDB(schema); err != nil {
+		return nil, mc.markBadConn(err)
+	}
+	tx, err := mc.begin(opts.ReadOnly, hints.Comment)
+	if err != nil {
+		if !mc.closed.Load() {
+			if prev == "" {
+				mc.Close()
+			} else if mc.initDB(prev) != nil {
+				mc.Close()
+			}
+		}
+		return nil, err
+	}
+	mtx := tx.(*mysqlTx)
+	mtx.restoreSchema = prev
+	mtx.schemaSwitched = true
+	return mtx, nil
+}
+
+// resetConnection resets the session state using COM_RESET_CONNECTION and
+// sets up the session again as configured. The reset command must be answered
+// within timeout.
+func (mc *mysqlConn) resetConnection(ctx context.Context, timeout time.Duration) error {
+	if err := mc.watchCancel(ctx); err != nil {
+		return err
+	}
+	defer mc.finish()
+
+	if err := mc.setDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	handleOk := mc.clearResult()
+	err := mc.writeCommandPacket(comResetConnection)
+	if err == nil {
+		err = handleOk.readResultOK()
+	}
+	if derr := mc.setDeadline(time.Time{}); err == nil {
+		err = derr
+	}
+	if err != nil {
+		return err
+	}
+	if err := mc.initSession(); err != nil {
+		return err
+	}
+
+	// All prepared statements have been deallocated
+	mc.stmtCache = nil
+	mc.stmtLRU = nil
+	return mc.prewarmStatements()
+}
+
+// initDB changes the default database of the connection using COM_INIT_DB.
+func (mc *mysqlConn) initDB(schema string) error {
+	handleOk := mc.clearResult()
+	if err := mc.writeCommandPacketStr(comInitDB, schema); err != nil {
+		return err
+	}
+	if err := handleOk.readResultOK(); err != nil {
+		return err
+	}
+	mc.schema = schema
+	return nil
 }
 
 func (mc *mysqlConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
@@ -541,13 +946,72 @@ func (mc *mysqlConn) QueryContext(ctx context.Context, query string, args []driv
 	if err != nil {
 		return nil, err
 	}
+	if hasOutArgs(dargs) {
+		return nil, errOutQuery
+	}
+	query, dargs, spread, err := expandSpread(query, dargs, mc.status&statusNoBackslashEscapes != 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mc.setProfile(ctx); err != nil {
+		return nil, err
+	}
+	if err := mc.setQueryAttrs(ctx); err != nil {
+		return nil, err
+	}
+	defer mc.clearQueryAttrs()
+	mc.ctx = ctx
+	defer mc.clearContext()
+	if err := mc.setInterpolation(ctx); err != nil {
+		return nil, err
+	}
+	defer mc.clearInterpolation()
+	if hasLiteralArgs(dargs) && !mc.interpolates() {
+		return nil, driver.ErrSkip
+	}
 
 	if err := mc.watchCancel(ctx); err != nil {
 		return nil, err
 	}
+	mc.startQuery()
+	if err := mc.waitForGTIDs(ctx); err != nil {
+		mc.finish()
+		return nil, err
+	}
+	if err := mc.setSessionVars(ctx); err != nil {
+		mc.finish()
+		return nil, err
+	}
+
+	if len(dargs) > 0 && mc.cachesStmts() {
+		rows, err := mc.queryCached(query, dargs)
+		if err != nil {
+			mc.logQueryError(query, err)
+			mc.restoreSessionVars()
+			mc.finish()
+			return nil, err
+		}
+		rows.finish = mc.finish
+		return rows, err
+	}
+
+	if spread && !mc.interpolates() {
+		rows, err := mc.querySpread(query, dargs)
+		if err != nil {
+			mc.logQueryError(query, err)
+			mc.restoreSessionVars()
+			mc.finish()
+			return nil, err
+		}
+		rows.finish = mc.finish
+		return rows, err
+	}
 
 	rows, err := mc.query(query, dargs)
 	if err != nil {
+		mc.logQueryError(query, err)
+		mc.restoreSessionVars()
 		mc.finish()
 		return nil, err
 	}
@@ -560,16 +1024,96 @@ func (mc *mysqlConn) ExecContext(ctx context.Context, query string, args []drive
 	if err != nil {
 		return nil, err
 	}
+	if hasOutArgs(dargs) {
+		// OUT parameters are returned by prepared statements only
+		return nil, driver.ErrSkip
+	}
+
+	query, dargs, spread, err := expandSpread(query, dargs, mc.status&statusNoBackslashEscapes != 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mc.setProfile(ctx); err != nil {
+		return nil, err
+	}
+	if err := mc.setQueryAttrs(ctx); err != nil {
+		return nil, err
+	}
+	defer mc.clearQueryAttrs()
+	mc.ctx = ctx
+	defer mc.clearContext()
+	if err := mc.setInterpolation(ctx); err != nil {
+		return nil, err
+	}
+	defer mc.clearInterpolation()
+	if hasLiteralArgs(dargs) && !mc.interpolates() {
+		return nil, driver.ErrSkip
+	}
 
 	if err := mc.watchCancel(ctx); err != nil {
 		return nil, err
 	}
+	mc.startQuery()
 	defer mc.finish()
+	if err := mc.setSessionVars(ctx); err != nil {
+		return nil, err
+	}
+	defer mc.restoreSessionVars()
+
+	var res driver.Result
+	switch {
+	case len(dargs) > 0 && mc.cachesStmts():
+		res, err = mc.execCached(query, dargs)
+	case spread && !mc.interpolates():
+		res, err = mc.execSpread(query, dargs)
+	default:
+		res, err = mc.Exec(query, dargs)
+	}
+	mc.logQueryError(query, err)
+	return res, err
+}
+
+// execSpread executes a query with expanded Spread arguments as prepared
+// statement. database/sql would prepare the original query on driver.ErrSkip.
+func (mc *mysqlConn) execSpread(query string, args []driver.Value) (driver.Result, error) {
+	stmt, err := mc.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	res, err := stmt.Exec(args)
+	if cerr := stmt.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
 
-	return mc.Exec(query, dargs)
+// querySpread is like execSpread for queries. The statement is closed when
+// the rows are closed.
+func (mc *mysqlConn) querySpread(query string, args []driver.Value) (*binaryRows, error) {
+	stmt, err := mc.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	mstmt := stmt.(*mysqlStmt)
+	rows, err := mstmt.query(args)
+	if err != nil {
+		stmt.Close()
+		return nil, err
+	}
+	rows.stmt = mstmt
+	return rows, nil
 }
 
 func (mc *mysqlConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := mc.setProfile(ctx); err != nil {
+		return nil, err
+	}
+	mc.ctx = ctx
+	defer mc.clearContext()
 	if err := mc.watchCancel(ctx); err != nil {
 		return nil, err
 	}
@@ -577,6 +1121,7 @@ func (mc *mysqlConn) PrepareContext(ctx context.Context, query string) (driver.S
 	stmt, err := mc.Prepare(query)
 	mc.finish()
 	if err != nil {
+		mc.logQueryError(query, err)
 		return nil, err
 	}
 
@@ -584,7 +1129,7 @@ func (mc *mysqlConn) PrepareContext(ctx context.Context, query string) (driver.S
 	default:
 	case <-ctx.Done():
 		stmt.Close()
-		return nil, ctx.Err()
+		return nil, &CancelInfo{Phase: CancelReceive, Err: ctx.Err()}
 	}
 	return stmt, nil
 }
@@ -595,12 +1140,33 @@ func (stmt *mysqlStmt) QueryContext(ctx context.Context, args []driver.NamedValu
 		return nil, err
 	}
 
+	if err := stmt.mc.setProfile(ctx); err != nil {
+		return nil, err
+	}
+	if err := stmt.mc.setQueryAttrs(ctx); err != nil {
+		return nil, err
+	}
+	defer stmt.mc.clearQueryAttrs()
+	stmt.mc.ctx = ctx
+	defer stmt.mc.clearContext()
+
 	if err := stmt.mc.watchCancel(ctx); err != nil {
 		return nil, err
 	}
+	stmt.mc.startQuery()
+	if err := stmt.mc.waitForGTIDs(ctx); err != nil {
+		stmt.mc.finish()
+		return nil, err
+	}
+	if err := stmt.mc.setSessionVars(ctx); err != nil {
+		stmt.mc.finish()
+		return nil, err
+	}
 
 	rows, err := stmt.query(dargs)
 	if err != nil {
+		stmt.mc.logQueryError(stmt.sql, err)
+		stmt.mc.restoreSessionVars()
 		stmt.mc.finish()
 		return nil, err
 	}
@@ -614,12 +1180,29 @@ func (stmt *mysqlStmt) ExecContext(ctx context.Context, args []driver.NamedValue
 		return nil, err
 	}
 
+	if err := stmt.mc.setProfile(ctx); err != nil {
+		return nil, err
+	}
+	if err := stmt.mc.setQueryAttrs(ctx); err != nil {
+		return nil, err
+	}
+	defer stmt.mc.clearQueryAttrs()
+	stmt.mc.ctx = ctx
+	defer stmt.mc.clearContext()
+
 	if err := stmt.mc.watchCancel(ctx); err != nil {
 		return nil, err
 	}
+	stmt.mc.startQuery()
 	defer stmt.mc.finish()
+	if err := stmt.mc.setSessionVars(ctx); err != nil {
+		return nil, err
+	}
+	defer stmt.mc.restoreSessionVars()
 
-	return stmt.Exec(dargs)
+	res, err := stmt.Exec(dargs)
+	stmt.mc.logQueryError(stmt.sql, err)
+	return res, err
 }
 
 func (mc *mysqlConn) watchCancel(ctx context.Context) error {
@@ -631,7 +1214,7 @@ func (mc *mysqlConn) watchCancel(ctx context.Context) error {
 	}
 	// When ctx is already cancelled, don't watch it.
 	if err := ctx.Err(); err != nil {
-		return err
+		return &CancelInfo{Phase: CancelBeforeSend, Err: err}
 	}
 	// When ctx is not cancellable, don't watch it.
 	if ctx.Done() == nil {
@@ -643,6 +1226,7 @@ func (mc *mysqlConn) watchCancel(ctx context.Context) error {
 	}
 
 	mc.watching = true
+	mc.watchCtx = ctx
 	mc.watcher <- ctx
 	return nil
 }
@@ -673,16 +1257,37 @@ func (mc *mysqlConn) startWatcher() {
 }
 
 func (mc *mysqlConn) CheckNamedValue(nv *driver.NamedValue) (err error) {
-	nv.Value, err = converter{}.ConvertValue(nv.Value)
+	if s, ok := nv.Value.(SpreadArg); ok {
+		nv.Value, err = mc.converter().checkSpreadArg(s)
+		return
+	}
+	if out, ok := nv.Value.(sql.Out); ok {
+		return mc.converter().checkOutArg(out)
+	}
+	if lit, ok, err := mc.interpolateArg(nv.Value); ok || err != nil {
+		nv.Value = lit
+		return err
+	}
+	nv.Value, err = mc.converter().ConvertValue(nv.Value)
 	return
 }
 
+// converter returns the converter for the arguments of statements.
+func (mc *mysqlConn) converter() converter {
+	if mc.cfg == nil {
+		return converter{}
+	}
+	return converter{parseJSON: mc.cfg.parseJSON, parseDuration: mc.cfg.parseDuration}
+}
+
 // ResetSession implements driver.SessionResetter.
 // (From Go 1.10)
 func (mc *mysqlConn) ResetSession(ctx context.Context) error {
 	if mc.closed.Load() || mc.buf.busy() {
 		return driver.ErrBadConn
 	}
+	mc.profile = nil
+	mc.failoverArmed = false
 
 	// Perform a stale connection check. We only perform this check for
 	// the first query on a connection that has been checked out of the
@@ -704,17 +1309,64 @@ func (mc *mysqlConn) ResetSession(ctx context.Context) error {
 		}
 		if err != nil {
 			mc.log("closing bad idle connection: ", err)
-			return driver.ErrBadConn
+			mc.broken.Set(err)
+			return mc.discardConn()
 		}
 	}
 
+	// Make sure that the server is still a read-only replica
+	if mc.cfg.AssertReadOnly && time.Since(mc.readOnlyChecked) >= readOnlyCheckInterval {
+		if err := mc.checkReadOnly(); err != nil {
+			mc.log("closing connection: ", err)
+			mc.cleanup()
+			return mc.discardConn()
+		}
+	}
+
+	// Reset the session state. This also makes sure that the server still
+	// responds, so no additional ping is required. The reset deallocates
+	// prepared statements, so it is skipped while database/sql still holds
+	// statements prepared on the connection.
+	reset := false
+	if mc.cfg.resetConnection && !mc.noResetConnection && mc.openStmts == 0 {
+		err := mc.resetConnection(ctx, resetPingTimeout)
+		// ER_UNKNOWN_COM_ERROR: COM_RESET_CONNECTION requires MySQL 5.7.3+
+		// or MariaDB 10.2.4+. Don't try again on this connection.
+		if err == nil {
+			reset = true
+		} else if me, ok := err.(*MySQLError); ok && me.Number == 1047 {
+			mc.log("COM_RESET_CONNECTION is not supported by the server")
+			mc.noResetConnection = true
+		} else {
+			mc.log("closing bad idle connection: ", err)
+			mc.cleanup()
+			return mc.discardConn()
+		}
+	}
+
+	// Make sure the server still responds. The connection may be silently
+	// dropped, e.g. by a load balancer after a failover.
+	if mc.cfg.resetWithPing && !reset {
+		if err := mc.pingWithTimeout(ctx, resetPingTimeout); err != nil {
+			mc.log("closing bad idle connection: ", err)
+			// The state of the connection is unknown after a failed ping.
+			mc.cleanup()
+			return mc.discardConn()
+		}
+	}
+
+	// The connection does not carry any state which would be lost by
+	// replacing it, unless statements prepared on it are still in use.
+	mc.failoverArmed = mc.cfg.transparentFailover && mc.openStmts == 0
+
 	return nil
 }
 
 // IsValid implements driver.Validator interface
 // (From Go 1.15)
 func (mc *mysqlConn) IsValid() bool {
-	return !mc.closed.Load() && !mc.buf.busy()
+	// a connection with an unfinished XA transaction branch must not be reused
+	return !mc.closed.Load() && !mc.buf.busy() && mc.xaState == xaNone
 }
 
 var _ driver.SessionResetter = &mysqlConn{}
@@ -9,12 +9,18 @@
 package mysql
 
 import (
+	"bytes"
 	"context"
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"math/big"
 	"net"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestInterpolateParams(t *testing.T) {
@@ -79,7 +85,6 @@ func TestInterpolateParamsTooManyPlaceholders(t *testing.T) {
 	}
 }
 
-// We don't support placeholder in string literal for now.
 // https://github.com/go-sql-driver/mysql/pull/490
 func TestInterpolateParamsPlaceholderInString(t *testing.T) {
 	mc := &mysqlConn{
@@ -90,8 +95,13 @@ func TestInterpolateParamsPlaceholderInString(t *testing.T) {
 		},
 	}
 
-	q, err := mc.interpolateParams("SELECT 'abc?xyz',?", []driver.Value{int64(42)})
-	// When InterpolateParams support string literal, this should return `"SELECT 'abc?xyz', 42`
+	q, err := mc.interpolateParams("SELECT 'abc?xyz',? -- why?", []driver.Value{int64(42)})
+	if err != nil || q != "SELECT 'abc?xyz',42 -- why?" {
+		t.Errorf("Expected err=nil, got err=%#v, q=%#v", err, q)
+	}
+
+	// the ? in the string literal must not be replaced
+	q, err = mc.interpolateParams("SELECT 'abc?xyz',?", []driver.Value{int64(42), int64(43)})
 	if err != driver.ErrSkip {
 		t.Errorf("Expected err=driver.ErrSkip, got err=%#v, q=%#v", err, q)
 	}
@@ -115,6 +125,71 @@ func TestInterpolateParamsUint64(t *testing.T) {
 	}
 }
 
+func TestInterpolateParamsNumbers(t *testing.T) {
+	mc := &mysqlConn{
+		buf:              newBuffer(),
+		maxAllowedPacket: maxPacketSize,
+		cfg: &Config{
+			InterpolateParams: true,
+		},
+	}
+
+	dec, _ := NewDecimal("-12.50")
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	args := []any{float32(0.1), dec, NullDecimal{}, huge, big.NewFloat(0.5)}
+	dargs := make([]driver.Value, len(args))
+	for i, arg := range args {
+		nv := driver.NamedValue{Ordinal: i + 1, Value: arg}
+		if err := mc.CheckNamedValue(&nv); err != nil {
+			t.Fatalf("%T: %v", arg, err)
+		}
+		dargs[i] = nv.Value
+	}
+	q, err := mc.interpolateParams("SELECT ?, ?, ?, ?, ?", dargs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "SELECT 0.1, -12.50, NULL, 123456789012345678901234567890, 0.5"; q != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, q)
+	}
+}
+
+func TestInterpolator(t *testing.T) {
+	type cents int64
+	conn, mc := newRWMockConn(0)
+	mc.cfg.InterpolateParams = true
+	mc.cfg.Interpolator = func(arg any) (string, error) {
+		if c, ok := arg.(cents); ok {
+			return fmt.Sprintf("%d.%02d", c/100, c%100), nil
+		}
+		return "", driver.ErrSkip
+	}
+
+	args := []driver.NamedValue{{Ordinal: 1, Value: cents(1234)}, {Ordinal: 2, Value: "x"}}
+	for i := range args {
+		if err := mc.CheckNamedValue(&args[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if args[0].Value != sqlLiteral("12.34") || args[1].Value != "x" {
+		t.Fatalf("unexpected values %#v", args)
+	}
+
+	// the literal can not be sent with a prepared statement
+	ctx := WithInterpolation(context.Background(), false)
+	if _, err := mc.ExecContext(ctx, "UPDATE t SET price = ? WHERE id = ?", args); err != driver.ErrSkip {
+		t.Fatalf("expected driver.ErrSkip, got %v", err)
+	}
+
+	conn.queuedReplies = [][]byte{mockPacket(1, []byte{iOK, 1, 0, 2, 0, 0, 0})}
+	if _, err := mc.ExecContext(context.Background(), "UPDATE t SET price = ? WHERE id = ?", args); err != nil {
+		t.Fatal(err)
+	}
+	if q, expected := string(conn.written[5:]), "UPDATE t SET price = 12.34 WHERE id = 'x'"; q != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, q)
+	}
+}
+
 func TestCheckNamedValue(t *testing.T) {
 	value := driver.NamedValue{Value: ^uint64(0)}
 	mc := &mysqlConn{}
@@ -143,9 +218,13 @@ func TestCleanCancel(t *testing.T) {
 
 	for i := 0; i < 3; i++ { // Repeat same behavior
 		err := mc.Ping(ctx)
-		if err != context.Canceled {
+		if !errors.Is(err, context.Canceled) {
 			t.Errorf("expected context.Canceled, got %#v", err)
 		}
+		var ci *CancelInfo
+		if !errors.As(err, &ci) || ci.Phase != CancelBeforeSend {
+			t.Errorf("expected CancelBeforeSend, got %#v", err)
+		}
 
 		if mc.closed.Load() {
 			t.Error("expected mc is not closed, closed actually")
@@ -204,3 +283,221 @@ func (bc badConnection) Write(b []byte) (n int, err error) {
 func (bc badConnection) Close() error {
 	return nil
 }
+
+func TestInitSessionMaxExecutionTime(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.maxExecutionTime = 1500 * time.Millisecond
+	conn.data = []byte{7, 0, 0, 1, 0, 0, 0, 2, 0, 0, 0}
+	conn.maxReads = 1
+
+	if err := mc.initSession(); err != nil {
+		t.Fatal(err)
+	}
+	expected := "SET SESSION max_execution_time = 1500"
+	if len(conn.written) < 5 || string(conn.written[5:]) != expected {
+		t.Errorf("expected %q to be sent, got %q", expected, conn.written)
+	}
+}
+
+func TestInitSessionInitCommands(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.initCommands = []string{"SET time_zone = '+00:00'", "CALL init_session()"}
+	mc.cfg.Params = map[string]string{"autocommit": "1"}
+	conn.queuedReplies = [][]byte{okPacket, okPacket, okPacket}
+
+	if err := mc.initSession(); err != nil {
+		t.Fatal(err)
+	}
+	expected := commandPacket(comQuery, "SET autocommit = 1")
+	expected = append(expected, commandPacket(comQuery, "SET time_zone = '+00:00'")...)
+	expected = append(expected, commandPacket(comQuery, "CALL init_session()")...)
+	if !bytes.Equal(conn.written, expected) {
+		t.Errorf("unexpected packets written:\nexpected %q\ngot      %q", expected, conn.written)
+	}
+
+	// errors of the commands fail the connection
+	conn.written = nil
+	conn.queuedReplies = [][]byte{okPacket, mockErr(1, 1193, "Unknown system variable")}
+	if err := mc.initSession(); err == nil {
+		t.Error("expected error of the init command")
+	}
+}
+
+func TestResetSessionWithPing(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.resetWithPing = true
+	conn.data = []byte{7, 0, 0, 1, 0, 0, 0, 2, 0, 0, 0}
+	conn.maxReads = 1
+
+	if err := mc.ResetSession(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []byte{1, 0, 0, 0, byte(comPing)}; !bytes.Equal(conn.written, expected) {
+		t.Errorf("expected COM_PING to be sent, got %v", conn.written)
+	}
+	if !mc.deadline.IsZero() {
+		t.Errorf("expected deadline to be cleared, got %v", mc.deadline)
+	}
+
+	// no response
+	conn.written = nil
+	conn.reads = 0
+	if err := mc.ResetSession(context.Background()); err != driver.ErrBadConn {
+		t.Fatalf("expected driver.ErrBadConn, got %v", err)
+	}
+	if !mc.closed.Load() {
+		t.Error("expected connection to be closed after failed ping")
+	}
+}
+
+func TestResetSessionWithResetConnection(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.resetConnection = true
+	mc.cfg.maxExecutionTime = time.Second
+	conn.queuedReplies = [][]byte{okPacket, okPacket}
+
+	if err := mc.ResetSession(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{1, 0, 0, 0, comResetConnection}
+	expected = append(expected, commandPacket(comQuery, "SET SESSION max_execution_time = 1000")...)
+	if !bytes.Equal(conn.written, expected) {
+		t.Errorf("unexpected packets written:\nexpected %q\ngot      %q", expected, conn.written)
+	}
+}
+
+func TestResetSessionResetConnectionOpenStmts(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.resetConnection = true
+	mc.openStmts = 1
+
+	// the statement must not be deallocated
+	if err := mc.ResetSession(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(conn.written) != 0 {
+		t.Errorf("expected no packets to be written, got %v", conn.written)
+	}
+}
+
+func TestResetSessionResetConnectionUnsupported(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.resetConnection = true
+	errPacket := []byte{0xff, 0x17, 0x04, '#', '0', '8', 'S', '0', '1', 'U', 'n', 'k', 'n', 'o', 'w', 'n'}
+	conn.queuedReplies = [][]byte{append([]byte{byte(len(errPacket)), 0, 0, 1}, errPacket...)}
+
+	if err := mc.ResetSession(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !mc.noResetConnection {
+		t.Error("expected COM_RESET_CONNECTION to be disabled")
+	}
+
+	conn.written = nil
+	if err := mc.ResetSession(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(conn.written) != 0 {
+		t.Errorf("expected no packets to be written, got %v", conn.written)
+	}
+}
+
+func TestColumnNameCase(t *testing.T) {
+	mc := &mysqlConn{cfg: NewConfig()}
+	mc.cfg.ColumnsWithAlias = true
+	columns := []mysqlField{{tableName: "Users", name: "ID"}, {name: "Name"}}
+
+	for _, tc := range []struct {
+		c        string
+		expected []string
+	}{
+		{"original", []string{"Users.ID", "Name"}},
+		{"lower", []string{"users.id", "name"}},
+		{"upper", []string{"USERS.ID", "NAME"}},
+	} {
+		if err := mc.cfg.Apply(ColumnNameCase(tc.c)); err != nil {
+			t.Fatal(err)
+		}
+		rows := &textRows{mysqlRows{mc: mc, rs: resultSet{columns: columns}}}
+		if got := rows.Columns(); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.c, tc.expected, got)
+		}
+	}
+}
+
+// slowResultServer answers the first command on conn with a result set of
+// three rows, sending every packet after delay.
+func slowResultServer(conn net.Conn, delay time.Duration) {
+	eof := []byte{iEOF, 0, 0, 2, 0}
+	pkts := [][]byte{
+		mockPacket(1, []byte{1}),
+		mockColumn(2, "n", fieldTypeLongLong),
+		mockPacket(3, eof),
+		mockPacket(4, appendLengthEncodedString(nil, "1")),
+		mockPacket(5, appendLengthEncodedString(nil, "2")),
+		mockPacket(6, appendLengthEncodedString(nil, "3")),
+		mockPacket(7, eof),
+	}
+	go func() {
+		buf := make([]byte, 1024)
+		if _, err := conn.Read(buf); err != nil {
+			return
+		}
+		for _, pkt := range pkts {
+			time.Sleep(delay)
+			if _, err := conn.Write(pkt); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+func TestPacketReadAndQueryTimeout(t *testing.T) {
+	query := func(packetReadTimeout, queryTimeout, delay time.Duration) (*mysqlConn, error) {
+		client, server := net.Pipe()
+		t.Cleanup(func() { server.Close() })
+		_, mc := newRWMockConn(0)
+		mc.netConn = client
+		mc.cfg.PacketReadTimeout = packetReadTimeout
+		mc.cfg.QueryTimeout = queryTimeout
+		slowResultServer(server, delay)
+
+		rows, err := mc.QueryContext(context.Background(), "SELECT n FROM t", nil)
+		if err != nil {
+			return mc, err
+		}
+		dest := make([]driver.Value, 1)
+		for err == nil {
+			err = rows.Next(dest)
+		}
+		if cerr := rows.Close(); err == io.EOF {
+			err = cerr
+		}
+		return mc, err
+	}
+
+	// the whole result takes longer than the packet read timeout
+	mc, err := query(100*time.Millisecond, 0, 30*time.Millisecond)
+	if err != nil {
+		t.Fatalf("slowly streamed result: %v", err)
+	}
+	if !mc.packetDeadline.After(time.Now()) {
+		t.Error("expected packet deadline")
+	}
+
+	if _, err := query(20*time.Millisecond, 0, 100*time.Millisecond); err != ErrInvalidConn {
+		t.Errorf("hung server: expected ErrInvalidConn, got %v", err)
+	}
+
+	if _, err := query(0, 100*time.Millisecond, 30*time.Millisecond); err != ErrInvalidConn {
+		t.Errorf("query timeout: expected ErrInvalidConn, got %v", err)
+	}
+
+	mc, err = query(0, time.Second, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mc.queryDeadline.IsZero() {
+		t.Error("query deadline not cleared when the rows were closed")
+	}
+}
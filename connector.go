@@ -10,18 +10,77 @@ package mysql
 
 import (
 	"context"
+	"crypto/rsa"
 	"database/sql/driver"
 	"net"
 	"os"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
+// Connector is the driver.Connector returned by NewConnector and
+// MySQLDriver.OpenConnector.
+type Connector interface {
+	driver.Connector
+
+	// UpdateConfig replaces the configuration used for new connections,
+	// e.g. to rotate credentials. Open connections keep their configuration
+	// until they are closed by the connection pool.
+	UpdateConfig(cfg *Config) error
+
+	// WarmUp establishes n connections and pings them, so that the first
+	// requests after a start do not wait for handshakes, see WarmUpMode.
+	WarmUp(ctx context.Context, n int, mode WarmUpMode) error
+}
+
 type connector struct {
+	state  atomic.Pointer[connectorState] // replaced by UpdateConfig.
+	parked parkedConns                    // connections established by WarmUp
+}
+
+type connectorState struct {
 	cfg               *Config // immutable private copy.
 	encodedAttributes string  // Encoded connection attributes.
+
+	// max_allowed_packet fetched by the last connection, see fetchMaxAllowedPacket
+	maxAllowedPacket atomic.Pointer[cachedMaxAllowedPacket]
+
+	// RSA public key retrieved by the last connection, see serverPubKey
+	pubKey atomic.Pointer[cachedServerPubKey]
+
+	// GIPK mode fetched by the last connection, see fetchGIPKMode
+	gipkMode atomic.Pointer[cachedGIPKMode]
+}
+
+// maxAllowedPacketTTL is how long the max_allowed_packet fetched from a server
+// is used for the new connections of a connector with MaxAllowedPacket 0.
+const maxAllowedPacketTTL = 5 * time.Minute
+
+type cachedMaxAllowedPacket struct {
+	addr    string // Net and Addr of the server
+	value   int
+	expires time.Time
+}
+
+// gipkModeTTL is how long the sql_generate_invisible_primary_key fetched
+// from a server is used for the new connections of a connector.
+const gipkModeTTL = 5 * time.Minute
+
+type cachedGIPKMode struct {
+	addr    string // Net and Addr of the server
+	on      bool
+	expires time.Time
 }
 
+type cachedServerPubKey struct {
+	addr string // Net and Addr of the server
+	key  *rsa.PublicKey
+}
+
+var _ Connector = &connector{}
+
 func encodeConnectionAttributes(cfg *Config) string {
 	connAttrsBuf := make([]byte, 0)
 
@@ -54,26 +113,53 @@ func encodeConnectionAttributes(cfg *Config) string {
 }
 
 func newConnector(cfg *Config) *connector {
-	encodedAttributes := encodeConnectionAttributes(cfg)
-	return &connector{
+	c := &connector{}
+	c.setConfig(cfg)
+	return c
+}
+
+func (c *connector) setConfig(cfg *Config) {
+	c.state.Store(&connectorState{
 		cfg:               cfg,
-		encodedAttributes: encodedAttributes,
+		encodedAttributes: encodeConnectionAttributes(cfg),
+	})
+}
+
+// config returns the current configuration of the connector.
+func (c *connector) config() *Config {
+	return c.state.Load().cfg
+}
+
+// UpdateConfig implements Connector interface.
+func (c *connector) UpdateConfig(cfg *Config) error {
+	cfg = cfg.Clone()
+	if err := cfg.normalize(); err != nil {
+		return err
 	}
+	c.setConfig(cfg)
+	// parked connections use the old configuration
+	c.parked.closeAll()
+	return nil
 }
 
 // Connect implements driver.Connector interface.
 // Connect returns a connection to the database.
 func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
-	var err error
+	if mc := c.parked.take(); mc != nil {
+		return mc, nil
+	}
+	mc, err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mc, nil
+}
 
-	// Invoke beforeConnect if present, with a copy of the configuration
-	cfg := c.cfg
-	if c.cfg.beforeConnect != nil {
-		cfg = c.cfg.Clone()
-		err = c.cfg.beforeConnect(ctx, cfg)
-		if err != nil {
-			return nil, err
-		}
+// connect establishes a new connection to the database.
+func (c *connector) connect(ctx context.Context) (*mysqlConn, error) {
+	cfg, connAttrs, err := c.connectConfig(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	// New mysqlConn
@@ -82,58 +168,176 @@ func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
 		maxWriteSize:     maxPacketSize - 1,
 		closech:          make(chan struct{}),
 		cfg:              cfg,
+		connAttrs:        connAttrs,
 		connector:        c,
 	}
-	mc.parseTime = mc.cfg.ParseTime
+	if cfg.protocolTrace != "" {
+		mc.trace = &protocolTrace{}
+	}
 
 	// Connect to Server
+	start := time.Now()
+	if err = c.dial(ctx, mc); err != nil {
+		if cerr := ctx.Err(); cerr != nil {
+			return nil, &CancelInfo{Phase: CancelConnect, Err: cerr}
+		}
+		return nil, err
+	}
+
+	// Call startWatcher for context support (From Go 1.8)
+	mc.startWatcher()
+	if err := mc.watchCancel(ctx); err != nil {
+		mc.cleanup()
+		return nil, connectCanceled(err)
+	}
+	defer mc.finish()
+
+	if err = mc.handshake(); err != nil {
+		return nil, connectCanceled(err)
+	}
+
+	if s := mc.cfg.StatsCollector; s != nil {
+		s.Handshake(time.Since(start))
+	}
+	return mc, nil
+}
+
+// dial opens the network connection of mc.
+func (c *connector) dial(ctx context.Context, mc *mysqlConn) (err error) {
 	dctx := ctx
 	if mc.cfg.Timeout > 0 {
 		var cancel context.CancelFunc
-		dctx, cancel = context.WithTimeout(ctx, c.cfg.Timeout)
+		dctx, cancel = context.WithTimeout(ctx, mc.cfg.Timeout)
 		defer cancel()
 	}
 
-	if c.cfg.DialFunc != nil {
-		mc.netConn, err = c.cfg.DialFunc(dctx, mc.cfg.Net, mc.cfg.Addr)
+	// Connect to the proxy instead of the server
+	addr := mc.cfg.Addr
+	if addr == "" && mc.cfg.Net == "unix" {
+		addr = mc.cfg.findSocket()
+	}
+	proxy, err := mc.cfg.proxyURL()
+	if err != nil {
+		return err
+	}
+	if proxy != nil {
+		addr = proxyAddr(proxy)
+	}
+
+	if mc.cfg.DialFunc != nil {
+		mc.netConn, err = mc.cfg.DialFunc(dctx, mc.cfg.Net, addr)
 	} else {
-		dialsLock.RLock()
-		dial, ok := dials[mc.cfg.Net]
-		dialsLock.RUnlock()
+		dial, ok := dials.get(mc.cfg.Net)
 		if ok {
-			mc.netConn, err = dial(dctx, mc.cfg.Addr)
+			mc.netConn, err = dial(dctx, addr)
+		} else if mc.cfg.Net == "pipe" {
+			mc.netConn, err = dialPipe(dctx, addr)
 		} else {
 			nd := net.Dialer{}
-			mc.netConn, err = nd.DialContext(dctx, mc.cfg.Net, mc.cfg.Addr)
+			mc.netConn, err = nd.DialContext(dctx, mc.cfg.Net, addr)
 		}
 	}
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if proxy != nil {
+		if err = proxyConnect(dctx, mc.netConn, proxy, mc.cfg.Addr); err != nil {
+			mc.netConn.Close()
+			return err
+		}
+	}
+	if mc.cfg.ProxyHeader != "" {
+		if err = writeProxyHeader(dctx, mc.netConn, mc.cfg.ProxyHeader); err != nil {
+			mc.netConn.Close()
+			return err
+		}
 	}
 	mc.rawConn = mc.netConn
+	if s := mc.cfg.StatsCollector; s != nil {
+		s.ConnOpened()
+	}
 
-	// Enable TCP Keepalives on TCP connections
+	// Configure TCP Keepalives on TCP connections
 	if tc, ok := mc.netConn.(*net.TCPConn); ok {
-		if err := tc.SetKeepAlive(true); err != nil {
-			c.cfg.Logger.Print(err)
+		if err := mc.cfg.setKeepAlive(tc); err != nil {
+			mc.cfg.Logger.Print(err)
 		}
 	}
+	return nil
+}
 
-	// Call startWatcher for context support (From Go 1.8)
-	mc.startWatcher()
-	if err := mc.watchCancel(ctx); err != nil {
-		mc.cleanup()
-		return nil, err
+// fetchMaxAllowedPacket sets mc.maxAllowedPacket to the max_allowed_packet
+// of the server. The value is reused by the other connections of the
+// connector to the same server for maxAllowedPacketTTL, which saves a
+// roundtrip per connection.
+func (mc *mysqlConn) fetchMaxAllowedPacket() error {
+	st := mc.connector.state.Load()
+	addr := mc.cfg.Net + "/" + mc.cfg.Addr
+	if cached := st.maxAllowedPacket.Load(); cached != nil && cached.addr == addr && time.Now().Before(cached.expires) {
+		mc.maxAllowedPacket = cached.value
+		return nil
 	}
-	defer mc.finish()
 
+	maxap, err := mc.getSystemVar("max_allowed_packet")
+	if err != nil {
+		return err
+	}
+	mc.maxAllowedPacket = stringToInt(maxap) - 1
+	st.maxAllowedPacket.Store(&cachedMaxAllowedPacket{
+		addr:    addr,
+		value:   mc.maxAllowedPacket,
+		expires: time.Now().Add(maxAllowedPacketTTL),
+	})
+	return nil
+}
+
+// fetchGIPKMode sets mc.gipkMode to sql_generate_invisible_primary_key of
+// the server, which exists since MySQL 8.0.30. Like max_allowed_packet, the
+// value is reused by the other connections of the connector to the same
+// server for gipkModeTTL.
+func (mc *mysqlConn) fetchGIPKMode() error {
+	if !mc.info.isMySQL(8, 0, 30) {
+		return nil
+	}
+	st := mc.connector.state.Load()
+	addr := mc.cfg.Net + "/" + mc.cfg.Addr
+	if cached := st.gipkMode.Load(); cached != nil && cached.addr == addr && time.Now().Before(cached.expires) {
+		mc.gipkMode = cached.on
+		return nil
+	}
+
+	on, err := mc.getSystemVar("sql_generate_invisible_primary_key")
+	if err != nil {
+		if _, ok := err.(*MySQLError); !ok {
+			return err
+		}
+		// e.g. a proxy which does not know the variable
+		on = nil
+	}
+	mc.gipkMode = string(on) == "1" || strings.EqualFold(string(on), "ON")
+	st.gipkMode.Store(&cachedGIPKMode{
+		addr:    addr,
+		on:      mc.gipkMode,
+		expires: time.Now().Add(gipkModeTTL),
+	})
+	return nil
+}
+
+// handshake authenticates on the freshly dialed connection and sets up the
+// session. The connection is closed on error.
+func (mc *mysqlConn) handshake() error {
+	mc.parseTime = mc.cfg.ParseTime
+	mc.schema = mc.cfg.DBName
 	mc.buf = newBuffer()
+	if mc.cfg.MaxBufferSize > 0 {
+		mc.buf.maxSize = max(mc.cfg.MaxBufferSize, defaultBufSize)
+	}
 
 	// Reading Handshake Initialization Packet
 	authData, plugin, err := mc.readHandshakePacket()
 	if err != nil {
 		mc.cleanup()
-		return nil, err
+		return err
 	}
 
 	if plugin == "" {
@@ -141,20 +345,22 @@ func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
 	}
 
 	// Send Client Authentication Packet
+	defer mc.endAuth()
 	authResp, err := mc.auth(authData, plugin)
 	if err != nil {
 		// try the default auth plugin, if using the requested plugin failed
-		c.cfg.Logger.Print("could not use requested auth plugin '"+plugin+"': ", err.Error())
+		mc.cfg.Logger.Print("could not use requested auth plugin '"+plugin+"': ", err.Error())
 		plugin = defaultAuthPlugin
 		authResp, err = mc.auth(authData, plugin)
 		if err != nil {
 			mc.cleanup()
-			return nil, err
+			return err
 		}
 	}
+	mc.scramble, mc.authPlugin = authData, plugin
 	if err = mc.writeHandshakeResponsePacket(authResp, plugin); err != nil {
 		mc.cleanup()
-		return nil, err
+		return err
 	}
 
 	// Handle response to auth packet, switch methods if possible
@@ -163,7 +369,11 @@ func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
 		// (https://dev.mysql.com/doc/internals/en/authentication-fails.html).
 		// Do not send COM_QUIT, just cleanup and return the error.
 		mc.cleanup()
-		return nil, err
+		if mc.cachedPubKeyUsed {
+			// the server may have a new key
+			mc.connector.state.Load().pubKey.Store(nil)
+		}
+		return err
 	}
 
 	if mc.cfg.compress && mc.flags&clientCompress == clientCompress {
@@ -172,46 +382,68 @@ func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
 	}
 	if mc.cfg.MaxAllowedPacket > 0 {
 		mc.maxAllowedPacket = mc.cfg.MaxAllowedPacket
-	} else {
-		// Get max allowed packet size
-		maxap, err := mc.getSystemVar("max_allowed_packet")
-		if err != nil {
-			mc.Close()
-			return nil, err
-		}
-		mc.maxAllowedPacket = stringToInt(maxap) - 1
+	} else if err := mc.fetchMaxAllowedPacket(); err != nil {
+		mc.Close()
+		return err
 	}
 	if mc.maxAllowedPacket < maxPacketSize {
 		mc.maxWriteSize = mc.maxAllowedPacket
 	}
+	if err := mc.fetchGIPKMode(); err != nil {
+		mc.Close()
+		return err
+	}
+
+	// Set up the session: charset, system variables and DSN params
+	if err = mc.initSession(); err != nil {
+		mc.Close()
+		return err
+	}
+
+	if err = mc.prewarmStatements(); err != nil {
+		mc.Close()
+		return err
+	}
+	return nil
+}
+
+// connectConfig returns the configuration and the encoded connection
+// attributes for a new connection. The connector's configuration is copied
+// before it is modified.
+func (c *connector) connectConfig(ctx context.Context) (*Config, string, error) {
+	st := c.state.Load()
 
-	// Charset: character_set_connection, character_set_client, character_set_results
-	if len(mc.cfg.charsets) > 0 {
-		for _, cs := range mc.cfg.charsets {
-			// ignore errors here - a charset may not exist
-			if mc.cfg.Collation != "" {
-				err = mc.exec("SET NAMES " + cs + " COLLATE " + mc.cfg.Collation)
-			} else {
-				err = mc.exec("SET NAMES " + cs)
-			}
-			if err == nil {
-				break
-			}
+	// Invoke beforeConnect if present, with a copy of the configuration
+	cfg := st.cfg
+	if st.cfg.beforeConnect != nil {
+		cfg = st.cfg.Clone()
+		if err := st.cfg.beforeConnect(ctx, cfg); err != nil {
+			return nil, "", err
 		}
+	}
+
+	// Fetch the password for this connection
+	if cfg.PasswordProvider != nil {
+		passwd, err := cfg.PasswordProvider(ctx)
 		if err != nil {
-			mc.Close()
-			return nil, err
+			return nil, "", err
 		}
+		if cfg == st.cfg {
+			cfg = st.cfg.Clone()
+		}
+		cfg.Passwd = passwd
 	}
 
-	// Handle DSN Params
-	err = mc.handleParams()
-	if err != nil {
-		mc.Close()
-		return nil, err
+	// Resolve the password if it refers to a secret
+	if passwd, ok, err := resolveSecret(ctx, cfg.Passwd); err != nil {
+		return nil, "", err
+	} else if ok {
+		if cfg == st.cfg {
+			cfg = st.cfg.Clone()
+		}
+		cfg.Passwd = passwd
 	}
-
-	return mc, nil
+	return cfg, st.encodedAttributes, nil
 }
 
 // Driver implements driver.Connector interface.
@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"context"
+	"errors"
 	"net"
 	"testing"
 	"time"
@@ -28,3 +29,143 @@ func TestConnectorReturnsTimeout(t *testing.T) {
 		t.Fatalf("expected %T, got %T", nerr, err)
 	}
 }
+
+func TestConnectorPasswordProvider(t *testing.T) {
+	calls := 0
+	cfg := NewConfig()
+	cfg.Passwd = "static"
+	cfg.PasswordProvider = func(ctx context.Context) (string, error) {
+		calls++
+		return "token" + string(rune('0'+calls)), nil
+	}
+	connector := newConnector(cfg)
+
+	for _, expected := range []string{"token1", "token2"} {
+		connCfg, _, err := connector.connectConfig(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if connCfg.Passwd != expected {
+			t.Errorf("expected password %q, got %q", expected, connCfg.Passwd)
+		}
+	}
+	if cfg.Passwd != "static" {
+		t.Errorf("connector config was modified: %q", cfg.Passwd)
+	}
+
+	errProvider := errors.New("token expired")
+	cfg.PasswordProvider = func(ctx context.Context) (string, error) {
+		return "", errProvider
+	}
+	if _, _, err := connector.connectConfig(context.Background()); err != errProvider {
+		t.Errorf("expected provider error, got %v", err)
+	}
+}
+
+func TestConnectorUpdateConfig(t *testing.T) {
+	cfg := NewConfig()
+	cfg.User = "old"
+	cfg.ConnectionAttributes = "rotation:1"
+	dc, err := NewConnector(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	connector := dc.(*connector)
+	_, oldAttrs, err := connector.connectConfig(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.User = "new"
+	cfg.ConnectionAttributes = "rotation:2"
+	if err := dc.(Connector).UpdateConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.User = "modified"
+
+	connCfg, attrs, err := connector.connectConfig(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if connCfg.User != "new" {
+		t.Errorf("expected user 'new', got %q", connCfg.User)
+	}
+	if attrs == oldAttrs {
+		t.Errorf("connection attributes were not updated")
+	}
+
+	cfg.InterpolateParams = true
+	cfg.Collation = "gbk_chinese_ci"
+	if err := dc.(Connector).UpdateConfig(cfg); err != errInvalidDSNUnsafeCollation {
+		t.Errorf("expected errInvalidDSNUnsafeCollation, got %v", err)
+	}
+	if connector.config().User != "new" {
+		t.Errorf("invalid config was applied")
+	}
+}
+
+func TestFetchMaxAllowedPacket(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	conn.queuedReplies = [][]byte{mockSystemVar("max_allowed_packet", "4194304")}
+	if err := mc.fetchMaxAllowedPacket(); err != nil {
+		t.Fatal(err)
+	}
+	if mc.maxAllowedPacket != 4194303 {
+		t.Errorf("expected 4194303, got %d", mc.maxAllowedPacket)
+	}
+
+	// the next connection uses the cached value
+	mc.maxAllowedPacket = maxPacketSize
+	if err := mc.fetchMaxAllowedPacket(); err != nil {
+		t.Fatal(err)
+	}
+	if conn.writes != 1 || mc.maxAllowedPacket != 4194303 {
+		t.Errorf("cached value not used: %d writes, max packet %d", conn.writes, mc.maxAllowedPacket)
+	}
+
+	// the value is fetched again after the TTL
+	mc.connector.state.Load().maxAllowedPacket.Load().expires = time.Now()
+	conn.queuedReplies = [][]byte{mockSystemVar("max_allowed_packet", "1048576")}
+	if err := mc.fetchMaxAllowedPacket(); err != nil {
+		t.Fatal(err)
+	}
+	if mc.maxAllowedPacket != 1048575 {
+		t.Errorf("expected 1048575, got %d", mc.maxAllowedPacket)
+	}
+}
+
+func TestFetchGIPKMode(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.info.ServerVersion = "8.0.29"
+	if err := mc.fetchGIPKMode(); err != nil || mc.gipkMode || conn.writes != 0 {
+		t.Fatalf("expected no query before MySQL 8.0.30, got %d writes, %v", conn.writes, err)
+	}
+
+	mc.info.ServerVersion = "8.0.36-log"
+	conn.queuedReplies = [][]byte{mockSystemVar("sql_generate_invisible_primary_key", "1")}
+	if err := mc.fetchGIPKMode(); err != nil {
+		t.Fatal(err)
+	}
+	if !mc.gipkMode {
+		t.Error("expected GIPK mode")
+	}
+
+	// the next connection uses the cached value
+	mc.gipkMode = false
+	if err := mc.fetchGIPKMode(); err != nil {
+		t.Fatal(err)
+	}
+	if conn.writes != 1 || !mc.gipkMode {
+		t.Errorf("cached value not used: %d writes, GIPK mode %v", conn.writes, mc.gipkMode)
+	}
+
+	// an unknown variable disables the detection
+	mc.connector.state.Load().gipkMode.Load().expires = time.Now()
+	conn.queuedReplies = [][]byte{mockErr(1, 1193, "Unknown system variable")}
+	if err := mc.fetchGIPKMode(); err != nil {
+		t.Fatal(err)
+	}
+	if mc.gipkMode {
+		t.Error("expected no GIPK mode")
+	}
+}
@@ -0,0 +1,140 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ConnectionInfo is the information about a connection which the server
+// sent in the initial handshake and in session state changes, see ConnInfo.
+type ConnectionInfo struct {
+	ServerVersion   string // e.g. "8.0.36" or "5.5.5-10.11.6-MariaDB"
+	ProtocolVersion byte   // always 10 for supported servers
+	ConnectionID    uint32 // id of the connection (thread), as returned by CONNECTION_ID()
+
+	// ServerCapabilities are the capability flags (CLIENT_*) offered by the
+	// server, Capabilities are those negotiated by the driver.
+	ServerCapabilities uint32
+	Capabilities       uint32
+
+	CollationID uint8  // id of the default collation of the server
+	Collation   string // name of CollationID, if known to the driver
+
+	// Charset and ConnectionCollation are the charset and collation of the
+	// connection set up by the driver, see the charset and collation
+	// parameters. ConnectionCollation is empty if the server chose it for
+	// the charset, unless with collation=auto.
+	Charset             string
+	ConnectionCollation string
+
+	// Schema is the current default database of the connection. With
+	// trackSchema=true, it is updated when the server reports a change, e.g.
+	// by a USE statement.
+	Schema string
+
+	// GTIDs is the GTID set of the last transaction committed on the
+	// connection, reported by the server with trackGTIDs=true.
+	GTIDs string
+}
+
+// ConnInfo returns the information about the connection sent by the server
+// in the handshake and in later session state changes, so that it is not necessary to query e.g. VERSION() and
+// CONNECTION_ID(). driverConn must be a connection of this driver, which is
+// accessible with sql.Conn.Raw. ok is false for other types.
+func ConnInfo(driverConn any) (info ConnectionInfo, ok bool) {
+	mc, ok := driverConn.(*mysqlConn)
+	if !ok {
+		return info, false
+	}
+	info = mc.info
+	info.Schema = mc.schema
+	return info, true
+}
+
+// isMariaDB reports whether the server is MariaDB of at least the given
+// version. MariaDB before 11 reports e.g. "5.5.5-10.11.6-MariaDB".
+func (info *ConnectionInfo) isMariaDB(major, minor int) bool {
+	v := info.ServerVersion
+	if !strings.Contains(v, "MariaDB") {
+		return false
+	}
+	v = strings.TrimPrefix(v, "5.5.5-")
+	majorStr, rest, _ := strings.Cut(v, ".")
+	minorStr, _, _ := strings.Cut(rest, ".")
+	maj, err1 := strconv.Atoi(majorStr)
+	min, err2 := strconv.Atoi(minorStr)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	return maj > major || maj == major && min >= minor
+}
+
+// isMySQL reports whether the server is MySQL (not MariaDB) of at least the
+// given version, e.g. "8.0.36" or "8.4.0-log".
+func (info *ConnectionInfo) isMySQL(major, minor, patch int) bool {
+	v := info.ServerVersion
+	if strings.Contains(v, "MariaDB") {
+		return false
+	}
+	v, _, _ = strings.Cut(v, "-")
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	want := [3]int{major, minor, patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return false
+		}
+		if n != want[i] {
+			return n > want[i]
+		}
+	}
+	return true
+}
+
+// collationName returns the name of the collation with the given id, or ""
+// if the id is unknown.
+func collationName(id uint8) string {
+	for name, cid := range collations {
+		if cid == id {
+			return name
+		}
+	}
+	return ""
+}
+
+// collationCharset returns the charset of the named collation.
+func collationCharset(collation string) string {
+	charset, _, _ := strings.Cut(collation, "_")
+	return charset
+}
+
+// collationAuto is the value of the collation parameter which selects the
+// default collation of the server for the charset.
+const collationAuto = "auto"
+
+// autoCharset returns the charset which is used with collation=auto: the
+// first of the charset parameter, or utf8mb4.
+func (mc *mysqlConn) autoCharset() string {
+	if len(mc.cfg.charsets) > 0 {
+		return mc.cfg.charsets[0]
+	}
+	return "utf8mb4"
+}
+
+// serverCollationFits reports whether the default collation of the server,
+// as sent in the handshake, belongs to the charset used with collation=auto,
+// so that it can be negotiated in the handshake without SET NAMES.
+func (mc *mysqlConn) serverCollationFits() bool {
+	return mc.info.Collation != "" && collationCharset(mc.info.Collation) == mc.autoCharset()
+}
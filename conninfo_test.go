@@ -0,0 +1,163 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestConnInfo(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	// handshake of MySQL 5.5.8 with connection id 165 and collation 33
+	conn.data = []byte{72, 0, 0, 0, 10, 53, 46, 53, 46, 56, 0, 165, 0, 0, 0,
+		60, 70, 63, 58, 68, 104, 34, 97, 0, 223, 247, 33, 2, 0, 15, 128, 21, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 98, 120, 114, 47, 85, 75, 109, 99, 51, 77,
+		50, 64, 0, 109, 121, 115, 113, 108, 95, 110, 97, 116, 105, 118, 101, 95,
+		112, 97, 115, 115, 119, 111, 114, 100}
+	if _, _, err := mc.readHandshakePacket(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mc.writeHandshakeResponsePacket(nil, "mysql_native_password"); err != nil {
+		t.Fatal(err)
+	}
+
+	info, ok := ConnInfo(mc)
+	if !ok {
+		t.Fatal("no info for a driver connection")
+	}
+	if info.ServerVersion != "5.5.8" || info.ProtocolVersion != 10 || info.ConnectionID != 165 {
+		t.Errorf("unexpected info %+v", info)
+	}
+	if info.CollationID != 33 || info.Collation != "utf8_general_ci" {
+		t.Errorf("unexpected collation %d %q", info.CollationID, info.Collation)
+	}
+	if info.Charset != "utf8mb4" || info.ConnectionCollation != "utf8mb4_general_ci" {
+		t.Errorf("unexpected connection collation %q %q", info.Charset, info.ConnectionCollation)
+	}
+	if info.ServerCapabilities != 0x800ff7df || clientFlag(info.Capabilities)&clientProtocol41 == 0 ||
+		clientFlag(info.Capabilities)&clientCompress != 0 {
+		t.Errorf("unexpected capabilities %x / %x", info.ServerCapabilities, info.Capabilities)
+	}
+
+	if _, ok := ConnInfo(conn); ok {
+		t.Error("info for another type")
+	}
+}
+
+func TestConnInfoSchema(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.schema = "dbname"
+	mc.sessionTrack = true
+	changed := byte(statusSessionStateChanged >> 8)
+	ok := []byte{iOK, 0, 0, 2, changed, 0, 0, 0, 8, sessionTrackSchema, 6, 5, 'o', 't', 'h', 'e', 'r'}
+	conn.queuedReplies = [][]byte{mockPacket(1, ok)}
+
+	if info, _ := ConnInfo(mc); info.Schema != "dbname" {
+		t.Errorf("expected schema %q, got %q", "dbname", info.Schema)
+	}
+	if _, err := mc.Exec("USE other", nil); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := ConnInfo(mc); info.Schema != "other" {
+		t.Errorf("expected schema %q, got %q", "other", info.Schema)
+	}
+}
+
+func TestTrackSchemaCapability(t *testing.T) {
+	for _, track := range []bool{false, true} {
+		conn, mc := newRWMockConn(1)
+		mc.flags |= clientSessionTrack
+		mc.cfg.trackSchema = track
+		if err := mc.writeHandshakeResponsePacket(nil, "mysql_native_password"); err != nil {
+			t.Fatal(err)
+		}
+		flags := clientFlag(binary.LittleEndian.Uint32(conn.written[4:]))
+		if negotiated := flags&clientSessionTrack != 0; negotiated != track {
+			t.Errorf("trackSchema=%v: CLIENT_SESSION_TRACK negotiated: %v", track, negotiated)
+		}
+	}
+}
+
+func TestCollationAuto(t *testing.T) {
+	// handshake of MySQL 5.5.8 with the default collation utf8_general_ci
+	handshake := []byte{72, 0, 0, 0, 10, 53, 46, 53, 46, 56, 0, 165, 0, 0, 0,
+		60, 70, 63, 58, 68, 104, 34, 97, 0, 223, 247, 33, 2, 0, 15, 128, 21, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 98, 120, 114, 47, 85, 75, 109, 99, 51, 77,
+		50, 64, 0, 109, 121, 115, 113, 108, 95, 110, 97, 116, 105, 118, 101, 95,
+		112, 97, 115, 115, 119, 111, 114, 100}
+	connect := func(charsets ...string) (*mockConn, *mysqlConn) {
+		conn, mc := newRWMockConn(0)
+		mc.cfg.Collation = "auto"
+		mc.cfg.charsets = charsets
+		conn.data = handshake
+		if _, _, err := mc.readHandshakePacket(); err != nil {
+			t.Fatal(err)
+		}
+		if err := mc.writeHandshakeResponsePacket(nil, "mysql_native_password"); err != nil {
+			t.Fatal(err)
+		}
+		return conn, mc
+	}
+
+	// the default collation of the server is negotiated in the handshake
+	conn, mc := connect("utf8", "latin1")
+	if collation := conn.written[12]; collation != 33 {
+		t.Errorf("expected collation 33 in the handshake, got %d", collation)
+	}
+	conn.written = nil
+	if err := mc.initSession(); err != nil {
+		t.Fatal(err)
+	}
+	if len(conn.written) != 0 {
+		t.Errorf("unexpected commands %q", conn.written)
+	}
+	if mc.info.Charset != "utf8" || mc.info.ConnectionCollation != "utf8_general_ci" {
+		t.Errorf("unexpected connection collation %q %q", mc.info.Charset, mc.info.ConnectionCollation)
+	}
+
+	// the server chooses the collation of utf8mb4
+	conn, mc = connect()
+	conn.written = nil
+	resp := mockPacket(1, []byte{1})
+	resp = append(resp, mockColumn(2, "@@collation_connection", fieldTypeVarString)...)
+	resp = append(resp, mockPacket(3, []byte{iEOF, 0, 0, 2, 0})...)
+	resp = append(resp, mockPacket(4, appendLengthEncodedString(nil, "utf8mb4_unicode_ci"))...)
+	resp = append(resp, mockPacket(5, []byte{iEOF, 0, 0, 2, 0})...)
+	conn.queuedReplies = [][]byte{mockPacket(1, []byte{iOK, 0, 0, 2, 0, 0, 0}), resp}
+	if err := mc.initSession(); err != nil {
+		t.Fatal(err)
+	}
+	if q := string(conn.written[5 : 4+getUint24(conn.written)]); q != "SET NAMES utf8mb4" {
+		t.Errorf("unexpected query %q", q)
+	}
+	if mc.info.Charset != "utf8mb4" || mc.info.ConnectionCollation != "utf8mb4_unicode_ci" {
+		t.Errorf("unexpected connection collation %q %q", mc.info.Charset, mc.info.ConnectionCollation)
+	}
+}
+
+func TestIsMariaDB(t *testing.T) {
+	for _, tc := range []struct {
+		version string
+		want    bool
+	}{
+		{"5.5.5-10.11.6-MariaDB", true},
+		{"5.5.5-10.1.48-MariaDB-0ubuntu0.18.04.1", true},
+		{"11.4.2-MariaDB-log", true},
+		{"10.0.38-MariaDB", false},
+		{"5.5.5-10.0.38-MariaDB", false},
+		{"8.0.36", false},
+		{"", false},
+	} {
+		info := ConnectionInfo{ServerVersion: tc.version}
+		if got := info.isMariaDB(10, 1); got != tc.want {
+			t.Errorf("%q: expected %v, got %v", tc.version, tc.want, got)
+		}
+	}
+}
@@ -8,7 +8,10 @@
 
 package mysql
 
-import "runtime"
+import (
+	"runtime"
+	"time"
+)
 
 const (
 	debug = false // for debugging. Set true only in development.
@@ -18,6 +21,7 @@ const (
 	minProtocolVersion      = 10
 	maxPacketSize           = 1<<24 - 1
 	timeFormat              = "2006-01-02 15:04:05.999999"
+	resetPingTimeout        = 1 * time.Second // See Config.resetWithPing
 
 	// Connection attributes
 	// See https://dev.mysql.com/doc/refman/8.0/en/performance-schema-connection-attribute-tables.html#performance-schema-connection-attributes-available
@@ -71,6 +75,19 @@ const (
 	clientCanHandleExpiredPasswords
 	clientSessionTrack
 	clientDeprecateEOF
+	clientOptionalResultsetMetadata
+	clientZstdCompressionAlgorithm
+	clientQueryAttributes
+)
+
+// MariaDB extended capability flags. They are exchanged in reserved bytes of
+// the handshake if the server does not set clientLongPassword (CLIENT_MYSQL).
+// https://mariadb.com/kb/en/connection/#capabilities
+type mariadbFlag uint32
+
+const (
+	mariadbClientStmtBulkOperations mariadbFlag = 1 << 2 // MARIADB_CLIENT_STMT_BULK_OPERATIONS
+	mariadbClientBulkUnitResults    mariadbFlag = 1 << 5 // MARIADB_CLIENT_BULK_UNIT_RESULTS
 )
 
 const (
@@ -102,6 +119,21 @@ const (
 	comStmtReset
 	comSetOption
 	comStmtFetch
+	comDaemon
+	comBinlogDumpGTID
+	comResetConnection
+)
+
+// MariaDB COM_STMT_BULK_EXECUTE
+// https://mariadb.com/kb/en/com_stmt_bulk_execute/
+const (
+	comStmtBulkExecute byte = 0xfa
+
+	bulkFlagSendUnitResults uint16 = 64  // return the generated id and affected rows of each row
+	bulkFlagSendTypes       uint16 = 128 // parameter types are sent
+
+	bulkIndicatorNone byte = 0 // a value follows
+	bulkIndicatorNull byte = 1
 )
 
 // https://dev.mysql.com/doc/internals/en/com-query-response.html#packet-Protocol::ColumnType
@@ -185,6 +217,15 @@ const (
 	statusSessionStateChanged
 )
 
+// Types of the session state changes in OK packets
+// https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_basic_ok_packet.html
+const (
+	sessionTrackSystemVariables byte = iota
+	sessionTrackSchema
+	sessionTrackStateChange
+	sessionTrackGTIDs
+)
+
 const (
 	cachingSha2PasswordRequestPublicKey          = 2
 	cachingSha2PasswordFastAuthSuccess           = 3
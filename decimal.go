@@ -0,0 +1,141 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// Decimal is an exact decimal number, e.g. the value of a DECIMAL column.
+// It keeps the textual representation sent by the server. The zero value is 0.
+//
+// Decimal implements the sql.Scanner and driver.Valuer interfaces. It is
+// returned by ColumnTypeScanType for DECIMAL columns if parseDecimal=true.
+type Decimal struct {
+	s string
+}
+
+// NewDecimal parses a decimal number of the form [+-]digits[.digits].
+func NewDecimal(s string) (Decimal, error) {
+	if !isDecimal(s) {
+		return Decimal{}, fmt.Errorf("invalid decimal: %q", s)
+	}
+	return Decimal{s: s}, nil
+}
+
+func isDecimal(s string) bool {
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	digits, dot := 0, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case '0' <= c && c <= '9':
+			digits++
+		case c == '.' && !dot:
+			dot = true
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+// String returns the decimal number as sent by the server.
+func (d Decimal) String() string {
+	if d.s == "" {
+		return "0"
+	}
+	return d.s
+}
+
+// BigRat returns the exact value of d.
+func (d Decimal) BigRat() *big.Rat {
+	r, ok := new(big.Rat).SetString(d.String())
+	if !ok {
+		// unreachable, d.s is validated by NewDecimal
+		panic("mysql: invalid decimal " + d.s)
+	}
+	return r
+}
+
+// Float64 returns the nearest float64 value of d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// Cmp compares d and e and returns -1, 0 or +1 if d is less than, equal to or
+// greater than e.
+func (d Decimal) Cmp(e Decimal) int {
+	return d.BigRat().Cmp(e.BigRat())
+}
+
+// Scan implements the sql.Scanner interface.
+func (d *Decimal) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case uint64:
+		s = strconv.FormatUint(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return errors.New("mysql: can not scan NULL into Decimal, use NullDecimal")
+	default:
+		return fmt.Errorf("mysql: can not scan %T into Decimal", src)
+	}
+
+	dec, err := NewDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = dec
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// NullDecimal represents a Decimal that may be NULL.
+//
+// NullDecimal implements the sql.Scanner and driver.Valuer interfaces.
+type NullDecimal struct {
+	Decimal Decimal
+	Valid   bool // Valid is true if Decimal is not NULL
+}
+
+// Scan implements the sql.Scanner interface.
+func (nd *NullDecimal) Scan(src any) error {
+	if src == nil {
+		nd.Decimal, nd.Valid = Decimal{}, false
+		return nil
+	}
+	nd.Valid = true
+	return nd.Decimal.Scan(src)
+}
+
+// Value implements the driver.Valuer interface.
+func (nd NullDecimal) Value() (driver.Value, error) {
+	if !nd.Valid {
+		return nil, nil
+	}
+	return nd.Decimal.Value()
+}
@@ -0,0 +1,113 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestNewDecimal(t *testing.T) {
+	for _, s := range []string{"0", "-1.50", "+3", "12345678901234567890.123456789", ".5", "5."} {
+		d, err := NewDecimal(s)
+		if err != nil {
+			t.Errorf("%q: %v", s, err)
+		} else if d.String() != s {
+			t.Errorf("%q: got %q", s, d.String())
+		}
+	}
+	for _, s := range []string{"", "-", ".", "1.2.3", "1e5", "abc", "1 "} {
+		if _, err := NewDecimal(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestDecimalCmp(t *testing.T) {
+	tests := []struct {
+		a, b string
+		cmp  int
+	}{
+		{"1.10", "1.1", 0},
+		{"-0.01", "0", -1},
+		{"99999999999999999999.99", "99999999999999999999.98", 1},
+	}
+	for _, tt := range tests {
+		a, _ := NewDecimal(tt.a)
+		b, _ := NewDecimal(tt.b)
+		if cmp := a.Cmp(b); cmp != tt.cmp {
+			t.Errorf("%s cmp %s: expected %d, got %d", tt.a, tt.b, tt.cmp, cmp)
+		}
+	}
+
+	d, _ := NewDecimal("0.10")
+	if d.BigRat().Cmp(big.NewRat(1, 10)) != 0 {
+		t.Errorf("unexpected BigRat %v", d.BigRat())
+	}
+	if d.Float64() != 0.1 {
+		t.Errorf("unexpected Float64 %v", d.Float64())
+	}
+	if (Decimal{}).String() != "0" {
+		t.Errorf("zero value must be 0")
+	}
+}
+
+func TestDecimalScan(t *testing.T) {
+	var d Decimal
+	for src, expected := range map[any]string{
+		"12.50":         "12.50",
+		int64(-42):      "-42",
+		uint64(1 << 63): "9223372036854775808",
+		float64(0.25):   "0.25",
+	} {
+		if err := d.Scan(src); err != nil {
+			t.Errorf("%v: %v", src, err)
+		} else if d.String() != expected {
+			t.Errorf("%v: expected %s, got %s", src, expected, d)
+		}
+	}
+	if err := d.Scan([]byte("3.14")); err != nil || d.String() != "3.14" {
+		t.Errorf("unexpected result %s, %v", d, err)
+	}
+	if err := d.Scan(nil); err == nil {
+		t.Errorf("expected error for NULL")
+	}
+	if err := d.Scan("NaN"); err == nil {
+		t.Errorf("expected error for NaN")
+	}
+
+	var nd NullDecimal
+	if err := nd.Scan(nil); err != nil || nd.Valid {
+		t.Errorf("unexpected result %v, %v", nd, err)
+	}
+	if v, _ := nd.Value(); v != nil {
+		t.Errorf("expected nil value, got %v", v)
+	}
+	if err := nd.Scan([]byte("1.5")); err != nil || !nd.Valid || nd.Decimal.String() != "1.5" {
+		t.Errorf("unexpected result %v, %v", nd, err)
+	}
+	if v, _ := nd.Value(); v != "1.5" {
+		t.Errorf("expected value 1.5, got %v", v)
+	}
+}
+
+func TestDecimalScanType(t *testing.T) {
+	mf := mysqlField{fieldType: fieldTypeNewDecimal}
+	if mf.scanType() != scanTypeNullString {
+		t.Errorf("expected NullString, got %v", mf.scanType())
+	}
+	mf.parseDecimal = true
+	if mf.scanType() != scanTypeNullDec {
+		t.Errorf("expected NullDecimal, got %v", mf.scanType())
+	}
+	mf.flags |= flagNotNULL
+	if mf.scanType() != scanTypeDecimal {
+		t.Errorf("expected Decimal, got %v", mf.scanType())
+	}
+}
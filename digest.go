@@ -0,0 +1,198 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// QueryDigest returns the normalized form of a statement, similar to the
+// digests of the Performance Schema: string, number, hexadecimal and bit
+// literals are replaced by ?, lists of them by (...), comments are removed
+// and whitespace is collapsed. Statements which differ only in their
+// literals have the same digest, which identifies the statement without
+// revealing its data, e.g. in logs. If noBackslashEscapes is set, backslashes
+// are not escape characters in string literals, as with the sql_mode
+// NO_BACKSLASH_ESCAPES.
+func QueryDigest(query string, noBackslashEscapes bool) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	space := false // whitespace before the next token
+	emit := func(token string) {
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteString(token)
+	}
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case isSpace(c):
+			space = true
+			i++
+		case c == '#' || strings.HasPrefix(query[i:], "--") && (i+2 == len(query) || query[i+2] <= ' '):
+			space = true
+			i = skipLine(query, i)
+		case strings.HasPrefix(query[i:], "/*"):
+			space = true
+			if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+				i += 2 + end + 2
+			} else {
+				i = len(query)
+			}
+		case c == '\'' || c == '"':
+			emit("?")
+			i = skipQuoted(query, i, noBackslashEscapes)
+		case c == '`':
+			j := skipQuoted(query, i, noBackslashEscapes)
+			emit(query[i:j])
+			i = j
+		case isDigit(c) || c == '.' && i+1 < len(query) && isDigit(query[i+1]) && (i == 0 || !isIdentByte(query[i-1])):
+			emit("?")
+			for i++; i < len(query); i++ {
+				if c := query[i]; !isIdentByte(c) && c != '.' &&
+					!((c == '+' || c == '-') && (query[i-1] == 'e' || query[i-1] == 'E')) {
+					break
+				}
+			}
+		case isIdentByte(c):
+			j := i + 1
+			for j < len(query) && isIdentByte(query[j]) {
+				j++
+			}
+			// X'..', B'..', N'..' and charset introducers like _utf8mb4'..'
+			// are part of the literal
+			if j < len(query) && query[j] == '\'' && (j-i == 1 && strings.IndexByte("xXbBnN", c) >= 0 || c == '_') {
+				i = j
+				continue
+			}
+			emit(query[i:j])
+			i = j
+		default:
+			emit(query[i : i+1])
+			i++
+		}
+	}
+	return collapseLists(b.String())
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isIdentByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || isDigit(c) || c == '_' || c == '$' || c >= 0x80
+}
+
+// skipQuoted returns the index after the string literal or quoted identifier
+// starting at i. Backslashes escape the next byte in string literals unless
+// noBackslashEscapes is set.
+func skipQuoted(s string, i int, noBackslashEscapes bool) int {
+	q := s[i]
+	for i++; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if q != '`' && !noBackslashEscapes {
+				i++
+			}
+		case q:
+			if i+1 < len(s) && s[i+1] == q {
+				// doubled quote
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// collapseLists replaces lists of literals like (?, ?, ?) by (...) and
+// repeated lists like (...), (...) by a single one, so that e.g. IN lists and
+// multi-row INSERTs of different lengths have the same digest.
+func collapseLists(s string) string {
+	const list = "(...)"
+	buf := make([]byte, 0, len(s))
+	listEnd := -1 // end of the last list in buf
+	for i := 0; i < len(s); i++ {
+		if s[i] != '(' {
+			buf = append(buf, s[i])
+			continue
+		}
+		j, ok := i+1, false
+		for ; j < len(s); j++ {
+			if c := s[j]; c == '?' {
+				ok = true
+			} else if c != ' ' && (c != ',' || !ok) {
+				break
+			}
+		}
+		if !ok || j == len(s) || s[j] != ')' {
+			buf = append(buf, '(')
+			continue
+		}
+		if listEnd >= 0 && strings.TrimSpace(string(buf[listEnd:])) == "," {
+			// repeated list
+			buf = buf[:listEnd]
+		} else {
+			buf = append(buf, list...)
+			listEnd = len(buf)
+		}
+		i = j
+	}
+	return string(buf)
+}
+
+// logQueryError logs the digest of a statement which failed with err if
+// LogQueryDigestsOnError is set. Only the number and state of server errors
+// and the levels and codes of warnings are logged, since their messages may
+// contain literals, e.g. duplicate keys or truncated values.
+func (mc *mysqlConn) logQueryError(query string, err error) {
+	if err == nil || err == driver.ErrSkip || !mc.cfg.LogQueryDigestsOnError {
+		return
+	}
+	msg := err.Error()
+	var me *MySQLError
+	var warnings MySQLWarnings
+	var te *TruncationError
+	switch {
+	case errors.As(err, &me):
+		msg = fmt.Sprintf("Error %d", me.Number)
+		if me.SQLState != [5]byte{} {
+			msg += fmt.Sprintf(" (%s)", me.SQLState)
+		}
+	case errors.As(err, &warnings):
+		msg = warningCodes(warnings)
+	case errors.As(err, &te):
+		warnings = make(MySQLWarnings, len(te.Columns))
+		for i, c := range te.Columns {
+			warnings[i] = c.MySQLWarning
+		}
+		msg = warningCodes(warnings)
+	}
+	mc.log("statement failed: ", msg, ": ", QueryDigest(query, mc.status&statusNoBackslashEscapes != 0))
+}
+
+// warningCodes returns the levels and codes of warnings without their
+// messages, e.g. "Warning 1265, Warning 1366".
+func warningCodes(warnings MySQLWarnings) string {
+	var sb strings.Builder
+	for i, w := range warnings {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%s %d", w.Level, w.Code)
+	}
+	return sb.String()
+}
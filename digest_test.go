@@ -0,0 +1,91 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestQueryDigest(t *testing.T) {
+	for _, tc := range []struct {
+		query, expected string
+	}{
+		{"SELECT * FROM t WHERE id = 42", "SELECT * FROM t WHERE id = ?"},
+		{"select  a,\n\tb from `t 1` where name='O''Brien' and x=\"a\\\"b\"", "select a, b from `t 1` where name=? and x=?"},
+		{"SELECT 1.5e-3, -.5, 0x1F, X'4F', b'101', _utf8mb4'abc', N'x'", "SELECT ?, -?, ?, ?, ?, ?, ?"},
+		{"SELECT t1.c2 FROM t1 /* secret 'x' */ WHERE a = 1 -- 'y'\nAND b = 2 # z", "SELECT t1.c2 FROM t1 WHERE a = ? AND b = ?"},
+		{"SELECT * FROM t WHERE id IN (1, 2, 3) AND k IN (?)", "SELECT * FROM t WHERE id IN (...) AND k IN (...)"},
+		{"INSERT INTO t (a, b) VALUES (1, 'x'), (2, 'y'),(3,'z')", "INSERT INTO t (a, b) VALUES (...)"},
+		{"SELECT COUNT(*), f(1) FROM t", "SELECT COUNT(*), f(...) FROM t"},
+		{"SELECT 'unterminated", "SELECT ?"},
+	} {
+		if actual := QueryDigest(tc.query, false); actual != tc.expected {
+			t.Errorf("QueryDigest(%q):\nexpected %q\ngot      %q", tc.query, tc.expected, actual)
+		}
+	}
+
+	// the backslash is a literal character with NO_BACKSLASH_ESCAPES
+	query := `SELECT 'C:\' AS p, 'secret' AS s`
+	if actual, expected := QueryDigest(query, true), "SELECT ? AS p, ? AS s"; actual != expected {
+		t.Errorf("QueryDigest(%q, true):\nexpected %q\ngot      %q", query, expected, actual)
+	}
+}
+
+func TestLogQueryDigestsOnError(t *testing.T) {
+	var buf bytes.Buffer
+	conn, mc := newRWMockConn(0)
+	mc.cfg.Logger = log.New(&buf, "", 0)
+	mc.cfg.LogQueryDigestsOnError = true
+	conn.queuedReplies = [][]byte{
+		mockPacket(1, append([]byte{iERR, 0x26, 0x04, '#', '2', '3', '0', '0', '0'}, "Duplicate entry 'alice@example.com' for key 'email'"...)),
+	}
+
+	query := "INSERT INTO users (email) VALUES ('alice@example.com')"
+	if _, err := mc.ExecContext(context.Background(), query, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	logged := buf.String()
+	if !strings.Contains(logged, "statement failed: Error 1062 (23000): INSERT INTO users (email) VALUES (...)") {
+		t.Errorf("unexpected log %q", logged)
+	}
+	if strings.Contains(logged, "alice") {
+		t.Errorf("literal logged: %q", logged)
+	}
+
+	// warnings returned as errors are logged without their messages
+	buf.Reset()
+	mc.cfg.strict = true
+	conn.queuedReplies = [][]byte{
+		mockPacket(1, []byte{iOK, 1, 0, 2, 0, 1, 0}),
+		mockShowWarnings(MySQLWarning{"Warning", 1265, "Data truncated for column 'email' at row 1"}),
+	}
+	if _, err := mc.ExecContext(context.Background(), query, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	logged = buf.String()
+	if !strings.Contains(logged, "statement failed: Warning 1265: INSERT INTO users (email) VALUES (...)") {
+		t.Errorf("unexpected log %q", logged)
+	}
+	mc.cfg.strict = false
+
+	// statements which fall back to a prepared statement did not fail
+	buf.Reset()
+	mc.cfg.InterpolateParams = false
+	if _, err := mc.ExecContext(context.Background(), "SELECT ?", []driver.NamedValue{{Ordinal: 1, Value: int64(1)}}); err != driver.ErrSkip {
+		t.Fatalf("expected driver.ErrSkip, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("unexpected log %q", buf.String())
+	}
+}
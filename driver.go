@@ -21,7 +21,6 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"net"
-	"sync"
 )
 
 // MySQLDriver is exported to make the driver directly accessible.
@@ -38,30 +37,28 @@ type DialFunc func(addr string) (net.Conn, error)
 // Custom dial functions must be registered with RegisterDialContext
 type DialContextFunc func(ctx context.Context, addr string) (net.Conn, error)
 
-var (
-	dialsLock sync.RWMutex
-	dials     map[string]DialContextFunc
-)
+var dials registry[DialContextFunc]
 
 // RegisterDialContext registers a custom dial function. It can then be used by the
 // network address mynet(addr), where mynet is the registered new network.
 // The current context for the connection and its address is passed to the dial function.
 func RegisterDialContext(net string, dial DialContextFunc) {
-	dialsLock.Lock()
-	defer dialsLock.Unlock()
-	if dials == nil {
-		dials = make(map[string]DialContextFunc)
-	}
-	dials[net] = dial
+	dials.set(net, dial)
 }
 
 // DeregisterDialContext removes the custom dial function registered with the given net.
 func DeregisterDialContext(net string) {
-	dialsLock.Lock()
-	defer dialsLock.Unlock()
-	if dials != nil {
-		delete(dials, net)
-	}
+	dials.delete(net)
+}
+
+// ListDials returns the sorted networks of the registered dial functions.
+func ListDials() []string {
+	return dials.names()
+}
+
+// ResetDials removes all registered dial functions.
+func ResetDials() {
+	dials.reset()
 }
 
 // RegisterDial registers a custom dial function. It can then be used by the
@@ -79,7 +76,7 @@ func RegisterDial(network string, dial DialFunc) {
 // See https://github.com/go-sql-driver/mysql#dsn-data-source-name for how
 // the DSN string is formatted
 func (d MySQLDriver) Open(dsn string) (driver.Conn, error) {
-	cfg, err := ParseDSN(dsn)
+	cfg, err := ParseDSNCached(dsn)
 	if err != nil {
 		return nil, err
 	}
@@ -98,6 +95,7 @@ func init() {
 }
 
 // NewConnector returns new driver.Connector.
+// The returned connector implements Connector.
 func NewConnector(cfg *Config) (driver.Connector, error) {
 	cfg = cfg.Clone()
 	// normalize the contents of cfg so calls to NewConnector have the same
@@ -110,7 +108,7 @@ func NewConnector(cfg *Config) (driver.Connector, error) {
 
 // OpenConnector implements driver.DriverContext.
 func (d MySQLDriver) OpenConnector(dsn string) (driver.Connector, error) {
-	cfg, err := ParseDSN(dsn)
+	cfg, err := ParseDSNCached(dsn)
 	if err != nil {
 		return nil, err
 	}
@@ -16,6 +16,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -2415,8 +2416,8 @@ var (
 
 // Ensure that all the driver interfaces are implemented
 var (
-	// _ driver.RowsColumnTypeLength        = &binaryRows{}
-	// _ driver.RowsColumnTypeLength        = &textRows{}
+	_ driver.RowsColumnTypeLength           = &binaryRows{}
+	_ driver.RowsColumnTypeLength           = &textRows{}
 	_ driver.RowsColumnTypeDatabaseTypeName = &binaryRows{}
 	_ driver.RowsColumnTypeDatabaseTypeName = &textRows{}
 	_ driver.RowsColumnTypeNullable         = &binaryRows{}
@@ -2686,7 +2687,7 @@ func TestPingContext(t *testing.T) {
 	runTestsParallel(t, dsn, func(dbt *DBTest, _ string) {
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
-		if err := dbt.db.PingContext(ctx); err != context.Canceled {
+		if err := dbt.db.PingContext(ctx); !errors.Is(err, context.Canceled) {
 			dbt.Errorf("expected context.Canceled, got %v", err)
 		}
 	})
@@ -2702,7 +2703,7 @@ func TestContextCancelExec(t *testing.T) {
 
 		// This query will be canceled.
 		startTime := time.Now()
-		if _, err := dbt.db.ExecContext(ctx, "INSERT INTO "+tbl+" VALUES (SLEEP(1))"); err != context.Canceled {
+		if _, err := dbt.db.ExecContext(ctx, "INSERT INTO "+tbl+" VALUES (SLEEP(1))"); !errors.Is(err, context.Canceled) {
 			dbt.Errorf("expected context.Canceled, got %v", err)
 		}
 		if d := time.Since(startTime); d > 500*time.Millisecond {
@@ -2748,7 +2749,7 @@ func TestContextCancelQuery(t *testing.T) {
 
 		// This query will be canceled.
 		startTime := time.Now()
-		if _, err := dbt.db.QueryContext(ctx, "INSERT INTO "+tbl+" VALUES (SLEEP(1))"); err != context.Canceled {
+		if _, err := dbt.db.QueryContext(ctx, "INSERT INTO "+tbl+" VALUES (SLEEP(1))"); !errors.Is(err, context.Canceled) {
 			dbt.Errorf("expected context.Canceled, got %v", err)
 		}
 		if d := time.Since(startTime); d > 500*time.Millisecond {
@@ -2768,7 +2769,7 @@ func TestContextCancelQuery(t *testing.T) {
 		}
 
 		// Context is already canceled, so error should come before execution.
-		if _, err := dbt.db.QueryContext(ctx, "INSERT INTO "+tbl+" VALUES (1)"); err != context.Canceled {
+		if _, err := dbt.db.QueryContext(ctx, "INSERT INTO "+tbl+" VALUES (1)"); !errors.Is(err, context.Canceled) {
 			dbt.Errorf("expected context.Canceled, got %v", err)
 		}
 
@@ -2809,7 +2810,7 @@ func TestContextCancelQueryRow(t *testing.T) {
 		if rows.Next() {
 			dbt.Errorf("expected end, but not")
 		}
-		if err := rows.Err(); err != context.Canceled {
+		if err := rows.Err(); !errors.Is(err, context.Canceled) {
 			dbt.Errorf("expected context.Canceled, got %v", err)
 		}
 	})
@@ -2819,7 +2820,7 @@ func TestContextCancelPrepare(t *testing.T) {
 	runTestsParallel(t, dsn, func(dbt *DBTest, _ string) {
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
-		if _, err := dbt.db.PrepareContext(ctx, "SELECT 1"); err != context.Canceled {
+		if _, err := dbt.db.PrepareContext(ctx, "SELECT 1"); !errors.Is(err, context.Canceled) {
 			dbt.Errorf("expected context.Canceled, got %v", err)
 		}
 	})
@@ -2839,7 +2840,7 @@ func TestContextCancelStmtExec(t *testing.T) {
 
 		// This query will be canceled.
 		startTime := time.Now()
-		if _, err := stmt.ExecContext(ctx); err != context.Canceled {
+		if _, err := stmt.ExecContext(ctx); !errors.Is(err, context.Canceled) {
 			dbt.Errorf("expected context.Canceled, got %v", err)
 		}
 		if d := time.Since(startTime); d > 500*time.Millisecond {
@@ -2874,7 +2875,7 @@ func TestContextCancelStmtQuery(t *testing.T) {
 
 		// This query will be canceled.
 		startTime := time.Now()
-		if _, err := stmt.QueryContext(ctx); err != context.Canceled {
+		if _, err := stmt.QueryContext(ctx); !errors.Is(err, context.Canceled) {
 			dbt.Errorf("expected context.Canceled, got %v", err)
 		}
 		if d := time.Since(startTime); d > 500*time.Millisecond {
@@ -2918,7 +2919,7 @@ func TestContextCancelBegin(t *testing.T) {
 
 		// This query will be canceled.
 		startTime := time.Now()
-		if _, err := tx.ExecContext(ctx, "INSERT INTO "+tbl+" VALUES (SLEEP(1))"); err != context.Canceled {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO "+tbl+" VALUES (SLEEP(1))"); !errors.Is(err, context.Canceled) {
 			dbt.Errorf("expected context.Canceled, got %v", err)
 		}
 		if d := time.Since(startTime); d > 500*time.Millisecond {
@@ -2926,12 +2927,12 @@ func TestContextCancelBegin(t *testing.T) {
 		}
 
 		// Transaction is canceled, so expect an error.
-		switch err := tx.Commit(); err {
-		case sql.ErrTxDone:
+		switch err := tx.Commit(); {
+		case err == sql.ErrTxDone:
 			// because the transaction has already been rollbacked.
 			// the database/sql package watches ctx
 			// and rollbacks when ctx is canceled.
-		case context.Canceled:
+		case errors.Is(err, context.Canceled):
 			// the database/sql package rollbacks on another goroutine,
 			// so the transaction may not be rollbacked depending on goroutine scheduling.
 		default:
@@ -2949,7 +2950,7 @@ func TestContextCancelBegin(t *testing.T) {
 		}
 
 		// cannot begin a transaction (on a different conn) with a canceled context
-		if _, err := dbt.db.BeginTx(ctx, nil); err != context.Canceled {
+		if _, err := dbt.db.BeginTx(ctx, nil); !errors.Is(err, context.Canceled) {
 			dbt.Errorf("expected context.Canceled, got %v", err)
 		}
 	})
@@ -3079,57 +3080,58 @@ func TestRowsColumnTypes(t *testing.T) {
 		databaseTypeName string // actual type used by MySQL
 		scanType         reflect.Type
 		nullable         bool
+		length           int64 // 0 if not ok
 		precision        int64 // 0 if not ok
 		scale            int64
 		valuesIn         [3]string
 		valuesOut        [3]any
 	}{
-		{"bit8null", "BIT(8)", "BIT", scanTypeBytes, true, 0, 0, [3]string{"0x0", "NULL", "0x42"}, [3]any{bx0, bNULL, bx42}},
-		{"boolnull", "BOOL", "TINYINT", scanTypeNullInt, true, 0, 0, [3]string{"NULL", "true", "0"}, [3]any{niNULL, ni1, ni0}},
-		{"bool", "BOOL NOT NULL", "TINYINT", scanTypeInt8, false, 0, 0, [3]string{"1", "0", "FALSE"}, [3]any{int8(1), int8(0), int8(0)}},
-		{"intnull", "INTEGER", "INT", scanTypeNullInt, true, 0, 0, [3]string{"0", "NULL", "42"}, [3]any{ni0, niNULL, ni42}},
-		{"smallint", "SMALLINT NOT NULL", "SMALLINT", scanTypeInt16, false, 0, 0, [3]string{"0", "-32768", "32767"}, [3]any{int16(0), int16(-32768), int16(32767)}},
-		{"smallintnull", "SMALLINT", "SMALLINT", scanTypeNullInt, true, 0, 0, [3]string{"0", "NULL", "42"}, [3]any{ni0, niNULL, ni42}},
-		{"int3null", "INT(3)", "INT", scanTypeNullInt, true, 0, 0, [3]string{"0", "NULL", "42"}, [3]any{ni0, niNULL, ni42}},
-		{"int7", "INT(7) NOT NULL", "INT", scanTypeInt32, false, 0, 0, [3]string{"0", "-1337", "42"}, [3]any{int32(0), int32(-1337), int32(42)}},
-		{"mediumintnull", "MEDIUMINT", "MEDIUMINT", scanTypeNullInt, true, 0, 0, [3]string{"0", "42", "NULL"}, [3]any{ni0, ni42, niNULL}},
-		{"bigint", "BIGINT NOT NULL", "BIGINT", scanTypeInt64, false, 0, 0, [3]string{"0", "65535", "-42"}, [3]any{int64(0), int64(65535), int64(-42)}},
-		{"bigintnull", "BIGINT", "BIGINT", scanTypeNullInt, true, 0, 0, [3]string{"NULL", "1", "42"}, [3]any{niNULL, ni1, ni42}},
-		{"tinyuint", "TINYINT UNSIGNED NOT NULL", "UNSIGNED TINYINT", scanTypeUint8, false, 0, 0, [3]string{"0", "255", "42"}, [3]any{uint8(0), uint8(255), uint8(42)}},
-		{"smalluint", "SMALLINT UNSIGNED NOT NULL", "UNSIGNED SMALLINT", scanTypeUint16, false, 0, 0, [3]string{"0", "65535", "42"}, [3]any{uint16(0), uint16(65535), uint16(42)}},
-		{"biguint", "BIGINT UNSIGNED NOT NULL", "UNSIGNED BIGINT", scanTypeUint64, false, 0, 0, [3]string{"0", "65535", "42"}, [3]any{uint64(0), uint64(65535), uint64(42)}},
-		{"mediumuint", "MEDIUMINT UNSIGNED NOT NULL", "UNSIGNED MEDIUMINT", scanTypeUint32, false, 0, 0, [3]string{"0", "16777215", "42"}, [3]any{uint32(0), uint32(16777215), uint32(42)}},
-		{"uint13", "INT(13) UNSIGNED NOT NULL", "UNSIGNED INT", scanTypeUint32, false, 0, 0, [3]string{"0", "1337", "42"}, [3]any{uint32(0), uint32(1337), uint32(42)}},
-		{"float", "FLOAT NOT NULL", "FLOAT", scanTypeFloat32, false, math.MaxInt64, math.MaxInt64, [3]string{"0", "42", "13.37"}, [3]any{float32(0), float32(42), float32(13.37)}},
-		{"floatnull", "FLOAT", "FLOAT", scanTypeNullFloat, true, math.MaxInt64, math.MaxInt64, [3]string{"0", "NULL", "13.37"}, [3]any{nf0, nfNULL, nf1337}},
-		{"float74null", "FLOAT(7,4)", "FLOAT", scanTypeNullFloat, true, math.MaxInt64, 4, [3]string{"0", "NULL", "13.37"}, [3]any{nf0, nfNULL, nf1337}},
-		{"double", "DOUBLE NOT NULL", "DOUBLE", scanTypeFloat64, false, math.MaxInt64, math.MaxInt64, [3]string{"0", "42", "13.37"}, [3]any{float64(0), float64(42), float64(13.37)}},
-		{"doublenull", "DOUBLE", "DOUBLE", scanTypeNullFloat, true, math.MaxInt64, math.MaxInt64, [3]string{"0", "NULL", "13.37"}, [3]any{nf0, nfNULL, nf1337}},
-		{"decimal1", "DECIMAL(10,6) NOT NULL", "DECIMAL", scanTypeString, false, 10, 6, [3]string{"0", "13.37", "1234.123456"}, [3]any{"0.000000", "13.370000", "1234.123456"}},
-		{"decimal1null", "DECIMAL(10,6)", "DECIMAL", scanTypeNullString, true, 10, 6, [3]string{"0", "NULL", "1234.123456"}, [3]any{ns("0.000000"), nsNULL, ns("1234.123456")}},
-		{"decimal2", "DECIMAL(8,4) NOT NULL", "DECIMAL", scanTypeString, false, 8, 4, [3]string{"0", "13.37", "1234.123456"}, [3]any{"0.0000", "13.3700", "1234.1235"}},
-		{"decimal2null", "DECIMAL(8,4)", "DECIMAL", scanTypeNullString, true, 8, 4, [3]string{"0", "NULL", "1234.123456"}, [3]any{ns("0.0000"), nsNULL, ns("1234.1235")}},
-		{"decimal3", "DECIMAL(5,0) NOT NULL", "DECIMAL", scanTypeString, false, 5, 0, [3]string{"0", "13.37", "-12345.123456"}, [3]any{"0", "13", "-12345"}},
-		{"decimal3null", "DECIMAL(5,0)", "DECIMAL", scanTypeNullString, true, 5, 0, [3]string{"0", "NULL", "-12345.123456"}, [3]any{ns0, nsNULL, ns("-12345")}},
-		{"char25null", "CHAR(25)", "CHAR", scanTypeNullString, true, 0, 0, [3]string{"0", "NULL", "'Test'"}, [3]any{ns0, nsNULL, nsTest}},
-		{"varchar42", "VARCHAR(42) NOT NULL", "VARCHAR", scanTypeString, false, 0, 0, [3]string{"0", "'Test'", "42"}, [3]any{"0", "Test", "42"}},
-		{"binary4null", "BINARY(4)", "BINARY", scanTypeBytes, true, 0, 0, [3]string{"0", "NULL", "'Test'"}, [3]any{b0pad4, bNULL, bTest}},
-		{"varbinary42", "VARBINARY(42) NOT NULL", "VARBINARY", scanTypeBytes, false, 0, 0, [3]string{"0", "'Test'", "42"}, [3]any{b0, bTest, b42}},
-		{"tinyblobnull", "TINYBLOB", "BLOB", scanTypeBytes, true, 0, 0, [3]string{"0", "NULL", "'Test'"}, [3]any{b0, bNULL, bTest}},
-		{"tinytextnull", "TINYTEXT", "TEXT", scanTypeNullString, true, 0, 0, [3]string{"0", "NULL", "'Test'"}, [3]any{ns0, nsNULL, nsTest}},
-		{"blobnull", "BLOB", "BLOB", scanTypeBytes, true, 0, 0, [3]string{"0", "NULL", "'Test'"}, [3]any{b0, bNULL, bTest}},
-		{"textnull", "TEXT", "TEXT", scanTypeNullString, true, 0, 0, [3]string{"0", "NULL", "'Test'"}, [3]any{ns0, nsNULL, nsTest}},
-		{"mediumblob", "MEDIUMBLOB NOT NULL", "BLOB", scanTypeBytes, false, 0, 0, [3]string{"0", "'Test'", "42"}, [3]any{b0, bTest, b42}},
-		{"mediumtext", "MEDIUMTEXT NOT NULL", "TEXT", scanTypeString, false, 0, 0, [3]string{"0", "'Test'", "42"}, [3]any{"0", "Test", "42"}},
-		{"longblob", "LONGBLOB NOT NULL", "BLOB", scanTypeBytes, false, 0, 0, [3]string{"0", "'Test'", "42"}, [3]any{b0, bTest, b42}},
-		{"longtext", "LONGTEXT NOT NULL", "TEXT", scanTypeString, false, 0, 0, [3]string{"0", "'Test'", "42"}, [3]any{"0", "Test", "42"}},
-		{"datetime", "DATETIME", "DATETIME", scanTypeNullTime, true, 0, 0, [3]string{"'2006-01-02 15:04:05'", "'2006-01-02 15:04:05.1'", "'2006-01-02 15:04:05.111111'"}, [3]any{nt0, nt0, nt0}},
-		{"datetime2", "DATETIME(2)", "DATETIME", scanTypeNullTime, true, 2, 2, [3]string{"'2006-01-02 15:04:05'", "'2006-01-02 15:04:05.1'", "'2006-01-02 15:04:05.111111'"}, [3]any{nt0, nt1, nt2}},
-		{"datetime6", "DATETIME(6)", "DATETIME", scanTypeNullTime, true, 6, 6, [3]string{"'2006-01-02 15:04:05'", "'2006-01-02 15:04:05.1'", "'2006-01-02 15:04:05.111111'"}, [3]any{nt0, nt1, nt6}},
-		{"date", "DATE", "DATE", scanTypeNullTime, true, 0, 0, [3]string{"'2006-01-02'", "NULL", "'2006-03-04'"}, [3]any{nd1, ndNULL, nd2}},
-		{"year", "YEAR NOT NULL", "YEAR", scanTypeUint16, false, 0, 0, [3]string{"2006", "2000", "1994"}, [3]any{uint16(2006), uint16(2000), uint16(1994)}},
-		{"enum", "ENUM('', 'v1', 'v2')", "ENUM", scanTypeNullString, true, 0, 0, [3]string{"''", "'v1'", "'v2'"}, [3]any{ns(""), ns("v1"), ns("v2")}},
-		{"set", "set('', 'v1', 'v2')", "SET", scanTypeNullString, true, 0, 0, [3]string{"''", "'v1'", "'v1,v2'"}, [3]any{ns(""), ns("v1"), ns("v1,v2")}},
+		{"bit8null", "BIT(8)", "BIT", scanTypeBytes, true, 0, 0, 0, [3]string{"0x0", "NULL", "0x42"}, [3]any{bx0, bNULL, bx42}},
+		{"boolnull", "BOOL", "TINYINT", scanTypeNullInt, true, 0, 0, 0, [3]string{"NULL", "true", "0"}, [3]any{niNULL, ni1, ni0}},
+		{"bool", "BOOL NOT NULL", "TINYINT", scanTypeInt8, false, 0, 0, 0, [3]string{"1", "0", "FALSE"}, [3]any{int8(1), int8(0), int8(0)}},
+		{"intnull", "INTEGER", "INT", scanTypeNullInt, true, 0, 0, 0, [3]string{"0", "NULL", "42"}, [3]any{ni0, niNULL, ni42}},
+		{"smallint", "SMALLINT NOT NULL", "SMALLINT", scanTypeInt16, false, 0, 0, 0, [3]string{"0", "-32768", "32767"}, [3]any{int16(0), int16(-32768), int16(32767)}},
+		{"smallintnull", "SMALLINT", "SMALLINT", scanTypeNullInt, true, 0, 0, 0, [3]string{"0", "NULL", "42"}, [3]any{ni0, niNULL, ni42}},
+		{"int3null", "INT(3)", "INT", scanTypeNullInt, true, 0, 0, 0, [3]string{"0", "NULL", "42"}, [3]any{ni0, niNULL, ni42}},
+		{"int7", "INT(7) NOT NULL", "INT", scanTypeInt32, false, 0, 0, 0, [3]string{"0", "-1337", "42"}, [3]any{int32(0), int32(-1337), int32(42)}},
+		{"mediumintnull", "MEDIUMINT", "MEDIUMINT", scanTypeNullInt, true, 0, 0, 0, [3]string{"0", "42", "NULL"}, [3]any{ni0, ni42, niNULL}},
+		{"bigint", "BIGINT NOT NULL", "BIGINT", scanTypeInt64, false, 0, 0, 0, [3]string{"0", "65535", "-42"}, [3]any{int64(0), int64(65535), int64(-42)}},
+		{"bigintnull", "BIGINT", "BIGINT", scanTypeNullInt, true, 0, 0, 0, [3]string{"NULL", "1", "42"}, [3]any{niNULL, ni1, ni42}},
+		{"tinyuint", "TINYINT UNSIGNED NOT NULL", "UNSIGNED TINYINT", scanTypeUint8, false, 0, 0, 0, [3]string{"0", "255", "42"}, [3]any{uint8(0), uint8(255), uint8(42)}},
+		{"smalluint", "SMALLINT UNSIGNED NOT NULL", "UNSIGNED SMALLINT", scanTypeUint16, false, 0, 0, 0, [3]string{"0", "65535", "42"}, [3]any{uint16(0), uint16(65535), uint16(42)}},
+		{"biguint", "BIGINT UNSIGNED NOT NULL", "UNSIGNED BIGINT", scanTypeUint64, false, 0, 0, 0, [3]string{"0", "65535", "42"}, [3]any{uint64(0), uint64(65535), uint64(42)}},
+		{"mediumuint", "MEDIUMINT UNSIGNED NOT NULL", "UNSIGNED MEDIUMINT", scanTypeUint32, false, 0, 0, 0, [3]string{"0", "16777215", "42"}, [3]any{uint32(0), uint32(16777215), uint32(42)}},
+		{"uint13", "INT(13) UNSIGNED NOT NULL", "UNSIGNED INT", scanTypeUint32, false, 0, 0, 0, [3]string{"0", "1337", "42"}, [3]any{uint32(0), uint32(1337), uint32(42)}},
+		{"float", "FLOAT NOT NULL", "FLOAT", scanTypeFloat32, false, 0, math.MaxInt64, math.MaxInt64, [3]string{"0", "42", "13.37"}, [3]any{float32(0), float32(42), float32(13.37)}},
+		{"floatnull", "FLOAT", "FLOAT", scanTypeNullFloat, true, 0, math.MaxInt64, math.MaxInt64, [3]string{"0", "NULL", "13.37"}, [3]any{nf0, nfNULL, nf1337}},
+		{"float74null", "FLOAT(7,4)", "FLOAT", scanTypeNullFloat, true, 0, math.MaxInt64, 4, [3]string{"0", "NULL", "13.37"}, [3]any{nf0, nfNULL, nf1337}},
+		{"double", "DOUBLE NOT NULL", "DOUBLE", scanTypeFloat64, false, 0, math.MaxInt64, math.MaxInt64, [3]string{"0", "42", "13.37"}, [3]any{float64(0), float64(42), float64(13.37)}},
+		{"doublenull", "DOUBLE", "DOUBLE", scanTypeNullFloat, true, 0, math.MaxInt64, math.MaxInt64, [3]string{"0", "NULL", "13.37"}, [3]any{nf0, nfNULL, nf1337}},
+		{"decimal1", "DECIMAL(10,6) NOT NULL", "DECIMAL", scanTypeString, false, 0, 10, 6, [3]string{"0", "13.37", "1234.123456"}, [3]any{"0.000000", "13.370000", "1234.123456"}},
+		{"decimal1null", "DECIMAL(10,6)", "DECIMAL", scanTypeNullString, true, 0, 10, 6, [3]string{"0", "NULL", "1234.123456"}, [3]any{ns("0.000000"), nsNULL, ns("1234.123456")}},
+		{"decimal2", "DECIMAL(8,4) NOT NULL", "DECIMAL", scanTypeString, false, 0, 8, 4, [3]string{"0", "13.37", "1234.123456"}, [3]any{"0.0000", "13.3700", "1234.1235"}},
+		{"decimal2null", "DECIMAL(8,4)", "DECIMAL", scanTypeNullString, true, 0, 8, 4, [3]string{"0", "NULL", "1234.123456"}, [3]any{ns("0.0000"), nsNULL, ns("1234.1235")}},
+		{"decimal3", "DECIMAL(5,0) NOT NULL", "DECIMAL", scanTypeString, false, 0, 5, 0, [3]string{"0", "13.37", "-12345.123456"}, [3]any{"0", "13", "-12345"}},
+		{"decimal3null", "DECIMAL(5,0)", "DECIMAL", scanTypeNullString, true, 0, 5, 0, [3]string{"0", "NULL", "-12345.123456"}, [3]any{ns0, nsNULL, ns("-12345")}},
+		{"char25null", "CHAR(25)", "CHAR", scanTypeNullString, true, 25, 0, 0, [3]string{"0", "NULL", "'Test'"}, [3]any{ns0, nsNULL, nsTest}},
+		{"varchar42", "VARCHAR(42) NOT NULL", "VARCHAR", scanTypeString, false, 42, 0, 0, [3]string{"0", "'Test'", "42"}, [3]any{"0", "Test", "42"}},
+		{"binary4null", "BINARY(4)", "BINARY", scanTypeBytes, true, 4, 0, 0, [3]string{"0", "NULL", "'Test'"}, [3]any{b0pad4, bNULL, bTest}},
+		{"varbinary42", "VARBINARY(42) NOT NULL", "VARBINARY", scanTypeBytes, false, 42, 0, 0, [3]string{"0", "'Test'", "42"}, [3]any{b0, bTest, b42}},
+		{"tinyblobnull", "TINYBLOB", "BLOB", scanTypeBytes, true, 255, 0, 0, [3]string{"0", "NULL", "'Test'"}, [3]any{b0, bNULL, bTest}},
+		{"tinytextnull", "TINYTEXT", "TEXT", scanTypeNullString, true, 255, 0, 0, [3]string{"0", "NULL", "'Test'"}, [3]any{ns0, nsNULL, nsTest}},
+		{"blobnull", "BLOB", "BLOB", scanTypeBytes, true, 65535, 0, 0, [3]string{"0", "NULL", "'Test'"}, [3]any{b0, bNULL, bTest}},
+		{"textnull", "TEXT", "TEXT", scanTypeNullString, true, 65535, 0, 0, [3]string{"0", "NULL", "'Test'"}, [3]any{ns0, nsNULL, nsTest}},
+		{"mediumblob", "MEDIUMBLOB NOT NULL", "BLOB", scanTypeBytes, false, 16777215, 0, 0, [3]string{"0", "'Test'", "42"}, [3]any{b0, bTest, b42}},
+		{"mediumtext", "MEDIUMTEXT NOT NULL", "TEXT", scanTypeString, false, 16777215, 0, 0, [3]string{"0", "'Test'", "42"}, [3]any{"0", "Test", "42"}},
+		{"longblob", "LONGBLOB NOT NULL", "BLOB", scanTypeBytes, false, 4294967295, 0, 0, [3]string{"0", "'Test'", "42"}, [3]any{b0, bTest, b42}},
+		{"longtext", "LONGTEXT NOT NULL", "TEXT", scanTypeString, false, 1073741823, 0, 0, [3]string{"0", "'Test'", "42"}, [3]any{"0", "Test", "42"}},
+		{"datetime", "DATETIME", "DATETIME", scanTypeNullTime, true, 0, 0, 0, [3]string{"'2006-01-02 15:04:05'", "'2006-01-02 15:04:05.1'", "'2006-01-02 15:04:05.111111'"}, [3]any{nt0, nt0, nt0}},
+		{"datetime2", "DATETIME(2)", "DATETIME", scanTypeNullTime, true, 0, 2, 2, [3]string{"'2006-01-02 15:04:05'", "'2006-01-02 15:04:05.1'", "'2006-01-02 15:04:05.111111'"}, [3]any{nt0, nt1, nt2}},
+		{"datetime6", "DATETIME(6)", "DATETIME", scanTypeNullTime, true, 0, 6, 6, [3]string{"'2006-01-02 15:04:05'", "'2006-01-02 15:04:05.1'", "'2006-01-02 15:04:05.111111'"}, [3]any{nt0, nt1, nt6}},
+		{"date", "DATE", "DATE", scanTypeNullTime, true, 0, 0, 0, [3]string{"'2006-01-02'", "NULL", "'2006-03-04'"}, [3]any{nd1, ndNULL, nd2}},
+		{"year", "YEAR NOT NULL", "YEAR", scanTypeUint16, false, 0, 0, 0, [3]string{"2006", "2000", "1994"}, [3]any{uint16(2006), uint16(2000), uint16(1994)}},
+		{"enum", "ENUM('', 'v1', 'v2')", "ENUM", scanTypeNullString, true, 0, 0, 0, [3]string{"''", "'v1'", "'v2'"}, [3]any{ns(""), ns("v1"), ns("v2")}},
+		{"set", "set('', 'v1', 'v2')", "SET", scanTypeNullString, true, 0, 0, 0, [3]string{"''", "'v1'", "'v1,v2'"}, [3]any{ns(""), ns("v1"), ns("v1,v2")}},
 	}
 
 	schema := ""
@@ -3206,15 +3208,15 @@ func TestRowsColumnTypes(t *testing.T) {
 			}
 
 			// Length
-			// length, ok := tp.Length()
-			// if length != column.length {
-			// 	if !ok {
-			// 		t.Errorf("length not ok for column %q", name)
-			// 	} else {
-			// 		t.Errorf("length mismatch for column %q: %d != %d", name, length, column.length)
-			// 	}
-			// 	continue
-			// }
+			length, ok := tp.Length()
+			if length != column.length {
+				if !ok {
+					t.Errorf("length not ok for column %q", name)
+				} else {
+					t.Errorf("length mismatch for column %q: %d != %d", name, length, column.length)
+				}
+				continue
+			}
 
 			// Precision and Scale
 			precision, scale, ok := tp.DecimalSize()
@@ -3439,10 +3441,10 @@ func TestConnectorTimeoutsDuringOpen(t *testing.T) {
 	defer cancel()
 
 	_, err = db.ExecContext(ctx, "DO 1")
-	if err != context.DeadlineExceeded {
+	if !errors.Is(err, context.DeadlineExceeded) {
 		t.Fatalf("ExecContext should have timed out")
 	}
-	if hijack.connErr != context.DeadlineExceeded {
+	if !errors.Is(hijack.connErr, context.DeadlineExceeded) {
 		t.Fatalf("(*Connector).Connect should have timed out")
 	}
 }
@@ -3488,7 +3490,7 @@ func TestConnectorTimeoutsWatchCancel(t *testing.T) {
 	ctx, cancel = context.WithCancel(context.Background())
 	defer cancel()
 
-	if _, err := db.Conn(ctx); err != context.Canceled {
+	if _, err := db.Conn(ctx); !errors.Is(err, context.Canceled) {
 		t.Errorf("got %v, want context.Canceled", err)
 	}
 
@@ -3636,3 +3638,264 @@ func TestIssue1567(t *testing.T) {
 		}
 	})
 }
+
+func TestSpread(t *testing.T) {
+	runTests(t, dsn, func(dbt *DBTest) {
+		dbt.mustExec("CREATE TABLE test (id INT NOT NULL PRIMARY KEY)")
+		dbt.mustExec("INSERT INTO test VALUES (?), (?), (?), (?)", 1, 2, 3, 4)
+
+		res := dbt.mustExec("DELETE FROM test WHERE id IN (?) AND id > ?", Spread([]int{1, 2, 3}), 1)
+		if n, _ := res.RowsAffected(); n != 2 {
+			dbt.Errorf("expected 2 affected rows, got %d", n)
+		}
+
+		var count int
+		if err := dbt.db.QueryRow("SELECT COUNT(*) FROM test WHERE id IN (?)", Spread([]int{1, 4})).Scan(&count); err != nil {
+			dbt.Fatal(err)
+		}
+		if count != 2 {
+			dbt.Errorf("expected 2 rows, got %d", count)
+		}
+
+		if err := dbt.db.QueryRow("SELECT COUNT(*) FROM test WHERE id IN (?)", Spread([]int{})).Scan(&count); err != nil {
+			dbt.Fatal(err)
+		}
+		if count != 0 {
+			dbt.Errorf("expected no rows, got %d", count)
+		}
+	})
+}
+
+func TestXA(t *testing.T) {
+	runTests(t, dsn, func(dbt *DBTest) {
+		dbt.mustExec("CREATE TABLE test (value INT) ENGINE=InnoDB")
+		ctx := context.Background()
+		conn, err := dbt.db.Conn(ctx)
+		if err != nil {
+			dbt.Fatal(err)
+		}
+		defer conn.Close()
+
+		xid := XID{GlobalID: "go-mysql-test", BranchID: "1", FormatID: 1}
+		if err := XAStart(ctx, conn, xid); err != nil {
+			dbt.Fatal(err)
+		}
+		if _, err := conn.ExecContext(ctx, "INSERT INTO test VALUES (1)"); err != nil {
+			dbt.Fatal(err)
+		}
+		if err := XAEnd(ctx, conn, xid); err != nil {
+			dbt.Fatal(err)
+		}
+		if err := XAPrepare(ctx, conn, xid); err != nil {
+			dbt.Fatal(err)
+		}
+
+		xids, err := XARecover(ctx, conn)
+		if err != nil {
+			dbt.Fatal(err)
+		}
+		found := false
+		for _, x := range xids {
+			found = found || x == xid
+		}
+		if !found {
+			dbt.Errorf("expected %v in recovered XIDs %v", xid, xids)
+		}
+
+		if err := XACommit(ctx, conn, xid, false); err != nil {
+			dbt.Fatal(err)
+		}
+		var count int
+		if err := dbt.db.QueryRow("SELECT COUNT(*) FROM test").Scan(&count); err != nil {
+			dbt.Fatal(err)
+		}
+		if count != 1 {
+			dbt.Errorf("expected 1 row, got %d", count)
+		}
+	})
+}
+
+func TestPollQueryProgress(t *testing.T) {
+	runTests(t, dsn, func(dbt *DBTest) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		conn, err := dbt.db.Conn(ctx)
+		if err != nil {
+			dbt.Fatal(err)
+		}
+		defer conn.Close()
+
+		var connID uint64
+		if err := conn.QueryRowContext(ctx, "SELECT CONNECTION_ID()").Scan(&connID); err != nil {
+			dbt.Fatal(err)
+		}
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := conn.ExecContext(ctx, "DO SLEEP(1)")
+			done <- err
+		}()
+
+		pollCtx, stop := context.WithCancel(ctx)
+		var seen bool
+		err = PollQueryProgress(pollCtx, dbt.db, connID, 50*time.Millisecond, func(p QueryProgress) {
+			if strings.Contains(p.Info, "SLEEP") {
+				seen = true
+				stop()
+			}
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			dbt.Fatal(err)
+		}
+		if !seen {
+			dbt.Error("running query was not reported")
+		}
+		if err := <-done; err != nil {
+			dbt.Fatal(err)
+		}
+	})
+}
+
+func TestParseDecimal(t *testing.T) {
+	runTests(t, dsn+"&parseDecimal=true", func(dbt *DBTest) {
+		dbt.mustExec("CREATE TABLE test (a DECIMAL(30,4) NOT NULL, b DECIMAL(5,2))")
+		dbt.mustExec("INSERT INTO test VALUES (?, NULL)", "12345678901234567890.1234")
+
+		rows := dbt.mustQuery("SELECT a, b FROM test")
+		defer rows.Close()
+		types, err := rows.ColumnTypes()
+		if err != nil {
+			dbt.Fatal(err)
+		}
+		if st := types[0].ScanType(); st != scanTypeDecimal {
+			dbt.Errorf("expected Decimal scan type, got %v", st)
+		}
+		if st := types[1].ScanType(); st != scanTypeNullDec {
+			dbt.Errorf("expected NullDecimal scan type, got %v", st)
+		}
+
+		if !rows.Next() {
+			dbt.Fatal("no rows")
+		}
+		var a Decimal
+		var b NullDecimal
+		if err := rows.Scan(&a, &b); err != nil {
+			dbt.Fatal(err)
+		}
+		if a.String() != "12345678901234567890.1234" {
+			dbt.Errorf("unexpected value %s", a)
+		}
+		if b.Valid {
+			dbt.Errorf("expected NULL, got %s", b.Decimal)
+		}
+	})
+}
+
+func TestParseJSON(t *testing.T) {
+	runTests(t, dsn+"&parseJSON=true", func(dbt *DBTest) {
+		dbt.mustExec("CREATE TABLE test (id INT, doc JSON)")
+		doc := map[string]any{"tags": []string{"a", "b"}, "n": 1}
+
+		stmt, err := dbt.db.Prepare("INSERT INTO test VALUES (?, ?)")
+		if err != nil {
+			dbt.Fatal(err)
+		}
+		defer stmt.Close()
+		if _, err := stmt.Exec(1, doc); err != nil {
+			dbt.Fatal(err)
+		}
+		dbt.mustExec("INSERT INTO test VALUES (?, ?)", 2, doc)
+
+		rows := dbt.mustQuery("SELECT doc FROM test ORDER BY id")
+		defer rows.Close()
+		types, err := rows.ColumnTypes()
+		if err != nil {
+			dbt.Fatal(err)
+		}
+		if st := types[0].ScanType(); st != scanTypeJSON {
+			dbt.Errorf("expected json.RawMessage scan type, got %v", st)
+		}
+		for rows.Next() {
+			var raw json.RawMessage
+			if err := rows.Scan(&raw); err != nil {
+				dbt.Fatal(err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(raw, &got); err != nil {
+				dbt.Fatal(err)
+			}
+			if got["n"] != float64(1) || len(got["tags"].([]any)) != 2 {
+				dbt.Errorf("unexpected document %s", raw)
+			}
+		}
+	})
+}
+
+func TestVector(t *testing.T) {
+	runTests(t, dsn, func(dbt *DBTest) {
+		if _, err := dbt.db.Exec("CREATE TABLE test (id INT, v VECTOR(3))"); err != nil {
+			dbt.Skipf("VECTOR is not supported: %v", err)
+		}
+		want := Vector{1, 2.5, -3}
+		dbt.mustExec("INSERT INTO test VALUES (1, ?), (2, NULL)", want)
+
+		stmt, err := dbt.db.Prepare("SELECT v FROM test ORDER BY id")
+		if err != nil {
+			dbt.Fatal(err)
+		}
+		defer stmt.Close()
+		rows, err := stmt.Query()
+		if err != nil {
+			dbt.Fatal(err)
+		}
+		defer rows.Close()
+
+		var got []Vector
+		for rows.Next() {
+			var v Vector
+			if err := rows.Scan(&v); err != nil {
+				dbt.Fatal(err)
+			}
+			got = append(got, v)
+		}
+		if !reflect.DeepEqual(got, []Vector{want, nil}) {
+			dbt.Errorf("expected %v, got %v", []Vector{want, nil}, got)
+		}
+	})
+}
+
+func TestGeometry(t *testing.T) {
+	runTests(t, dsn, func(dbt *DBTest) {
+		dbt.mustExec("CREATE TABLE test (id INT, g GEOMETRY)")
+		g, err := ParseWKT("POLYGON((0 0,1 0,1 1,0 0))", 0)
+		if err != nil {
+			dbt.Fatal(err)
+		}
+		dbt.mustExec("INSERT INTO test VALUES (1, ?), (2, NULL)", g)
+
+		var wkt string
+		if err := dbt.db.QueryRow("SELECT ST_AsText(g) FROM test WHERE id = 1").Scan(&wkt); err != nil {
+			dbt.Fatal(err)
+		}
+		if wkt != "POLYGON((0 0,1 0,1 1,0 0))" {
+			dbt.Errorf("unexpected WKT %s", wkt)
+		}
+
+		rows := dbt.mustQuery("SELECT g FROM test ORDER BY id")
+		defer rows.Close()
+		var got []NullGeometry
+		for rows.Next() {
+			var ng NullGeometry
+			if err := rows.Scan(&ng); err != nil {
+				dbt.Fatal(err)
+			}
+			got = append(got, ng)
+		}
+		if len(got) != 2 || !got[0].Valid || got[1].Valid {
+			dbt.Fatalf("unexpected rows %+v", got)
+		}
+		if wkt, err := got[0].Geometry.WKT(); err != nil || wkt != "POLYGON((0 0,1 0,1 1,0 0))" {
+			dbt.Errorf("unexpected WKT %s, %v", wkt, err)
+		}
+	})
+}
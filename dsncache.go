@@ -0,0 +1,98 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"container/list"
+	"sync"
+)
+
+// dsnCacheSize is the number of parsed DSNs kept by ParseDSNCached.
+const dsnCacheSize = 64
+
+type dsnCacheEntry struct {
+	dsn string
+	cfg *Config // never modified nor returned, only clones of it
+}
+
+// dsnCache is a LRU cache of parsed configurations keyed by DSN.
+type dsnCache struct {
+	mu  sync.Mutex
+	ll  list.List // of *dsnCacheEntry, most recently used first
+	m   map[string]*list.Element
+	gen uint64 // incremented by purge
+}
+
+var parsedDSNs dsnCache
+
+func (c *dsnCache) get(dsn string) (cfg *Config, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.m[dsn]; ok {
+		c.ll.MoveToFront(e)
+		return e.Value.(*dsnCacheEntry).cfg, c.gen
+	}
+	return nil, c.gen
+}
+
+// add caches cfg unless the cache was purged since generation gen, as cfg
+// may have been parsed with a registered value which was changed since.
+func (c *dsnCache) add(dsn string, cfg *Config, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen {
+		return
+	}
+	if e, ok := c.m[dsn]; ok {
+		c.ll.MoveToFront(e)
+		e.Value.(*dsnCacheEntry).cfg = cfg
+		return
+	}
+	if c.m == nil {
+		c.m = make(map[string]*list.Element)
+	}
+	c.m[dsn] = c.ll.PushFront(&dsnCacheEntry{dsn: dsn, cfg: cfg})
+	if c.ll.Len() > dsnCacheSize {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.m, oldest.Value.(*dsnCacheEntry).dsn)
+	}
+}
+
+// purge removes all cached configurations. It is called when a registered
+// value which is resolved by ParseDSN changes, e.g. a TLS config.
+func (c *dsnCache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	c.m = nil
+	c.gen++
+}
+
+// ParseDSNCached is like ParseDSN, but keeps the configurations of the most
+// recently parsed DSNs, so that opening many pools with the same DSNs does not
+// parse them again. The returned Config is a copy which may be modified.
+//
+// The cache is cleared when a TLS config or server public key is registered
+// or deregistered. DSNs which can not be parsed are not cached, nor DSNs with
+// tlsCA, tlsCert or tlsKey, so that renewed files are read by new pools.
+func ParseDSNCached(dsn string) (*Config, error) {
+	cached, gen := parsedDSNs.get(dsn)
+	if cached != nil {
+		return cached.Clone(), nil
+	}
+	cfg, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.tlsCA == "" && cfg.tlsCert == "" && cfg.tlsKey == "" {
+		parsedDSNs.add(dsn, cfg.Clone(), gen)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,87 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"crypto/tls"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParseDSNCached(t *testing.T) {
+	parsedDSNs.purge()
+	defer parsedDSNs.purge()
+
+	const dsn = "user:pass@tcp(localhost:3306)/db?loc=Local&parseTime=true"
+	cfg1, err := ParseDSNCached(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg1.DBName = "modified"
+	cfg2, err := ParseDSNCached(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := ParseDSN(dsn)
+	if !reflect.DeepEqual(cfg2, want) {
+		t.Errorf("got %+v, want %+v", cfg2, want)
+	}
+	if cfg1 == cfg2 {
+		t.Error("cached config is shared")
+	}
+
+	if _, err := ParseDSNCached("invalid"); err == nil {
+		t.Error("expected error")
+	}
+	if _, ok := parsedDSNs.m["invalid"]; ok {
+		t.Error("invalid DSN is cached")
+	}
+}
+
+func TestParseDSNCachedEviction(t *testing.T) {
+	parsedDSNs.purge()
+	defer parsedDSNs.purge()
+
+	for i := 0; i <= dsnCacheSize; i++ {
+		if _, err := ParseDSNCached(fmt.Sprintf("/db%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := parsedDSNs.ll.Len(); n != dsnCacheSize {
+		t.Errorf("got %d cached configs, want %d", n, dsnCacheSize)
+	}
+	if _, ok := parsedDSNs.m["/db0"]; ok {
+		t.Error("least recently used DSN was not evicted")
+	}
+}
+
+func TestParseDSNCachedTLSConfig(t *testing.T) {
+	parsedDSNs.purge()
+	defer parsedDSNs.purge()
+	defer DeregisterTLSConfig("cached")
+
+	RegisterTLSConfig("cached", &tls.Config{ServerName: "first"})
+	cfg, err := ParseDSNCached("tcp(example.com:3306)/?tls=cached")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TLS.ServerName != "first" {
+		t.Fatalf("got ServerName %q", cfg.TLS.ServerName)
+	}
+
+	RegisterTLSConfig("cached", &tls.Config{ServerName: "second"})
+	cfg, err = ParseDSNCached("tcp(example.com:3306)/?tls=cached")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TLS.ServerName != "second" {
+		t.Errorf("got ServerName %q from stale cache", cfg.TLS.ServerName)
+	}
+}
@@ -0,0 +1,176 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// NullDuration represents a time.Duration, e.g. the value of a TIME column,
+// that may be NULL. It can scan TIME values with and without parseDuration.
+//
+// NullDuration implements the sql.Scanner and driver.Valuer interfaces.
+type NullDuration struct {
+	Duration time.Duration
+	Valid    bool // Valid is true if Duration is not NULL
+}
+
+// Scan implements the sql.Scanner interface.
+func (nd *NullDuration) Scan(src any) (err error) {
+	switch v := src.(type) {
+	case nil:
+		nd.Duration, nd.Valid = 0, false
+		return nil
+	case time.Duration:
+		nd.Duration = v
+	case []byte:
+		nd.Duration, err = parseTimeDuration(v)
+	case string:
+		nd.Duration, err = parseTimeDuration([]byte(v))
+	default:
+		err = fmt.Errorf("mysql: can not scan %T into NullDuration", src)
+	}
+	nd.Valid = err == nil
+	return err
+}
+
+// Value implements the driver.Valuer interface. The duration is sent in the
+// format of TIME values.
+func (nd NullDuration) Value() (driver.Value, error) {
+	if !nd.Valid {
+		return nil, nil
+	}
+	return string(appendTimeDuration(nil, nd.Duration)), nil
+}
+
+// parseTimeDuration parses a TIME value in the format [-]H+:MM:SS[.fraction].
+func parseTimeDuration(b []byte) (time.Duration, error) {
+	s := b
+	neg := len(s) > 0 && s[0] == '-'
+	if neg {
+		s = s[1:]
+	}
+
+	var hours int64
+	i := 0
+	for ; i < len(s) && s[i] != ':'; i++ {
+		v, err := bToi(s[i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid TIME value %q: %w", b, err)
+		}
+		hours = hours*10 + int64(v)
+	}
+	if i == 0 || len(s) < i+6 || s[i] != ':' || s[i+3] != ':' {
+		return 0, fmt.Errorf("invalid TIME value %q", b)
+	}
+	min, err := parseByte2Digits(s[i+1], s[i+2])
+	if err != nil {
+		return 0, fmt.Errorf("invalid TIME value %q: %w", b, err)
+	}
+	sec, err := parseByte2Digits(s[i+4], s[i+5])
+	if err != nil {
+		return 0, fmt.Errorf("invalid TIME value %q: %w", b, err)
+	}
+	d := time.Duration(hours)*time.Hour + time.Duration(min)*time.Minute + time.Duration(sec)*time.Second
+
+	if frac := s[i+6:]; len(frac) > 0 {
+		if frac[0] != '.' || len(frac) > 7 {
+			return 0, fmt.Errorf("invalid TIME value %q", b)
+		}
+		ns, err := parseByteNanoSec(frac[1:])
+		if err != nil {
+			return 0, fmt.Errorf("invalid TIME value %q: %w", b, err)
+		}
+		d += time.Duration(ns)
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// parseBinaryTimeDuration parses a TIME value of the binary protocol.
+func parseBinaryTimeDuration(src []byte) (time.Duration, error) {
+	switch len(src) {
+	case 0:
+		return 0, nil
+	case 8, 12:
+	default:
+		return 0, fmt.Errorf("invalid TIME packet length %d", len(src))
+	}
+	days := binary.LittleEndian.Uint32(src[1:5])
+	d := time.Duration(days)*24*time.Hour + time.Duration(src[5])*time.Hour +
+		time.Duration(src[6])*time.Minute + time.Duration(src[7])*time.Second
+	if len(src) == 12 {
+		d += time.Duration(binary.LittleEndian.Uint32(src[8:12])) * time.Microsecond
+	}
+	if src[0] == 1 {
+		d = -d
+	}
+	return d, nil
+}
+
+// appendTimeDuration appends d in the format of TIME values to buf. Fractions
+// of microseconds are truncated.
+func appendTimeDuration(buf []byte, d time.Duration) []byte {
+	u := uint64(d)
+	if d < 0 {
+		buf = append(buf, '-')
+		u = -u
+	}
+	u /= uint64(time.Microsecond)
+	micros, secs := u%1e6, u/1e6
+	hours, min, sec := secs/3600, secs/60%60, secs%60
+
+	if hours < 10 {
+		buf = append(buf, '0')
+	}
+	buf = strconv.AppendUint(buf, hours, 10)
+	buf = append(buf, ':', digits10[min], digits01[min], ':', digits10[sec], digits01[sec])
+	if micros > 0 {
+		var a [7]byte
+		frac := strconv.AppendUint(a[:0], 1e6+micros, 10) // leading 1 keeps the zeros
+		buf = append(buf, '.')
+		buf = append(buf, frac[1:]...)
+	}
+	return buf
+}
+
+// appendBinaryTimeDuration appends d as a TIME value of the binary protocol
+// to buf.
+func appendBinaryTimeDuration(buf []byte, d time.Duration) []byte {
+	if d == 0 {
+		return append(buf, 0)
+	}
+	var neg byte
+	u := uint64(d)
+	if d < 0 {
+		neg = 1
+		u = -u
+	}
+	u /= uint64(time.Microsecond)
+	micros, secs := u%1e6, u/1e6
+	days, hours, min, sec := secs/86400, secs/3600%24, secs/60%60, secs%60
+
+	if micros == 0 {
+		buf = append(buf, 8, neg)
+	} else {
+		buf = append(buf, 12, neg)
+	}
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(days))
+	buf = append(buf, byte(hours), byte(min), byte(sec))
+	if micros > 0 {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(micros))
+	}
+	return buf
+}
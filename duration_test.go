@@ -0,0 +1,94 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+var durationTests = []struct {
+	text string
+	d    time.Duration
+}{
+	{"00:00:00", 0},
+	{"12:34:56", 12*time.Hour + 34*time.Minute + 56*time.Second},
+	{"-01:00:00.5", -(time.Hour + 500*time.Millisecond)},
+	{"838:59:59.000001", 838*time.Hour + 59*time.Minute + 59*time.Second + time.Microsecond},
+	{"-100:00:00", -100 * time.Hour},
+}
+
+func TestTimeDuration(t *testing.T) {
+	for _, tt := range durationTests {
+		d, err := parseTimeDuration([]byte(tt.text))
+		if err != nil || d != tt.d {
+			t.Errorf("parseTimeDuration(%q) = %v, %v; want %v", tt.text, d, err, tt.d)
+		}
+		d, err = parseBinaryTimeDuration(appendBinaryTimeDuration(nil, tt.d)[1:])
+		if err != nil || d != tt.d {
+			t.Errorf("binary round trip of %v: got %v, %v", tt.d, d, err)
+		}
+	}
+	if got := string(appendTimeDuration(nil, -(time.Hour + 500*time.Millisecond))); got != "-01:00:00.500000" {
+		t.Errorf("unexpected TIME value %q", got)
+	}
+	for _, invalid := range []string{"", "12", "12:3:45", "12:34:56.1234567", "a:00:00"} {
+		if _, err := parseTimeDuration([]byte(invalid)); err == nil {
+			t.Errorf("%q: expected error", invalid)
+		}
+	}
+}
+
+func TestParseDurationRows(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.parseDuration = true
+
+	resp := mockPacket(1, []byte{1})
+	resp = append(resp, mockColumn(2, "t", fieldTypeTime)...)
+	resp = append(resp, mockPacket(3, []byte{iEOF, 0, 0, 2, 0})...)
+	resp = append(resp, mockPacket(4, appendLengthEncodedString(nil, "-100:00:00"))...)
+	resp = append(resp, mockPacket(5, []byte{iEOF, 0, 0, 2, 0})...)
+	conn.queuedReplies = [][]byte{resp}
+
+	rows, err := mc.Query("SELECT t FROM d", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := make([]driver.Value, 1)
+	if err := rows.Next(dest); err != nil {
+		t.Fatal(err)
+	}
+	if dest[0] != -100*time.Hour {
+		t.Errorf("expected -100h, got %#v", dest[0])
+	}
+	if typ := rows.(*textRows).ColumnTypeScanType(0); typ != scanTypeNullDur {
+		t.Errorf("unexpected scan type %v", typ)
+	}
+}
+
+func TestNullDuration(t *testing.T) {
+	var nd NullDuration
+	if err := nd.Scan([]byte("-01:00:00.5")); err != nil || !nd.Valid || nd.Duration != -(time.Hour+500*time.Millisecond) {
+		t.Errorf("unexpected %+v, %v", nd, err)
+	}
+	if v, err := nd.Value(); err != nil || v != "-01:00:00.500000" {
+		t.Errorf("unexpected value %v, %v", v, err)
+	}
+	if err := nd.Scan(nil); err != nil || nd.Valid {
+		t.Errorf("unexpected %+v, %v", nd, err)
+	}
+
+	if v, err := (converter{parseDuration: true}).ConvertValue(time.Second); err != nil || v != time.Second {
+		t.Errorf("expected time.Duration, got %#v, %v", v, err)
+	}
+	if v, err := (converter{}).ConvertValue(time.Second); err != nil || v != int64(time.Second) {
+		t.Errorf("expected int64, got %#v, %v", v, err)
+	}
+}
@@ -0,0 +1,91 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"time"
+)
+
+// FailoverCollector can be implemented by a StatsCollector to be notified
+// about transparent failovers, see TransparentFailover.
+type FailoverCollector interface {
+	// Failover is called after the network connection has been replaced.
+	// cause is the error of the failed write.
+	Failover(cause error)
+}
+
+// failover replaces the network connection of a connection reused from the
+// pool, after the first write failed without sending any data. The session is
+// set up as for a new connection, so that the command can be sent again.
+func (mc *mysqlConn) failover(cause error) error {
+	mc.failoverArmed = false
+	if !mc.retryAllowed() {
+		return cause
+	}
+	mc.log("reconnecting after write on reused connection failed: ", cause)
+
+	// The context watcher must not close the connection while it is
+	// replaced. It watches the context again once the new connection is
+	// established, as Connect does.
+	ctx := mc.stopWatching()
+	if mc.closed.Load() {
+		// canceled before the watcher was stopped
+		return cause
+	}
+	watched := ctx != nil
+	if !watched {
+		ctx = context.Background()
+	}
+	cfg, connAttrs, err := mc.connector.connectConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := mc.rawConn.Close(); err != nil {
+		mc.log("closing connection:", err)
+	}
+	if s := mc.cfg.StatsCollector; s != nil {
+		s.ConnClosed()
+	}
+	mc.netConn, mc.rawConn = nil, nil
+
+	start := time.Now()
+	mc.cfg = cfg
+	mc.connAttrs = connAttrs
+	mc.compress = false
+	mc.compIO = nil
+	mc.maxAllowedPacket = maxPacketSize
+	mc.maxWriteSize = maxPacketSize - 1
+	mc.stmtCache = nil
+	mc.stmtLRU = nil
+	if err := mc.connector.dial(ctx, mc); err != nil {
+		return err
+	}
+	if watched {
+		if err := mc.watchCancel(ctx); err != nil {
+			mc.cleanup()
+			return err
+		}
+	}
+	if err := mc.handshake(); err != nil {
+		return err
+	}
+
+	// the command is sent again from the beginning
+	mc.resetSequence()
+
+	if s := mc.cfg.StatsCollector; s != nil {
+		s.Handshake(time.Since(start))
+		if fc, ok := s.(FailoverCollector); ok {
+			fc.Failover(cause)
+		}
+	}
+	return nil
+}
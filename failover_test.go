@@ -0,0 +1,152 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"testing"
+)
+
+type testFailoverCollector struct {
+	testStatsCollector
+	failovers int
+}
+
+func (s *testFailoverCollector) Failover(cause error) { s.failovers++ }
+
+func TestTransparentFailover(t *testing.T) {
+	oldConn, mc := newRWMockConn(0)
+	mc.rawConn = oldConn
+	oldConn.closed = true // the first write fails without sending anything
+
+	newConn := new(mockConn)
+	// handshake initialization packet
+	newConn.data = []byte{72, 0, 0, 0, 10, 53, 46, 53, 46, 56, 0, 165, 0, 0, 0,
+		60, 70, 63, 58, 68, 104, 34, 97, 0, 223, 247, 33, 2, 0, 15, 128, 21, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 98, 120, 114, 47, 85, 75, 109, 99, 51, 77,
+		50, 64, 0, 109, 121, 115, 113, 108, 95, 110, 97, 116, 105, 118, 101, 95,
+		112, 97, 115, 115, 119, 111, 114, 100}
+	newConn.queuedReplies = [][]byte{
+		{7, 0, 0, 2, 0, 0, 0, 2, 0, 0, 0}, // auth OK
+		{7, 0, 0, 1, 0, 0, 0, 2, 0, 0, 0}, // OK for the command
+	}
+	newConn.maxReads = 3
+
+	type ctxKey struct{}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "stmt"))
+	defer cancel()
+
+	dials := 0
+	mc.cfg.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dials++
+		if ctx.Value(ctxKey{}) != "stmt" {
+			t.Error("the context of the statement was not passed to the dial")
+		}
+		return newConn, nil
+	}
+	stats := &testFailoverCollector{}
+	mc.cfg.StatsCollector = stats
+	mc.failoverArmed = true
+
+	mc.startWatcher()
+	defer mc.cleanup()
+	if err := mc.watchCancel(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := mc.exec("DO 1"); err != nil {
+		t.Fatal(err)
+	}
+	if !mc.watching || mc.watchCtx != ctx {
+		t.Error("the context is not watched after the failover")
+	}
+	mc.finish()
+	if dials != 1 || stats.failovers != 1 || stats.closed != 1 || stats.opened != 1 {
+		t.Errorf("unexpected failover: dials=%d, failovers=%d, closed=%d, opened=%d",
+			dials, stats.failovers, stats.closed, stats.opened)
+	}
+	expected := []byte{5, 0, 0, 0, comQuery, 'D', 'O', ' ', '1'}
+	if !bytes.HasSuffix(newConn.written, expected) {
+		t.Errorf("command was not sent again: %v", newConn.written)
+	}
+	if mc.failoverArmed {
+		t.Error("failover must only be done once")
+	}
+}
+
+func TestTransparentFailoverNotArmed(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.rawConn = conn
+	conn.closed = true
+	mc.cfg.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("unexpected dial")
+	}
+
+	if err := mc.markBadConn(mc.exec("DO 1")); err != driver.ErrBadConn {
+		t.Errorf("expected ErrBadConn, got %v", err)
+	}
+}
+
+func TestTransparentFailoverArming(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.CheckConnLiveness = false
+	conn.maxReads = 1
+
+	if err := mc.ResetSession(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if mc.failoverArmed {
+		t.Error("failover armed without transparentFailover")
+	}
+
+	mc.cfg.transparentFailover = true
+	mc.openStmts = 1
+	if err := mc.ResetSession(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if mc.failoverArmed {
+		t.Error("failover armed with open statements")
+	}
+
+	mc.openStmts = 0
+	if err := mc.ResetSession(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !mc.failoverArmed {
+		t.Error("failover not armed")
+	}
+}
+
+func TestTransparentFailoverArmingWithReset(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.CheckConnLiveness = false
+	mc.cfg.resetConnection = true
+	mc.cfg.transparentFailover = true
+	conn.queuedReplies = [][]byte{okPacket}
+
+	if err := mc.ResetSession(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !mc.failoverArmed {
+		t.Error("failover not armed after COM_RESET_CONNECTION")
+	}
+
+	// the value of an earlier checkout is not kept
+	mc.cfg.resetConnection = false
+	mc.openStmts = 1
+	if err := mc.ResetSession(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if mc.failoverArmed {
+		t.Error("failover armed with open statements")
+	}
+}
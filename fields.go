@@ -10,7 +10,9 @@ package mysql
 
 import (
 	"database/sql"
+	"encoding/json"
 	"reflect"
+	"time"
 )
 
 func (mf *mysqlField) typeDatabaseName() string {
@@ -136,17 +138,61 @@ var (
 	scanTypeString     = reflect.TypeOf("")
 	scanTypeNullString = reflect.TypeOf(sql.NullString{})
 	scanTypeBytes      = reflect.TypeOf([]byte{})
+	scanTypeDecimal    = reflect.TypeOf(Decimal{})
+	scanTypeNullDec    = reflect.TypeOf(NullDecimal{})
+	scanTypeJSON       = reflect.TypeOf(json.RawMessage{})
+	scanTypeVector     = reflect.TypeOf(Vector{})
+	scanTypeGeometry   = reflect.TypeOf(Geometry{})
+	scanTypeNullGeom   = reflect.TypeOf(NullGeometry{})
+	scanTypeDuration   = reflect.TypeOf(time.Duration(0))
+	scanTypeNullDur    = reflect.TypeOf(NullDuration{})
 	scanTypeUnknown    = reflect.TypeOf(new(any))
 )
 
 type mysqlField struct {
-	tableName string
-	name      string
-	length    uint32
-	flags     fieldFlag
-	fieldType fieldType
-	decimals  byte
-	charSet   uint8
+	tableName            string
+	name                 string
+	length               uint32
+	flags                fieldFlag
+	fieldType            fieldType
+	decimals             byte
+	charSet              uint8
+	generatedInvisiblePK bool
+	parseDecimal         bool // scan type of DECIMAL is Decimal, see Config.parseDecimal
+	parseJSON            bool // scan type of JSON is json.RawMessage, see Config.parseJSON
+	parseDuration        bool // scan type of TIME is time.Duration, see Config.parseDuration
+	parseGeometry        bool // scan type of GEOMETRY is Geometry, see Config.parseGeometry
+	parseVector          bool // scan type of VECTOR is Vector, see Config.parseVector
+
+	// set with Config.columnDefaults only
+	schema       string
+	orgTable     string
+	orgName      string
+	defaultValue string
+	hasDefault   bool
+}
+
+// typeLength returns the maximum length of CHAR, VARCHAR, TEXT and BLOB
+// columns, in characters for text and in bytes for binary strings.
+func (mf *mysqlField) typeLength() (int64, bool) {
+	switch mf.fieldType {
+	case fieldTypeString, fieldTypeVarString, fieldTypeVarChar,
+		fieldTypeTinyBLOB, fieldTypeMediumBLOB, fieldTypeBLOB, fieldTypeLongBLOB:
+		if mf.flags&(flagEnum|flagSet) != 0 {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	length := int64(mf.length)
+	if mf.charSet != binaryCollationID {
+		// the server sends the length in bytes of the result charset
+		if n, ok := collationMaxLen()[mf.charSet]; ok {
+			length /= n
+		}
+	}
+	return length, true
 }
 
 func (mf *mysqlField) scanType() reflect.Type {
@@ -199,14 +245,46 @@ func (mf *mysqlField) scanType() reflect.Type {
 		}
 		return scanTypeNullFloat
 
+	case fieldTypeDecimal, fieldTypeNewDecimal:
+		if mf.parseDecimal {
+			if mf.flags&flagNotNULL != 0 {
+				return scanTypeDecimal
+			}
+			return scanTypeNullDec
+		}
+		if mf.flags&flagNotNULL != 0 {
+			return scanTypeString
+		}
+		return scanTypeNullString
+
 	case fieldTypeBit, fieldTypeTinyBLOB, fieldTypeMediumBLOB, fieldTypeLongBLOB,
-		fieldTypeBLOB, fieldTypeVarString, fieldTypeString, fieldTypeGeometry, fieldTypeVector:
+		fieldTypeBLOB, fieldTypeVarString, fieldTypeString, fieldTypeGeometry,
+		fieldTypeVector:
+		if mf.fieldType == fieldTypeGeometry && mf.parseGeometry {
+			if mf.flags&flagNotNULL != 0 {
+				return scanTypeGeometry
+			}
+			return scanTypeNullGeom
+		}
+		if mf.fieldType == fieldTypeVector && mf.parseVector {
+			// NULL is scanned as a nil Vector
+			return scanTypeVector
+		}
 		if mf.charSet == binaryCollationID {
 			return scanTypeBytes
 		}
 		fallthrough
-	case fieldTypeDecimal, fieldTypeNewDecimal, fieldTypeVarChar,
-		fieldTypeEnum, fieldTypeSet, fieldTypeJSON, fieldTypeTime:
+	case fieldTypeVarChar, fieldTypeEnum, fieldTypeSet, fieldTypeJSON, fieldTypeTime:
+		if mf.fieldType == fieldTypeTime && mf.parseDuration {
+			if mf.flags&flagNotNULL != 0 {
+				return scanTypeDuration
+			}
+			return scanTypeNullDur
+		}
+		if mf.fieldType == fieldTypeJSON && mf.parseJSON {
+			// NULL is scanned as a nil json.RawMessage
+			return scanTypeJSON
+		}
 		if mf.flags&flagNotNULL != 0 {
 			return scanTypeString
 		}
@@ -0,0 +1,34 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import "testing"
+
+func TestFieldTypeLength(t *testing.T) {
+	tests := []struct {
+		field  mysqlField
+		length int64
+		ok     bool
+	}{
+		{mysqlField{fieldType: fieldTypeVarString, length: 168, charSet: defaultCollationID}, 42, true},
+		{mysqlField{fieldType: fieldTypeVarString, length: 126, charSet: 33}, 42, true}, // utf8_general_ci
+		{mysqlField{fieldType: fieldTypeString, length: 25, charSet: 8}, 25, true},      // latin1_swedish_ci
+		{mysqlField{fieldType: fieldTypeString, length: 4, charSet: binaryCollationID}, 4, true},
+		{mysqlField{fieldType: fieldTypeBLOB, length: 262140, charSet: defaultCollationID}, 65535, true},
+		{mysqlField{fieldType: fieldTypeLongBLOB, length: 4294967295, charSet: binaryCollationID}, 4294967295, true},
+		{mysqlField{fieldType: fieldTypeString, length: 8, charSet: defaultCollationID, flags: flagEnum}, 0, false},
+		{mysqlField{fieldType: fieldTypeLong, length: 11, charSet: binaryCollationID}, 0, false},
+	}
+	for i, tt := range tests {
+		length, ok := tt.field.typeLength()
+		if length != tt.length || ok != tt.ok {
+			t.Errorf("%d: got %d, %t, want %d, %t", i, length, ok, tt.length, tt.ok)
+		}
+	}
+}
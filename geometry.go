@@ -0,0 +1,442 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Geometry is the value of a spatial column, e.g. GEOMETRY or POINT. The
+// server sends it as a 4 byte SRID followed by the geometry in the
+// well-known binary (WKB) format.
+//
+// Geometry implements the sql.Scanner and driver.Valuer interfaces, so it
+// can be used as a statement parameter and scanned from spatial columns.
+// ColumnTypeScanType returns Geometry (or NullGeometry) for spatial columns.
+type Geometry struct {
+	SRID uint32 // spatial reference system identifier, 0 for a cartesian plane
+	WKB  []byte // well-known binary representation
+}
+
+var errInvalidWKB = errors.New("mysql: invalid WKB geometry")
+
+// Scan implements the sql.Scanner interface.
+func (g *Geometry) Scan(src any) error {
+	var b []byte
+	switch src := src.(type) {
+	case []byte:
+		b = src
+	case string:
+		b = []byte(src)
+	case nil:
+		return errors.New("mysql: can not scan NULL into Geometry, use NullGeometry")
+	default:
+		return fmt.Errorf("mysql: can not scan %T into Geometry", src)
+	}
+	if len(b) < 4 {
+		return fmt.Errorf("mysql: invalid geometry length %d", len(b))
+	}
+	g.SRID = binary.LittleEndian.Uint32(b)
+	g.WKB = append([]byte(nil), b[4:]...)
+	return nil
+}
+
+// Value implements the driver.Valuer interface. It returns the internal
+// format of the server, which is accepted for spatial columns.
+func (g Geometry) Value() (driver.Value, error) {
+	b := make([]byte, 4, 4+len(g.WKB))
+	binary.LittleEndian.PutUint32(b, g.SRID)
+	return append(b, g.WKB...), nil
+}
+
+// ParseWKT parses a geometry in the well-known text (WKT) format, e.g.
+// "POINT(1 2)" or "POLYGON((0 0,1 0,1 1,0 0))", like ST_GeomFromText().
+func ParseWKT(wkt string, srid uint32) (Geometry, error) {
+	p := wktParser{s: wkt}
+	wkb, err := p.geometry(nil)
+	if err != nil {
+		return Geometry{}, err
+	}
+	if p.next() != "" {
+		return Geometry{}, p.errorf("unexpected %q", p.tok)
+	}
+	return Geometry{SRID: srid, WKB: wkb}, nil
+}
+
+// WKT returns the geometry in the well-known text (WKT) format, like
+// ST_AsText().
+func (g Geometry) WKT() (string, error) {
+	r := wkbReader{b: g.WKB}
+	sb := new(strings.Builder)
+	if err := r.geometry(sb, true); err != nil {
+		return "", err
+	}
+	if len(r.b) != 0 {
+		return "", errInvalidWKB
+	}
+	return sb.String(), nil
+}
+
+// NullGeometry represents a Geometry that may be NULL.
+//
+// NullGeometry implements the sql.Scanner and driver.Valuer interfaces.
+type NullGeometry struct {
+	Geometry Geometry
+	Valid    bool // Valid is true if Geometry is not NULL
+}
+
+// Scan implements the sql.Scanner interface.
+func (ng *NullGeometry) Scan(src any) error {
+	if src == nil {
+		ng.Geometry, ng.Valid = Geometry{}, false
+		return nil
+	}
+	ng.Valid = true
+	return ng.Geometry.Scan(src)
+}
+
+// Value implements the driver.Valuer interface.
+func (ng NullGeometry) Value() (driver.Value, error) {
+	if !ng.Valid {
+		return nil, nil
+	}
+	return ng.Geometry.Value()
+}
+
+// WKB geometry types
+const (
+	wkbPoint              = 1
+	wkbLineString         = 2
+	wkbPolygon            = 3
+	wkbMultiPoint         = 4
+	wkbMultiLineString    = 5
+	wkbMultiPolygon       = 6
+	wkbGeometryCollection = 7
+)
+
+var wkbTypeNames = [...]string{
+	wkbPoint:              "POINT",
+	wkbLineString:         "LINESTRING",
+	wkbPolygon:            "POLYGON",
+	wkbMultiPoint:         "MULTIPOINT",
+	wkbMultiLineString:    "MULTILINESTRING",
+	wkbMultiPolygon:       "MULTIPOLYGON",
+	wkbGeometryCollection: "GEOMETRYCOLLECTION",
+}
+
+// wkbReader decodes WKB into WKT.
+type wkbReader struct {
+	b     []byte
+	order binary.ByteOrder
+}
+
+func (r *wkbReader) uint32() (uint32, error) {
+	if len(r.b) < 4 {
+		return 0, errInvalidWKB
+	}
+	n := r.order.Uint32(r.b)
+	r.b = r.b[4:]
+	return n, nil
+}
+
+// count reads the number of elements, each of at least size bytes.
+func (r *wkbReader) count(size int) (int, error) {
+	n, err := r.uint32()
+	if err != nil {
+		return 0, err
+	}
+	if uint64(n)*uint64(size) > uint64(len(r.b)) {
+		return 0, errInvalidWKB
+	}
+	return int(n), nil
+}
+
+func (r *wkbReader) point(sb *strings.Builder) error {
+	if len(r.b) < 16 {
+		return errInvalidWKB
+	}
+	x := math.Float64frombits(r.order.Uint64(r.b))
+	y := math.Float64frombits(r.order.Uint64(r.b[8:]))
+	r.b = r.b[16:]
+	sb.WriteString(strconv.FormatFloat(x, 'g', -1, 64))
+	sb.WriteByte(' ')
+	sb.WriteString(strconv.FormatFloat(y, 'g', -1, 64))
+	return nil
+}
+
+// points writes a parenthesized list of points.
+func (r *wkbReader) points(sb *strings.Builder) error {
+	n, err := r.count(16)
+	if err != nil {
+		return err
+	}
+	sb.WriteByte('(')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if err := r.point(sb); err != nil {
+			return err
+		}
+	}
+	sb.WriteByte(')')
+	return nil
+}
+
+// polygon writes a parenthesized list of rings.
+func (r *wkbReader) polygon(sb *strings.Builder) error {
+	n, err := r.count(4)
+	if err != nil {
+		return err
+	}
+	sb.WriteByte('(')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if err := r.points(sb); err != nil {
+			return err
+		}
+	}
+	sb.WriteByte(')')
+	return nil
+}
+
+// geometry decodes a WKB geometry including its header. The type name is
+// omitted for the elements of multi geometries.
+func (r *wkbReader) geometry(sb *strings.Builder, withName bool) error {
+	if len(r.b) < 5 {
+		return errInvalidWKB
+	}
+	switch r.b[0] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return errInvalidWKB
+	}
+	r.b = r.b[1:]
+	typ, err := r.uint32()
+	if err != nil {
+		return err
+	}
+	if typ < wkbPoint || typ > wkbGeometryCollection {
+		return fmt.Errorf("mysql: unsupported WKB geometry type %d", typ)
+	}
+	if withName {
+		sb.WriteString(wkbTypeNames[typ])
+	}
+
+	switch typ {
+	case wkbPoint:
+		sb.WriteByte('(')
+		if err := r.point(sb); err != nil {
+			return err
+		}
+		sb.WriteByte(')')
+		return nil
+	case wkbLineString:
+		return r.points(sb)
+	case wkbPolygon:
+		return r.polygon(sb)
+	}
+
+	n, err := r.count(5)
+	if err != nil {
+		return err
+	}
+	if n == 0 && typ == wkbGeometryCollection {
+		sb.WriteString(" EMPTY")
+		return nil
+	}
+	sb.WriteByte('(')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		elem := wkbReader{b: r.b}
+		if typ != wkbGeometryCollection {
+			// the elements must have the matching single type
+			if len(elem.b) >= 5 && wkbTypeOf(elem.b) != typ-3 {
+				return errInvalidWKB
+			}
+		}
+		if err := elem.geometry(sb, typ == wkbGeometryCollection); err != nil {
+			return err
+		}
+		r.b = elem.b
+	}
+	sb.WriteByte(')')
+	return nil
+}
+
+// wkbTypeOf returns the type of the WKB geometry b.
+func wkbTypeOf(b []byte) uint32 {
+	if b[0] == 0 {
+		return binary.BigEndian.Uint32(b[1:])
+	}
+	return binary.LittleEndian.Uint32(b[1:])
+}
+
+// wktParser encodes WKT as little-endian WKB.
+type wktParser struct {
+	s   string
+	pos int
+	tok string
+}
+
+func (p *wktParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("mysql: invalid WKT at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// next returns the next token: a word, a number, "(", ")" or ",". It
+// returns "" at the end of the input.
+func (p *wktParser) next() string {
+	p.pos = skipSpace(p.s, p.pos)
+	start := p.pos
+	if p.pos < len(p.s) {
+		switch c := p.s[p.pos]; c {
+		case '(', ')', ',':
+			p.pos++
+		default:
+			for p.pos < len(p.s) && !isSpace(p.s[p.pos]) && !strings.ContainsRune("(),", rune(p.s[p.pos])) {
+				p.pos++
+			}
+		}
+	}
+	p.tok = p.s[start:p.pos]
+	return p.tok
+}
+
+func (p *wktParser) expect(tok string) error {
+	if p.next() != tok {
+		return p.errorf("expected %q, got %q", tok, p.tok)
+	}
+	return nil
+}
+
+func (p *wktParser) point(b []byte) ([]byte, error) {
+	for i := 0; i < 2; i++ {
+		f, err := strconv.ParseFloat(p.next(), 64)
+		if err != nil {
+			return nil, p.errorf("invalid coordinate %q", p.tok)
+		}
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(f))
+	}
+	return b, nil
+}
+
+// list parses a parenthesized, comma-separated list, appending the number of
+// elements and the elements encoded by elem.
+func (p *wktParser) list(b []byte, elem func([]byte) ([]byte, error)) ([]byte, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	countPos := len(b)
+	b = append(b, 0, 0, 0, 0)
+	var n uint32
+	for {
+		var err error
+		if b, err = elem(b); err != nil {
+			return nil, err
+		}
+		n++
+		if p.next() == ")" {
+			break
+		}
+		if p.tok != "," {
+			return nil, p.errorf("expected \",\" or \")\", got %q", p.tok)
+		}
+	}
+	binary.LittleEndian.PutUint32(b[countPos:], n)
+	return b, nil
+}
+
+func (p *wktParser) points(b []byte) ([]byte, error) {
+	return p.list(b, p.point)
+}
+
+func (p *wktParser) polygon(b []byte) ([]byte, error) {
+	return p.list(b, p.points)
+}
+
+// wkbHeader appends the WKB header of a geometry of the given type.
+func wkbHeader(b []byte, typ uint32) []byte {
+	b = append(b, 1) // little-endian
+	return binary.LittleEndian.AppendUint32(b, typ)
+}
+
+// geometry parses a tagged geometry, e.g. POINT(1 2).
+func (p *wktParser) geometry(b []byte) ([]byte, error) {
+	name := strings.ToUpper(p.next())
+	typ := uint32(0)
+	for t, n := range wkbTypeNames {
+		if n != "" && n == name {
+			typ = uint32(t)
+		}
+	}
+	if typ == 0 {
+		return nil, p.errorf("unsupported geometry type %q", name)
+	}
+	b = wkbHeader(b, typ)
+
+	switch typ {
+	case wkbPoint:
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		b, err := p.point(b)
+		if err != nil {
+			return nil, err
+		}
+		return b, p.expect(")")
+	case wkbLineString:
+		return p.points(b)
+	case wkbPolygon:
+		return p.polygon(b)
+	case wkbMultiPoint:
+		return p.list(b, func(b []byte) ([]byte, error) {
+			// both MULTIPOINT(0 0,1 1) and MULTIPOINT((0 0),(1 1))
+			b = wkbHeader(b, wkbPoint)
+			save := p.pos
+			if p.next() != "(" {
+				p.pos = save
+				return p.point(b)
+			}
+			b, err := p.point(b)
+			if err != nil {
+				return nil, err
+			}
+			return b, p.expect(")")
+		})
+	case wkbMultiLineString:
+		return p.list(b, func(b []byte) ([]byte, error) {
+			return p.points(wkbHeader(b, wkbLineString))
+		})
+	case wkbMultiPolygon:
+		return p.list(b, func(b []byte) ([]byte, error) {
+			return p.polygon(wkbHeader(b, wkbPolygon))
+		})
+	}
+
+	// GEOMETRYCOLLECTION
+	save := p.pos
+	if strings.EqualFold(p.next(), "EMPTY") {
+		return binary.LittleEndian.AppendUint32(b, 0), nil
+	}
+	p.pos = save
+	return p.list(b, p.geometry)
+}
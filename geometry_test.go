@@ -0,0 +1,145 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestGeometryScanValue(t *testing.T) {
+	// SELECT ST_GeomFromText('POINT(1 2)', 4326)
+	raw, _ := hex.DecodeString("E6100000" + "0101000000" + "000000000000F03F" + "0000000000000040")
+
+	var g Geometry
+	if err := g.Scan(raw); err != nil {
+		t.Fatal(err)
+	}
+	if g.SRID != 4326 {
+		t.Errorf("expected SRID 4326, got %d", g.SRID)
+	}
+	if wkt, err := g.WKT(); err != nil || wkt != "POINT(1 2)" {
+		t.Errorf("unexpected WKT %q, %v", wkt, err)
+	}
+	v, err := g.Value()
+	if err != nil || !bytes.Equal(v.([]byte), raw) {
+		t.Errorf("unexpected value %x, %v", v, err)
+	}
+
+	if err := g.Scan(nil); err == nil {
+		t.Error("expected error for NULL")
+	}
+	if err := g.Scan([]byte{1, 2}); err == nil {
+		t.Error("expected error for short value")
+	}
+	var ng NullGeometry
+	if err := ng.Scan(nil); err != nil || ng.Valid {
+		t.Errorf("unexpected result for NULL: %+v, %v", ng, err)
+	}
+	if v, err := ng.Value(); v != nil || err != nil {
+		t.Errorf("expected NULL, got %v, %v", v, err)
+	}
+	if err := ng.Scan(raw); err != nil || !ng.Valid || ng.Geometry.SRID != 4326 {
+		t.Errorf("unexpected result: %+v, %v", ng, err)
+	}
+}
+
+func TestWKTRoundTrip(t *testing.T) {
+	tests := []string{
+		"POINT(1 2)",
+		"POINT(-1.5 1e-07)",
+		"LINESTRING(0 0,1 1,2 0)",
+		"POLYGON((0 0,10 0,10 10,0 10,0 0),(1 1,2 1,2 2,1 1))",
+		"MULTIPOINT((0 0),(1 1))",
+		"MULTILINESTRING((0 0,1 1),(2 2,3 3))",
+		"MULTIPOLYGON(((0 0,1 0,1 1,0 0)),((5 5,6 5,6 6,5 5)))",
+		"GEOMETRYCOLLECTION(POINT(1 2),LINESTRING(0 0,1 1))",
+		"GEOMETRYCOLLECTION EMPTY",
+	}
+	for _, wkt := range tests {
+		g, err := ParseWKT(wkt, 0)
+		if err != nil {
+			t.Errorf("%s: %v", wkt, err)
+			continue
+		}
+		got, err := g.WKT()
+		if err != nil {
+			t.Errorf("%s: %v", wkt, err)
+		} else if got != wkt {
+			t.Errorf("expected %s, got %s", wkt, got)
+		}
+	}
+}
+
+func TestParseWKT(t *testing.T) {
+	g, err := ParseWKT(" point ( 1  2 ) ", 4326)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := hex.DecodeString("0101000000" + "000000000000F03F" + "0000000000000040")
+	if g.SRID != 4326 || !bytes.Equal(g.WKB, want) {
+		t.Errorf("unexpected geometry %d %x", g.SRID, g.WKB)
+	}
+
+	// points of MULTIPOINT without parentheses
+	g, err = ParseWKT("MULTIPOINT(0 0, 1 1)", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wkt, _ := g.WKT(); wkt != "MULTIPOINT((0 0),(1 1))" {
+		t.Errorf("unexpected WKT %s", wkt)
+	}
+
+	for _, wkt := range []string{
+		"",
+		"POINT(1)",
+		"POINT(1 2",
+		"POINT(1 2) x",
+		"LINESTRING(0 0;1 1)",
+		"CIRCLE(0 0, 1)",
+		"POINT(a b)",
+	} {
+		if _, err := ParseWKT(wkt, 0); err == nil {
+			t.Errorf("%q: expected error", wkt)
+		}
+	}
+}
+
+func TestInvalidWKB(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"02",
+		"0101000000",         // truncated point
+		"010200000010000000", // too many points
+		"0108000000",         // unknown type
+		"010400000001000000" + "010200000000000000", // MULTIPOINT containing a LINESTRING
+	} {
+		wkb, _ := hex.DecodeString(s)
+		if _, err := (Geometry{WKB: wkb}).WKT(); err == nil {
+			t.Errorf("%s: expected error", s)
+		}
+	}
+}
+
+func TestGeometryScanType(t *testing.T) {
+	mf := mysqlField{fieldType: fieldTypeGeometry, charSet: binaryCollationID}
+	if st := mf.scanType(); st != scanTypeBytes {
+		t.Errorf("expected []byte, got %v", st)
+	}
+
+	mf.parseGeometry = true
+	if st := mf.scanType(); st != scanTypeNullGeom {
+		t.Errorf("expected NullGeometry, got %v", st)
+	}
+	mf.flags = flagNotNULL
+	if st := mf.scanType(); st != scanTypeGeometry {
+		t.Errorf("expected Geometry, got %v", st)
+	}
+}
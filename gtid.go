@@ -0,0 +1,87 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrGTIDWaitTimeout is returned by queries with a context of WithGTIDWait
+// if the server did not execute the GTIDs within the timeout.
+var ErrGTIDWaitTimeout = errors.New("mysql: timeout waiting for GTIDs to be executed")
+
+type gtidWaitKey struct{}
+
+type gtidWait struct {
+	gtids   string
+	timeout time.Duration
+}
+
+// WithGTIDWait returns a copy of ctx which makes queries wait until the
+// server has executed the transactions of the GTID set gtids, e.g. the GTIDs
+// of a write on the primary, so that a query on a replica reads its own
+// writes. With trackGTIDs=true the GTIDs of the last transaction committed
+// on a connection are reported by ConnInfo:
+//
+//	info, _ := mysql.ConnInfo(driverConn) // after the write on the primary
+//	ctx = mysql.WithGTIDWait(ctx, info.GTIDs, time.Second)
+//	rows, err := replica.QueryContext(ctx, "SELECT ...")
+//
+// The driver executes WAIT_FOR_EXECUTED_GTID_SET before the query, unless
+// the connection already waited for the same set. If the set is not executed
+// within timeout, the query fails with ErrGTIDWaitTimeout. A timeout of 0
+// waits without limit. Statements executed with Exec do not wait. This
+// requires MySQL 5.7.5 or newer with GTIDs enabled.
+func WithGTIDWait(ctx context.Context, gtids string, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, gtidWaitKey{}, gtidWait{gtids, timeout})
+}
+
+// isGTIDSet reports whether s only consists of the characters of GTID sets,
+// so that it can be quoted without escaping.
+func isGTIDSet(s string) bool {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case isIdentByte(c) && c < 0x80, c == '-', c == ':', c == ',', isSpace(c):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// waitForGTIDs waits until the GTIDs of ctx have been executed, see
+// WithGTIDWait.
+func (mc *mysqlConn) waitForGTIDs(ctx context.Context) error {
+	w, ok := ctx.Value(gtidWaitKey{}).(gtidWait)
+	if !ok || w.gtids == "" || w.gtids == mc.waitedGTIDs {
+		return nil
+	}
+	if !isGTIDSet(w.gtids) {
+		return fmt.Errorf("mysql: invalid GTID set %q", w.gtids)
+	}
+	query := "SELECT WAIT_FOR_EXECUTED_GTID_SET('" + w.gtids + "'"
+	if w.timeout > 0 {
+		query += ", " + strconv.FormatFloat(w.timeout.Seconds(), 'f', -1, 64)
+	}
+	query += ")"
+	res, err := mc.queryValue(query)
+	if err != nil {
+		return err
+	}
+	if string(res) != "0" {
+		return ErrGTIDWaitTimeout
+	}
+	// executed GTIDs are never removed
+	mc.waitedGTIDs = w.gtids
+	return nil
+}
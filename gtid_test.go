@@ -0,0 +1,112 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// gtidOkPacket returns an OK packet with the session state change of
+// session_track_gtids.
+func gtidOkPacket(affectedRows byte, gtids string) []byte {
+	data := append([]byte{0, byte(len(gtids))}, gtids...)
+	entry := append([]byte{sessionTrackGTIDs, byte(len(data))}, data...)
+	changed := byte(statusSessionStateChanged >> 8)
+	ok := []byte{iOK, affectedRows, 0, 2, changed, 0, 0, 0, byte(len(entry))}
+	return append(ok, entry...)
+}
+
+func TestHandleOkPacketGTIDs(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.sessionTrack = true
+	gtids := "3e11fa47-71ca-11e1-9e33-c80aa9429562:23"
+	conn.queuedReplies = [][]byte{mockPacket(1, gtidOkPacket(1, gtids))}
+
+	res, err := mc.Exec("UPDATE t SET a = 1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mc.info.GTIDs != gtids {
+		t.Errorf("expected GTIDs %q, got %q", gtids, mc.info.GTIDs)
+	}
+	results, _ := MultiResults(res)
+	if len(results) != 1 || results[0].GTIDs != gtids {
+		t.Errorf("unexpected results %+v", results)
+	}
+
+	// other session state changes are skipped
+	ok := []byte{iOK, 0, 0, 2, byte(statusSessionStateChanged >> 8), 0, 0, 0, 6, sessionTrackSchema, 4, 3, 'a', 'b', 'c'}
+	if err := mc.clearResult().handleOkPacket(ok); err != nil {
+		t.Fatal(err)
+	}
+	if mc.info.GTIDs != gtids || mc.result.gtids != nil {
+		t.Errorf("unexpected GTIDs %q, %q", mc.info.GTIDs, mc.result.gtids)
+	}
+}
+
+func TestWithGTIDWait(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	gtids := "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-23"
+	result := func(value byte) []byte {
+		resp := mockPacket(1, []byte{1})
+		resp = append(resp, mockColumn(2, "w", fieldTypeLongLong)...)
+		resp = append(resp, mockPacket(3, []byte{iEOF, 0, 0, 2, 0})...)
+		resp = append(resp, mockPacket(4, []byte{1, value})...)
+		return append(resp, mockPacket(5, []byte{iEOF, 0, 0, 2, 0})...)
+	}
+
+	ctx := WithGTIDWait(context.Background(), gtids, 1500*time.Millisecond)
+	conn.queuedReplies = [][]byte{result('0'), result('1')}
+	rows, err := mc.QueryContext(ctx, "SELECT a FROM t", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"SELECT WAIT_FOR_EXECUTED_GTID_SET('" + gtids + "', 1.5)",
+		"SELECT a FROM t",
+	}
+	if queries := writtenQueries(conn.written); !reflect.DeepEqual(queries, expected) {
+		t.Errorf("unexpected queries:\n%q\nexpected:\n%q", queries, expected)
+	}
+
+	// the connection already waited for the GTIDs
+	conn.written = nil
+	conn.queuedReplies = [][]byte{result('1')}
+	rows, err = mc.QueryContext(ctx, "SELECT a FROM t", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+	if queries := writtenQueries(conn.written); !reflect.DeepEqual(queries, []string{"SELECT a FROM t"}) {
+		t.Errorf("unexpected queries %q", queries)
+	}
+
+	// timeout
+	conn.written = nil
+	conn.queuedReplies = [][]byte{result('1')}
+	ctx = WithGTIDWait(context.Background(), gtids+":25", 0)
+	if _, err := mc.QueryContext(ctx, "SELECT a FROM t", nil); err != ErrGTIDWaitTimeout {
+		t.Errorf("expected ErrGTIDWaitTimeout, got %v", err)
+	}
+	expected = []string{"SELECT WAIT_FOR_EXECUTED_GTID_SET('" + gtids + ":25')"}
+	if queries := writtenQueries(conn.written); !reflect.DeepEqual(queries, expected) {
+		t.Errorf("unexpected queries:\n%q\nexpected:\n%q", queries, expected)
+	}
+
+	ctx = WithGTIDWait(context.Background(), "a'); DO SLEEP(1); --", 0)
+	if _, err := mc.QueryContext(ctx, "SELECT a FROM t", nil); err == nil {
+		t.Error("expected error for invalid GTID set")
+	}
+}
@@ -9,18 +9,22 @@
 package mysql
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 )
 
 var (
-	fileRegister       map[string]struct{}
-	fileRegisterLock   sync.RWMutex
-	readerRegister     map[string]func() io.Reader
-	readerRegisterLock sync.RWMutex
+	fileRegister     registry[struct{}]
+	readerRegister   registry[func(context.Context) io.Reader]
+	readerAtRegister registry[func() (io.ReaderAt, int64)]
+	fsRegister       registry[fs.FS]
 )
 
 // RegisterLocalFile adds the given file to the file allowlist,
@@ -34,21 +38,22 @@ var (
 //	if err != nil {
 //	...
 func RegisterLocalFile(filePath string) {
-	fileRegisterLock.Lock()
-	// lazy map init
-	if fileRegister == nil {
-		fileRegister = make(map[string]struct{})
-	}
-
-	fileRegister[strings.Trim(filePath, `"`)] = struct{}{}
-	fileRegisterLock.Unlock()
+	fileRegister.set(strings.Trim(filePath, `"`), struct{}{})
 }
 
 // DeregisterLocalFile removes the given filepath from the allowlist.
 func DeregisterLocalFile(filePath string) {
-	fileRegisterLock.Lock()
-	delete(fileRegister, strings.Trim(filePath, `"`))
-	fileRegisterLock.Unlock()
+	fileRegister.delete(strings.Trim(filePath, `"`))
+}
+
+// ListLocalFiles returns the sorted filepaths of the allowlist.
+func ListLocalFiles() []string {
+	return fileRegister.names()
+}
+
+// ResetLocalFiles removes all filepaths from the allowlist.
+func ResetLocalFiles() {
+	fileRegister.reset()
 }
 
 // RegisterReaderHandler registers a handler function which is used
@@ -66,22 +71,113 @@ func DeregisterLocalFile(filePath string) {
 //	if err != nil {
 //	...
 func RegisterReaderHandler(name string, handler func() io.Reader) {
-	readerRegisterLock.Lock()
-	// lazy map init
-	if readerRegister == nil {
-		readerRegister = make(map[string]func() io.Reader)
-	}
+	RegisterReaderHandlerContext(name, func(context.Context) io.Reader {
+		return handler()
+	})
+}
 
-	readerRegister[name] = handler
-	readerRegisterLock.Unlock()
+// RegisterReaderHandlerContext is like RegisterReaderHandler, but the handler
+// receives the context of the statement, so that the Reader can stop reading
+// when the statement is canceled. The remaining contents are not sent when
+// the context is done.
+//
+//	mysql.RegisterReaderHandlerContext("data", func(ctx context.Context) io.Reader {
+//		req, _ := http.NewRequestWithContext(ctx, "GET", "https://example.com/data.csv", nil)
+//		resp, err := http.DefaultClient.Do(req)
+//		... // handle err
+//		return resp.Body
+//	})
+//	_, err := db.ExecContext(ctx, "LOAD DATA LOCAL INFILE 'Reader::data' INTO TABLE foo")
+//	if err != nil {
+//	...
+func RegisterReaderHandlerContext(name string, handler func(ctx context.Context) io.Reader) {
+	readerRegister.set(name, handler)
 }
 
 // DeregisterReaderHandler removes the ReaderHandler function with
 // the given name from the registry.
 func DeregisterReaderHandler(name string) {
-	readerRegisterLock.Lock()
-	delete(readerRegister, name)
-	readerRegisterLock.Unlock()
+	readerRegister.delete(name)
+}
+
+// ListReaderHandlers returns the sorted names of the handlers registered
+// with RegisterReaderHandler, RegisterReaderHandlerContext and
+// RegisterReaderAtHandler.
+func ListReaderHandlers() []string {
+	names := readerRegister.names()
+	for _, name := range readerAtRegister.names() {
+		if _, ok := readerRegister.get(name); !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResetReaderHandlers removes all handlers registered with
+// RegisterReaderHandler, RegisterReaderHandlerContext and
+// RegisterReaderAtHandler.
+func ResetReaderHandlers() {
+	readerRegister.reset()
+	readerAtRegister.reset()
+}
+
+// RegisterReaderAtHandler registers a handler function which is used to
+// receive a io.ReaderAt and the size of its contents.
+// The ReaderAt can be used by "LOAD DATA LOCAL INFILE Reader::<name>" like
+// readers registered with RegisterReaderHandler, which take precedence.
+// The contents are read in chunks concurrently while previous chunks are
+// sent, which is faster than a io.Reader for large files.
+// If the handler returns a io.Closer Close() is called when the request is
+// finished.
+//
+//	mysql.RegisterReaderAtHandler("data", func() (io.ReaderAt, int64) {
+//		file, _ := os.Open("/home/gopher/data.csv")
+//		fi, _ := file.Stat()
+//		return file, fi.Size()
+//	})
+//	err := db.Exec("LOAD DATA LOCAL INFILE 'Reader::data' INTO TABLE foo")
+//	if err != nil {
+//	...
+func RegisterReaderAtHandler(name string, handler func() (io.ReaderAt, int64)) {
+	readerAtRegister.set(name, handler)
+}
+
+// DeregisterReaderAtHandler removes the ReaderAtHandler function with
+// the given name from the registry.
+func DeregisterReaderAtHandler(name string) {
+	readerAtRegister.delete(name)
+}
+
+// RegisterFS registers a file system whose files can be used by
+// "LOAD DATA LOCAL INFILE FS::<prefix>/<path>", e.g. an embed.FS.
+// The path is relative to the root of fsys as required by fs.FS.
+//
+//	//go:embed testdata
+//	var testdata embed.FS
+//
+//	mysql.RegisterFS("fixtures", testdata)
+//	err := db.Exec("LOAD DATA LOCAL INFILE 'FS::fixtures/testdata/users.csv' INTO TABLE users")
+//	if err != nil {
+//	...
+func RegisterFS(prefix string, fsys fs.FS) {
+	fsRegister.set(prefix, fsys)
+}
+
+// DeregisterFS removes the file system with the given prefix from the
+// registry.
+func DeregisterFS(prefix string) {
+	fsRegister.delete(prefix)
+}
+
+// ListFS returns the sorted prefixes of the registered file systems.
+func ListFS() []string {
+	return fsRegister.names()
+}
+
+// ResetFS removes all registered file systems.
+func ResetFS() {
+	fsRegister.reset()
 }
 
 func deferredClose(err *error, closer io.Closer) {
@@ -93,6 +189,11 @@ func deferredClose(err *error, closer io.Closer) {
 
 const defaultPacketSize = 16 * 1024 // 16KB is small enough for disk readahead and large enough for TCP
 
+const (
+	readerAtPacketSize = 256 * 1024 // size of the chunks read from a io.ReaderAt
+	readerAtWorkers    = 4          // number of chunks read concurrently
+)
+
 func (mc *okHandler) handleInFileRequest(name string) (err error) {
 	var rdr io.Reader
 	packetSize := defaultPacketSize
@@ -104,12 +205,25 @@ func (mc *okHandler) handleInFileRequest(name string) (err error) {
 		// The server might return an an absolute path. See issue #355.
 		name = name[idx+8:]
 
-		readerRegisterLock.RLock()
-		handler, inMap := readerRegister[name]
-		readerRegisterLock.RUnlock()
-
-		if inMap {
-			rdr = handler()
+		handler, atHandler := mc.conn().readerHandler(name)
+		if handler == nil && atHandler != nil {
+			rdrAt, size := atHandler()
+			if rdrAt == nil {
+				err = fmt.Errorf("reader '%s' is <nil>", name)
+			} else {
+				if cl, ok := rdrAt.(io.Closer); ok {
+					defer deferredClose(&err, cl)
+				}
+				packetSize = min(readerAtPacketSize, mc.maxWriteSize)
+				var ioErr error
+				if err, ioErr = mc.sendReaderAt(rdrAt, size, packetSize); ioErr != nil {
+					return ioErr
+				}
+				// the contents have been sent
+				packetSize = 0
+			}
+		} else if handler != nil {
+			rdr = handler(mc.conn().context())
 			if rdr != nil {
 				if cl, ok := rdr.(io.Closer); ok {
 					defer deferredClose(&err, cl)
@@ -120,12 +234,35 @@ func (mc *okHandler) handleInFileRequest(name string) (err error) {
 		} else {
 			err = fmt.Errorf("reader '%s' is not registered", name)
 		}
+	} else if idx := strings.Index(name, "FS::"); idx == 0 || (idx > 0 && name[idx-1] == '/') { // fs.FS
+		name = name[idx+4:]
+		prefix, path, _ := strings.Cut(name, "/")
+
+		fsys, inMap := mc.conn().fileSystem(prefix)
+
+		if !inMap {
+			err = fmt.Errorf("file system '%s' is not registered", prefix)
+		} else if !fs.ValidPath(path) {
+			err = fmt.Errorf("invalid path '%s' in file system '%s'", path, prefix)
+		} else {
+			var file fs.File
+			var fi fs.FileInfo
+
+			if file, err = fsys.Open(path); err == nil {
+				defer deferredClose(&err, file)
+
+				// get file size
+				if fi, err = file.Stat(); err == nil {
+					rdr = file
+					if fileSize := int(fi.Size()); fileSize < packetSize {
+						packetSize = fileSize
+					}
+				}
+			}
+		}
 	} else { // File
 		name = strings.Trim(name, `"`)
-		fileRegisterLock.RLock()
-		_, exists := fileRegister[name]
-		fileRegisterLock.RUnlock()
-		if mc.cfg.AllowAllFiles || exists {
+		if mc.cfg.AllowAllFiles || mc.conn().localFileAllowed(name) {
 			var file *os.File
 			var fi os.FileInfo
 
@@ -152,7 +289,13 @@ func (mc *okHandler) handleInFileRequest(name string) (err error) {
 	if err == nil && packetSize > 0 {
 		data = make([]byte, 4+packetSize)
 		var n int
+		ctx := mc.conn().context()
 		for err == nil {
+			if cerr := ctx.Err(); cerr != nil {
+				// the empty packet would load the contents sent so far
+				mc.conn().cancel(cerr)
+				return &CancelInfo{Phase: CancelSend, Err: cerr}
+			}
 			n, err = rdr.Read(data[4:])
 			if n > 0 {
 				if ioErr := mc.conn().writePacket(data[:4+n]); ioErr != nil {
@@ -182,3 +325,123 @@ func (mc *okHandler) handleInFileRequest(name string) (err error) {
 	mc.conn().readPacket()
 	return err
 }
+
+// sendReaderAt sends size bytes of r in packets of packetSize bytes. The
+// chunks are read by readerAtWorkers goroutines ahead of the packet written.
+// readErr is the first error reading r, ioErr the error writing a packet or
+// the cancellation, which closes the connection.
+func (mc *okHandler) sendReaderAt(r io.ReaderAt, size int64, packetSize int) (readErr, ioErr error) {
+	type chunk struct {
+		data []byte
+		err  error
+	}
+	if packetSize <= 0 {
+		return nil, nil
+	}
+
+	free := make(chan []byte, readerAtWorkers+2)
+	pending := make(chan chan chunk, readerAtWorkers)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	defer func() {
+		// r must not be used after returning, e.g. because it is closed
+		close(done)
+		for range pending {
+		}
+		wg.Wait()
+	}()
+
+	go func() {
+		defer close(pending)
+		for off := int64(0); off < size; off += int64(packetSize) {
+			c := make(chan chunk, 1)
+			select {
+			case pending <- c:
+			case <-done:
+				return
+			}
+			wg.Add(1)
+			go func(off int64) {
+				defer wg.Done()
+				var data []byte
+				select {
+				case data = <-free:
+				default:
+					data = make([]byte, 4+packetSize)
+				}
+				n := int(min(int64(packetSize), size-off))
+				data = data[:4+n]
+				m, err := r.ReadAt(data[4:], off)
+				if err == io.EOF && m == n {
+					err = nil
+				} else if err == nil && m < n {
+					err = io.ErrUnexpectedEOF
+				}
+				c <- chunk{data, err}
+			}(off)
+		}
+	}()
+
+	ctx := mc.conn().context()
+	for c := range pending {
+		if cerr := ctx.Err(); cerr != nil {
+			mc.conn().cancel(cerr)
+			return nil, &CancelInfo{Phase: CancelSend, Err: cerr}
+		}
+		ch := <-c
+		if ch.err != nil {
+			return ch.err, nil
+		}
+		if err := mc.conn().writePacket(ch.data); err != nil {
+			return nil, err
+		}
+		select {
+		case free <- ch.data[:cap(ch.data)]:
+		default:
+		}
+	}
+	return nil, nil
+}
+
+// readerHandler returns the handlers registered for the reader name, either
+// in Config.ReaderHandlers or globally.
+func (mc *mysqlConn) readerHandler(name string) (func(context.Context) io.Reader, func() (io.ReaderAt, int64)) {
+	if mc.cfg.ReaderHandlers != nil {
+		return mc.cfg.ReaderHandlers[name], nil
+	}
+	handler, _ := readerRegister.get(name)
+	atHandler, _ := readerAtRegister.get(name)
+	return handler, atHandler
+}
+
+// fileSystem returns the file system registered for prefix, either in
+// Config.FileSystems or globally.
+func (mc *mysqlConn) fileSystem(prefix string) (fs.FS, bool) {
+	if mc.cfg.FileSystems != nil {
+		fsys, ok := mc.cfg.FileSystems[prefix]
+		return fsys, ok
+	}
+	return fsRegister.get(prefix)
+}
+
+// localFileAllowed reports whether the file is allowed by
+// Config.AllowedLocalFiles or by RegisterLocalFile.
+func (mc *mysqlConn) localFileAllowed(name string) bool {
+	if mc.cfg.AllowedLocalFiles != nil {
+		return slices.Contains(mc.cfg.AllowedLocalFiles, name)
+	}
+	_, exists := fileRegister.get(name)
+	return exists
+}
+
+// context returns the context of the current statement.
+func (mc *mysqlConn) context() context.Context {
+	if mc.ctx == nil {
+		return context.Background()
+	}
+	return mc.ctx
+}
+
+func (mc *mysqlConn) clearContext() {
+	mc.ctx = nil
+}
@@ -0,0 +1,235 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+type failingReaderAt struct {
+	io.ReaderAt
+	failAt int64
+}
+
+func (r failingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off >= r.failAt {
+		return 0, errors.New("read failed")
+	}
+	return r.ReaderAt.ReadAt(p, off)
+}
+
+func TestReaderAtHandler(t *testing.T) {
+	contents := make([]byte, 10500)
+	for i := range contents {
+		contents[i] = byte(i)
+	}
+	RegisterReaderAtHandler("chunks", func() (io.ReaderAt, int64) {
+		return bytes.NewReader(contents), int64(len(contents))
+	})
+	defer DeregisterReaderAtHandler("chunks")
+
+	conn, mc := newRWMockConn(2)
+	mc.maxWriteSize = 1000
+	// 11 content packets and the empty packet
+	conn.data = []byte{7, 0, 0, 14, 0, 0, 0, 2, 0, 0, 0}
+	if err := mc.clearResult().handleInFileRequest("Reader::chunks"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []byte
+	seq := byte(2)
+	for w := conn.written; len(w) > 0; seq++ {
+		n := int(getUint24(w))
+		if w[3] != seq {
+			t.Fatalf("expected sequence %d, got %d", seq, w[3])
+		}
+		if n > 1000 {
+			t.Fatalf("packet of %d bytes", n)
+		}
+		got = append(got, w[4:4+n]...)
+		w = w[4+n:]
+	}
+	if seq != 14 {
+		t.Errorf("expected 12 packets, got %d", seq-2)
+	}
+	if !bytes.Equal(got, contents) {
+		t.Error("contents were not sent in order")
+	}
+}
+
+func TestReaderAtHandlerError(t *testing.T) {
+	RegisterReaderAtHandler("failing", func() (io.ReaderAt, int64) {
+		return failingReaderAt{bytes.NewReader(make([]byte, 5000)), 3000}, 5000
+	})
+	defer DeregisterReaderAtHandler("failing")
+
+	conn, mc := newRWMockConn(2)
+	mc.maxWriteSize = 1000
+	// the server replies to the empty packet with an error
+	conn.data = mockErr(6, 1300, "invalid data")
+	err := mc.clearResult().handleInFileRequest("Reader::failing")
+	if err == nil || err.Error() != "read failed" {
+		t.Errorf("expected read error, got %v", err)
+	}
+	// 3 content packets and the empty packet
+	if len(conn.written) != 3*1004+4 {
+		t.Errorf("unexpected packets written: %d bytes", len(conn.written))
+	}
+}
+
+func TestRegisterFS(t *testing.T) {
+	RegisterFS("fixtures", fstest.MapFS{
+		"data/users.csv": &fstest.MapFile{Data: []byte("1,gopher\n")},
+	})
+	defer DeregisterFS("fixtures")
+
+	conn, mc := newRWMockConn(2)
+	mc.maxWriteSize = 1000
+	conn.data = []byte{7, 0, 0, 4, 0, 0, 0, 2, 0, 0, 0}
+	// the server might return an absolute path
+	if err := mc.clearResult().handleInFileRequest("/var/lib/mysql/FS::fixtures/data/users.csv"); err != nil {
+		t.Fatal(err)
+	}
+	expected := append(mockPacket(2, []byte("1,gopher\n")), 0, 0, 0, 3)
+	if !bytes.Equal(conn.written, expected) {
+		t.Errorf("expected %q, got %q", expected, conn.written)
+	}
+
+	for name, msg := range map[string]string{
+		"FS::other/data/users.csv":   "file system 'other' is not registered",
+		"FS::fixtures/data/none.csv": "open data/none.csv: file does not exist",
+		"FS::fixtures/../etc/passwd": "invalid path '../etc/passwd' in file system 'fixtures'",
+		"FS::fixtures/data//x.csv":   "invalid path 'data//x.csv' in file system 'fixtures'",
+	} {
+		conn, mc := newRWMockConn(2)
+		mc.maxWriteSize = 1000
+		conn.data = mockErr(3, 1300, "invalid data")
+		if err := mc.clearResult().handleInFileRequest(name); err == nil || err.Error() != msg {
+			t.Errorf("%s: expected %q, got %v", name, msg, err)
+		}
+	}
+}
+
+// cancelingReader cancels the statement after the first read.
+type cancelingReader struct {
+	cancel context.CancelFunc
+}
+
+func (r cancelingReader) Read(p []byte) (int, error) {
+	r.cancel()
+	return copy(p, "1,gopher\n"), nil
+}
+
+func TestReaderHandlerContext(t *testing.T) {
+	type ctxKey struct{}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "stmt"))
+	defer cancel()
+	RegisterReaderHandlerContext("ctx", func(ctx context.Context) io.Reader {
+		if ctx.Value(ctxKey{}) != "stmt" {
+			t.Error("handler did not receive the context of the statement")
+		}
+		return cancelingReader{cancel}
+	})
+	defer DeregisterReaderHandler("ctx")
+
+	conn, mc := newRWMockConn(2)
+	mc.maxWriteSize = 1000
+	mc.ctx = ctx
+	err := mc.clearResult().handleInFileRequest("Reader::ctx")
+	var ci *CancelInfo
+	if !errors.As(err, &ci) || ci.Phase != CancelSend || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected CancelSend, got %v", err)
+	}
+	// the first read is sent, but not the empty packet which would load it
+	expected := mockPacket(2, []byte("1,gopher\n"))
+	if !bytes.Equal(conn.written, expected) {
+		t.Errorf("expected %q, got %q", expected, conn.written)
+	}
+	if !mc.closed.Load() {
+		t.Error("connection was not closed")
+	}
+}
+
+func TestConfigLocalFileRegistries(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("1,gopher\n")
+	file.Close()
+	RegisterLocalFile(file.Name())
+	defer DeregisterLocalFile(file.Name())
+	RegisterReaderHandler("global", func() io.Reader { return strings.NewReader("1,gopher\n") })
+	defer DeregisterReaderHandler("global")
+	fsys := fstest.MapFS{"users.csv": &fstest.MapFile{Data: []byte("1,gopher\n")}}
+	RegisterFS("global", fsys)
+	defer DeregisterFS("global")
+
+	load := func(cfg *Config, name string) error {
+		conn, mc := newRWMockConn(2)
+		mc.cfg = cfg
+		mc.maxWriteSize = 1000
+		conn.data = []byte{7, 0, 0, 4, 0, 0, 0, 2, 0, 0, 0}
+		return mc.clearResult().handleInFileRequest(name)
+	}
+
+	// the global registries are used without registries of the config
+	cfg := NewConfig()
+	if err := load(cfg, file.Name()); err != nil {
+		t.Error(err)
+	}
+	if err := load(cfg, "Reader::global"); err != nil {
+		t.Error(err)
+	}
+	if err := load(cfg, "FS::global/users.csv"); err != nil {
+		t.Error(err)
+	}
+
+	// the registries of the config replace the global ones
+	cfg.AllowedLocalFiles = []string{}
+	cfg.ReaderHandlers = map[string]func(context.Context) io.Reader{
+		"local": func(context.Context) io.Reader { return strings.NewReader("1,gopher\n") },
+	}
+	cfg.FileSystems = map[string]fs.FS{"local": fsys}
+	if err := load(cfg, file.Name()); err == nil {
+		t.Error("expected error for globally registered file")
+	}
+	if err := load(cfg, "Reader::global"); err == nil {
+		t.Error("expected error for globally registered reader")
+	}
+	if err := load(cfg, "Reader::local"); err != nil {
+		t.Error(err)
+	}
+	if err := load(cfg, "FS::global/users.csv"); err == nil {
+		t.Error("expected error for globally registered file system")
+	}
+	if err := load(cfg, "FS::local/users.csv"); err != nil {
+		t.Error(err)
+	}
+	cfg.AllowedLocalFiles = []string{file.Name()}
+	if err := load(cfg, file.Name()); err != nil {
+		t.Error(err)
+	}
+
+	cp := cfg.Clone()
+	cp.AllowedLocalFiles[0] = "other"
+	delete(cp.ReaderHandlers, "local")
+	delete(cp.FileSystems, "local")
+	if cfg.AllowedLocalFiles[0] != file.Name() || len(cfg.ReaderHandlers) != 1 || len(cfg.FileSystems) != 1 {
+		t.Error("registries of the original config were modified")
+	}
+}
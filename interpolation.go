@@ -0,0 +1,88 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+type interpolationKey struct{}
+
+// WithInterpolation returns a copy of ctx which overrides InterpolateParams
+// for the statements executed with it. With false, the parameters of the
+// statements are sent with a prepared statement, e.g. for untrusted input
+// which should never be part of the query string. With true, they are
+// interpolated even if InterpolateParams is not set.
+//
+// Enabling the interpolation fails for the same collations as
+// InterpolateParams.
+func WithInterpolation(ctx context.Context, interpolate bool) context.Context {
+	return context.WithValue(ctx, interpolationKey{}, interpolate)
+}
+
+// setInterpolation sets the interpolation override of ctx for the next
+// statement.
+func (mc *mysqlConn) setInterpolation(ctx context.Context) error {
+	interpolate, ok := ctx.Value(interpolationKey{}).(bool)
+	if !ok {
+		mc.interpolation = nil
+		return nil
+	}
+	if interpolate && mc.cfg.Collation != "" && unsafeCollations[mc.cfg.Collation] {
+		return errInvalidDSNUnsafeCollation
+	}
+	mc.interpolation = &interpolate
+	return nil
+}
+
+func (mc *mysqlConn) clearInterpolation() {
+	mc.interpolation = nil
+}
+
+// interpolates reports whether the parameters of the next statement are
+// interpolated into the query string.
+func (mc *mysqlConn) interpolates() bool {
+	if mc.interpolation != nil {
+		return *mc.interpolation
+	}
+	return mc.cfg.InterpolateParams
+}
+
+// sqlLiteral is an argument converted to an SQL literal by
+// Config.Interpolator. It can only be interpolated.
+type sqlLiteral string
+
+// interpolateArg converts an argument which is not a driver.Value with
+// Config.Interpolator. It reports whether v has been converted.
+func (mc *mysqlConn) interpolateArg(v any) (driver.Value, bool, error) {
+	if mc.cfg == nil || mc.cfg.Interpolator == nil || driver.IsValue(v) {
+		return nil, false, nil
+	}
+	lit, err := mc.cfg.Interpolator(v)
+	switch err {
+	case nil:
+		return sqlLiteral(lit), true, nil
+	case driver.ErrSkip:
+		return nil, false, nil
+	}
+	return nil, false, err
+}
+
+// hasLiteralArgs reports whether args contain arguments converted by
+// Config.Interpolator. Such statements are prepared by database/sql with the
+// original arguments unless they are interpolated.
+func hasLiteralArgs(args []driver.Value) bool {
+	for _, arg := range args {
+		if _, ok := arg.(sqlLiteral); ok {
+			return true
+		}
+	}
+	return false
+}
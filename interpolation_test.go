@@ -0,0 +1,59 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+func TestWithInterpolation(t *testing.T) {
+	args := []driver.NamedValue{{Ordinal: 1, Value: int64(42)}}
+
+	// disabled for a single statement
+	_, mc := newRWMockConn(0)
+	mc.cfg.InterpolateParams = true
+	ctx := WithInterpolation(context.Background(), false)
+	if _, err := mc.ExecContext(ctx, "SELECT ?", args); err != driver.ErrSkip {
+		t.Errorf("expected driver.ErrSkip, got %v", err)
+	}
+	if _, err := mc.QueryContext(ctx, "SELECT ?", args); err != driver.ErrSkip {
+		t.Errorf("expected driver.ErrSkip, got %v", err)
+	}
+	if !mc.interpolates() {
+		t.Error("override was not cleared")
+	}
+
+	// enabled for a single statement
+	conn, mc := newRWMockConn(0)
+	conn.queuedReplies = [][]byte{mockPacket(1, []byte{iOK, 0, 0, 2, 0, 0, 0})}
+	conn.maxReads = 1
+	ctx = WithInterpolation(context.Background(), true)
+	if _, err := mc.ExecContext(ctx, "SELECT ?", args); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(conn.written, []byte("SELECT 42")) {
+		t.Errorf("parameters were not interpolated: %q", conn.written)
+	}
+	if mc.interpolates() {
+		t.Error("override was not cleared")
+	}
+}
+
+func TestWithInterpolationUnsafeCollation(t *testing.T) {
+	_, mc := newRWMockConn(0)
+	mc.cfg.Collation = "gbk_chinese_ci"
+	ctx := WithInterpolation(context.Background(), true)
+	args := []driver.NamedValue{{Ordinal: 1, Value: "abc"}}
+	if _, err := mc.ExecContext(ctx, "SELECT ?", args); err != errInvalidDSNUnsafeCollation {
+		t.Errorf("expected errInvalidDSNUnsafeCollation, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build !go1.23
+
+package mysql
+
+import "net"
+
+// setKeepAlive configures the TCP keep-alives of tc. The interval can not be
+// set separately before Go 1.23.
+func (cfg *Config) setKeepAlive(tc *net.TCPConn) error {
+	if cfg.tcpKeepAlive < 0 {
+		return tc.SetKeepAlive(false)
+	}
+	if err := tc.SetKeepAlive(true); err != nil {
+		return err
+	}
+	if cfg.tcpKeepAlive > 0 {
+		return tc.SetKeepAlivePeriod(cfg.tcpKeepAlive)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSetKeepAlive(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	tc := conn.(*net.TCPConn)
+
+	for _, opt := range []Option{
+		TCPKeepAlive(0, 0),
+		TCPKeepAlive(time.Minute, 0),
+		TCPKeepAlive(time.Minute, 10*time.Second),
+	} {
+		cfg := NewConfig()
+		if err := cfg.Apply(opt); err != nil {
+			t.Fatal(err)
+		}
+		if err := cfg.setKeepAlive(tc); err != nil {
+			t.Errorf("tcpKeepAlive=%v, tcpKeepAliveInterval=%v: %v", cfg.tcpKeepAlive, cfg.tcpKeepAliveIntv, err)
+		}
+	}
+	if err := NewConfig().setKeepAlive(tc); err != nil {
+		t.Errorf("default: %v", err)
+	}
+
+	if err := TCPKeepAlive(-time.Second, 0)(NewConfig()); err == nil {
+		t.Error("expected error for negative idle time")
+	}
+}
@@ -0,0 +1,168 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package kerberos implements Kerberos / GSSAPI authentication for the
+// Go MySQL Driver.
+//
+// It supports the authentication_kerberos_client plugin of MySQL Enterprise
+// (8.0.26+) and the auth_gssapi_client plugin of MariaDB. The GSSAPI
+// implementation itself is not part of this package, since it requires
+// either cgo or a large dependency. Instead an adapter for the GSSAPI library
+// of your choice (e.g. github.com/jcmturner/gokrb5 or the system libgssapi)
+// must be provided:
+//
+//	err := kerberos.Register(func() (kerberos.Client, error) {
+//		return newGokrb5Client(krbConfig, ccache), nil
+//	})
+//	db, err := sql.Open("mysql", "alice@tcp(db.example.com:3306)/dbname")
+//
+// The user name must match the principal of the Kerberos ticket. No password
+// is required.
+package kerberos
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Plugin names used by the servers.
+const (
+	MySQLPluginName   = "authentication_kerberos_client"
+	MariaDBPluginName = "auth_gssapi_client"
+)
+
+// Client is a GSSAPI client establishing a security context with the
+// database server. A new Client is created for every connection.
+type Client interface {
+	// InitSecContext initiates a security context for the given service
+	// principal name and returns the first token to send to the server.
+	// needContinue reports whether the server is expected to answer with a
+	// token which must be passed to Continue.
+	InitSecContext(spn string) (token []byte, needContinue bool, err error)
+
+	// Continue processes a token received from the server and returns the
+	// next token to send.
+	Continue(token []byte) (out []byte, needContinue bool, err error)
+
+	// DeleteSecContext releases the resources of the security context.
+	DeleteSecContext() error
+}
+
+// ErrMalformedSPN is returned when the server does not send a valid
+// service principal name.
+var ErrMalformedSPN = errors.New("kerberos: malformed service principal name from server")
+
+// Register registers the Kerberos authentication plugins of MySQL and
+// MariaDB with the driver. newClient is called for every connection
+// authenticating with Kerberos.
+func Register(newClient func() (Client, error)) error {
+	if err := mysql.RegisterAuthPlugin(MySQLPluginName, &plugin{newClient: newClient, parseSPN: parseMySQLSPN}); err != nil {
+		return err
+	}
+	return mysql.RegisterAuthPlugin(MariaDBPluginName, &plugin{newClient: newClient, parseSPN: parseMariaDBSPN})
+}
+
+// Deregister removes the Kerberos authentication plugins.
+func Deregister() {
+	mysql.DeregisterAuthPlugin(MySQLPluginName)
+	mysql.DeregisterAuthPlugin(MariaDBPluginName)
+}
+
+type plugin struct {
+	newClient func() (Client, error)
+	parseSPN  func(authData []byte) (string, error)
+}
+
+func (p *plugin) InitAuth(authData []byte, cfg *mysql.Config) ([]byte, mysql.AuthMoreDataFunc, error) {
+	spn, err := p.parseSPN(authData)
+	if err != nil {
+		if err == errNoSPN {
+			// The plugin has been announced in the initial handshake, which
+			// only carries a scramble. Send an empty response; the server
+			// switches to this plugin again with the SPN.
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	client, err := p.newClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	token, needContinue, err := client.InitSecContext(spn)
+	if err != nil || !needContinue {
+		client.DeleteSecContext()
+		return token, nil, err
+	}
+
+	released := false
+	release := func() {
+		if !released {
+			released = true
+			client.DeleteSecContext()
+		}
+	}
+	next := func(data []byte) ([]byte, error) {
+		if data == nil {
+			// the exchange ended, e.g. the server sent an error
+			release()
+			return nil, nil
+		}
+		out, needContinue, err := client.Continue(data)
+		if err != nil || !needContinue {
+			release()
+		}
+		return out, err
+	}
+	return token, next, nil
+}
+
+var errNoSPN = errors.New("kerberos: no service principal name")
+
+// parseMySQLSPN parses the auth data sent by authentication_kerberos:
+// SPN length [2 bytes], SPN, realm length [2 bytes], realm.
+func parseMySQLSPN(authData []byte) (string, error) {
+	if len(authData) < 2 {
+		return "", errNoSPN
+	}
+	n := int(binary.LittleEndian.Uint16(authData))
+	if len(authData) < 2+n+2 {
+		return "", errNoSPN
+	}
+	spn := string(authData[2 : 2+n])
+	pos := 2 + n
+	m := int(binary.LittleEndian.Uint16(authData[pos:]))
+	if len(authData) != pos+2+m {
+		return "", errNoSPN
+	}
+	realm := string(authData[pos+2:])
+
+	if spn == "" {
+		return "", ErrMalformedSPN
+	}
+	if realm != "" && !strings.Contains(spn, "@") {
+		spn += "@" + realm
+	}
+	return spn, nil
+}
+
+// parseMariaDBSPN parses the auth data sent by auth_gssapi:
+// SPN [null terminated string], mechanism [null terminated string].
+func parseMariaDBSPN(authData []byte) (string, error) {
+	spn, _, ok := strings.Cut(string(authData), "\x00")
+	if !ok {
+		return "", errNoSPN
+	}
+	if spn == "" {
+		return "", ErrMalformedSPN
+	}
+	return spn, nil
+}
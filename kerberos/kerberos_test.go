@@ -0,0 +1,116 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kerberos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+type fakeClient struct {
+	spn     string
+	rounds  int
+	deleted bool
+}
+
+func (c *fakeClient) InitSecContext(spn string) ([]byte, bool, error) {
+	c.spn = spn
+	return []byte("token0"), true, nil
+}
+
+func (c *fakeClient) Continue(token []byte) ([]byte, bool, error) {
+	c.rounds++
+	return append([]byte("re:"), token...), c.rounds < 2, nil
+}
+
+func (c *fakeClient) DeleteSecContext() error {
+	c.deleted = true
+	return nil
+}
+
+func TestParseMySQLSPN(t *testing.T) {
+	data := []byte{17, 0}
+	data = append(data, "mysql/db.example."...)
+	data = append(data, 11, 0)
+	data = append(data, "EXAMPLE.COM"...)
+
+	spn, err := parseMySQLSPN(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "mysql/db.example.@EXAMPLE.COM"; spn != expected {
+		t.Errorf("expected %q, got %q", expected, spn)
+	}
+
+	// scramble from the initial handshake
+	if _, err := parseMySQLSPN(bytes.Repeat([]byte{0x42}, 20)); err != errNoSPN {
+		t.Errorf("expected errNoSPN, got %v", err)
+	}
+}
+
+func TestParseMariaDBSPN(t *testing.T) {
+	spn, err := parseMariaDBSPN([]byte("mariadb/db@EXAMPLE.COM\x00Kerberos\x00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "mariadb/db@EXAMPLE.COM"; spn != expected {
+		t.Errorf("expected %q, got %q", expected, spn)
+	}
+}
+
+func TestPluginExchange(t *testing.T) {
+	client := &fakeClient{}
+	p := &plugin{
+		newClient: func() (Client, error) { return client, nil },
+		parseSPN:  parseMariaDBSPN,
+	}
+
+	token, next, err := p.InitAuth([]byte("mariadb/db\x00Kerberos\x00"), mysql.NewConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(token) != "token0" || next == nil {
+		t.Fatalf("unexpected initial response %q", token)
+	}
+	if client.spn != "mariadb/db" {
+		t.Errorf("unexpected SPN %q", client.spn)
+	}
+
+	for _, in := range []string{"a", "b"} {
+		out, err := next([]byte(in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != "re:"+in {
+			t.Errorf("unexpected token %q", out)
+		}
+	}
+	if !client.deleted {
+		t.Error("expected security context to be deleted")
+	}
+
+	// the server fails the authentication before the exchange is complete
+	client = &fakeClient{}
+	_, next, err = p.InitAuth([]byte("mariadb/db\x00Kerberos\x00"), mysql.NewConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := next(nil); err != nil || !client.deleted {
+		t.Errorf("expected security context to be deleted, got %v", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if err := Register(func() (Client, error) { return &fakeClient{}, nil }); err != nil {
+		t.Fatal(err)
+	}
+	Deregister()
+}
@@ -0,0 +1,355 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned by Keyset.Where for cursors which were not
+// created by Keyset.Cursor of a keyset with the same number of columns.
+var ErrInvalidCursor = errors.New("mysql: invalid keyset cursor")
+
+// Keyset describes the ORDER BY columns of a keyset paginated query. Instead
+// of skipping the rows of the previous pages with OFFSET, the next page
+// starts after the last row of the previous page, which is identified by a
+// cursor containing the values of the keyset columns:
+//
+//	ks := mysql.Keyset{{Name: "created_at", Desc: true}, {Name: "id", Desc: true}}
+//	where, args, err := ks.Where(cursor)
+//	...
+//	query := "SELECT id, created_at, title FROM posts"
+//	if where != "" {
+//		query += " WHERE " + where
+//	}
+//	rows, err := db.Query(query+" "+ks.OrderBy()+" LIMIT 50", args...)
+//	...
+//	next, err := ks.Cursor(lastCreatedAt, lastID)
+//
+// The columns must be NOT NULL and identify a row uniquely, e.g. by ending
+// with the primary key.
+type Keyset []KeysetColumn
+
+// KeysetColumn is a column of a Keyset.
+type KeysetColumn struct {
+	// Name is the column name, optionally qualified with the table name
+	// ("posts.id"). It is quoted in the generated SQL.
+	Name string
+	// Desc sorts the column in descending order.
+	Desc bool
+}
+
+// keysetValue is the encoded form of a cursor value. The type tag keeps
+// times, decimals and binary strings intact across the round trip.
+type keysetValue [2]string
+
+const (
+	keysetInt     = "i"
+	keysetUint    = "u"
+	keysetFloat   = "f"
+	keysetString  = "s"
+	keysetBytes   = "b"
+	keysetBool    = "?"
+	keysetTime    = "t"
+	keysetDecimal = "d"
+)
+
+// Cursor returns the opaque cursor of the row with the given keyset column
+// values, in the order of the keyset columns. The cursor is URL safe.
+func (k Keyset) Cursor(values ...any) (string, error) {
+	if len(values) != len(k) {
+		return "", fmt.Errorf("mysql: keyset has %d columns, got %d values", len(k), len(values))
+	}
+	enc := make([]keysetValue, len(values))
+	for i, v := range values {
+		kv, err := encodeKeysetValue(v)
+		if err != nil {
+			return "", fmt.Errorf("mysql: keyset column %q: %w", k[i].Name, err)
+		}
+		enc[i] = kv
+	}
+	b, err := json.Marshal(enc)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CursorFromRow returns the cursor of a row scanned into row, e.g. with
+// *any or sql.RawBytes destinations, using the column metadata of the result
+// to find the keyset columns by name and to encode values scanned as bytes.
+func (k Keyset) CursorFromRow(columns []*sql.ColumnType, row []any) (string, error) {
+	if len(columns) != len(row) {
+		return "", fmt.Errorf("mysql: got %d columns and %d values", len(columns), len(row))
+	}
+	values := make([]any, len(k))
+	for i, kc := range k {
+		name := kc.Name
+		if dot := strings.LastIndexByte(name, '.'); dot >= 0 {
+			name = name[dot+1:]
+		}
+		j := 0
+		for j < len(columns) && !strings.EqualFold(columns[j].Name(), name) {
+			j++
+		}
+		if j == len(columns) {
+			return "", fmt.Errorf("mysql: keyset column %q is not in the result", kc.Name)
+		}
+		v := row[j]
+		switch rv := v.(type) {
+		case *any:
+			v = *rv
+		case *sql.RawBytes:
+			v = []byte(*rv)
+		case sql.RawBytes:
+			v = []byte(rv)
+		}
+		if b, ok := v.([]byte); ok {
+			v = keysetValueOf(columns[j].DatabaseTypeName(), b)
+		}
+		values[i] = v
+	}
+	return k.Cursor(values...)
+}
+
+// keysetValueOf converts a value in the text protocol format to the Go type
+// matching the column type. Temporal and decimal values are compared as
+// strings by the server, which is exact for the formats sent by it.
+func keysetValueOf(typeName string, b []byte) any {
+	switch typeName {
+	case "BINARY", "VARBINARY", "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BIT":
+		return b
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT", "YEAR":
+		if n, err := strconv.ParseInt(string(b), 10, 64); err == nil {
+			return n
+		}
+	case "UNSIGNED TINYINT", "UNSIGNED SMALLINT", "UNSIGNED MEDIUMINT", "UNSIGNED INT", "UNSIGNED BIGINT":
+		if n, err := strconv.ParseUint(string(b), 10, 64); err == nil {
+			return n
+		}
+	case "DECIMAL":
+		return Decimal{s: string(b)}
+	}
+	return string(b)
+}
+
+func encodeKeysetValue(v any) (keysetValue, error) {
+	switch v := v.(type) {
+	case Decimal:
+		return keysetValue{keysetDecimal, v.String()}, nil
+	case *Decimal:
+		if v != nil {
+			return keysetValue{keysetDecimal, v.String()}, nil
+		}
+		return keysetValue{}, errors.New("NULL values are not supported")
+	case time.Time:
+		return keysetValue{keysetTime, v.UTC().Format(time.RFC3339Nano)}, nil
+	case []byte:
+		if v == nil {
+			return keysetValue{}, errors.New("NULL values are not supported")
+		}
+		return keysetValue{keysetBytes, base64.RawStdEncoding.EncodeToString(v)}, nil
+	case string:
+		return keysetValue{keysetString, v}, nil
+	case bool:
+		return keysetValue{keysetBool, strconv.FormatBool(v)}, nil
+	case float32:
+		return keysetValue{keysetFloat, strconv.FormatFloat(float64(v), 'g', -1, 32)}, nil
+	case float64:
+		return keysetValue{keysetFloat, strconv.FormatFloat(v, 'g', -1, 64)}, nil
+	case int:
+		return keysetValue{keysetInt, strconv.FormatInt(int64(v), 10)}, nil
+	case int8:
+		return keysetValue{keysetInt, strconv.FormatInt(int64(v), 10)}, nil
+	case int16:
+		return keysetValue{keysetInt, strconv.FormatInt(int64(v), 10)}, nil
+	case int32:
+		return keysetValue{keysetInt, strconv.FormatInt(int64(v), 10)}, nil
+	case int64:
+		return keysetValue{keysetInt, strconv.FormatInt(v, 10)}, nil
+	case uint:
+		return keysetValue{keysetUint, strconv.FormatUint(uint64(v), 10)}, nil
+	case uint8:
+		return keysetValue{keysetUint, strconv.FormatUint(uint64(v), 10)}, nil
+	case uint16:
+		return keysetValue{keysetUint, strconv.FormatUint(uint64(v), 10)}, nil
+	case uint32:
+		return keysetValue{keysetUint, strconv.FormatUint(uint64(v), 10)}, nil
+	case uint64:
+		return keysetValue{keysetUint, strconv.FormatUint(v, 10)}, nil
+	case nil:
+		return keysetValue{}, errors.New("NULL values are not supported")
+	case driver.Valuer:
+		dv, err := v.Value()
+		if err != nil {
+			return keysetValue{}, err
+		}
+		if _, ok := dv.(driver.Valuer); ok {
+			return keysetValue{}, fmt.Errorf("unsupported type %T", v)
+		}
+		return encodeKeysetValue(dv)
+	}
+	return keysetValue{}, fmt.Errorf("unsupported type %T", v)
+}
+
+func decodeKeysetValue(kv keysetValue) (any, error) {
+	switch kv[0] {
+	case keysetInt:
+		return strconv.ParseInt(kv[1], 10, 64)
+	case keysetUint:
+		return strconv.ParseUint(kv[1], 10, 64)
+	case keysetFloat:
+		return strconv.ParseFloat(kv[1], 64)
+	case keysetString:
+		return kv[1], nil
+	case keysetBytes:
+		return base64.RawStdEncoding.DecodeString(kv[1])
+	case keysetBool:
+		return strconv.ParseBool(kv[1])
+	case keysetTime:
+		return time.Parse(time.RFC3339Nano, kv[1])
+	case keysetDecimal:
+		return NewDecimal(kv[1])
+	}
+	return nil, fmt.Errorf("unknown type %q", kv[0])
+}
+
+// decodeCursor returns the values of a cursor created by Cursor.
+func (k Keyset) decodeCursor(cursor string) ([]any, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	var enc []keysetValue
+	if err := json.Unmarshal(b, &enc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	if len(enc) != len(k) {
+		return nil, fmt.Errorf("%w: got %d values for %d columns", ErrInvalidCursor, len(enc), len(k))
+	}
+	values := make([]any, len(enc))
+	for i, kv := range enc {
+		v, err := decodeKeysetValue(kv)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
+		if d, ok := v.(Decimal); ok {
+			// Decimal is a driver.Valuer, send the plain string
+			v = d.String()
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// Where returns the condition selecting the rows after the row of cursor and
+// its arguments. It returns an empty condition for an empty cursor, i.e. the
+// first page.
+//
+// If all columns are sorted in the same direction, the condition is a row
+// comparison "(a, b) > (?, ?)". Otherwise it is expanded to
+// "(a > ?) OR (a = ? AND b < ?)".
+func (k Keyset) Where(cursor string) (string, []any, error) {
+	if len(k) == 0 {
+		return "", nil, errors.New("mysql: empty keyset")
+	}
+	if cursor == "" {
+		return "", nil, nil
+	}
+	values, err := k.decodeCursor(cursor)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sameDirection := true
+	for _, c := range k[1:] {
+		sameDirection = sameDirection && c.Desc == k[0].Desc
+	}
+
+	var b strings.Builder
+	if sameDirection {
+		b.WriteByte('(')
+		for i, c := range k {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(quoteKeysetColumn(c.Name))
+		}
+		b.WriteString(") ")
+		b.WriteString(keysetOperator(k[0].Desc))
+		b.WriteString(" (")
+		for i := range k {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteByte('?')
+		}
+		b.WriteByte(')')
+		return b.String(), values, nil
+	}
+
+	args := make([]any, 0, len(k)*(len(k)+1)/2)
+	for i, c := range k {
+		if i > 0 {
+			b.WriteString(" OR ")
+		}
+		b.WriteByte('(')
+		for j := 0; j < i; j++ {
+			b.WriteString(quoteKeysetColumn(k[j].Name))
+			b.WriteString(" = ? AND ")
+			args = append(args, values[j])
+		}
+		b.WriteString(quoteKeysetColumn(c.Name))
+		b.WriteByte(' ')
+		b.WriteString(keysetOperator(c.Desc))
+		b.WriteString(" ?)")
+		args = append(args, values[i])
+	}
+	return b.String(), args, nil
+}
+
+// OrderBy returns the ORDER BY clause of the keyset.
+func (k Keyset) OrderBy() string {
+	var b strings.Builder
+	b.WriteString("ORDER BY ")
+	for i, c := range k {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(quoteKeysetColumn(c.Name))
+		if c.Desc {
+			b.WriteString(" DESC")
+		}
+	}
+	return b.String()
+}
+
+func keysetOperator(desc bool) string {
+	if desc {
+		return "<"
+	}
+	return ">"
+}
+
+// quoteKeysetColumn quotes each part of a possibly qualified column name.
+func quoteKeysetColumn(name string) string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = "`" + strings.ReplaceAll(p, "`", "``") + "`"
+	}
+	return strings.Join(parts, ".")
+}
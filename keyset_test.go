@@ -0,0 +1,106 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestKeysetCursorRoundTrip(t *testing.T) {
+	ks := Keyset{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}, {Name: "f"}, {Name: "g"}}
+	ts := time.Date(2026, 3, 4, 5, 6, 7, 123456000, time.FixedZone("", 3600))
+	dec, _ := NewDecimal("-12345678901234567890.10")
+	cursor, err := ks.Cursor(int32(-7), uint64(1<<63), "x'y", []byte{0, 0xff}, ts, dec, sql.NullInt64{Int64: 42, Valid: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	where, args, err := ks.Where(cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "(`a`, `b`, `c`, `d`, `e`, `f`, `g`) > (?, ?, ?, ?, ?, ?, ?)"; where != want {
+		t.Errorf("got %q, want %q", where, want)
+	}
+	want := []any{int64(-7), uint64(1 << 63), "x'y", []byte{0, 0xff}, ts.UTC(), "-12345678901234567890.10", int64(42)}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("got %#v, want %#v", args, want)
+	}
+}
+
+func TestKeysetWhereMixedDirections(t *testing.T) {
+	ks := Keyset{{Name: "p.score", Desc: true}, {Name: "p.id"}}
+	if got, want := ks.OrderBy(), "ORDER BY `p`.`score` DESC, `p`.`id`"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	cursor, err := ks.Cursor(3.5, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	where, args, err := ks.Where(cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "(`p`.`score` < ?) OR (`p`.`score` = ? AND `p`.`id` > ?)"; where != want {
+		t.Errorf("got %q, want %q", where, want)
+	}
+	if want := []any{3.5, 3.5, int64(10)}; !reflect.DeepEqual(args, want) {
+		t.Errorf("got %#v, want %#v", args, want)
+	}
+}
+
+func TestKeysetFirstPage(t *testing.T) {
+	where, args, err := Keyset{{Name: "id"}}.Where("")
+	if where != "" || args != nil || err != nil {
+		t.Errorf("got %q, %v, %v", where, args, err)
+	}
+}
+
+func TestKeysetErrors(t *testing.T) {
+	ks := Keyset{{Name: "id"}}
+	if _, err := ks.Cursor(nil); err == nil {
+		t.Error("expected error for NULL value")
+	}
+	if _, err := ks.Cursor(1, 2); err == nil {
+		t.Error("expected error for wrong number of values")
+	}
+	if _, err := ks.Cursor(struct{}{}); err == nil {
+		t.Error("expected error for unsupported type")
+	}
+
+	two, _ := Keyset{{Name: "a"}, {Name: "b"}}.Cursor(1, 2)
+	for _, cursor := range []string{"!", "bm90IGpzb24", two} {
+		if _, _, err := ks.Where(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%q: got %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+}
+
+func TestKeysetValueOf(t *testing.T) {
+	tests := []struct {
+		typeName string
+		in       string
+		want     any
+	}{
+		{"BIGINT", "-5", int64(-5)},
+		{"UNSIGNED BIGINT", "18446744073709551615", uint64(18446744073709551615)},
+		{"DECIMAL", "1.50", Decimal{s: "1.50"}},
+		{"DATETIME", "2026-01-02 03:04:05.123", "2026-01-02 03:04:05.123"},
+		{"VARBINARY", "ab", []byte("ab")},
+		{"VARCHAR", "ab", "ab"},
+	}
+	for _, tt := range tests {
+		if got := keysetValueOf(tt.typeName, []byte(tt.in)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s %q: got %#v, want %#v", tt.typeName, tt.in, got, tt.want)
+		}
+	}
+}
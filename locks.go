@@ -0,0 +1,165 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Lock errors returned by the server. They match the errors of all statements
+// failing for the same reason with errors.Is:
+//
+//	if errors.Is(err, mysql.ErrLockNotAvailable) {
+//		// the row is locked by another worker, try the next one
+//	}
+var (
+	// ErrLockWaitTimeout is returned if a lock could not be acquired within
+	// innodb_lock_wait_timeout (ER_LOCK_WAIT_TIMEOUT).
+	ErrLockWaitTimeout = &MySQLError{Number: 1205, SQLState: [5]byte{'H', 'Y', '0', '0', '0'}, Message: "Lock wait timeout exceeded; try restarting transaction"}
+
+	// ErrLockNotAvailable is returned by statements with NOWAIT if a lock is
+	// held by another transaction (ER_LOCK_NOWAIT).
+	ErrLockNotAvailable = &MySQLError{Number: 3572, SQLState: [5]byte{'H', 'Y', '0', '0', '0'}, Message: "Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set."}
+)
+
+// lockDiagnosticsQuery fetches the current lock waits of the server.
+const lockDiagnosticsQuery = "SELECT locked_table, locked_index, waiting_pid, waiting_query, blocking_pid, blocking_query, blocking_trx_age " +
+	"FROM sys.innodb_lock_waits ORDER BY wait_age_secs DESC LIMIT 20"
+
+// LockWait is a row of sys.innodb_lock_waits: a transaction waiting for a lock
+// held by another transaction.
+type LockWait struct {
+	LockedTable    string
+	LockedIndex    string
+	WaitingPID     uint64 // connection ID of the waiting transaction
+	WaitingQuery   string
+	BlockingPID    uint64 // connection ID of the transaction holding the lock
+	BlockingQuery  string // empty if the blocking connection is idle
+	BlockingTrxAge string
+}
+
+// LockError is returned instead of the *MySQLError for ErrLockWaitTimeout
+// and ErrLockNotAvailable if Config.LockDiagnostics is set. It still matches
+// them with errors.Is and *MySQLError with errors.As.
+type LockError struct {
+	*MySQLError
+
+	// LockWaits holds the lock waits of the server queried right after the
+	// error. The wait of the failed statement itself has ended by then, but
+	// transactions blocking it usually still block other waiters.
+	LockWaits []LockWait
+
+	// DiagnosticsErr is the error of the query for LockWaits, e.g. if the sys
+	// schema is not available or the user lacks the privileges for it.
+	DiagnosticsErr error
+}
+
+func (le *LockError) Error() string {
+	if len(le.LockWaits) == 0 {
+		return le.MySQLError.Error()
+	}
+
+	var sb strings.Builder
+	sb.WriteString(le.MySQLError.Error())
+	sb.WriteString("; blocking connections:")
+	seen := make(map[uint64]bool)
+	for _, w := range le.LockWaits {
+		if seen[w.BlockingPID] {
+			continue
+		}
+		seen[w.BlockingPID] = true
+		fmt.Fprintf(&sb, " %d (transaction age %s", w.BlockingPID, w.BlockingTrxAge)
+		if w.BlockingQuery != "" {
+			fmt.Fprintf(&sb, ", query %q", w.BlockingQuery)
+		}
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
+
+func (le *LockError) Unwrap() error {
+	return le.MySQLError
+}
+
+// lockError attaches the current lock waits to the lock error me.
+func (mc *mysqlConn) lockError(me *MySQLError) error {
+	waits, err := mc.getLockWaits()
+	return &LockError{MySQLError: me, LockWaits: waits, DiagnosticsErr: err}
+}
+
+// getLockWaits queries sys.innodb_lock_waits.
+func (mc *mysqlConn) getLockWaits() ([]LockWait, error) {
+	// Send command
+	handleOk := mc.clearResult()
+	if err := mc.writeCommandPacketStr(comQuery, lockDiagnosticsQuery); err != nil {
+		return nil, err
+	}
+
+	// Read Result
+	resLen, err := handleOk.readResultSetHeaderPacket()
+	if err != nil {
+		return nil, err
+	}
+	rows := new(textRows)
+	rows.mc = mc
+	if rows.rs.columns, err = mc.readColumns(resLen); err != nil {
+		return nil, err
+	}
+	if resLen != 7 {
+		if err := mc.readUntilEOF(); err != nil {
+			return nil, err
+		}
+		return nil, ErrMalformPkt
+	}
+
+	var waits []LockWait
+	dest := make([]driver.Value, resLen)
+	for {
+		err := rows.readRow(dest)
+		if err == io.EOF {
+			return waits, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		waits = append(waits, LockWait{
+			LockedTable:    lockWaitString(dest[0]),
+			LockedIndex:    lockWaitString(dest[1]),
+			WaitingPID:     lockWaitUint(dest[2]),
+			WaitingQuery:   lockWaitString(dest[3]),
+			BlockingPID:    lockWaitUint(dest[4]),
+			BlockingQuery:  lockWaitString(dest[5]),
+			BlockingTrxAge: lockWaitString(dest[6]),
+		})
+	}
+}
+
+func lockWaitString(v driver.Value) string {
+	if raw, ok := v.([]byte); ok {
+		return string(raw)
+	}
+	return ""
+}
+
+func lockWaitUint(v driver.Value) uint64 {
+	switch v := v.(type) {
+	case int64:
+		return uint64(v)
+	case uint64:
+		return v
+	case []byte:
+		n, _ := strconv.ParseUint(string(v), 10, 64)
+		return n
+	}
+	return 0
+}
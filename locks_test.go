@@ -0,0 +1,132 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// mockErr returns an ERR packet.
+func mockErr(seq byte, errno uint16, message string) []byte {
+	payload := []byte{iERR, byte(errno), byte(errno >> 8), '#', 'H', 'Y', '0', '0', '0'}
+	return mockPacket(seq, append(payload, message...))
+}
+
+// mockLockWaits returns the response to lockDiagnosticsQuery.
+func mockLockWaits(waits ...LockWait) []byte {
+	resp := mockPacket(1, []byte{7})
+	names := []string{"locked_table", "locked_index", "waiting_pid", "waiting_query", "blocking_pid", "blocking_query", "blocking_trx_age"}
+	for i, name := range names {
+		resp = append(resp, mockColumn(byte(2+i), name, fieldTypeVarChar)...)
+	}
+	resp = append(resp, mockPacket(9, []byte{iEOF, 0, 0, 2, 0})...)
+	seq := byte(10)
+	for _, w := range waits {
+		row := appendLengthEncodedString(nil, w.LockedTable)
+		row = appendLengthEncodedString(row, w.LockedIndex)
+		row = appendLengthEncodedString(row, string(uint64ToString(w.WaitingPID)))
+		row = appendLengthEncodedString(row, w.WaitingQuery)
+		row = appendLengthEncodedString(row, string(uint64ToString(w.BlockingPID)))
+		if w.BlockingQuery == "" {
+			row = append(row, 0xfb) // NULL
+		} else {
+			row = appendLengthEncodedString(row, w.BlockingQuery)
+		}
+		row = appendLengthEncodedString(row, w.BlockingTrxAge)
+		resp = append(resp, mockPacket(seq, row)...)
+		seq++
+	}
+	return append(resp, mockPacket(seq, []byte{iEOF, 0, 0, 2, 0})...)
+}
+
+func TestLockErrorIs(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	conn.queuedReplies = [][]byte{mockErr(1, 3572, "Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set.")}
+	conn.maxReads = 1
+
+	_, err := mc.Exec("SELECT id FROM jobs FOR UPDATE NOWAIT", nil)
+	if !errors.Is(err, ErrLockNotAvailable) || errors.Is(err, ErrLockWaitTimeout) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var le *LockError
+	if errors.As(err, &le) {
+		t.Errorf("unexpected LockError without LockDiagnostics")
+	}
+	if conn.writes != 1 {
+		t.Errorf("expected 1 write, got %d", conn.writes)
+	}
+}
+
+func TestLockDiagnostics(t *testing.T) {
+	wait := LockWait{
+		LockedTable:    "`app`.`jobs`",
+		LockedIndex:    "PRIMARY",
+		WaitingPID:     12,
+		WaitingQuery:   "UPDATE jobs SET state = 'done' WHERE id = 1",
+		BlockingPID:    10,
+		BlockingTrxAge: "00:01:05",
+	}
+
+	conn, mc := newRWMockConn(0)
+	mc.cfg.LockDiagnostics = true
+	conn.queuedReplies = [][]byte{
+		mockErr(1, 1205, "Lock wait timeout exceeded; try restarting transaction"),
+		mockLockWaits(wait, wait),
+	}
+	conn.maxReads = 2
+
+	_, err := mc.Exec("UPDATE jobs SET state = 'done' WHERE id = 1", nil)
+	if !errors.Is(err, ErrLockWaitTimeout) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var me *MySQLError
+	if !errors.As(err, &me) || me.Number != 1205 {
+		t.Errorf("expected *MySQLError 1205, got %v", me)
+	}
+	var le *LockError
+	if !errors.As(err, &le) {
+		t.Fatalf("expected LockError, got %T", err)
+	}
+	if le.DiagnosticsErr != nil {
+		t.Fatal(le.DiagnosticsErr)
+	}
+	if !reflect.DeepEqual(le.LockWaits, []LockWait{wait, wait}) {
+		t.Errorf("unexpected lock waits %+v", le.LockWaits)
+	}
+	want := "Error 1205 (HY000): Lock wait timeout exceeded; try restarting transaction; blocking connections: 10 (transaction age 00:01:05)"
+	if err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err.Error())
+	}
+}
+
+func TestLockDiagnosticsError(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.LockDiagnostics = true
+	conn.queuedReplies = [][]byte{
+		mockErr(1, 1205, "Lock wait timeout exceeded; try restarting transaction"),
+		mockErr(1, 1142, "SELECT command denied to user"),
+	}
+	conn.maxReads = 2
+
+	_, err := mc.Exec("UPDATE jobs SET state = 'done' WHERE id = 1", nil)
+	var le *LockError
+	if !errors.As(err, &le) {
+		t.Fatalf("expected LockError, got %T", err)
+	}
+	var me *MySQLError
+	if !errors.As(le.DiagnosticsErr, &me) || me.Number != 1142 {
+		t.Errorf("unexpected diagnostics error %v", le.DiagnosticsErr)
+	}
+	if strings.Contains(err.Error(), "blocking") {
+		t.Errorf("unexpected error message %q", err.Error())
+	}
+}
@@ -0,0 +1,619 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package mysqltest provides a fake MySQL server for tests of code using the
+// Go MySQL Driver.
+//
+// The server speaks enough of the client/server protocol to connect with the
+// driver, authenticate and run text protocol queries. The responses to the
+// queries are scripted with canned result sets, errors and delays:
+//
+//	srv := mysqltest.NewServer()
+//	defer srv.Close()
+//
+//	srv.Handle("SELECT id, name FROM users", mysqltest.Response{
+//		Columns: []string{"id", "name"},
+//		Rows:    [][]any{{1, "alice"}, {2, "bob"}},
+//	})
+//	srv.Handle("DELETE FROM users", mysqltest.Response{
+//		Err: &mysql.MySQLError{Number: 1213, Message: "Deadlock found"},
+//	})
+//
+//	db, err := sql.Open("mysql", srv.DSN())
+//
+// Prepared statements are not supported. The DSN returned by Server.DSN sets
+// interpolateParams=true, so that queries with arguments are sent as text.
+package mysqltest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Response is the scripted response to a query.
+type Response struct {
+	// Columns and Rows are the result set. Values can be nil (NULL),
+	// integers, floats, bools, strings, []byte and time.Time. The type of a
+	// column is derived from its first non-nil value. If Columns is nil,
+	// the server responds with an OK packet.
+	Columns []string
+	Rows    [][]any
+
+	AffectedRows uint64 // affected rows of the OK packet
+	LastInsertID uint64 // last insert id of the OK packet
+
+	Err   error         // sent as error packet, with number 1105 unless Err is a *mysql.MySQLError
+	Close bool          // close the connection instead of responding
+	Delay time.Duration // delay of the response, in addition to Server.SetLatency
+}
+
+// HandlerFunc returns the response to query. It returns false to fall back to
+// the next handler.
+type HandlerFunc func(query string) (Response, bool)
+
+// Server is a fake MySQL server listening on a loopback address.
+type Server struct {
+	// Version is the server version sent in the handshake.
+	Version string
+
+	// Auth, if set, is called with the user and database of every new
+	// connection. An error rejects the connection, with number 1045 unless
+	// the error is a *mysql.MySQLError.
+	Auth func(user, database string) error
+
+	ln      net.Listener
+	latency atomic.Int64
+	connID  atomic.Uint32
+	wg      sync.WaitGroup
+	done    chan struct{} // closed by Close, interrupts delays
+
+	mu       sync.Mutex
+	queries  map[string]Response
+	handlers []HandlerFunc
+	received []string
+	conns    map[net.Conn]struct{}
+	closed   bool
+}
+
+// defaultResponses answer the statements of transactions which are not
+// scripted.
+var defaultResponses = map[string]Response{
+	"START TRANSACTION":           {},
+	"START TRANSACTION READ ONLY": {},
+	"COMMIT":                      {},
+	"ROLLBACK":                    {},
+	"SELECT @@max_allowed_packet": {Columns: []string{"@@max_allowed_packet"}, Rows: [][]any{{64 << 20}}},
+	"SELECT @@sql_generate_invisible_primary_key":      {Columns: []string{"@@sql_generate_invisible_primary_key"}, Rows: [][]any{{0}}},
+	"SET TRANSACTION ISOLATION LEVEL READ UNCOMMITTED": {},
+	"SET TRANSACTION ISOLATION LEVEL READ COMMITTED":   {},
+	"SET TRANSACTION ISOLATION LEVEL REPEATABLE READ":  {},
+	"SET TRANSACTION ISOLATION LEVEL SERIALIZABLE":     {},
+}
+
+// NewServer starts and returns a new Server. It panics if it fails to
+// listen on a loopback address, like httptest.NewServer.
+func NewServer() *Server {
+	s := NewUnstartedServer()
+	s.Start()
+	return s
+}
+
+// NewUnstartedServer returns a new Server which is not started yet, so that
+// Version and Auth can be set before Start is called.
+func NewUnstartedServer() *Server {
+	return &Server{
+		Version: "8.0.36-mysqltest",
+		queries: make(map[string]Response),
+		conns:   make(map[net.Conn]struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start starts the server.
+func (s *Server) Start() {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		if ln, err = net.Listen("tcp6", "[::1]:0"); err != nil {
+			panic("mysqltest: failed to listen on a port: " + err.Error())
+		}
+	}
+	s.ln = ln
+	s.wg.Add(1)
+	go s.serve()
+}
+
+// Addr returns the address of the server.
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// DSN returns a DSN to connect to the server.
+func (s *Server) DSN() string {
+	return "root@tcp(" + s.Addr() + ")/test?interpolateParams=true"
+}
+
+// Close closes the listener and all connections and waits until all
+// connections are closed.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		close(s.done)
+	}
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	err := s.ln.Close()
+	s.wg.Wait()
+	return err
+}
+
+// Handle sets the response to query. The query must match exactly.
+func (s *Server) Handle(query string, resp Response) {
+	s.mu.Lock()
+	s.queries[query] = resp
+	s.mu.Unlock()
+}
+
+// HandleFunc adds a handler for the queries without a response set by
+// Handle. Handlers are called in the order in which they were added.
+func (s *Server) HandleFunc(fn HandlerFunc) {
+	s.mu.Lock()
+	s.handlers = append(s.handlers, fn)
+	s.mu.Unlock()
+}
+
+// SetLatency sets the delay of all responses, including the handshake.
+func (s *Server) SetLatency(d time.Duration) {
+	s.latency.Store(int64(d))
+}
+
+// Queries returns the queries received by the server. They include the
+// queries of the driver while connecting, e.g.
+// SELECT @@sql_generate_invisible_primary_key, which is sent by the first
+// connection of a connector to a server with Version 8.0.30 or later.
+func (s *Server) Queries() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.received...)
+}
+
+// response returns the response to query.
+func (s *Server) response(query string) Response {
+	s.mu.Lock()
+	s.received = append(s.received, query)
+	resp, ok := s.queries[query]
+	handlers := s.handlers
+	s.mu.Unlock()
+	if ok {
+		return resp
+	}
+	for _, fn := range handlers {
+		if resp, ok := fn(query); ok {
+			return resp
+		}
+	}
+	if resp, ok := defaultResponses[query]; ok {
+		return resp
+	}
+	return Response{Err: &mysql.MySQLError{Number: 1105, Message: "mysqltest: unexpected query: " + query}}
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			c := &serverConn{srv: s, conn: conn}
+			c.serve()
+			conn.Close()
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// Capabilities of the server.
+const (
+	clientLongPassword     = 0x00000001
+	clientLongFlag         = 0x00000004
+	clientConnectWithDB    = 0x00000008
+	clientProtocol41       = 0x00000200
+	clientTransactions     = 0x00002000
+	clientSecureConn       = 0x00008000
+	clientMultiStatements  = 0x00010000
+	clientMultiResults     = 0x00020000
+	clientPluginAuth       = 0x00080000
+	clientPluginAuthLenEnc = 0x00200000
+
+	serverCapabilities = clientLongPassword | clientLongFlag | clientConnectWithDB | clientProtocol41 |
+		clientTransactions | clientSecureConn | clientMultiStatements | clientMultiResults |
+		clientPluginAuth | clientPluginAuthLenEnc
+)
+
+// Commands answered by the server.
+const (
+	comQuit            = 0x01
+	comInitDB          = 0x02
+	comQuery           = 0x03
+	comPing            = 0x0e
+	comResetConnection = 0x1f
+)
+
+// Column types sent in result sets.
+const (
+	typeDouble    = 0x05
+	typeLongLong  = 0x08
+	typeDateTime  = 0x0c
+	typeVarString = 0xfd
+)
+
+const statusAutocommit = 0x0002
+
+var errClose = errors.New("close connection")
+
+type serverConn struct {
+	srv  *Server
+	conn net.Conn
+	seq  byte
+	buf  []byte
+}
+
+func (c *serverConn) serve() {
+	if err := c.handshake(); err != nil {
+		return
+	}
+	for {
+		c.seq = 0
+		data, err := c.readPacket()
+		if err != nil || len(data) == 0 {
+			return
+		}
+		switch data[0] {
+		case comQuit:
+			return
+		case comInitDB, comPing, comResetConnection:
+			c.delay(0)
+			err = c.writeOK(0, 0)
+		case comQuery:
+			err = c.writeResponse(c.srv.response(string(data[1:])))
+		default:
+			c.delay(0)
+			err = c.writeError(&mysql.MySQLError{Number: 1047, Message: fmt.Sprintf("mysqltest: unsupported command %d", data[0])})
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// delay sleeps for the latency of the server and d.
+func (c *serverConn) delay(d time.Duration) {
+	if d += time.Duration(c.srv.latency.Load()); d > 0 {
+		t := time.NewTimer(d)
+		select {
+		case <-t.C:
+		case <-c.srv.done:
+			t.Stop()
+		}
+	}
+}
+
+func (c *serverConn) handshake() error {
+	c.delay(0)
+	scramble := []byte("mysqltest-scramble12")
+
+	pkt := append(c.startPacket(), 10)
+	pkt = append(pkt, c.srv.Version...)
+	pkt = append(pkt, 0)
+	pkt = binary.LittleEndian.AppendUint32(pkt, c.srv.connID.Add(1))
+	pkt = append(pkt, scramble[:8]...)
+	pkt = append(pkt, 0)
+	pkt = binary.LittleEndian.AppendUint16(pkt, uint16(serverCapabilities&0xffff))
+	pkt = append(pkt, 255) // utf8mb4_0900_ai_ci
+	pkt = binary.LittleEndian.AppendUint16(pkt, statusAutocommit)
+	pkt = binary.LittleEndian.AppendUint16(pkt, uint16(serverCapabilities>>16))
+	pkt = append(pkt, byte(len(scramble)+1))
+	pkt = append(pkt, make([]byte, 10)...)
+	pkt = append(pkt, scramble[8:]...)
+	pkt = append(pkt, 0)
+	pkt = append(pkt, "mysql_native_password"...)
+	pkt = append(pkt, 0)
+	if err := c.writePacket(pkt); err != nil {
+		return err
+	}
+
+	data, err := c.readPacket()
+	if err != nil {
+		return err
+	}
+	user, database, err := parseHandshakeResponse(data)
+	if err != nil {
+		return err
+	}
+	if c.srv.Auth != nil {
+		if err := c.srv.Auth(user, database); err != nil {
+			var merr *mysql.MySQLError
+			if !errors.As(err, &merr) {
+				merr = &mysql.MySQLError{Number: 1045, Message: err.Error()}
+			}
+			c.writeError(merr)
+			return errClose
+		}
+	}
+	return c.writeOK(0, 0)
+}
+
+// parseHandshakeResponse returns the user and database of the handshake
+// response packet of the client.
+func parseHandshakeResponse(data []byte) (user, database string, err error) {
+	if len(data) < 32 {
+		return "", "", io.ErrUnexpectedEOF
+	}
+	flags := binary.LittleEndian.Uint32(data)
+	data = data[32:]
+
+	n := bytes.IndexByte(data, 0)
+	if n < 0 {
+		return "", "", io.ErrUnexpectedEOF
+	}
+	user, data = string(data[:n]), data[n+1:]
+
+	// auth response
+	if len(data) == 0 {
+		return "", "", io.ErrUnexpectedEOF
+	}
+	var authLen uint64
+	if flags&clientPluginAuthLenEnc != 0 {
+		authLen, n = readLengthEncodedInteger(data)
+	} else {
+		authLen, n = uint64(data[0]), 1
+	}
+	if uint64(len(data)) < uint64(n)+authLen {
+		return "", "", io.ErrUnexpectedEOF
+	}
+	data = data[uint64(n)+authLen:]
+
+	if flags&clientConnectWithDB != 0 {
+		if n = bytes.IndexByte(data, 0); n >= 0 {
+			database = string(data[:n])
+		}
+	}
+	return user, database, nil
+}
+
+func (c *serverConn) writeResponse(resp Response) error {
+	c.delay(resp.Delay)
+	if resp.Close {
+		return errClose
+	}
+	if resp.Err != nil {
+		var merr *mysql.MySQLError
+		if !errors.As(resp.Err, &merr) {
+			merr = &mysql.MySQLError{Number: 1105, Message: resp.Err.Error()}
+		}
+		return c.writeError(merr)
+	}
+	if resp.Columns == nil {
+		return c.writeOK(resp.AffectedRows, resp.LastInsertID)
+	}
+
+	if err := c.writePacket(appendLengthEncodedInteger(c.startPacket(), uint64(len(resp.Columns)))); err != nil {
+		return err
+	}
+	for i, name := range resp.Columns {
+		if err := c.writePacket(appendColumn(c.startPacket(), name, columnType(resp.Rows, i))); err != nil {
+			return err
+		}
+	}
+	if err := c.writeEOF(); err != nil {
+		return err
+	}
+	for _, row := range resp.Rows {
+		pkt := c.startPacket()
+		for i := range resp.Columns {
+			var v any
+			if i < len(row) {
+				v = row[i]
+			}
+			if v == nil {
+				pkt = append(pkt, 0xfb)
+				continue
+			}
+			pkt = appendLengthEncodedString(pkt, formatValue(v))
+		}
+		if err := c.writePacket(pkt); err != nil {
+			return err
+		}
+	}
+	return c.writeEOF()
+}
+
+// columnType returns the type of column i, derived from its first non-nil
+// value.
+func columnType(rows [][]any, i int) byte {
+	for _, row := range rows {
+		if i >= len(row) || row[i] == nil {
+			continue
+		}
+		switch row[i].(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, bool:
+			return typeLongLong
+		case float32, float64:
+			return typeDouble
+		case time.Time:
+			return typeDateTime
+		}
+		break
+	}
+	return typeVarString
+}
+
+// formatValue formats v in the text protocol.
+func formatValue(v any) []byte {
+	switch v := v.(type) {
+	case []byte:
+		return v
+	case string:
+		return []byte(v)
+	case bool:
+		if v {
+			return []byte{'1'}
+		}
+		return []byte{'0'}
+	case float32:
+		return strconv.AppendFloat(nil, float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.AppendFloat(nil, v, 'g', -1, 64)
+	case time.Time:
+		return []byte(v.Format("2006-01-02 15:04:05.999999"))
+	}
+	return []byte(fmt.Sprint(v))
+}
+
+func appendColumn(pkt []byte, name string, typ byte) []byte {
+	pkt = appendLengthEncodedString(pkt, []byte("def")) // catalog
+	pkt = appendLengthEncodedString(pkt, nil)           // schema
+	pkt = appendLengthEncodedString(pkt, nil)           // table
+	pkt = appendLengthEncodedString(pkt, nil)           // org_table
+	pkt = appendLengthEncodedString(pkt, []byte(name))  // name
+	pkt = appendLengthEncodedString(pkt, []byte(name))  // org_name
+	pkt = append(pkt, 0x0c)
+	if typ == typeVarString {
+		pkt = binary.LittleEndian.AppendUint16(pkt, 255) // utf8mb4_0900_ai_ci
+	} else {
+		pkt = binary.LittleEndian.AppendUint16(pkt, 63) // binary
+	}
+	pkt = binary.LittleEndian.AppendUint32(pkt, math.MaxUint16) // column length
+	pkt = append(pkt, typ)
+	pkt = binary.LittleEndian.AppendUint16(pkt, 0) // flags
+	pkt = append(pkt, 0x1f)                        // decimals
+	return append(pkt, 0, 0)
+}
+
+func (c *serverConn) writeOK(affectedRows, insertID uint64) error {
+	pkt := append(c.startPacket(), 0x00)
+	pkt = appendLengthEncodedInteger(pkt, affectedRows)
+	pkt = appendLengthEncodedInteger(pkt, insertID)
+	pkt = binary.LittleEndian.AppendUint16(pkt, statusAutocommit)
+	pkt = binary.LittleEndian.AppendUint16(pkt, 0) // warnings
+	return c.writePacket(pkt)
+}
+
+func (c *serverConn) writeEOF() error {
+	pkt := append(c.startPacket(), 0xfe, 0, 0)
+	pkt = binary.LittleEndian.AppendUint16(pkt, statusAutocommit)
+	return c.writePacket(pkt)
+}
+
+func (c *serverConn) writeError(err *mysql.MySQLError) error {
+	pkt := append(c.startPacket(), 0xff)
+	pkt = binary.LittleEndian.AppendUint16(pkt, err.Number)
+	state := err.SQLState
+	if state == [5]byte{} {
+		state = [5]byte{'H', 'Y', '0', '0', '0'}
+	}
+	pkt = append(pkt, '#')
+	pkt = append(pkt, state[:]...)
+	pkt = append(pkt, err.Message...)
+	return c.writePacket(pkt)
+}
+
+// startPacket returns the buffer for the next packet, with space for the
+// header.
+func (c *serverConn) startPacket() []byte {
+	return append(c.buf[:0], 0, 0, 0, 0)
+}
+
+// writePacket writes pkt, which starts with 4 bytes for the header.
+// Payloads of 16 MiB or more are not supported.
+func (c *serverConn) writePacket(pkt []byte) error {
+	n := len(pkt) - 4
+	if n >= 1<<24-1 {
+		return errors.New("mysqltest: packet too large")
+	}
+	pkt[0], pkt[1], pkt[2], pkt[3] = byte(n), byte(n>>8), byte(n>>16), c.seq
+	c.seq++
+	c.buf = pkt
+	_, err := c.conn.Write(pkt)
+	return err
+}
+
+func (c *serverConn) readPacket() ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(c.conn, header[:]); err != nil {
+		return nil, err
+	}
+	n := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	c.seq = header[3] + 1
+	data := make([]byte, n)
+	if _, err := io.ReadFull(c.conn, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func appendLengthEncodedInteger(b []byte, n uint64) []byte {
+	switch {
+	case n <= 250:
+		return append(b, byte(n))
+	case n <= 0xffff:
+		return append(b, 0xfc, byte(n), byte(n>>8))
+	case n <= 0xffffff:
+		return append(b, 0xfd, byte(n), byte(n>>8), byte(n>>16))
+	}
+	return binary.LittleEndian.AppendUint64(append(b, 0xfe), n)
+}
+
+func appendLengthEncodedString(b, s []byte) []byte {
+	return append(appendLengthEncodedInteger(b, uint64(len(s))), s...)
+}
+
+func readLengthEncodedInteger(b []byte) (uint64, int) {
+	switch {
+	case len(b) == 0:
+		return 0, 0
+	case b[0] < 0xfb:
+		return uint64(b[0]), 1
+	case b[0] == 0xfc && len(b) >= 3:
+		return uint64(binary.LittleEndian.Uint16(b[1:])), 3
+	case b[0] == 0xfd && len(b) >= 4:
+		return uint64(b[1]) | uint64(b[2])<<8 | uint64(b[3])<<16, 4
+	case b[0] == 0xfe && len(b) >= 9:
+		return binary.LittleEndian.Uint64(b[1:]), 9
+	}
+	return 0, len(b)
+}
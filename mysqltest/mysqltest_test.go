@@ -0,0 +1,286 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysqltest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func openDB(t *testing.T, srv *Server) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("mysql", srv.DSN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestQuery(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Handle("SELECT id, name, score FROM users", Response{
+		Columns: []string{"id", "name", "score"},
+		Rows:    [][]any{{1, "alice", 1.5}, {2, nil, nil}},
+	})
+	db := openDB(t, srv)
+
+	rows, err := db.Query("SELECT id, name, score FROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	type user struct {
+		id    int64
+		name  sql.NullString
+		score sql.NullFloat64
+	}
+	var users []user
+	for rows.Next() {
+		var u user
+		if err := rows.Scan(&u.id, &u.name, &u.score); err != nil {
+			t.Fatal(err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := []user{
+		{1, sql.NullString{String: "alice", Valid: true}, sql.NullFloat64{Float64: 1.5, Valid: true}},
+		{2, sql.NullString{}, sql.NullFloat64{}},
+	}
+	if !reflect.DeepEqual(users, want) {
+		t.Errorf("expected %v, got %v", want, users)
+	}
+}
+
+func TestExec(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Handle("INSERT INTO users (name) VALUES ('bob')", Response{AffectedRows: 1, LastInsertID: 42})
+	db := openDB(t, srv)
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := tx.Exec("INSERT INTO users (name) VALUES (?)", "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := res.LastInsertId(); id != 42 {
+		t.Errorf("expected insert id 42, got %d", id)
+	}
+	want := []string{"SELECT @@sql_generate_invisible_primary_key", "START TRANSACTION", "INSERT INTO users (name) VALUES ('bob')", "COMMIT"}
+	if got := srv.Queries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected queries %q, got %q", want, got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Handle("DELETE FROM users", Response{Err: &mysql.MySQLError{Number: 1213, Message: "Deadlock found"}})
+	srv.HandleFunc(func(query string) (Response, bool) {
+		if strings.HasPrefix(query, "UPDATE") {
+			return Response{Err: errors.New("update failed")}, true
+		}
+		return Response{}, false
+	})
+	db := openDB(t, srv)
+
+	var merr *mysql.MySQLError
+	if _, err := db.Exec("DELETE FROM users"); !errors.As(err, &merr) || merr.Number != 1213 {
+		t.Errorf("expected error 1213, got %v", err)
+	}
+	if _, err := db.Exec("UPDATE users SET name = 'x'"); !errors.As(err, &merr) || merr.Number != 1105 || merr.Message != "update failed" {
+		t.Errorf("expected error 1105, got %v", err)
+	}
+	if _, err := db.Exec("TRUNCATE users"); !errors.As(err, &merr) || !strings.Contains(merr.Message, "unexpected query") {
+		t.Errorf("expected unexpected query error, got %v", err)
+	}
+	// the connection is still usable
+	if err := db.Ping(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Handle("SELECT 1", Response{Close: true})
+	db := openDB(t, srv)
+	db.SetMaxIdleConns(0)
+
+	if _, err := db.Exec("SELECT 1"); err == nil {
+		t.Error("expected error of the closed connection")
+	}
+}
+
+func TestLatency(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Handle("SELECT SLEEP(1)", Response{Delay: time.Second})
+	db := openDB(t, srv)
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := db.ExecContext(ctx, "SELECT SLEEP(1)"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	srv.SetLatency(20 * time.Millisecond)
+	start := time.Now()
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Errorf("latency not applied: %v", d)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	srv := NewUnstartedServer()
+	srv.Version = "5.7.44-test"
+	srv.Auth = func(user, database string) error {
+		if user != "root" || database != "test" {
+			return errors.New("access denied")
+		}
+		return nil
+	}
+	srv.Start()
+	defer srv.Close()
+
+	if err := openDB(t, srv).Ping(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("mysql", "alice@tcp("+srv.Addr()+")/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var merr *mysql.MySQLError
+	if err := db.Ping(); !errors.As(err, &merr) || merr.Number != 1045 {
+		t.Errorf("expected error 1045, got %v", err)
+	}
+}
+
+func TestWarmUp(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	cfg, err := mysql.ParseDSN(srv.DSN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := mysql.NewConnector(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	connector := c.(mysql.Connector)
+	ctx := context.Background()
+
+	connID := func() uint32 {
+		t.Helper()
+		conn, err := connector.Connect(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		info, _ := mysql.ConnInfo(conn)
+		return info.ConnectionID
+	}
+
+	// connections 1 and 2 are closed
+	if err := connector.WarmUp(ctx, 2, mysql.WarmUpClose); err != nil {
+		t.Fatal(err)
+	}
+	if id := connID(); id != 3 {
+		t.Errorf("expected a new connection, got %d", id)
+	}
+
+	// connections 4 to 6 are parked
+	if err := connector.WarmUp(ctx, 3, mysql.WarmUpPark); err != nil {
+		t.Fatal(err)
+	}
+	seen := map[uint32]bool{}
+	for i := 0; i < 3; i++ {
+		if id := connID(); id < 4 || id > 6 || seen[id] {
+			t.Errorf("expected a parked connection, got %d", id)
+		} else {
+			seen[id] = true
+		}
+	}
+	if id := connID(); id != 7 {
+		t.Errorf("expected a new connection, got %d", id)
+	}
+}
+
+func TestCancelToken(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Handle("KILL QUERY 1", Response{})
+	db := openDB(t, srv)
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	token, err := mysql.NewCancelToken(ctx, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := token.CancelQuery(ctx); err != nil {
+		t.Fatal(err)
+	}
+	queries := srv.Queries()
+	if len(queries) == 0 || queries[len(queries)-1] != "KILL QUERY 1" {
+		t.Errorf("unexpected queries %q", queries)
+	}
+}
+
+func TestXARecover(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Handle("XA RECOVER", Response{
+		Columns: []string{"formatID", "gtrid_length", "bqual_length", "data"},
+		Rows:    [][]any{{1, 1, 1, "gb"}, {-1, 1, 0, "g"}},
+	})
+	db := openDB(t, srv)
+
+	xids, err := mysql.XARecover(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []mysql.XID{
+		{GlobalID: "g", BranchID: "b", FormatID: 1},
+		{GlobalID: "g", FormatID: -1},
+	}
+	if !reflect.DeepEqual(xids, expected) {
+		t.Errorf("expected %v, got %v", expected, xids)
+	}
+}
@@ -0,0 +1,248 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var errOutQuery = errors.New("mysql: sql.Out arguments are only supported by Exec")
+
+// checkOutArg checks an sql.Out argument. Dest must be a non-nil pointer and
+// its value must be convertible if it is also an input.
+func (c converter) checkOutArg(out sql.Out) error {
+	rv := reflect.ValueOf(out.Dest)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("mysql: sql.Out destination must be a non-nil pointer, not %T", out.Dest)
+	}
+	if out.In {
+		if _, err := c.ConvertValue(rv.Elem().Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasOutArgs reports whether args contain sql.Out arguments.
+func hasOutArgs(args []driver.Value) bool {
+	for _, arg := range args {
+		if _, ok := arg.(sql.Out); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// splitOutArgs replaces the sql.Out arguments by the values sent to the
+// server: the value of Dest for INOUT parameters, NULL otherwise. It returns
+// the sql.Out arguments in order, or nil if there are none.
+func (c converter) splitOutArgs(args []driver.Value) ([]driver.Value, []sql.Out, error) {
+	if !hasOutArgs(args) {
+		return args, nil, nil
+	}
+	in := make([]driver.Value, len(args))
+	var outs []sql.Out
+	for i, arg := range args {
+		out, ok := arg.(sql.Out)
+		if !ok {
+			in[i] = arg
+			continue
+		}
+		outs = append(outs, out)
+		if out.In {
+			v, err := c.ConvertValue(reflect.ValueOf(out.Dest).Elem().Interface())
+			if err != nil {
+				return nil, nil, err
+			}
+			in[i] = v
+		}
+	}
+	return in, outs, nil
+}
+
+// readExecResults reads the result sets of an executed statement, starting
+// with a result set of resLen columns, and assigns the values of the result
+// set of the OUT parameters to outs. All result sets are read even if the
+// values can not be assigned, so that the connection stays usable.
+func (stmt *mysqlStmt) readExecResults(handleOk *okHandler, resLen int, outs []sql.Out) error {
+	mc := stmt.mc
+	var outErr error
+	for {
+		if resLen > 0 {
+			columns, values, err := mc.readOutParams(resLen)
+			if err != nil {
+				return err
+			}
+			if values != nil && outErr == nil {
+				outErr = assignOutParams(outs, columns, values)
+			}
+		}
+		if mc.status&statusMoreResultsExists == 0 {
+			return outErr
+		}
+		var err error
+		if resLen, err = handleOk.readResultSetHeaderPacket(); err != nil {
+			return err
+		}
+	}
+}
+
+// readOutParams reads a result set of resLen columns in the binary protocol.
+// If the server flags it as the values of the OUT parameters, they are
+// returned with their columns, otherwise values is nil. The flag is sent
+// after the column definitions, or with CLIENT_DEPRECATE_EOF after the row.
+func (mc *mysqlConn) readOutParams(resLen int) (columns []mysqlField, values []driver.Value, err error) {
+	rows := &binaryRows{}
+	rows.mc = mc
+	columns, err = mc.readColumns(resLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows.rs.columns = columns
+	isOut := mc.status&statusPsOutParams != 0
+
+	dest := make([]driver.Value, resLen)
+	for {
+		err := rows.readRow(dest)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		values = append(values[:0], dest...)
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				values[i] = append([]byte(nil), b...)
+			}
+		}
+	}
+	if !isOut && mc.status&statusPsOutParams == 0 {
+		return nil, nil, nil
+	}
+	return columns, values, nil
+}
+
+// assignOutParams assigns the values of the OUT parameters to outs.
+func assignOutParams(outs []sql.Out, columns []mysqlField, values []driver.Value) error {
+	if len(values) != len(outs) {
+		return fmt.Errorf("mysql: %d OUT parameters returned for %d sql.Out arguments", len(values), len(outs))
+	}
+	for i, out := range outs {
+		if err := assignOutValue(out.Dest, values[i]); err != nil {
+			return fmt.Errorf("mysql: OUT parameter %d (%s): %w", i+1, columns[i].name, err)
+		}
+	}
+	return nil
+}
+
+// assignOutValue stores the value src of an OUT parameter in dest, a non-nil
+// pointer. It supports sql.Scanner, *any and pointers to the basic types and
+// time.Time.
+func assignOutValue(dest any, src driver.Value) error {
+	switch d := dest.(type) {
+	case sql.Scanner:
+		return d.Scan(src)
+	case *any:
+		*d = src
+		return nil
+	}
+
+	dv := reflect.ValueOf(dest).Elem()
+	if src == nil {
+		switch dv.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+			dv.Set(reflect.Zero(dv.Type()))
+			return nil
+		}
+		return fmt.Errorf("can not assign NULL to %T", dest)
+	}
+	if dv.Kind() == reflect.Pointer {
+		p := reflect.New(dv.Type().Elem())
+		if err := assignOutValue(p.Interface(), src); err != nil {
+			return err
+		}
+		dv.Set(p)
+		return nil
+	}
+
+	sv := reflect.ValueOf(src)
+	if sv.Type().AssignableTo(dv.Type()) {
+		dv.Set(sv)
+		return nil
+	}
+
+	// text representation of the value
+	var s string
+	switch v := src.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case uint64:
+		s = strconv.FormatUint(v, 10)
+	case float32:
+		s = strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		s = strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		s = v.Format(time.RFC3339Nano)
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	switch dv.Kind() {
+	case reflect.String:
+		dv.SetString(s)
+		return nil
+	case reflect.Slice:
+		if dv.Type().Elem().Kind() == reflect.Uint8 {
+			dv.SetBytes([]byte(s))
+			return nil
+		}
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		dv.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, dv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dv.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, dv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dv.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, dv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dv.SetFloat(f)
+		return nil
+	}
+	return fmt.Errorf("can not assign %T to %T", src, dest)
+}
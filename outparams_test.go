@@ -0,0 +1,159 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// mockOutParams returns the response to CALL p(?, ?) with the OUT parameters
+// 42 and "hi". With deprecateEOF, the status is sent in the OK packet after
+// the row instead of the EOF packet after the column definitions.
+func mockOutParams(deprecateEOF bool) []byte {
+	outStatus := statusPsOutParams | statusMoreResultsExists | statusInAutocommit
+	status := func(s statusFlag) []byte { return []byte{byte(s), byte(s >> 8)} }
+
+	resp := mockPacket(1, []byte{2})
+	resp = append(resp, mockColumn(2, "a", fieldTypeLongLong)...)
+	resp = append(resp, mockColumn(3, "b", fieldTypeVarString)...)
+	seq := byte(4)
+	if !deprecateEOF {
+		resp = append(resp, mockPacket(seq, append([]byte{iEOF, 0, 0}, status(outStatus)...))...)
+		seq++
+	}
+	row := []byte{iOK, 0}
+	row = binary.LittleEndian.AppendUint64(row, 42)
+	row = appendLengthEncodedString(row, "hi")
+	resp = append(resp, mockPacket(seq, row)...)
+	seq++
+	if deprecateEOF {
+		resp = append(resp, mockPacket(seq, append([]byte{iEOF, 0, 0}, append(status(outStatus), 0, 0)...))...)
+	} else {
+		resp = append(resp, mockPacket(seq, append([]byte{iEOF, 0, 0}, status(statusMoreResultsExists|statusInAutocommit)...))...)
+	}
+	seq++
+	// final OK of the CALL
+	return append(resp, mockPacket(seq, append([]byte{iOK, 0, 0}, status(statusInAutocommit)...))...)
+}
+
+func TestExecOutParams(t *testing.T) {
+	for _, deprecateEOF := range []bool{false, true} {
+		conn, mc := newRWMockConn(0)
+		mc.deprecateEOF = deprecateEOF
+		stmt := &mysqlStmt{mc: mc, id: 1, paramCount: 2}
+		conn.queuedReplies = [][]byte{mockOutParams(deprecateEOF)}
+
+		a, b := 5, sql.NullString{}
+		args := []driver.Value{sql.Out{Dest: &a, In: true}, sql.Out{Dest: &b}}
+		for i := range args {
+			if err := stmt.CheckNamedValue(&driver.NamedValue{Ordinal: i + 1, Value: args[i]}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := stmt.Exec(args); err != nil {
+			t.Fatalf("deprecateEOF=%v: %v", deprecateEOF, err)
+		}
+		if a != 42 || b != (sql.NullString{String: "hi", Valid: true}) {
+			t.Errorf("deprecateEOF=%v: unexpected OUT values %d, %+v", deprecateEOF, a, b)
+		}
+		if mc.status&statusMoreResultsExists != 0 {
+			t.Errorf("deprecateEOF=%v: results not read completely", deprecateEOF)
+		}
+	}
+}
+
+func TestExecOutParamsAssignError(t *testing.T) {
+	for _, deprecateEOF := range []bool{false, true} {
+		conn, mc := newRWMockConn(0)
+		mc.deprecateEOF = deprecateEOF
+		stmt := &mysqlStmt{mc: mc, id: 1, paramCount: 2}
+		conn.queuedReplies = [][]byte{mockOutParams(deprecateEOF)}
+
+		var a, b int
+		if _, err := stmt.Exec([]driver.Value{sql.Out{Dest: &a}, sql.Out{Dest: &b}}); err == nil {
+			t.Fatalf("deprecateEOF=%v: expected error for \"hi\" in *int", deprecateEOF)
+		}
+		if mc.status&statusMoreResultsExists != 0 {
+			t.Errorf("deprecateEOF=%v: results not read completely", deprecateEOF)
+		}
+		if mc.closed.Load() {
+			t.Errorf("deprecateEOF=%v: connection closed", deprecateEOF)
+		}
+	}
+}
+
+func TestOutArgErrors(t *testing.T) {
+	_, mc := newRWMockConn(0)
+	var i int
+	for _, out := range []sql.Out{{Dest: i}, {Dest: (*int)(nil)}} {
+		if err := mc.CheckNamedValue(&driver.NamedValue{Value: out}); err == nil {
+			t.Errorf("%#v: expected error", out)
+		}
+	}
+	args := []driver.Value{sql.Out{Dest: &i}}
+	if _, err := (&mysqlStmt{mc: mc}).Query(args); err != errOutQuery {
+		t.Errorf("expected errOutQuery, got %v", err)
+	}
+	if _, err := mc.ExecContext(context.Background(), "CALL p(?)", []driver.NamedValue{{Ordinal: 1, Value: args[0]}}); err != driver.ErrSkip {
+		t.Errorf("expected driver.ErrSkip, got %v", err)
+	}
+}
+
+func TestAssignOutValue(t *testing.T) {
+	var (
+		s  string
+		n  int32
+		u  uint8
+		f  float64
+		bl bool
+		bs []byte
+		p  *string
+		a  any
+	)
+	tests := []struct {
+		dest any
+		src  driver.Value
+		want any
+	}{
+		{&s, []byte("abc"), "abc"},
+		{&s, int64(-3), "-3"},
+		{&n, int64(-3), int32(-3)},
+		{&n, []byte("17"), int32(17)},
+		{&u, uint64(200), uint8(200)},
+		{&f, []byte("1.25"), 1.25},
+		{&bl, int64(1), true},
+		{&bs, []byte("x"), []byte("x")},
+		{&p, nil, (*string)(nil)},
+		{&a, int64(7), int64(7)},
+	}
+	for _, tt := range tests {
+		if err := assignOutValue(tt.dest, tt.src); err != nil {
+			t.Errorf("%T <- %#v: %v", tt.dest, tt.src, err)
+			continue
+		}
+		if got := reflect.ValueOf(tt.dest).Elem().Interface(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%T <- %#v: expected %#v, got %#v", tt.dest, tt.src, tt.want, got)
+		}
+	}
+
+	if err := assignOutValue(&p, []byte("v")); err != nil || p == nil || *p != "v" {
+		t.Errorf("unexpected %v, %v", p, err)
+	}
+	if err := assignOutValue(&n, nil); err == nil {
+		t.Error("expected error for NULL into int32")
+	}
+	if err := assignOutValue(&u, int64(300)); err == nil {
+		t.Error("expected error for overflow")
+	}
+}
@@ -30,47 +30,12 @@ func (mc *mysqlConn) readPacket() ([]byte, error) {
 	var prevData []byte
 	invalidSequence := false
 
-	readNext := mc.buf.readNext
-	if mc.compress {
-		readNext = mc.compIO.readNext
-	}
-
 	for {
-		// read packet header
-		data, err := readNext(4, mc.readWithTimeout)
+		pktLen, seq, outOfSync, err := mc.readHeader(prevData != nil)
 		if err != nil {
-			mc.close()
-			if cerr := mc.canceled.Value(); cerr != nil {
-				return nil, cerr
-			}
-			mc.log(err)
-			return nil, ErrInvalidConn
-		}
-
-		// packet length [24 bit]
-		pktLen := getUint24(data[:3])
-		seq := data[3]
-
-		if mc.compress {
-			// MySQL and MariaDB doesn't check packet nr in compressed packet.
-			if debug && seq != mc.compressSequence {
-				fmt.Printf("[debug] mismatched compression sequence nr: expected: %v, got %v",
-					mc.compressSequence, seq)
-			}
-			mc.compressSequence = seq + 1
-		} else {
-			// check packet sync [8 bit]
-			if seq != mc.sequence {
-				mc.log(fmt.Sprintf("[warn] unexpected seq nr: expected %v, got %v", mc.sequence, seq))
-				// For large packets, we stop reading as soon as sync error.
-				if len(prevData) > 0 {
-					mc.close()
-					return nil, ErrPktSyncMul
-				}
-				invalidSequence = true
-			}
-			mc.sequence++
+			return nil, err
 		}
+		invalidSequence = invalidSequence || outOfSync
 
 		// packets with length 0 terminate a previous packet which is a
 		// multiple of (2^24)-1 bytes long
@@ -85,14 +50,16 @@ func (mc *mysqlConn) readPacket() ([]byte, error) {
 		}
 
 		// read packet body [pktLen bytes]
-		data, err = readNext(pktLen, mc.readWithTimeout)
+		mc.buf.observe(pktLen)
+		data, err := mc.readBytes(pktLen)
 		if err != nil {
-			mc.close()
-			if cerr := mc.canceled.Value(); cerr != nil {
-				return nil, cerr
-			}
-			mc.log(err)
-			return nil, ErrInvalidConn
+			return nil, err
+		}
+		if s := mc.cfg.StatsCollector; s != nil {
+			s.PacketRead(pktLen)
+		}
+		if mc.trace != nil {
+			mc.traceReceivedPacket(seq, data, prevData != nil)
 		}
 
 		// return data if this was the last packet
@@ -116,6 +83,75 @@ func (mc *mysqlConn) readPacket() ([]byte, error) {
 	}
 }
 
+// readHeader reads the header of the next packet and returns the length of
+// the packet and its sequence number. continued is true for the packets
+// following a packet of maxPacketSize bytes. outOfSync reports an unexpected
+// sequence number of a packet which is not continued.
+func (mc *mysqlConn) readHeader(continued bool) (pktLen int, seq byte, outOfSync bool, err error) {
+	if to := mc.cfg.PacketReadTimeout; to > 0 {
+		mc.packetDeadline = time.Now().Add(to)
+	}
+
+	// read packet header
+	data, err := mc.readBytes(4)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	// packet length [24 bit]
+	pktLen = getUint24(data[:3])
+	seq = data[3]
+
+	if mc.compress {
+		// MySQL and MariaDB doesn't check packet nr in compressed packet.
+		if debug && seq != mc.compressSequence {
+			fmt.Printf("[debug] mismatched compression sequence nr: expected: %v, got %v",
+				mc.compressSequence, seq)
+		}
+		mc.compressSequence = seq + 1
+	} else {
+		// check packet sync [8 bit]
+		if seq != mc.sequence {
+			if mc.trace != nil {
+				mc.traceFailed("SYNC")
+			}
+			mc.log(fmt.Sprintf("[warn] unexpected seq nr: expected %v, got %v", mc.sequence, seq))
+			// For large packets, we stop reading as soon as sync error.
+			if continued {
+				mc.close()
+				return 0, 0, false, ErrPktSyncMul
+			}
+			outOfSync = true
+		}
+		mc.sequence++
+	}
+	return pktLen, seq, outOfSync, nil
+}
+
+// readBytes reads the next n bytes from the connection. The returned slice
+// is only valid until the next read.
+func (mc *mysqlConn) readBytes(n int) ([]byte, error) {
+	var data []byte
+	var err error
+	if mc.compress {
+		data, err = mc.compIO.readNext(n, mc.readWithTimeout)
+	} else {
+		data, err = mc.buf.readNext(n, mc.readWithTimeout)
+	}
+	if err != nil {
+		if mc.trace != nil {
+			mc.traceFailed("READ")
+		}
+		mc.close()
+		if cerr := mc.canceledError(CancelReceive); cerr != nil {
+			return nil, cerr
+		}
+		mc.log(err)
+		return nil, ErrInvalidConn
+	}
+	return data, nil
+}
+
 // Write packet buffer 'data'
 func (mc *mysqlConn) writePacket(data []byte) error {
 	pktLen := len(data) - 4
@@ -128,6 +164,7 @@ func (mc *mysqlConn) writePacket(data []byte) error {
 		writeFunc = mc.compIO.writePackets
 	}
 
+	continued := false
 	for {
 		size := min(maxPacketSize, pktLen)
 		putUint24(data[:3], size)
@@ -139,9 +176,27 @@ func (mc *mysqlConn) writePacket(data []byte) error {
 		}
 
 		n, err := writeFunc(data[:4+size])
+		if err != nil && n == 0 && mc.failoverArmed {
+			// nothing has been sent on the reused connection yet
+			if mc.failover(err) == nil {
+				// the new connection may allow smaller packets
+				if pktLen > mc.maxAllowedPacket {
+					return ErrPktTooLarge
+				}
+				writeFunc = mc.writeWithTimeout
+				if mc.compress {
+					writeFunc = mc.compIO.writePackets
+				}
+				continue
+			}
+		}
 		if err != nil {
+			if mc.trace != nil {
+				mc.traceFailed("WRITE")
+				defer mc.writeTrace()
+			}
 			mc.cleanup()
-			if cerr := mc.canceled.Value(); cerr != nil {
+			if cerr := mc.canceledError(CancelSend); cerr != nil {
 				return cerr
 			}
 			if n == 0 && pktLen == len(data)-4 {
@@ -159,12 +214,21 @@ func (mc *mysqlConn) writePacket(data []byte) error {
 			return io.ErrShortWrite
 		}
 
+		if s := mc.cfg.StatsCollector; s != nil {
+			s.PacketWritten(size)
+		}
+		if mc.trace != nil {
+			mc.traceSentPacket(mc.sequence, data[4:4+size], continued)
+		}
+		mc.failoverArmed = false
+
 		mc.sequence++
 		if size != maxPacketSize {
 			return nil
 		}
 		pktLen -= size
 		data = data[size:]
+		continued = true
 	}
 }
 
@@ -195,7 +259,17 @@ func (mc *mysqlConn) readHandshakePacket() (data []byte, plugin string, err erro
 
 	// server version [null terminated string]
 	// connection id [4 bytes]
-	pos := 1 + bytes.IndexByte(data[1:], 0x00) + 1 + 4
+	versionEnd := 1 + bytes.IndexByte(data[1:], 0x00)
+	if versionEnd == 0 || len(data) < versionEnd+1+4+8+1+2 {
+		return nil, "", ErrMalformPkt
+	}
+	pos := versionEnd + 1 + 4
+	mc.info = ConnectionInfo{
+		ServerVersion:   string(data[1:versionEnd]),
+		ProtocolVersion: data[0],
+		ConnectionID:    binary.LittleEndian.Uint32(data[versionEnd+1 : pos]),
+	}
+	mc.connID.Store(mc.info.ConnectionID)
 
 	// first part of the password cipher [8 bytes]
 	authData := data[pos : pos+8]
@@ -219,6 +293,8 @@ func (mc *mysqlConn) readHandshakePacket() (data []byte, plugin string, err erro
 
 	if len(data) > pos {
 		// character set [1 byte]
+		mc.info.CollationID = data[pos]
+		mc.info.Collation = collationName(data[pos])
 		// status flags [2 bytes]
 		pos += 3
 		// capability flags (upper 2 bytes) [2 bytes]
@@ -226,6 +302,10 @@ func (mc *mysqlConn) readHandshakePacket() (data []byte, plugin string, err erro
 		pos += 2
 		// length of auth-plugin-data [1 byte]
 		// reserved (all [00]) [10 bytes]
+		// MariaDB: filler [6 bytes], extended capability flags [4 bytes]
+		if mc.flags&clientLongPassword == 0 {
+			mc.mariadbFlags = mariadbFlag(binary.LittleEndian.Uint32(data[pos+7 : pos+11]))
+		}
 		pos += 11
 
 		// second part of the password cipher [minimum 13 bytes],
@@ -263,6 +343,28 @@ func (mc *mysqlConn) readHandshakePacket() (data []byte, plugin string, err erro
 	return b[:], plugin, nil
 }
 
+// handshakeCollationID returns the ID of the collation sent with the
+// handshake response and sets the charset and collation of the connection.
+func (mc *mysqlConn) handshakeCollationID() (byte, error) {
+	var id byte = defaultCollationID
+	if cname := mc.cfg.Collation; cname == collationAuto {
+		if mc.serverCollationFits() {
+			id = mc.info.CollationID
+		}
+	} else if cname != "" {
+		colID, ok := collations[cname]
+		if ok {
+			id = colID
+		} else if len(mc.cfg.charsets) > 0 {
+			// When cfg.charset is set, the collation is set by `SET NAMES <charset> COLLATE <collation>`.
+			return 0, fmt.Errorf("unknown collation: %q", cname)
+		}
+	}
+	mc.info.ConnectionCollation = collationName(id)
+	mc.info.Charset = collationCharset(mc.info.ConnectionCollation)
+	return id, nil
+}
+
 // Client Authentication Packet
 // http://dev.mysql.com/doc/internals/en/connection-phase-packets.html#packet-Protocol::HandshakeResponse
 func (mc *mysqlConn) writeHandshakeResponsePacket(authResp []byte, plugin string) error {
@@ -275,7 +377,8 @@ func (mc *mysqlConn) writeHandshakeResponsePacket(authResp []byte, plugin string
 		clientPluginAuth |
 		clientMultiResults |
 		mc.flags&clientConnectAttrs |
-		mc.flags&clientLongFlag
+		mc.flags&clientLongFlag |
+		mc.flags&clientDeprecateEOF
 
 	sendConnectAttrs := mc.flags&clientConnectAttrs != 0
 
@@ -294,6 +397,21 @@ func (mc *mysqlConn) writeHandshakeResponsePacket(authResp []byte, plugin string
 		clientFlags |= clientMultiStatements
 	}
 
+	if (mc.cfg.queryAttributes || mc.cfg.queryAttributeParams) && mc.flags&clientQueryAttributes != 0 {
+		clientFlags |= clientQueryAttributes
+	}
+
+	// session state changes, e.g. of the default database
+	if (mc.cfg.trackGTIDs || mc.cfg.trackSchema) && mc.flags&clientSessionTrack != 0 {
+		clientFlags |= clientSessionTrack
+	}
+
+	// MariaDB reads the extended capabilities only without CLIENT_MYSQL
+	mariadbFlags := mc.mariadbFlags & (mariadbClientStmtBulkOperations | mariadbClientBulkUnitResults)
+	if mariadbFlags != 0 {
+		clientFlags &^= clientLongPassword
+	}
+
 	// encode length of the auth plugin data
 	var authRespLEIBuf [9]byte
 	authRespLen := len(authResp)
@@ -316,9 +434,9 @@ func (mc *mysqlConn) writeHandshakeResponsePacket(authResp []byte, plugin string
 	var connAttrsLEI []byte
 	if sendConnectAttrs {
 		var connAttrsLEIBuf [9]byte
-		connAttrsLen := len(mc.connector.encodedAttributes)
+		connAttrsLen := len(mc.connAttrs)
 		connAttrsLEI = appendLengthEncodedInteger(connAttrsLEIBuf[:0], uint64(connAttrsLen))
-		pktLen += len(connAttrsLEI) + len(mc.connector.encodedAttributes)
+		pktLen += len(connAttrsLEI) + len(mc.connAttrs)
 	}
 
 	// Calculate packet length and get buffer with that size
@@ -330,20 +448,17 @@ func (mc *mysqlConn) writeHandshakeResponsePacket(authResp []byte, plugin string
 
 	// ClientFlags [32 bit]
 	binary.LittleEndian.PutUint32(data[4:], uint32(clientFlags))
+	mc.info.ServerCapabilities = uint32(mc.flags)
+	mc.info.Capabilities = uint32(clientFlags & mc.flags)
+	mc.deprecateEOF = clientFlags&clientDeprecateEOF != 0
+	mc.sessionTrack = clientFlags&clientSessionTrack != 0
 
 	// MaxPacketSize [32 bit] (none)
 	binary.LittleEndian.PutUint32(data[8:], 0)
 
 	// Collation ID [1 byte]
-	data[12] = defaultCollationID
-	if cname := mc.cfg.Collation; cname != "" {
-		colID, ok := collations[cname]
-		if ok {
-			data[12] = colID
-		} else if len(mc.cfg.charsets) > 0 {
-			// When cfg.charset is set, the collation is set by `SET NAMES <charset> COLLATE <collation>`.
-			return fmt.Errorf("unknown collation: %q", cname)
-		}
+	if data[12], err = mc.handshakeCollationID(); err != nil {
+		return err
 	}
 
 	// Filler [23 bytes] (all 0x00)
@@ -351,6 +466,9 @@ func (mc *mysqlConn) writeHandshakeResponsePacket(authResp []byte, plugin string
 	for ; pos < 13+23; pos++ {
 		data[pos] = 0
 	}
+	// MariaDB: filler [19 bytes], extended capability flags [4 bytes]
+	binary.LittleEndian.PutUint32(data[13+19:], uint32(mariadbFlags))
+	mc.mariadbFlags = mariadbFlags
 
 	// SSL Connection Request Packet
 	// http://dev.mysql.com/doc/internals/en/connection-phase-packets.html#packet-Protocol::SSLRequest
@@ -363,7 +481,7 @@ func (mc *mysqlConn) writeHandshakeResponsePacket(authResp []byte, plugin string
 		// Switch to TLS
 		tlsConn := tls.Client(mc.netConn, mc.cfg.TLS)
 		if err := tlsConn.Handshake(); err != nil {
-			if cerr := mc.canceled.Value(); cerr != nil {
+			if cerr := mc.canceledError(CancelConnect); cerr != nil {
 				return cerr
 			}
 			return err
@@ -396,7 +514,7 @@ func (mc *mysqlConn) writeHandshakeResponsePacket(authResp []byte, plugin string
 	// Connection Attributes
 	if sendConnectAttrs {
 		pos += copy(data[pos:], connAttrsLEI)
-		pos += copy(data[pos:], []byte(mc.connector.encodedAttributes))
+		pos += copy(data[pos:], []byte(mc.connAttrs))
 	}
 
 	// Send Auth packet
@@ -422,6 +540,10 @@ func (mc *mysqlConn) writeAuthSwitchPacket(authData []byte) error {
 ******************************************************************************/
 
 func (mc *mysqlConn) writeCommandPacket(command byte) error {
+	if err := mc.flushCoalesced(); err != nil {
+		return err
+	}
+
 	// Reset Packet Sequence
 	mc.resetSequence()
 
@@ -438,10 +560,20 @@ func (mc *mysqlConn) writeCommandPacket(command byte) error {
 }
 
 func (mc *mysqlConn) writeCommandPacketStr(command byte, arg string) error {
+	if err := mc.flushCoalesced(); err != nil {
+		return err
+	}
+
 	// Reset Packet Sequence
 	mc.resetSequence()
 
-	pktLen := 1 + len(arg)
+	// Query attributes precede the query
+	var attrs []byte
+	if command == comQuery && mc.sendsQueryAttrs() {
+		attrs = appendQueryAttrs(nil, mc.queryAttrs)
+	}
+
+	pktLen := 1 + len(attrs) + len(arg)
 	data, err := mc.buf.takeBuffer(pktLen + 4)
 	if err != nil {
 		return err
@@ -451,7 +583,7 @@ func (mc *mysqlConn) writeCommandPacketStr(command byte, arg string) error {
 	data[4] = command
 
 	// Add arg
-	copy(data[5:], arg)
+	copy(data[5+copy(data[5:], attrs):], arg)
 
 	// Send CMD packet
 	err = mc.writePacket(data)
@@ -460,6 +592,10 @@ func (mc *mysqlConn) writeCommandPacketStr(command byte, arg string) error {
 }
 
 func (mc *mysqlConn) writeCommandPacketUint32(command byte, arg uint32) error {
+	if err := mc.flushCoalesced(); err != nil {
+		return err
+	}
+
 	// Reset Packet Sequence
 	mc.resetSequence()
 
@@ -536,6 +672,7 @@ func (mc *okHandler) readResultSetHeaderPacket() (int, error) {
 	// handleOkPacket replaces both values; other cases leave the values unchanged.
 	mc.result.affectedRows = append(mc.result.affectedRows, 0)
 	mc.result.insertIds = append(mc.result.insertIds, 0)
+	mc.result.warningCounts = append(mc.result.warningCounts, 0)
 
 	data, err := mc.conn().readPacket()
 	if err != nil {
@@ -563,44 +700,56 @@ func (mc *okHandler) readResultSetHeaderPacket() (int, error) {
 // Error Packet
 // http://dev.mysql.com/doc/internals/en/generic-response-packets.html#packet-ERR_Packet
 func (mc *mysqlConn) handleErrorPacket(data []byte) error {
-	if data[0] != iERR {
+	me := parseErrorPacket(data)
+	if me == nil {
 		return ErrMalformPkt
 	}
-
-	// 0xff [1 byte]
-
-	// Error Number [16 bit uint]
-	errno := binary.LittleEndian.Uint16(data[1:3])
+	errno := me.Number
 
 	// 1792: ER_CANT_EXECUTE_IN_READ_ONLY_TRANSACTION
 	// 1290: ER_OPTION_PREVENTS_STATEMENT (returned by Aurora during failover)
-	if (errno == 1792 || errno == 1290) && mc.cfg.RejectReadOnly {
-		// Oops; we are connected to a read-only connection, and won't be able
-		// to issue any write statements. Since RejectReadOnly is configured,
-		// we throw away this connection hoping this one would have write
-		// permission. This is specifically for a possible race condition
-		// during failover (e.g. on AWS Aurora). See README.md for more.
-		//
-		// We explicitly close the connection before returning
-		// driver.ErrBadConn to ensure that `database/sql` purges this
-		// connection and initiates a new one for next statement next time.
+	// 1836: ER_READ_ONLY_MODE, only with rejectReadOnly=error
+	if (errno == 1792 || errno == 1290 || (errno == 1836 && mc.cfg.readOnlyError)) && mc.cfg.RejectReadOnly {
+		return mc.rejectReadOnly(me)
+	}
+
+	if mc.cfg.ShouldRetryError != nil && mc.cfg.ShouldRetryError(me) {
+		// See RejectReadOnly above
 		mc.Close()
-		return driver.ErrBadConn
+		return mc.badConn(me)
+	}
+
+	// 1205: ER_LOCK_WAIT_TIMEOUT
+	// 3572: ER_LOCK_NOWAIT
+	if (errno == 1205 || errno == 3572) && mc.cfg.LockDiagnostics {
+		return mc.lockError(me)
 	}
 
-	me := &MySQLError{Number: errno}
+	return me
+}
+
+// parseErrorPacket returns the error of an ERR packet, or nil if data is not
+// an ERR packet.
+func parseErrorPacket(data []byte) *MySQLError {
+	if len(data) < 3 || data[0] != iERR {
+		return nil
+	}
+
+	// 0xff [1 byte]
+
+	// Error Number [16 bit uint]
+	me := &MySQLError{Number: binary.LittleEndian.Uint16(data[1:3])}
 
 	pos := 3
 
 	// SQL State [optional: # + 5bytes string]
-	if data[3] == 0x23 {
+	if len(data) >= 9 && data[3] == 0x23 {
 		copy(me.SQLState[:], data[4:4+5])
 		pos = 9
 	}
 
 	// Error Message [string]
 	me.Message = string(data[pos:])
-
 	return me
 }
 
@@ -665,30 +814,137 @@ func (mc *okHandler) handleOkPacket(data []byte) error {
 	}
 
 	// server_status [2 bytes]
-	mc.status = readStatus(data[1+n+m : 1+n+m+2])
-	if mc.status&statusMoreResultsExists != 0 {
-		return nil
+	pos := 1 + n + m
+	if len(data) < pos+2 {
+		return ErrMalformPkt
 	}
+	mc.status = readStatus(data[pos : pos+2])
+	pos += 2
 
 	// warning count [2 bytes]
+	if len(data) >= pos+2 {
+		mc.result.addWarnings(binary.LittleEndian.Uint16(data[pos : pos+2]))
+		pos += 2
+	}
 
+	// info [string<EOF>], or [len coded string] with CLIENT_SESSION_TRACK
+	if mc.sessionTrack {
+		mc.result.info = mc.conn().checkSessionState(data[pos:])
+	} else if len(data) > pos {
+		mc.result.info = string(data[pos:])
+	}
 	return nil
 }
 
+// checkSessionState checks the info and the session state changes following
+// the warning count of an OK packet if CLIENT_SESSION_TRACK was negotiated.
+// Proxies may strip them although the flag was negotiated with the server.
+// Tracking is disabled for the connection then, with a warning instead of
+// failing the statement. The info is returned.
+func (mc *mysqlConn) checkSessionState(data []byte) (info string) {
+	// info [len coded string], omitted if empty and no state changed
+	if len(data) == 0 && mc.status&statusSessionStateChanged == 0 {
+		return ""
+	}
+	n := lengthEncodedStringLen(data)
+	if n >= 0 {
+		b, _, _, _ := readLengthEncodedString(data)
+		info = string(b)
+	}
+	if n >= 0 && mc.status&statusSessionStateChanged != 0 {
+		// session state info [len coded string]
+		m := lengthEncodedStringLen(data[n:])
+		if m >= 0 {
+			state, _, _, _ := readLengthEncodedString(data[n:])
+			mc.handleSessionState(state)
+		}
+		n = m
+	}
+	if n < 0 {
+		mc.sessionTrack = false
+		mc.log("[warn] session state tracking data missing in OK packet, possibly stripped by a proxy; disabling session tracking for this connection")
+		return ""
+	}
+	return info
+}
+
+// handleSessionState processes the session state changes of an OK packet,
+// entries of a type and a length encoded string.
+func (mc *mysqlConn) handleSessionState(b []byte) {
+	for len(b) > 0 {
+		n := lengthEncodedStringLen(b[1:])
+		if n < 0 {
+			return
+		}
+		typ := b[0]
+		data, _, _, _ := readLengthEncodedString(b[1:])
+		b = b[1+n:]
+
+		switch typ {
+		case sessionTrackSchema:
+			// new default database [len coded string], e.g. after USE
+			if lengthEncodedStringLen(data) >= 0 {
+				schema, _, _, _ := readLengthEncodedString(data)
+				mc.schema = string(schema)
+			}
+		case sessionTrackGTIDs:
+			// encoding specification [1 byte], GTIDs [len coded string]
+			if len(data) > 1 && lengthEncodedStringLen(data[1:]) >= 0 {
+				gtids, _, _, _ := readLengthEncodedString(data[1:])
+				mc.info.GTIDs = string(gtids)
+				if i := len(mc.result.affectedRows) - 1; i >= 0 {
+					for len(mc.result.gtids) <= i {
+						mc.result.gtids = append(mc.result.gtids, "")
+					}
+					mc.result.gtids[i] = mc.info.GTIDs
+				}
+			}
+		}
+	}
+}
+
+// lengthEncodedStringLen returns the length of the length encoded string at
+// the start of b including its length, or -1 if b is too short.
+func lengthEncodedStringLen(b []byte) int {
+	if len(b) == 0 {
+		return -1
+	}
+	switch {
+	case b[0] == 0xfc && len(b) < 3,
+		b[0] == 0xfd && len(b) < 4,
+		b[0] == 0xfe && len(b) < 9:
+		return -1
+	}
+	num, _, n := readLengthEncodedInteger(b)
+	if uint64(len(b)-n) < num {
+		return -1
+	}
+	return n + int(num)
+}
+
 // Read Packets as Field Packets until EOF-Packet or an Error appears
 // http://dev.mysql.com/doc/internals/en/com-query-response.html#packet-Protocol::ColumnDefinition41
 func (mc *mysqlConn) readColumns(count int) ([]mysqlField, error) {
 	columns := make([]mysqlField, count)
 
 	for i := 0; ; i++ {
+		// no EOF Packet with CLIENT_DEPRECATE_EOF
+		if mc.deprecateEOF && i == count {
+			return columns, nil
+		}
+
 		data, err := mc.readPacket()
 		if err != nil {
 			return nil, err
 		}
 
 		// EOF Packet
-		if data[0] == iEOF && (len(data) == 5 || len(data) == 1) {
+		if !mc.deprecateEOF && data[0] == iEOF && (len(data) == 5 || len(data) == 1) {
 			if i == count {
+				if len(data) == 5 {
+					// server_status, e.g. with SERVER_PS_OUT_PARAMS
+					mc.status = readStatus(data[3:])
+				}
 				return columns, nil
 			}
 			return nil, fmt.Errorf("column count mismatch n:%d len:%d", count, len(columns))
@@ -701,12 +957,28 @@ func (mc *mysqlConn) readColumns(count int) ([]mysqlField, error) {
 		}
 
 		// Database [len coded string]
-		n, err := skipLengthEncodedString(data[pos:])
-		if err != nil {
-			return nil, err
+		var n int
+		if mc.cfg.columnDefaults {
+			schema, _, m, err := readLengthEncodedString(data[pos:])
+			if err != nil {
+				return nil, err
+			}
+			columns[i].schema = string(schema)
+			n = m
+		} else {
+			n, err = skipLengthEncodedString(data[pos:])
+			if err != nil {
+				return nil, err
+			}
 		}
 		pos += n
 
+		columns[i].parseDecimal = mc.cfg.parseDecimal
+		columns[i].parseJSON = mc.cfg.parseJSON
+		columns[i].parseDuration = mc.cfg.parseDuration
+		columns[i].parseGeometry = mc.cfg.parseGeometry
+		columns[i].parseVector = mc.cfg.parseVector
+
 		// Table [len coded string]
 		if mc.cfg.ColumnsWithAlias {
 			tableName, _, n, err := readLengthEncodedString(data[pos:])
@@ -724,11 +996,20 @@ func (mc *mysqlConn) readColumns(count int) ([]mysqlField, error) {
 		}
 
 		// Original table [len coded string]
-		n, err = skipLengthEncodedString(data[pos:])
-		if err != nil {
-			return nil, err
+		if mc.cfg.columnDefaults {
+			orgTable, _, n, err := readLengthEncodedString(data[pos:])
+			if err != nil {
+				return nil, err
+			}
+			columns[i].orgTable = string(orgTable)
+			pos += n
+		} else {
+			n, err = skipLengthEncodedString(data[pos:])
+			if err != nil {
+				return nil, err
+			}
+			pos += n
 		}
-		pos += n
 
 		// Name [len coded string]
 		name, _, n, err := readLengthEncodedString(data[pos:])
@@ -739,7 +1020,7 @@ func (mc *mysqlConn) readColumns(count int) ([]mysqlField, error) {
 		pos += n
 
 		// Original name [len coded string]
-		n, err = skipLengthEncodedString(data[pos:])
+		orgName, _, n, err := readLengthEncodedString(data[pos:])
 		if err != nil {
 			return nil, err
 		}
@@ -766,43 +1047,84 @@ func (mc *mysqlConn) readColumns(count int) ([]mysqlField, error) {
 
 		// Decimals [uint8]
 		columns[i].decimals = data[pos]
-		//pos++
+		pos++
+
+		// The primary key generated in GIPK mode (MySQL 8.0.30+) is always
+		// `my_row_id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT INVISIBLE`.
+		// The protocol has no flag for invisible columns, so such a column
+		// is only taken for it while the server is in GIPK mode.
+		const gipkFlags = flagNotNULL | flagPriKey | flagUnsigned | flagAutoIncrement
+		columns[i].generatedInvisiblePK = mc.gipkMode && string(orgName) == "my_row_id" &&
+			columns[i].fieldType == fieldTypeLongLong &&
+			columns[i].flags&gipkFlags == gipkFlags
+
+		if mc.cfg.columnDefaults {
+			columns[i].orgName = string(orgName)
+
+			// Filler [uint16]
+			pos += 2
 
-		// Default value [len coded binary]
-		//if pos < len(data) {
-		//	defaultVal, _, err = bytesToLengthCodedBinary(data[pos:])
-		//}
+			// Default value [len coded string], only sent for COM_FIELD_LIST
+			if pos < len(data) {
+				defaultValue, isNull, _, err := readLengthEncodedString(data[pos:])
+				if err != nil {
+					return nil, err
+				}
+				columns[i].defaultValue = string(defaultValue)
+				columns[i].hasDefault = !isNull
+			}
+		}
 	}
 }
 
-// Read Packets as Field Packets until EOF-Packet or an Error appears
-// http://dev.mysql.com/doc/internals/en/com-query-response.html#packet-ProtocolText::ResultsetRow
-func (rows *textRows) readRow(dest []driver.Value) error {
+// readRowPacket reads the next row packet of the result set. It returns
+// io.EOF after the last row.
+func (rows *textRows) readRowPacket() ([]byte, error) {
 	mc := rows.mc
 
+	if err := rows.skipRow(); err != nil {
+		return nil, err
+	}
 	if rows.rs.done {
-		return io.EOF
+		return nil, io.EOF
 	}
 
 	data, err := mc.readPacket()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// EOF Packet
-	if data[0] == iEOF && len(data) == 5 {
-		// server_status [2 bytes]
-		rows.mc.status = readStatus(data[3:])
+	if mc.isEOFPacket(data) {
+		if err := mc.handleEOFPacket(data); err != nil {
+			rows.mc = nil
+			return nil, err
+		}
 		rows.rs.done = true
 		if !rows.HasNextResultSet() {
 			rows.mc = nil
 		}
-		return io.EOF
+		return nil, io.EOF
 	}
 	if data[0] == iERR {
 		rows.mc = nil
-		return mc.handleErrorPacket(data)
+		return nil, mc.handleErrorPacket(data)
+	}
+
+	if err := rows.checkLimits(len(data)); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Read Packets as Field Packets until EOF-Packet or an Error appears
+// http://dev.mysql.com/doc/internals/en/com-query-response.html#packet-ProtocolText::ResultsetRow
+func (rows *textRows) readRow(dest []driver.Value) error {
+	data, err := rows.readRowPacket()
+	if err != nil {
+		return err
 	}
+	mc := rows.mc
 
 	// RowSet Packet
 	var (
@@ -826,6 +1148,16 @@ func (rows *textRows) readRow(dest []driver.Value) error {
 			continue
 		}
 
+		if rows.mapper != nil {
+			mapped, err := rows.mapValue(dest, i, buf)
+			if err != nil {
+				return err
+			}
+			if mapped {
+				continue
+			}
+		}
+
 		switch rows.rs.columns[i].fieldType {
 		case fieldTypeTimestamp,
 			fieldTypeDateTime,
@@ -837,6 +1169,13 @@ func (rows *textRows) readRow(dest []driver.Value) error {
 				dest[i] = buf
 			}
 
+		case fieldTypeTime:
+			if mc.cfg.parseDuration {
+				dest[i], err = parseTimeDuration(buf)
+			} else {
+				dest[i] = buf
+			}
+
 		case fieldTypeTiny, fieldTypeShort, fieldTypeInt24, fieldTypeYear, fieldTypeLong:
 			dest[i], err = strconv.ParseInt(string(buf), 10, 64)
 
@@ -878,7 +1217,14 @@ func (mc *mysqlConn) readUntilEOF() error {
 		case iERR:
 			return mc.handleErrorPacket(data)
 		case iEOF:
+			if mc.deprecateEOF {
+				if len(data) < maxPacketSize {
+					return mc.resultUnchanged().handleOkPacket(data)
+				}
+				continue // row starting with a value of 16 MiB or more
+			}
 			if len(data) == 5 {
+				mc.result.addWarnings(binary.LittleEndian.Uint16(data[1:3]))
 				mc.status = readStatus(data[3:])
 			}
 			return nil
@@ -886,12 +1232,78 @@ func (mc *mysqlConn) readUntilEOF() error {
 	}
 }
 
+// skipColumns reads the count column definitions of a result set and the
+// following EOF packet, which is not sent with CLIENT_DEPRECATE_EOF.
+func (mc *mysqlConn) skipColumns(count int) error {
+	if !mc.deprecateEOF {
+		return mc.readUntilEOF()
+	}
+	for i := 0; i < count; i++ {
+		data, err := mc.readPacket()
+		if err != nil {
+			return err
+		}
+		if data[0] == iERR {
+			return mc.handleErrorPacket(data)
+		}
+	}
+	return nil
+}
+
+// isEOFPacket reports whether data is the packet which terminates the rows of
+// a result set. With CLIENT_DEPRECATE_EOF it is an OK packet with the header
+// of EOF packets, which rows can only start with if their first value is
+// 16 MiB or longer.
+func (mc *mysqlConn) isEOFPacket(data []byte) bool {
+	if data[0] != iEOF {
+		return false
+	}
+	if mc.deprecateEOF {
+		return len(data) < maxPacketSize
+	}
+	return len(data) == 5
+}
+
+// handleEOFPacket updates the warning count and the status from the packet
+// which terminates the rows of a result set, see isEOFPacket.
+func (mc *mysqlConn) handleEOFPacket(data []byte) error {
+	if mc.deprecateEOF {
+		return mc.resultUnchanged().handleOkPacket(data)
+	}
+	// warning count [2 bytes]
+	mc.result.addWarnings(binary.LittleEndian.Uint16(data[1:3]))
+	// server_status [2 bytes]
+	mc.status = readStatus(data[3:])
+	return nil
+}
+
 /******************************************************************************
 *                           Prepared Statements                               *
 ******************************************************************************/
 
 // Prepare Result Packets
 // http://dev.mysql.com/doc/internals/en/com-stmt-prepare-response.html
+// readPrepareResult reads the complete response to COM_STMT_PREPARE.
+func (stmt *mysqlStmt) readPrepareResult() error {
+	columnCount, err := stmt.readPrepareResultPacket()
+	if err == nil {
+		if stmt.paramCount > 0 {
+			if err = stmt.mc.skipColumns(stmt.paramCount); err != nil {
+				return err
+			}
+		}
+
+		if columnCount > 0 {
+			if stmt.mc.cfg.columnDefaults {
+				stmt.columns, err = stmt.mc.readColumns(int(columnCount))
+			} else {
+				err = stmt.mc.skipColumns(int(columnCount))
+			}
+		}
+	}
+	return err
+}
+
 func (stmt *mysqlStmt) readPrepareResultPacket() (uint16, error) {
 	data, err := stmt.mc.readPacket()
 	if err == nil {
@@ -920,6 +1332,10 @@ func (stmt *mysqlStmt) readPrepareResultPacket() (uint16, error) {
 
 // http://dev.mysql.com/doc/internals/en/com-stmt-send-long-data.html
 func (stmt *mysqlStmt) writeCommandLongData(paramID int, arg []byte) error {
+	if err := stmt.mc.flushCoalesced(); err != nil {
+		return err
+	}
+
 	maxLen := stmt.mc.maxAllowedPacket - 1
 	pktLen := maxLen
 
@@ -978,6 +1394,19 @@ func (stmt *mysqlStmt) writeExecutePacket(args []driver.Value) error {
 
 	const minPktLen = 4 + 1 + 4 + 1 + 4
 	mc := stmt.mc
+	if err := mc.flushCoalesced(); err != nil {
+		return err
+	}
+
+	// Query attributes are sent after the parameters. Each parameter type is
+	// followed by the (empty) parameter name then.
+	var attrs []queryAttr
+	typeLen := 2
+	if mc.sendsQueryAttrs() {
+		attrs = mc.queryAttrs
+		typeLen = 3
+	}
+	paramCount := len(args) + len(attrs)
 
 	// Determine threshold dynamically to avoid packet size shortage.
 	longDataSize := mc.maxAllowedPacket / (stmt.paramCount + 1)
@@ -991,7 +1420,7 @@ func (stmt *mysqlStmt) writeExecutePacket(args []driver.Value) error {
 	var data []byte
 	var err error
 
-	if len(args) == 0 {
+	if paramCount == 0 {
 		data, err = mc.buf.takeBuffer(minPktLen)
 	} else {
 		data, err = mc.buf.takeCompleteBuffer()
@@ -1013,11 +1442,19 @@ func (stmt *mysqlStmt) writeExecutePacket(args []driver.Value) error {
 	// iteration_count (uint32(1)) [4 bytes]
 	binary.LittleEndian.PutUint32(data[10:], 1)
 
-	if len(args) > 0 {
+	if paramCount > 0 {
 		pos := minPktLen
 
+		if typeLen == 3 {
+			if len(args) == 0 {
+				data[9] |= parameterCountAvailable
+			}
+			// parameter_count [lenenc int]
+			pos = len(appendLengthEncodedInteger(data[:pos], uint64(paramCount)))
+		}
+
 		var nullMask []byte
-		if maskLen, typesLen := (len(args)+7)/8, 1+2*len(args); pos+maskLen+typesLen >= cap(data) {
+		if maskLen, typesLen := (paramCount+7)/8, 1+typeLen*len(args)+queryAttrTypesLen(attrs); pos+maskLen+typesLen >= cap(data) {
 			// buffer has to be extended but we don't know by how much so
 			// we depend on append after all data with known sizes fit.
 			// We stop at that because we deal with a lot of columns here
@@ -1040,20 +1477,28 @@ func (stmt *mysqlStmt) writeExecutePacket(args []driver.Value) error {
 		data[pos] = 0x01
 		pos++
 
-		// type of each parameter [len(args)*2 bytes]
+		// type of each parameter [len(args)*typeLen bytes]
 		paramTypes := data[pos:]
-		pos += len(args) * 2
+		pos += len(args) * typeLen
+		pos = len(appendQueryAttrTypes(data[:pos], attrs))
 
 		// value of each parameter [n bytes]
 		paramValues := data[pos:pos]
 		valuesCap := cap(paramValues)
 
 		for i, arg := range args {
+			t := i * typeLen
+			if typeLen == 3 {
+				// parameter name [lenenc string]
+				paramTypes[t+2] = 0x00
+			}
+
 			// build NULL-bitmap
+			arg = mc.zeroDateArg(arg)
 			if arg == nil {
 				nullMask[i/8] |= 1 << (uint(i) & 7)
-				paramTypes[i+i] = byte(fieldTypeNULL)
-				paramTypes[i+i+1] = 0x00
+				paramTypes[t] = byte(fieldTypeNULL)
+				paramTypes[t+1] = 0x00
 				continue
 			}
 
@@ -1063,23 +1508,34 @@ func (stmt *mysqlStmt) writeExecutePacket(args []driver.Value) error {
 			// cache types and values
 			switch v := arg.(type) {
 			case int64:
-				paramTypes[i+i] = byte(fieldTypeLongLong)
-				paramTypes[i+i+1] = 0x00
+				paramTypes[t] = byte(fieldTypeLongLong)
+				paramTypes[t+1] = 0x00
 				paramValues = binary.LittleEndian.AppendUint64(paramValues, uint64(v))
 
 			case uint64:
-				paramTypes[i+i] = byte(fieldTypeLongLong)
-				paramTypes[i+i+1] = 0x80 // type is unsigned
+				paramTypes[t] = byte(fieldTypeLongLong)
+				paramTypes[t+1] = 0x80 // type is unsigned
 				paramValues = binary.LittleEndian.AppendUint64(paramValues, uint64(v))
 
 			case float64:
-				paramTypes[i+i] = byte(fieldTypeDouble)
-				paramTypes[i+i+1] = 0x00
+				paramTypes[t] = byte(fieldTypeDouble)
+				paramTypes[t+1] = 0x00
 				paramValues = binary.LittleEndian.AppendUint64(paramValues, math.Float64bits(v))
 
+			case float32:
+				paramTypes[t] = byte(fieldTypeFloat)
+				paramTypes[t+1] = 0x00
+				paramValues = binary.LittleEndian.AppendUint32(paramValues, math.Float32bits(v))
+
+			case Decimal:
+				paramTypes[t] = byte(fieldTypeNewDecimal)
+				paramTypes[t+1] = 0x00
+				paramValues = appendLengthEncodedInteger(paramValues, uint64(len(v.String())))
+				paramValues = append(paramValues, v.String()...)
+
 			case bool:
-				paramTypes[i+i] = byte(fieldTypeTiny)
-				paramTypes[i+i+1] = 0x00
+				paramTypes[t] = byte(fieldTypeTiny)
+				paramTypes[t+1] = 0x00
 
 				if v {
 					paramValues = append(paramValues, 0x01)
@@ -1090,8 +1546,8 @@ func (stmt *mysqlStmt) writeExecutePacket(args []driver.Value) error {
 			case []byte:
 				// Common case (non-nil value) first
 				if v != nil {
-					paramTypes[i+i] = byte(fieldTypeString)
-					paramTypes[i+i+1] = 0x00
+					paramTypes[t] = byte(fieldTypeString)
+					paramTypes[t+1] = 0x00
 
 					if len(v) < longDataSize {
 						paramValues = appendLengthEncodedInteger(paramValues,
@@ -1108,12 +1564,12 @@ func (stmt *mysqlStmt) writeExecutePacket(args []driver.Value) error {
 
 				// Handle []byte(nil) as a NULL value
 				nullMask[i/8] |= 1 << (uint(i) & 7)
-				paramTypes[i+i] = byte(fieldTypeNULL)
-				paramTypes[i+i+1] = 0x00
+				paramTypes[t] = byte(fieldTypeNULL)
+				paramTypes[t+1] = 0x00
 
 			case string:
-				paramTypes[i+i] = byte(fieldTypeString)
-				paramTypes[i+i+1] = 0x00
+				paramTypes[t] = byte(fieldTypeString)
+				paramTypes[t+1] = 0x00
 
 				if len(v) < longDataSize {
 					paramValues = appendLengthEncodedInteger(paramValues,
@@ -1127,8 +1583,8 @@ func (stmt *mysqlStmt) writeExecutePacket(args []driver.Value) error {
 				}
 
 			case time.Time:
-				paramTypes[i+i] = byte(fieldTypeString)
-				paramTypes[i+i+1] = 0x00
+				paramTypes[t] = byte(fieldTypeString)
+				paramTypes[t+1] = 0x00
 
 				var a [64]byte
 				var b = a[:0]
@@ -1147,10 +1603,16 @@ func (stmt *mysqlStmt) writeExecutePacket(args []driver.Value) error {
 				)
 				paramValues = append(paramValues, b...)
 
+			case time.Duration:
+				paramTypes[t] = byte(fieldTypeTime)
+				paramTypes[t+1] = 0x00
+				paramValues = appendBinaryTimeDuration(paramValues, v)
+
 			default:
 				return fmt.Errorf("cannot convert type: %T", arg)
 			}
 		}
+		paramValues = appendQueryAttrValues(paramValues, attrs)
 
 		// Check if param values exceeded the available buffer
 		// In that case we must build the data packet with the new values buffer
@@ -1178,7 +1640,7 @@ func (mc *okHandler) discardResults() error {
 		}
 		if resLen > 0 {
 			// columns
-			if err := mc.conn().readUntilEOF(); err != nil {
+			if err := mc.conn().skipColumns(resLen); err != nil {
 				return err
 			}
 			// rows
@@ -1200,8 +1662,11 @@ func (rows *binaryRows) readRow(dest []driver.Value) error {
 	// packet indicator [1 byte]
 	if data[0] != iOK {
 		// EOF Packet
-		if data[0] == iEOF && len(data) == 5 {
-			rows.mc.status = readStatus(data[3:])
+		if rows.mc.isEOFPacket(data) {
+			if err := rows.mc.handleEOFPacket(data); err != nil {
+				rows.mc = nil
+				return err
+			}
 			rows.rs.done = true
 			if !rows.HasNextResultSet() {
 				rows.mc = nil
@@ -1214,6 +1679,9 @@ func (rows *binaryRows) readRow(dest []driver.Value) error {
 		// Error otherwise
 		return mc.handleErrorPacket(data)
 	}
+	if err := rows.checkLimits(len(data)); err != nil {
+		return err
+	}
 
 	// NULL-bitmap,  [(column-count + 7 + 2) / 8 bytes]
 	pos := 1 + (len(dest)+7+2)>>3
@@ -1264,7 +1732,9 @@ func (rows *binaryRows) readRow(dest []driver.Value) error {
 		case fieldTypeLongLong:
 			if rows.rs.columns[i].flags&flagUnsigned != 0 {
 				val := binary.LittleEndian.Uint64(data[pos : pos+8])
-				if val > math.MaxInt64 {
+				if rows.mc.cfg.allowUint64 {
+					dest[i] = val
+				} else if val > math.MaxInt64 {
 					dest[i] = uint64ToString(val)
 				} else {
 					dest[i] = int64(val)
@@ -1317,6 +1787,8 @@ func (rows *binaryRows) readRow(dest []driver.Value) error {
 			case isNull:
 				dest[i] = nil
 				continue
+			case rows.rs.columns[i].fieldType == fieldTypeTime && rows.mc.cfg.parseDuration:
+				dest[i], err = parseBinaryTimeDuration(data[pos : pos+int(num)])
 			case rows.rs.columns[i].fieldType == fieldTypeTime:
 				// database/sql does not support an equivalent to TIME, return a string
 				var dstlen uint8
@@ -1367,5 +1839,8 @@ func (rows *binaryRows) readRow(dest []driver.Value) error {
 		}
 	}
 
+	if rows.mapper != nil {
+		return rows.mapBinaryValues(dest)
+	}
 	return nil
 }
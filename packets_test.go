@@ -10,8 +10,14 @@ package mysql
 
 import (
 	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
 	"errors"
+	"io"
+	"math"
 	"net"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -99,7 +105,7 @@ func newRWMockConn(sequence uint8) (*mockConn, *mysqlConn) {
 	connector := newConnector(NewConfig())
 	mc := &mysqlConn{
 		buf:              newBuffer(),
-		cfg:              connector.cfg,
+		cfg:              connector.config(),
 		connector:        connector,
 		netConn:          conn,
 		closech:          make(chan struct{}),
@@ -347,3 +353,303 @@ func TestRegression801(t *testing.T) {
 		t.Errorf("expected authData '%v', got '%v'", expectedAuthData, authData)
 	}
 }
+
+// columnDefinition returns a column definition packet (Protocol::ColumnDefinition41)
+func columnDefinition(seq byte, name, orgName string, ft fieldType, flags fieldFlag) []byte {
+	var data []byte
+	data = appendLengthEncodedString(data, "def")
+	data = appendLengthEncodedString(data, "db")
+	data = appendLengthEncodedString(data, "t")
+	data = appendLengthEncodedString(data, "t")
+	data = appendLengthEncodedString(data, name)
+	data = appendLengthEncodedString(data, orgName)
+	data = append(data, 0x0c, 63, 0, 20, 0, 0, 0, byte(ft), byte(flags), byte(flags>>8), 0, 0, 0)
+	return append([]byte{byte(len(data)), 0, 0, seq}, data...)
+}
+
+func TestReadColumnsGeneratedInvisiblePK(t *testing.T) {
+	gipkFlags := flagNotNULL | flagPriKey | flagUnsigned | flagAutoIncrement
+	for _, gipkMode := range []bool{true, false} {
+		conn, mc := newRWMockConn(1)
+		mc.gipkMode = gipkMode
+		conn.data = append(conn.data, columnDefinition(1, "my_row_id", "my_row_id", fieldTypeLongLong, gipkFlags)...)
+		conn.data = append(conn.data, columnDefinition(2, "id", "my_row_id", fieldTypeLongLong, gipkFlags)...)
+		conn.data = append(conn.data, columnDefinition(3, "my_row_id", "my_row_id", fieldTypeLong, gipkFlags)...)
+		conn.data = append(conn.data, columnDefinition(4, "id", "id", fieldTypeLongLong, gipkFlags)...)
+		conn.data = append(conn.data, 5, 0, 0, 5, iEOF, 0, 0, 2, 0)
+
+		columns, err := mc.readColumns(4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows := &textRows{mysqlRows{mc: mc, rs: resultSet{columns: columns}}}
+		var _ Rows = rows
+		for i, expected := range []bool{gipkMode, gipkMode, false, false} {
+			if actual := rows.ColumnTypeGeneratedInvisiblePrimaryKey(i); actual != expected {
+				t.Errorf("GIPK mode %v, column %d: expected %v, got %v", gipkMode, i, expected, actual)
+			}
+		}
+	}
+}
+
+func TestHandleErrorPacketShouldRetryError(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.ShouldRetryError = func(err *MySQLError) bool {
+		return err.Number == 1836 // ER_READ_ONLY_MODE
+	}
+
+	// ER_DUP_ENTRY is returned unchanged
+	data := append([]byte{0xff, 0x26, 0x04, '#', '2', '3', '0', '0', '0'}, "Duplicate entry"...)
+	if err, ok := mc.handleErrorPacket(data).(*MySQLError); !ok || err.Number != 1062 {
+		t.Errorf("expected MySQLError 1062, got %v", err)
+	}
+	if mc.closed.Load() {
+		t.Fatal("connection must not be closed")
+	}
+
+	data = append([]byte{0xff, 0x2c, 0x07, '#', 'H', 'Y', '0', '0', '0'}, "Running in read-only mode"...)
+	if err := mc.handleErrorPacket(data); err != driver.ErrBadConn {
+		t.Errorf("expected ErrBadConn, got %v", err)
+	}
+	if !mc.closed.Load() {
+		t.Error("connection must be closed")
+	}
+	if len(conn.written) == 0 || conn.written[4] != comQuit {
+		t.Errorf("expected COM_QUIT, got %v", conn.written)
+	}
+}
+
+func TestReadTextRowsAllocs(t *testing.T) {
+	const n = 100
+	resp := textRowsResponse(n, 50)
+	conn, mc := newRWMockConn(2)
+	dest := make([]driver.Value, 2)
+
+	// one for boxing each value, none for reading the packets
+	allocs := testing.AllocsPerRun(10, func() {
+		if err := readTextRows(conn, mc, resp, dest); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs > 2*n+10 {
+		t.Errorf("expected at most %d allocations, got %v", 2*n+10, allocs)
+	}
+}
+
+func TestBufferReadAhead(t *testing.T) {
+	conn, mc := newRWMockConn(2)
+	mc.buf.maxSize = 16 * 1024
+	dest := make([]driver.Value, 2)
+
+	if err := readTextRows(conn, mc, textRowsResponse(100, 2000), dest); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(mc.buf.cachedBuf); n != mc.buf.maxSize {
+		t.Errorf("expected the buffer to grow to %d bytes, got %d", mc.buf.maxSize, n)
+	}
+
+	if err := readTextRows(conn, mc, textRowsResponse(1000, 50), dest); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(mc.buf.cachedBuf); n != defaultBufSize {
+		t.Errorf("expected the buffer to shrink to %d bytes, got %d", defaultBufSize, n)
+	}
+
+	// large writes do not grow the read buffer
+	avg := mc.buf.avgPkt
+	if err := mc.writeCommandPacketStr(comQuery, strings.Repeat("x", 100000)); err != nil {
+		t.Fatal(err)
+	}
+	if mc.buf.avgPkt != avg {
+		t.Errorf("written packet changed the average packet size from %d to %d", avg, mc.buf.avgPkt)
+	}
+}
+
+func TestHandleOkPacketInfo(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	info := "Records: 3  Duplicates: 0  Warnings: 0"
+	conn.queuedReplies = [][]byte{mockPacket(1, append([]byte{iOK, 3, 7, 2, 0, 0, 0}, info...))}
+
+	res, err := mc.Exec("INSERT INTO t (a) VALUES (1), (2), (3)", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := ResultInfo(res); !ok || got != info {
+		t.Errorf("expected info %q, got %q", info, got)
+	}
+	if _, ok := ResultInfo(driver.RowsAffected(3)); ok {
+		t.Error("expected ok to be false for other results")
+	}
+
+	// length encoded with CLIENT_SESSION_TRACK
+	mc.sessionTrack = true
+	if err := mc.clearResult().handleOkPacket([]byte{iOK, 0, 0, 2, 0, 0, 0, 3, 'a', 'b', 'c'}); err != nil {
+		t.Fatal(err)
+	}
+	if mc.result.info != "abc" {
+		t.Errorf("expected info %q, got %q", "abc", mc.result.info)
+	}
+}
+
+func TestHandleOkPacketSessionTrack(t *testing.T) {
+	_, mc := newRWMockConn(0)
+	mc.cfg.Logger = &NopLogger{}
+	mc.sessionTrack = true
+	changed := byte(statusSessionStateChanged >> 8)
+
+	// info and session state changes
+	ok := []byte{iOK, 0, 0, 2, changed, 0, 0, 0, 3, 'a', 'b', 'c'}
+	if err := mc.clearResult().handleOkPacket(ok); err != nil {
+		t.Fatal(err)
+	}
+	if !mc.sessionTrack {
+		t.Error("session tracking was disabled")
+	}
+
+	// no info without state changes
+	if err := mc.clearResult().handleOkPacket([]byte{iOK, 0, 0, 2, 0, 0, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if !mc.sessionTrack {
+		t.Error("session tracking was disabled")
+	}
+
+	// stripped by a proxy
+	for _, ok := range [][]byte{
+		{iOK, 0, 0, 2, changed, 0, 0},
+		{iOK, 0, 0, 2, changed, 0, 0, 0},
+		{iOK, 0, 0, 2, changed, 0, 0, 0, 0xfc, 1},
+	} {
+		mc.sessionTrack = true
+		if err := mc.clearResult().handleOkPacket(ok); err != nil {
+			t.Errorf("%v: %v", ok, err)
+		}
+		if mc.sessionTrack {
+			t.Errorf("%v: session tracking was not disabled", ok)
+		}
+	}
+
+	// truncated packet
+	if err := mc.clearResult().handleOkPacket([]byte{iOK, 0, 0, 2}); err != ErrMalformPkt {
+		t.Errorf("expected ErrMalformPkt, got %v", err)
+	}
+}
+
+func TestReadBinaryRowUint64(t *testing.T) {
+	row := []byte{0x00, 0x00} // header and NULL bitmap
+	row = binary.LittleEndian.AppendUint64(row, math.MaxUint64)
+	row = binary.LittleEndian.AppendUint64(row, 42)
+
+	for _, allowUint64 := range []bool{false, true} {
+		conn, mc := newRWMockConn(1)
+		mc.cfg.allowUint64 = allowUint64
+		conn.data = mockPacket(1, row)
+		rows := &binaryRows{}
+		rows.mc = mc
+		rows.rs.columns = []mysqlField{
+			{fieldType: fieldTypeLongLong, flags: flagUnsigned},
+			{fieldType: fieldTypeLongLong, flags: flagUnsigned},
+		}
+		dest := make([]driver.Value, 2)
+		if err := rows.readRow(dest); err != nil {
+			t.Fatal(err)
+		}
+		want := []driver.Value{[]byte("18446744073709551615"), int64(42)}
+		if allowUint64 {
+			want = []driver.Value{uint64(math.MaxUint64), uint64(42)}
+		}
+		if !reflect.DeepEqual(dest, want) {
+			t.Errorf("allowUint64=%t: got %#v, want %#v", allowUint64, dest, want)
+		}
+	}
+}
+
+func TestDeprecateEOF(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.deprecateEOF = true
+
+	// no EOF after the columns, OK packet with 0xfe header after the rows
+	resp := mockPacket(1, []byte{2})
+	resp = append(resp, mockColumn(2, "a", fieldTypeLong)...)
+	resp = append(resp, mockColumn(3, "b", fieldTypeVarString)...)
+	resp = append(resp, mockPacket(4, append(appendLengthEncodedString(nil, "1"), 0))...) // empty string
+	resp = append(resp, mockPacket(5, []byte{iEOF, 0, 0, 2, 0, 1, 0})...)
+	conn.queuedReplies = [][]byte{resp}
+
+	rows, err := mc.Query("SELECT a, b FROM t", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := make([]driver.Value, 2)
+	if err := rows.Next(dest); err != nil {
+		t.Fatal(err)
+	}
+	if dest[0] != int64(1) || string(dest[1].([]byte)) != "" {
+		t.Errorf("unexpected row %v", dest)
+	}
+	if err := rows.Next(dest); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+	if mc.result.warningCount != 1 || mc.status != statusInAutocommit {
+		t.Errorf("OK packet not handled: %d warnings, status %d", mc.result.warningCount, mc.status)
+	}
+
+	// prepared statement with one parameter and one column, which are
+	// skipped, and an Exec of a statement returning a result set
+	resp = mockPacket(1, []byte{iOK, 1, 0, 0, 0, 1, 0, 1, 0, 0, 0, 0})
+	resp = append(resp, mockColumn(2, "?", fieldTypeVarString)...)
+	resp = append(resp, mockColumn(3, "a", fieldTypeLong)...)
+	conn.queuedReplies = [][]byte{resp}
+	stmt, err := mc.Prepare("SELECT a FROM t WHERE b = ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp = mockPacket(1, []byte{1})
+	resp = append(resp, mockColumn(2, "a", fieldTypeLong)...)
+	resp = append(resp, mockPacket(3, []byte{iOK, 0, 1, 0, 0, 0})...)
+	resp = append(resp, mockPacket(4, []byte{iEOF, 0, 0, 2, 0, 0, 0})...)
+	conn.queuedReplies = [][]byte{resp}
+	if _, err := stmt.Exec([]driver.Value{int64(1)}); err != nil {
+		t.Fatal(err)
+	}
+	if len(conn.data) != 0 {
+		t.Errorf("%d bytes not read", len(conn.data))
+	}
+}
+
+// readFuncConn is a mockConn whose reads are made by read.
+type readFuncConn struct {
+	*mockConn
+	read func([]byte) (int, error)
+}
+
+func (c readFuncConn) Read(b []byte) (int, error) {
+	return c.read(b)
+}
+
+func TestReadPacketShortReads(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	data := []byte{3, 0, 0, 0, 'a', 'b', 'c'}
+	mc.netConn = readFuncConn{conn, func(b []byte) (int, error) {
+		if len(data) == 0 {
+			return 0, io.EOF
+		}
+		n := copy(b[:1], data)
+		data = data[n:]
+		return n, nil
+	}}
+	packet, err := mc.readPacket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(packet) != "abc" {
+		t.Errorf("expected %q, got %q", "abc", packet)
+	}
+
+	// a reader which never returns data must not block forever
+	conn, mc = newRWMockConn(0)
+	mc.netConn = readFuncConn{conn, func(b []byte) (int, error) { return 0, nil }}
+	if _, err := mc.readPacket(); err != ErrInvalidConn {
+		t.Errorf("expected ErrInvalidConn, got %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import "strings"
+
+// defaultPipeName is the default name of the named pipe of the server, see
+// the socket system variable.
+const defaultPipeName = "MySQL"
+
+// pipePath returns the path of the named pipe addr. addr is either the name
+// of a pipe on the local machine, e.g. "MySQL", or a full pipe path, e.g.
+// `\\.\pipe\MySQL` or `\\host\pipe\MySQL`.
+func pipePath(addr string) string {
+	if strings.HasPrefix(addr, `\\`) {
+		return addr
+	}
+	return `\\.\pipe\` + addr
+}
+
+// pipeAddr is the net.Addr of named pipe connections.
+type pipeAddr string
+
+func (a pipeAddr) Network() string { return "pipe" }
+func (a pipeAddr) String() string  { return string(a) }
@@ -0,0 +1,22 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build !windows
+
+package mysql
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// dialPipe fails, named pipes are only available on Windows.
+func dialPipe(ctx context.Context, addr string) (net.Conn, error) {
+	return nil, errors.New("named pipe " + pipePath(addr) + ": named pipes are only supported on Windows")
+}
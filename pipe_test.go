@@ -0,0 +1,23 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import "testing"
+
+func TestPipePath(t *testing.T) {
+	for addr, want := range map[string]string{
+		"MySQL":               `\\.\pipe\MySQL`,
+		`\\.\pipe\MySQL80`:    `\\.\pipe\MySQL80`,
+		`\\dbhost\pipe\MySQL`: `\\dbhost\pipe\MySQL`,
+	} {
+		if got := pipePath(addr); got != want {
+			t.Errorf("pipePath(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
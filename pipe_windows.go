@@ -0,0 +1,80 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//go:build windows
+
+package mysql
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// errorPipeBusy is ERROR_PIPE_BUSY, returned while all instances of the pipe
+// are in use.
+const errorPipeBusy syscall.Errno = 231
+
+// dialPipe opens the named pipe addr. Busy pipes are retried until ctx is
+// done.
+func dialPipe(ctx context.Context, addr string) (net.Conn, error) {
+	path := pipePath(addr)
+	for {
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err == nil {
+			return &pipeConn{File: f, addr: pipeAddr(path)}, nil
+		}
+		if !errors.Is(err, errorPipeBusy) {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// pipeConn is a net.Conn on a named pipe. The pipe is opened for synchronous
+// I/O, which does not support deadlines. Setting deadlines is a no-op;
+// normalize rejects the timeouts which rely on them for named pipes.
+//
+// Close does not interrupt a pending synchronous Read or Write either: the
+// handle is only released when the pending call returns, i.e. when the server
+// sends data or closes the pipe. Overlapped I/O would be required to cancel
+// them, which os.File does not support for the Go versions supported by this
+// driver.
+type pipeConn struct {
+	*os.File
+	addr pipeAddr
+}
+
+func (c *pipeConn) LocalAddr() net.Addr  { return c.addr }
+func (c *pipeConn) RemoteAddr() net.Addr { return c.addr }
+
+func (c *pipeConn) SetDeadline(t time.Time) error {
+	return ignoreNoDeadline(c.File.SetDeadline(t))
+}
+
+func (c *pipeConn) SetReadDeadline(t time.Time) error {
+	return ignoreNoDeadline(c.File.SetReadDeadline(t))
+}
+
+func (c *pipeConn) SetWriteDeadline(t time.Time) error {
+	return ignoreNoDeadline(c.File.SetWriteDeadline(t))
+}
+
+func ignoreNoDeadline(err error) error {
+	if errors.Is(err, os.ErrNoDeadline) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Profile overrides settings of the Config for the operations executed with
+// a context returned by WithProfile, e.g. long timeouts for batch jobs and
+// short ones for interactive requests sharing the same connection pool.
+// Zero fields do not override anything.
+type Profile struct {
+	ReadTimeout  time.Duration // replaces Config.ReadTimeout
+	WriteTimeout time.Duration // replaces Config.WriteTimeout
+
+	// Isolation is the isolation level of transactions started with
+	// sql.LevelDefault.
+	Isolation sql.IsolationLevel
+
+	// QueryHints are optimizer hints added to SELECT statements in addition
+	// to the hints set by DefaultQueryHints, e.g. "MAX_EXECUTION_TIME(1000)".
+	// For prepared statements the profile of the context passed to
+	// PrepareContext is used.
+	QueryHints []string
+}
+
+type profileKey struct{}
+
+// WithProfile returns a copy of ctx selecting the profile of Config.Profiles
+// with the given name for the statements and transactions executed with it.
+// Using a name missing in Config.Profiles returns an error.
+//
+// The profile stays in effect for reading the rows of a query until the
+// connection is used for the next operation.
+func WithProfile(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, profileKey{}, name)
+}
+
+// setProfile selects the profile of ctx for the next operation.
+func (mc *mysqlConn) setProfile(ctx context.Context) error {
+	name, ok := ctx.Value(profileKey{}).(string)
+	if !ok {
+		mc.profile = nil
+		return nil
+	}
+	p, ok := mc.cfg.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+	mc.profile = &p
+	return nil
+}
+
+func (mc *mysqlConn) readTimeout() time.Duration {
+	if p := mc.profile; p != nil && p.ReadTimeout > 0 {
+		return p.ReadTimeout
+	}
+	return mc.cfg.ReadTimeout
+}
+
+func (mc *mysqlConn) writeTimeout() time.Duration {
+	if p := mc.profile; p != nil && p.WriteTimeout > 0 {
+		return p.WriteTimeout
+	}
+	return mc.cfg.WriteTimeout
+}
+
+// isolation returns the isolation level for a transaction with the level
+// given to BeginTx.
+func (mc *mysqlConn) isolation(level sql.IsolationLevel) sql.IsolationLevel {
+	if p := mc.profile; p != nil && level == sql.LevelDefault {
+		return p.Isolation
+	}
+	return level
+}
+
+// queryHints returns the optimizer hints for the next statement.
+func (mc *mysqlConn) queryHints() []string {
+	if p := mc.profile; p != nil && len(p.QueryHints) > 0 {
+		return append(mc.cfg.queryHints[:len(mc.cfg.queryHints):len(mc.cfg.queryHints)], p.QueryHints...)
+	}
+	return mc.cfg.queryHints
+}
@@ -0,0 +1,92 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+func TestProfileSettings(t *testing.T) {
+	_, mc := newRWMockConn(0)
+	mc.cfg.ReadTimeout = time.Second
+	mc.cfg.WriteTimeout = time.Second
+	mc.cfg.queryHints = []string{"NO_INDEX_MERGE(t)"}
+	mc.cfg.Profiles = map[string]Profile{
+		"batch": {
+			ReadTimeout: 10 * time.Minute,
+			Isolation:   sql.LevelReadCommitted,
+			QueryHints:  []string{"MAX_EXECUTION_TIME(600000)"},
+		},
+	}
+
+	if err := mc.setProfile(WithProfile(context.Background(), "unknown")); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+
+	if err := mc.setProfile(WithProfile(context.Background(), "batch")); err != nil {
+		t.Fatal(err)
+	}
+	if mc.readTimeout() != 10*time.Minute || mc.writeTimeout() != time.Second {
+		t.Errorf("unexpected timeouts %v, %v", mc.readTimeout(), mc.writeTimeout())
+	}
+	if level := mc.isolation(sql.LevelDefault); level != sql.LevelReadCommitted {
+		t.Errorf("unexpected isolation %v", level)
+	}
+	if level := mc.isolation(sql.LevelSerializable); level != sql.LevelSerializable {
+		t.Errorf("unexpected isolation %v", level)
+	}
+	want := "SELECT /*+ NO_INDEX_MERGE(t) MAX_EXECUTION_TIME(600000) */ 1"
+	if query := addQueryHints("SELECT 1", mc.queryHints()); query != want {
+		t.Errorf("expected %q, got %q", want, query)
+	}
+	if len(mc.cfg.queryHints) != 1 {
+		t.Errorf("profile hints were added to the config: %v", mc.cfg.queryHints)
+	}
+
+	// operations without profile use the config
+	if err := mc.setProfile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if mc.readTimeout() != time.Second || mc.isolation(sql.LevelDefault) != sql.LevelDefault {
+		t.Errorf("unexpected settings without profile")
+	}
+}
+
+func TestProfileQueryHints(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.Profiles = map[string]Profile{
+		"interactive": {QueryHints: []string{"MAX_EXECUTION_TIME(2000)"}},
+	}
+	conn.queuedReplies = [][]byte{mockPacket(1, []byte{iOK, 0, 0, 2, 0, 0, 0})}
+	conn.maxReads = 1
+
+	ctx := WithProfile(context.Background(), "interactive")
+	if _, err := mc.ExecContext(ctx, "SELECT SLEEP(1)", []driver.NamedValue{}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(conn.written, []byte("SELECT /*+ MAX_EXECUTION_TIME(2000) */ SLEEP(1)")) {
+		t.Errorf("hints not sent: %q", conn.written)
+	}
+}
+
+func TestCloneConfigProfiles(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Profiles = map[string]Profile{"batch": {QueryHints: []string{"A"}}}
+	cp := cfg.Clone()
+	cp.Profiles["batch"].QueryHints[0] = "B"
+	cp.Profiles["interactive"] = Profile{}
+	if len(cfg.Profiles) != 1 || cfg.Profiles["batch"].QueryHints[0] != "A" {
+		t.Errorf("profiles of the original config were modified: %v", cfg.Profiles)
+	}
+}
@@ -0,0 +1,110 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// QueryProgress describes what a connection is doing at the moment.
+type QueryProgress struct {
+	ConnectionID uint64
+	Command      string        // e.g. "Query" or "Sleep"
+	State        string        // thread state, e.g. "executing" or "Sending data"
+	Info         string        // statement being executed
+	Time         time.Duration // time spent in the current state
+	RowsExamined uint64        // not available without performance_schema
+	RowsSent     uint64        // not available without performance_schema
+}
+
+const (
+	progressQueryPerformanceSchema = "SELECT t.PROCESSLIST_COMMAND, t.PROCESSLIST_STATE, t.PROCESSLIST_INFO, t.PROCESSLIST_TIME, s.ROWS_EXAMINED, s.ROWS_SENT" +
+		" FROM performance_schema.threads t LEFT JOIN performance_schema.events_statements_current s" +
+		" ON s.THREAD_ID = t.THREAD_ID AND s.END_EVENT_ID IS NULL WHERE t.PROCESSLIST_ID = ?"
+	progressQueryProcesslist = "SELECT COMMAND, STATE, INFO, TIME, NULL, NULL" +
+		" FROM information_schema.PROCESSLIST WHERE ID = ?"
+)
+
+// PollQueryProgress reports the progress of the query running on the
+// connection with the given id (SELECT CONNECTION_ID()) to fn, every interval
+// until ctx is done or the connection is closed. q must use another
+// connection, typically it is the *sql.DB.
+//
+// The progress is read from performance_schema. If it is not available, the
+// processlist is used, which does not provide the number of rows examined
+// and sent. Monitoring the connections of other users requires the PROCESS
+// privilege.
+//
+// PollQueryProgress returns nil when the connection has been closed and
+// ctx.Err() when ctx is done.
+func PollQueryProgress(ctx context.Context, q interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}, connID uint64, interval time.Duration, fn func(QueryProgress)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	query := progressQueryPerformanceSchema
+	for {
+		p, found, err := queryProgress(ctx, q, query, connID)
+		var mysqlErr *MySQLError
+		if query == progressQueryPerformanceSchema && errors.As(err, &mysqlErr) {
+			// performance_schema is disabled or not accessible
+			query = progressQueryProcesslist
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !found && query == progressQueryPerformanceSchema {
+			// performance_schema may be disabled
+			query = progressQueryProcesslist
+			continue
+		}
+		if !found {
+			return nil
+		}
+		fn(p)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func queryProgress(ctx context.Context, q interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}, query string, connID uint64) (QueryProgress, bool, error) {
+	p := QueryProgress{ConnectionID: connID}
+	rows, err := q.QueryContext(ctx, query, connID)
+	if err != nil {
+		return p, false, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return p, false, rows.Err()
+	}
+	var command, state, info sql.NullString
+	var seconds, rowsExamined, rowsSent sql.NullInt64
+	if err := rows.Scan(&command, &state, &info, &seconds, &rowsExamined, &rowsSent); err != nil {
+		return p, false, err
+	}
+	p.Command = command.String
+	p.State = state.String
+	p.Info = info.String
+	p.Time = time.Duration(seconds.Int64) * time.Second
+	p.RowsExamined = uint64(rowsExamined.Int64)
+	p.RowsSent = uint64(rowsSent.Int64)
+	return p, true, rows.Close()
+}
@@ -0,0 +1,327 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxProxyResponseSize limits the status line and headers of the response of
+// HTTP proxies to CONNECT.
+const maxProxyResponseSize = 4096
+
+// validateProxyURL checks the URL of a proxy set with Proxy.
+func validateProxyURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid proxy: %w", err)
+	}
+	switch u.Scheme {
+	case "socks5", "socks5h", "http":
+	default:
+		return errors.New("invalid proxy: unsupported scheme '" + u.Scheme + "'")
+	}
+	if u.Hostname() == "" {
+		return errors.New("invalid proxy: missing host")
+	}
+	return nil
+}
+
+// isTCPNet reports whether network is a TCP network.
+func isTCPNet(network string) bool {
+	return network == "tcp" || network == "tcp4" || network == "tcp6"
+}
+
+// proxyURL returns the proxy through which the connection is made, or nil.
+func (cfg *Config) proxyURL() (*url.URL, error) {
+	if !isTCPNet(cfg.Net) {
+		return nil, nil
+	}
+	raw := cfg.proxy
+	if raw == "" && cfg.proxyFromEnv {
+		raw = getEnvAny("ALL_PROXY", "all_proxy")
+		if raw == "" || !useProxy(getEnvAny("NO_PROXY", "no_proxy"), cfg.Addr) {
+			return nil, nil
+		}
+		if err := validateProxyURL(raw); err != nil {
+			return nil, fmt.Errorf("ALL_PROXY: %w", err)
+		}
+	}
+	if raw == "" {
+		return nil, nil
+	}
+	return url.Parse(raw)
+}
+
+// getEnvAny returns the value of the first non-empty environment variable.
+func getEnvAny(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// useProxy reports whether addr (host:port) is not excluded from proxying by
+// noProxy, a comma-separated list of hosts, domains, IPs and CIDR ranges,
+// optionally with port. "*" excludes all addresses. Domains match their
+// subdomains, with or without leading dot.
+func useProxy(noProxy, addr string) bool {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			return false
+		}
+		if _, ipnet, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && ipnet.Contains(ip) {
+				return false
+			}
+			continue
+		}
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			if p != port {
+				continue
+			}
+			entry = h
+		}
+		if eip := net.ParseIP(strings.Trim(entry, "[]")); eip != nil {
+			if ip != nil && eip.Equal(ip) {
+				return false
+			}
+			continue
+		}
+		entry = strings.TrimPrefix(entry, ".")
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return false
+		}
+	}
+	return true
+}
+
+// proxyAddr returns the address of the proxy, with the default port of the
+// scheme if the URL has none.
+func proxyAddr(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	port := "1080"
+	if u.Scheme == "http" {
+		port = "80"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// proxyConnect makes the proxy at the other end of conn connect to addr. The
+// handshake is aborted when ctx is done.
+func proxyConnect(ctx context.Context, conn net.Conn, u *url.URL, addr string) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+		defer conn.SetDeadline(time.Time{})
+	}
+	var err error
+	if u.Scheme == "http" {
+		err = httpConnect(conn, u, addr)
+	} else {
+		err = socks5Connect(conn, u, addr)
+	}
+	if err != nil {
+		return fmt.Errorf("proxy %s: %w", proxyAddr(u), err)
+	}
+	return nil
+}
+
+// SOCKS5 protocol constants, see RFC 1928 and RFC 1929.
+const (
+	socks5Version        = 0x05
+	socks5AuthNone       = 0x00
+	socks5AuthPassword   = 0x02
+	socks5AuthNoAccepted = 0xff
+	socks5CmdConnect     = 0x01
+	socks5AddrIPv4       = 0x01
+	socks5AddrDomain     = 0x03
+	socks5AddrIPv6       = 0x04
+)
+
+// socks5Replies are the messages of the SOCKS5 reply codes.
+var socks5Replies = [...]string{
+	1: "general SOCKS server failure",
+	2: "connection not allowed by ruleset",
+	3: "network unreachable",
+	4: "host unreachable",
+	5: "connection refused",
+	6: "TTL expired",
+	7: "command not supported",
+	8: "address type not supported",
+}
+
+// socks5Connect performs the SOCKS5 handshake on conn to connect to addr.
+// Host names are resolved by the proxy.
+func socks5Connect(conn net.Conn, u *url.URL, addr string) error {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return errors.New("invalid port " + portStr)
+	}
+
+	// method negotiation
+	method := byte(socks5AuthNone)
+	req := []byte{socks5Version, 1, socks5AuthNone}
+	if u.User != nil {
+		method = socks5AuthPassword
+		req = []byte{socks5Version, 2, socks5AuthNone, socks5AuthPassword}
+	}
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+	var resp [2]byte
+	if _, err := io.ReadFull(conn, resp[:]); err != nil {
+		return err
+	}
+	if resp[0] != socks5Version {
+		return fmt.Errorf("unexpected SOCKS version %d", resp[0])
+	}
+	switch resp[1] {
+	case socks5AuthNone:
+	case method:
+		// username/password authentication, see RFC 1929
+		user := u.User.Username()
+		pass, _ := u.User.Password()
+		if len(user) > 255 || len(pass) > 255 {
+			return errors.New("SOCKS username or password too long")
+		}
+		req = append([]byte{1, byte(len(user))}, user...)
+		req = append(req, byte(len(pass)))
+		req = append(req, pass...)
+		if _, err := conn.Write(req); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(conn, resp[:]); err != nil {
+			return err
+		}
+		if resp[1] != 0 {
+			return errors.New("SOCKS authentication failed")
+		}
+	case socks5AuthNoAccepted:
+		return errors.New("no acceptable SOCKS authentication method")
+	default:
+		return fmt.Errorf("unsupported SOCKS authentication method %d", resp[1])
+	}
+
+	// connect
+	req = []byte{socks5Version, socks5CmdConnect, 0}
+	if ip := net.ParseIP(host); ip == nil {
+		if len(host) > 255 {
+			return errors.New("host name too long: " + host)
+		}
+		req = append(req, socks5AddrDomain, byte(len(host)))
+		req = append(req, host...)
+	} else if ip4 := ip.To4(); ip4 != nil {
+		req = append(req, socks5AddrIPv4)
+		req = append(req, ip4...)
+	} else {
+		req = append(req, socks5AddrIPv6)
+		req = append(req, ip...)
+	}
+	req = binary.BigEndian.AppendUint16(req, uint16(port))
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	// reply: version, reply code, reserved, bound address and port
+	var reply [4]byte
+	if _, err := io.ReadFull(conn, reply[:]); err != nil {
+		return err
+	}
+	if reply[1] != 0 {
+		if int(reply[1]) < len(socks5Replies) {
+			return errors.New(socks5Replies[reply[1]])
+		}
+		return fmt.Errorf("unknown SOCKS error %d", reply[1])
+	}
+	var n int
+	switch reply[3] {
+	case socks5AddrIPv4:
+		n = net.IPv4len
+	case socks5AddrIPv6:
+		n = net.IPv6len
+	case socks5AddrDomain:
+		if _, err := io.ReadFull(conn, reply[:1]); err != nil {
+			return err
+		}
+		n = int(reply[0])
+	default:
+		return fmt.Errorf("unknown SOCKS address type %d", reply[3])
+	}
+	_, err = io.ReadFull(conn, make([]byte, n+2))
+	return err
+}
+
+// httpConnect sends a CONNECT request for addr to the HTTP proxy at the other
+// end of conn. The response is read byte by byte, so that no data which the
+// server sends after it is consumed.
+func httpConnect(conn net.Conn, u *url.URL, addr string) error {
+	req := "CONNECT " + addr + " HTTP/1.1\r\nHost: " + addr + "\r\n"
+	if u.User != nil {
+		pass, _ := u.User.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(u.User.Username() + ":" + pass))
+		req += "Proxy-Authorization: Basic " + auth + "\r\n"
+	}
+	if _, err := io.WriteString(conn, req+"\r\n"); err != nil {
+		return err
+	}
+
+	var resp []byte
+	var b [1]byte
+	for !bytes.HasSuffix(resp, []byte("\r\n\r\n")) {
+		if len(resp) >= maxProxyResponseSize {
+			return errors.New("proxy response too large")
+		}
+		if _, err := io.ReadFull(conn, b[:]); err != nil {
+			return err
+		}
+		resp = append(resp, b[0])
+	}
+	status, _, _ := strings.Cut(string(resp), "\r\n")
+	_, code, _ := strings.Cut(status, " ")
+	if !strings.HasPrefix(code, "200") {
+		return errors.New("CONNECT failed: " + status)
+	}
+	return nil
+}
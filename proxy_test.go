@@ -0,0 +1,204 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// startProxy starts a listener which serves a single connection with handle.
+func startProxy(t *testing.T, handle func(conn net.Conn) error) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	errc := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			errc <- err
+			return
+		}
+		defer conn.Close()
+		errc <- handle(conn)
+	}()
+	t.Cleanup(func() {
+		if err := <-errc; err != nil {
+			t.Error("proxy:", err)
+		}
+	})
+	return ln.Addr().String()
+}
+
+// dialThroughProxy dials the server of dsn and returns the first bytes sent
+// by the "server".
+func dialThroughProxy(t *testing.T, dsn string) (string, error) {
+	t.Helper()
+	cfg, err := ParseDSN(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewConnector(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mc := &mysqlConn{cfg: c.(*connector).state.Load().cfg}
+	if err := c.(*connector).dial(context.Background(), mc); err != nil {
+		return "", err
+	}
+	defer mc.netConn.Close()
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(mc.netConn, buf)
+	return string(buf), err
+}
+
+func expectRead(conn net.Conn, want []byte) error {
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(conn, got); err != nil {
+		return err
+	}
+	if !bytes.Equal(got, want) {
+		return fmt.Errorf("unexpected request %q", got)
+	}
+	return nil
+}
+
+func TestSOCKS5Proxy(t *testing.T) {
+	addr := startProxy(t, func(conn net.Conn) error {
+		if err := expectRead(conn, []byte{5, 2, 0, 2}); err != nil {
+			return err
+		}
+		conn.Write([]byte{5, 2})
+		if err := expectRead(conn, []byte("\x01\x04user\x02pw")); err != nil {
+			return err
+		}
+		conn.Write([]byte{1, 0})
+		if err := expectRead(conn, []byte("\x05\x01\x00\x03\x0adb.example\x0c\xea")); err != nil {
+			return err
+		}
+		conn.Write([]byte{5, 0, 0, 1, 10, 0, 0, 1, 0x0c, 0xea})
+		_, err := conn.Write([]byte("hello"))
+		return err
+	})
+
+	got, err := dialThroughProxy(t, "tcp(db.example:3306)/?proxy="+url.QueryEscape("socks5://user:pw@"+addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "hello" {
+		t.Errorf("expected hello, got %q", got)
+	}
+}
+
+func TestSOCKS5ProxyRefused(t *testing.T) {
+	addr := startProxy(t, func(conn net.Conn) error {
+		if err := expectRead(conn, []byte{5, 1, 0}); err != nil {
+			return err
+		}
+		conn.Write([]byte{5, 0})
+		if err := expectRead(conn, []byte{5, 1, 0, 1, 10, 0, 0, 1, 0x0c, 0xea}); err != nil {
+			return err
+		}
+		_, err := conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		return err
+	})
+
+	_, err := dialThroughProxy(t, "tcp(10.0.0.1:3306)/?proxy="+url.QueryEscape("socks5://"+addr))
+	if err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("expected connection refused, got %v", err)
+	}
+}
+
+func TestHTTPProxy(t *testing.T) {
+	addr := startProxy(t, func(conn net.Conn) error {
+		var req []byte
+		b := make([]byte, 1)
+		for !bytes.HasSuffix(req, []byte("\r\n\r\n")) {
+			if _, err := conn.Read(b); err != nil {
+				return err
+			}
+			req = append(req, b[0])
+		}
+		want := "CONNECT db.example:3306 HTTP/1.1\r\nHost: db.example:3306\r\nProxy-Authorization: Basic dXNlcjpwdw==\r\n\r\n"
+		if string(req) != want {
+			return fmt.Errorf("unexpected request %q", req)
+		}
+		// the server greeting follows the response immediately
+		_, err := conn.Write([]byte("HTTP/1.1 200 Connection established\r\nVia: test\r\n\r\nhello"))
+		return err
+	})
+
+	got, err := dialThroughProxy(t, "tcp(db.example:3306)/?proxy="+url.QueryEscape("http://user:pw@"+addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "hello" {
+		t.Errorf("expected hello, got %q", got)
+	}
+
+	addr = startProxy(t, func(conn net.Conn) error {
+		_, err := conn.Write([]byte("HTTP/1.1 407 Proxy Authentication Required\r\n\r\n"))
+		return err
+	})
+	if _, err := dialThroughProxy(t, "tcp(db.example:3306)/?proxy="+url.QueryEscape("http://"+addr)); err == nil || !strings.Contains(err.Error(), "407") {
+		t.Errorf("expected error 407, got %v", err)
+	}
+}
+
+func TestProxyFromEnv(t *testing.T) {
+	t.Setenv("ALL_PROXY", "socks5://proxy.example")
+	t.Setenv("NO_PROXY", "localhost,.internal,10.0.0.0/8,db.example:3307")
+
+	for addr, want := range map[string]bool{
+		"db.example:3306":          true,
+		"db.example:3307":          false,
+		"localhost:3306":           false,
+		"mysql.corp.internal:3306": false,
+		"10.1.2.3:3306":            false,
+		"192.168.0.1:3306":         true,
+	} {
+		cfg := NewConfig()
+		cfg.Addr = addr
+		cfg.Apply(ProxyFromEnvironment(true))
+		if err := cfg.normalize(); err != nil {
+			t.Fatal(err)
+		}
+		u, err := cfg.proxyURL()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := u != nil; got != want {
+			t.Errorf("%s: expected proxy %v, got %v", addr, want, u)
+		}
+		if u != nil && proxyAddr(u) != "proxy.example:1080" {
+			t.Errorf("unexpected proxy address %s", proxyAddr(u))
+		}
+	}
+
+	// unix sockets are never proxied
+	cfg := NewConfig()
+	cfg.Net, cfg.Addr = "unix", "/tmp/mysql.sock"
+	cfg.Apply(ProxyFromEnvironment(true))
+	if u, err := cfg.proxyURL(); u != nil || err != nil {
+		t.Errorf("expected no proxy, got %v, %v", u, err)
+	}
+
+	if err := Proxy("ftp://proxy.example")(NewConfig()); err == nil {
+		t.Error("expected error for unsupported scheme")
+	}
+}
@@ -0,0 +1,109 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strconv"
+	"time"
+)
+
+// Versions of the PROXY protocol, see Config.ProxyHeader.
+const (
+	proxyHeaderV1 = "v1"
+	proxyHeaderV2 = "v2"
+)
+
+// proxyV2Signature starts every header of version 2 of the PROXY protocol.
+const proxyV2Signature = "\r\n\r\n\x00\r\nQUIT\n"
+
+// writeProxyHeader writes the PROXY protocol header of the given version to
+// conn, with the local address of conn as source and the remote address as
+// destination. The write is aborted when ctx is done.
+func writeProxyHeader(ctx context.Context, conn net.Conn, version string) error {
+	var hdr []byte
+	if version == proxyHeaderV1 {
+		hdr = appendProxyHeaderV1(nil, conn.LocalAddr(), conn.RemoteAddr())
+	} else {
+		hdr = appendProxyHeaderV2(nil, conn.LocalAddr(), conn.RemoteAddr())
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetWriteDeadline(deadline); err != nil {
+			return err
+		}
+		defer conn.SetWriteDeadline(time.Time{})
+	}
+	_, err := conn.Write(hdr)
+	return err
+}
+
+// proxyAddrs returns the IPs and ports of src and dst if both are TCP
+// addresses of the same family. ipv4 reports whether it is IPv4.
+func proxyAddrs(src, dst net.Addr) (srcAddr, dstAddr *net.TCPAddr, ipv4, ok bool) {
+	srcAddr, ok1 := src.(*net.TCPAddr)
+	dstAddr, ok2 := dst.(*net.TCPAddr)
+	if !ok1 || !ok2 {
+		return nil, nil, false, false
+	}
+	ipv4 = srcAddr.IP.To4() != nil
+	if ipv4 != (dstAddr.IP.To4() != nil) {
+		return nil, nil, false, false
+	}
+	return srcAddr, dstAddr, ipv4, true
+}
+
+// appendProxyHeaderV1 appends the human-readable header of version 1 of the
+// PROXY protocol to buf.
+func appendProxyHeaderV1(buf []byte, src, dst net.Addr) []byte {
+	srcAddr, dstAddr, ipv4, ok := proxyAddrs(src, dst)
+	if !ok {
+		return append(buf, "PROXY UNKNOWN\r\n"...)
+	}
+	if ipv4 {
+		buf = append(buf, "PROXY TCP4 "...)
+		buf = append(buf, srcAddr.IP.To4().String()...)
+		buf = append(buf, ' ')
+		buf = append(buf, dstAddr.IP.To4().String()...)
+	} else {
+		buf = append(buf, "PROXY TCP6 "...)
+		buf = append(buf, srcAddr.IP.String()...)
+		buf = append(buf, ' ')
+		buf = append(buf, dstAddr.IP.String()...)
+	}
+	buf = append(buf, ' ')
+	buf = strconv.AppendInt(buf, int64(srcAddr.Port), 10)
+	buf = append(buf, ' ')
+	buf = strconv.AppendInt(buf, int64(dstAddr.Port), 10)
+	return append(buf, "\r\n"...)
+}
+
+// appendProxyHeaderV2 appends the binary header of version 2 of the PROXY
+// protocol to buf.
+func appendProxyHeaderV2(buf []byte, src, dst net.Addr) []byte {
+	buf = append(buf, proxyV2Signature...)
+	buf = append(buf, 0x21) // version 2, command PROXY
+
+	srcAddr, dstAddr, ipv4, ok := proxyAddrs(src, dst)
+	switch {
+	case !ok:
+		return append(buf, 0x00, 0, 0) // UNSPEC, no addresses
+	case ipv4:
+		buf = append(buf, 0x11, 0, 12) // TCP over IPv4
+		buf = append(buf, srcAddr.IP.To4()...)
+		buf = append(buf, dstAddr.IP.To4()...)
+	default:
+		buf = append(buf, 0x21, 0, 36) // TCP over IPv6
+		buf = append(buf, srcAddr.IP.To16()...)
+		buf = append(buf, dstAddr.IP.To16()...)
+	}
+	buf = binary.BigEndian.AppendUint16(buf, uint16(srcAddr.Port))
+	return binary.BigEndian.AppendUint16(buf, uint16(dstAddr.Port))
+}
@@ -0,0 +1,96 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestProxyHeader(t *testing.T) {
+	src4 := &net.TCPAddr{IP: net.IPv4(192, 168, 0, 1), Port: 56324}
+	dst4 := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 3306}
+	src6 := &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 56324}
+	dst6 := &net.TCPAddr{IP: net.ParseIP("2001:db8::2"), Port: 3306}
+	unix := &net.UnixAddr{Name: "/tmp/mysql.sock", Net: "unix"}
+
+	v1Tests := []struct {
+		src, dst net.Addr
+		want     string
+	}{
+		{src4, dst4, "PROXY TCP4 192.168.0.1 10.0.0.2 56324 3306\r\n"},
+		{src6, dst6, "PROXY TCP6 2001:db8::1 2001:db8::2 56324 3306\r\n"},
+		{src4, dst6, "PROXY UNKNOWN\r\n"},
+		{unix, unix, "PROXY UNKNOWN\r\n"},
+	}
+	for _, tt := range v1Tests {
+		if got := string(appendProxyHeaderV1(nil, tt.src, tt.dst)); got != tt.want {
+			t.Errorf("v1 %v -> %v: expected %q, got %q", tt.src, tt.dst, tt.want, got)
+		}
+	}
+
+	want := []byte(proxyV2Signature + "\x21\x11\x00\x0c\xc0\xa8\x00\x01\x0a\x00\x00\x02\xdc\x04\x0c\xea")
+	if got := appendProxyHeaderV2(nil, src4, dst4); !bytes.Equal(got, want) {
+		t.Errorf("v2 IPv4: expected %x, got %x", want, got)
+	}
+	if got := appendProxyHeaderV2(nil, src6, dst6); len(got) != 16+36 || got[13] != 0x21 || !bytes.Equal(got[16:32], src6.IP) {
+		t.Errorf("v2 IPv6: unexpected header %x", got)
+	}
+	want = []byte(proxyV2Signature + "\x21\x00\x00\x00")
+	if got := appendProxyHeaderV2(nil, unix, unix); !bytes.Equal(got, want) {
+		t.Errorf("v2 unix: expected %x, got %x", want, got)
+	}
+
+	cfg := NewConfig()
+	cfg.ProxyHeader = "v3"
+	if err := cfg.normalize(); err == nil {
+		t.Error("expected error for invalid proxyHeader")
+	}
+}
+
+func TestDialWritesProxyHeader(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			received <- err.Error()
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, 128)
+		n, _ := io.ReadAtLeast(conn, buf, len("PROXY TCP4 "))
+		received <- string(buf[:n])
+	}()
+
+	cfg := NewConfig()
+	cfg.Addr = ln.Addr().String()
+	cfg.ProxyHeader = proxyHeaderV1
+	c, err := NewConnector(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mc := &mysqlConn{cfg: c.(*connector).state.Load().cfg}
+	if err := c.(*connector).dial(context.Background(), mc); err != nil {
+		t.Fatal(err)
+	}
+	defer mc.netConn.Close()
+
+	want := string(appendProxyHeaderV1(nil, mc.netConn.LocalAddr(), mc.netConn.RemoteAddr()))
+	if got := <-received; got != want {
+		t.Errorf("expected header %q, got %q", want, got)
+	}
+}
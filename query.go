@@ -0,0 +1,154 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+// skipSpace returns the index of the first non-whitespace byte in s, starting
+// at i.
+func skipSpace(s string, i int) int {
+	for i < len(s) && isSpace(s[i]) {
+		i++
+	}
+	return i
+}
+
+// hasKeywordPrefix reports whether the statement starts with the given
+// keyword (case-insensitive), ignoring leading whitespace. It returns the
+// index after the keyword.
+func hasKeywordPrefix(query, keyword string) (int, bool) {
+	i := skipSpace(query, 0)
+	j := i + len(keyword)
+	if j > len(query) || !strings.EqualFold(query[i:j], keyword) {
+		return 0, false
+	}
+	if j < len(query) && !isSpace(query[j]) && query[j] != '/' && query[j] != '(' {
+		return 0, false
+	}
+	return j, true
+}
+
+// nextPlaceholder returns the index of the first ? placeholder in query at or
+// after i, or -1 if there is none. Question marks in string literals, quoted
+// identifiers and comments are skipped, except in /*! */ comments, which are
+// executed by the server. i must not be inside of a literal or comment.
+// Backslashes escape quotes in string literals unless noBackslashEscapes is
+// set (SQL mode NO_BACKSLASH_ESCAPES).
+func nextPlaceholder(query string, i int, noBackslashEscapes bool) int {
+	return indexUnquoted(query, i, "?", noBackslashEscapes)
+}
+
+// indexUnquoted returns the index of the first byte of chars in query at or
+// after i which is not in a string literal, quoted identifier or comment, or
+// -1 if there is none, see nextPlaceholder.
+func indexUnquoted(query string, i int, chars string, noBackslashEscapes bool) int {
+	for ; i < len(query); i++ {
+		if strings.IndexByte(chars, query[i]) >= 0 {
+			return i
+		}
+		switch c := query[i]; c {
+		case '\'', '"', '`':
+			escapes := c != '`' && !noBackslashEscapes
+			for i++; i < len(query) && query[i] != c; i++ {
+				if query[i] == '\\' && escapes {
+					i++
+				}
+			}
+		case '#':
+			i = skipLine(query, i)
+		case '-':
+			// "-- " comments require whitespace or a control character
+			if i+1 < len(query) && query[i+1] == '-' && (i+2 == len(query) || query[i+2] <= ' ') {
+				i = skipLine(query, i)
+			}
+		case '/':
+			if strings.HasPrefix(query[i:], "/*") && !strings.HasPrefix(query[i:], "/*!") {
+				end := strings.Index(query[i+2:], "*/")
+				if end < 0 {
+					return -1
+				}
+				i += 2 + end + 1
+			}
+		}
+	}
+	return -1
+}
+
+// skipLine returns the index of the end of the line in s which contains i.
+func skipLine(s string, i int) int {
+	if end := strings.IndexByte(s[i:], '\n'); end >= 0 {
+		return i + end
+	}
+	return len(s)
+}
+
+// countPlaceholders returns the number of ? placeholders in query, see
+// nextPlaceholder.
+func countPlaceholders(query string, noBackslashEscapes bool) int {
+	n := 0
+	for i := nextPlaceholder(query, 0, noBackslashEscapes); i >= 0; i = nextPlaceholder(query, i+1, noBackslashEscapes) {
+		n++
+	}
+	return n
+}
+
+// addStatementTime prefixes a SELECT statement with SET STATEMENT
+// max_statement_time=<seconds> FOR, limiting it to d, rounded up to
+// milliseconds. Statements other than SELECT are returned unchanged.
+func addStatementTime(query string, d time.Duration) string {
+	if _, ok := hasKeywordPrefix(query, "SELECT"); !ok {
+		return query
+	}
+	ms := (d + time.Millisecond - 1) / time.Millisecond
+	return fmt.Sprintf("SET STATEMENT max_statement_time=%d.%03d FOR %s", ms/1000, ms%1000, query)
+}
+
+// rewriteQuery returns the statement rewritten by Config.QueryRewriter, if
+// set, with the context of the current operation.
+func (mc *mysqlConn) rewriteQuery(query string) string {
+	if mc.cfg.QueryRewriter == nil {
+		return query
+	}
+	return mc.cfg.QueryRewriter(mc.context(), query)
+}
+
+// addQueryHints adds optimizer hints to a SELECT statement. Statements other
+// than SELECT are returned unchanged. If the statement already contains an
+// optimizer hint comment directly after the SELECT keyword, the hints are
+// appended to it.
+func addQueryHints(query string, hints []string) string {
+	if len(hints) == 0 {
+		return query
+	}
+	pos, ok := hasKeywordPrefix(query, "SELECT")
+	if !ok {
+		return query
+	}
+	hint := strings.Join(hints, " ")
+
+	// merge into an existing /*+ ... */ comment
+	if i := skipSpace(query, pos); strings.HasPrefix(query[i:], "/*+") {
+		if end := strings.Index(query[i+3:], "*/"); end >= 0 {
+			end += i + 3
+			if !isSpace(query[end-1]) {
+				hint = " " + hint
+			}
+			return query[:end] + hint + " " + query[end:]
+		}
+	}
+	return query[:pos] + " /*+ " + hint + " */" + query[pos:]
+}
@@ -0,0 +1,202 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddQueryHints(t *testing.T) {
+	hints := []string{"MAX_EXECUTION_TIME(1000)", "NO_ICP(t1)"}
+	for _, tc := range []struct {
+		query, expected string
+	}{
+		{"SELECT 1", "SELECT /*+ MAX_EXECUTION_TIME(1000) NO_ICP(t1) */ 1"},
+		{"  select * FROM t1", "  select /*+ MAX_EXECUTION_TIME(1000) NO_ICP(t1) */ * FROM t1"},
+		{"SELECT(1)", "SELECT /*+ MAX_EXECUTION_TIME(1000) NO_ICP(t1) */(1)"},
+		{"SELECT /*+ BKA(t1) */ * FROM t1", "SELECT /*+ BKA(t1) MAX_EXECUTION_TIME(1000) NO_ICP(t1) */ * FROM t1"},
+		{"SELECT /*+BKA(t1)*/ * FROM t1", "SELECT /*+BKA(t1) MAX_EXECUTION_TIME(1000) NO_ICP(t1) */ * FROM t1"},
+		{"SELECTED", "SELECTED"},
+		{"INSERT INTO t1 SELECT * FROM t2", "INSERT INTO t1 SELECT * FROM t2"},
+		{"UPDATE t1 SET a = 1", "UPDATE t1 SET a = 1"},
+		{"", ""},
+	} {
+		if actual := addQueryHints(tc.query, hints); actual != tc.expected {
+			t.Errorf("addQueryHints(%q):\nexpected %q\ngot      %q", tc.query, tc.expected, actual)
+		}
+	}
+
+	if actual := addQueryHints("SELECT 1", nil); actual != "SELECT 1" {
+		t.Errorf("expected query to be unchanged without hints, got %q", actual)
+	}
+}
+
+func TestAddStatementTime(t *testing.T) {
+	for _, tc := range []struct {
+		query    string
+		d        time.Duration
+		expected string
+	}{
+		{"SELECT 1", 1500 * time.Millisecond, "SET STATEMENT max_statement_time=1.500 FOR SELECT 1"},
+		{"select * FROM t1", 10 * time.Microsecond, "SET STATEMENT max_statement_time=0.001 FOR select * FROM t1"},
+		{"SELECT 1", 2 * time.Minute, "SET STATEMENT max_statement_time=120.000 FOR SELECT 1"},
+		{"UPDATE t1 SET a = 1", time.Second, "UPDATE t1 SET a = 1"},
+	} {
+		if actual := addStatementTime(tc.query, tc.d); actual != tc.expected {
+			t.Errorf("addStatementTime(%q, %v):\nexpected %q\ngot      %q", tc.query, tc.d, tc.expected, actual)
+		}
+	}
+}
+
+func TestPropagateDeadline(t *testing.T) {
+	query := func(version string, propagate bool, ctx context.Context) string {
+		conn, mc := newRWMockConn(0)
+		mc.info.ServerVersion = version
+		mc.cfg.propagateDeadline = propagate
+		conn.queuedReplies = [][]byte{mockPacket(1, []byte{iOK, 0, 0, 2, 0, 0, 0})}
+		rows, err := mc.QueryContext(ctx, "SELECT 1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows.Close()
+		return string(conn.written[5:])
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if q := query("5.5.5-10.11.6-MariaDB", true, ctx); !strings.HasPrefix(q, "SET STATEMENT max_statement_time=") || !strings.HasSuffix(q, " FOR SELECT 1") {
+		t.Errorf("unexpected query %q", q)
+	}
+	for _, tc := range []struct {
+		version   string
+		propagate bool
+		ctx       context.Context
+	}{
+		{"5.5.5-10.11.6-MariaDB", false, ctx},
+		{"8.0.36", true, ctx},
+		{"10.0.38-MariaDB", true, ctx},
+		{"11.4.2-MariaDB", true, context.Background()},
+	} {
+		if q := query(tc.version, tc.propagate, tc.ctx); q != "SELECT 1" {
+			t.Errorf("%s: unexpected query %q", tc.version, q)
+		}
+	}
+}
+
+func TestQueryRewriter(t *testing.T) {
+	type traceKey struct{}
+	ctx := context.WithValue(context.Background(), traceKey{}, "00-4bf92f3577b34da6-01")
+	rewriter := func(ctx context.Context, query string) string {
+		if id, ok := ctx.Value(traceKey{}).(string); ok {
+			return query + " /*traceparent='" + id + "'*/"
+		}
+		return query
+	}
+	expected := " /*traceparent='00-4bf92f3577b34da6-01'*/"
+
+	for _, tc := range []struct {
+		name  string
+		reply []byte
+		run   func(mc *mysqlConn) error
+	}{
+		{"Query", []byte{iOK, 0, 0, 2, 0, 0, 0}, func(mc *mysqlConn) error {
+			rows, err := mc.QueryContext(ctx, "SELECT 1", nil)
+			if err == nil {
+				rows.Close()
+			}
+			return err
+		}},
+		{"Exec", []byte{iOK, 1, 0, 2, 0, 0, 0}, func(mc *mysqlConn) error {
+			_, err := mc.ExecContext(ctx, "SELECT 1", nil)
+			return err
+		}},
+		{"Prepare", []byte{iOK, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, func(mc *mysqlConn) error {
+			_, err := mc.PrepareContext(ctx, "SELECT 1")
+			return err
+		}},
+	} {
+		conn, mc := newRWMockConn(0)
+		mc.cfg.QueryRewriter = rewriter
+		conn.queuedReplies = [][]byte{mockPacket(1, tc.reply)}
+		if err := tc.run(mc); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if q := string(conn.written[5:]); q != "SELECT 1"+expected {
+			t.Errorf("%s: unexpected query %q", tc.name, q)
+		}
+	}
+}
+
+func TestQueryRewriterCaches(t *testing.T) {
+	rewriter := func(ctx context.Context, query string) string {
+		return "/* comment */ " + query
+	}
+
+	// cached statements would keep the rewritten text of the first operation
+	conn, mc := newRWMockConn(0)
+	mc.cfg.QueryRewriter = rewriter
+	mc.cfg.stmtCacheSize = 10
+	mc.cfg.prewarmStmts = []string{"SELECT ?"}
+	if err := mc.prewarmStatements(); err != nil {
+		t.Fatal(err)
+	}
+	if conn.writes != 0 || mc.stmtCache != nil {
+		t.Errorf("statements prepared in advance with a rewriter")
+	}
+	if _, err := mc.ExecContext(context.Background(), "SELECT ?", []driver.NamedValue{{Ordinal: 1, Value: int64(1)}}); err != driver.ErrSkip {
+		t.Errorf("expected driver.ErrSkip, got %v", err)
+	}
+
+	// coalescing is decided on the statement before it is rewritten
+	conn, mc = newRWMockConn(0)
+	mc.cfg.QueryRewriter = rewriter
+	mc.cfg.coalesceWrites = time.Hour
+	mc.status |= statusInTrans
+	res, err := mc.Exec("INSERT INTO t VALUES (1)", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := res.(*coalescedResult); !ok {
+		t.Errorf("expected the insert to be coalesced, got %T", res)
+	}
+	if q := string(mc.coalescer.buf[5:]); q != "/* comment */ INSERT INTO t VALUES (1)" {
+		t.Errorf("unexpected query %q", q)
+	}
+}
+
+func TestNextPlaceholder(t *testing.T) {
+	for _, tc := range []struct {
+		query              string
+		noBackslashEscapes bool
+		expected           int
+	}{
+		{"SELECT ?, ?", false, 2},
+		{"SELECT '?', \"?\", `?`, ?", false, 1},
+		{"SELECT 'it''s?', ?", false, 1},
+		{`SELECT 'a\'?', ?`, false, 1},
+		{`SELECT 'a\', ?, '?'`, true, 1},
+		{"SELECT ? -- ?\n, ?", false, 2},
+		{"SELECT ? --?", false, 2},
+		{"SELECT ? # ?\n, ?", false, 2},
+		{"SELECT /* ? */ ?", false, 1},
+		{"SELECT /*+ MAX_EXECUTION_TIME(?) */ ?", false, 1},
+		{"SELECT /*!50700 ? */ ?", false, 2},
+		{"SELECT ? /* ?", false, 1},
+		{"SELECT ?-?", false, 2},
+		{"SELECT 'unterminated ?", false, 0},
+	} {
+		if n := countPlaceholders(tc.query, tc.noBackslashEscapes); n != tc.expected {
+			t.Errorf("%q: expected %d placeholders, got %d", tc.query, tc.expected, n)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// ErrQueryAttrs is returned for statements with query attributes if they can
+// not be sent, see WithQueryAttrs.
+var ErrQueryAttrs = errors.New("query attributes are not enabled with queryAttributes=true or not supported by the server")
+
+// PARAMETER_COUNT_AVAILABLE flag of COM_STMT_EXECUTE
+const parameterCountAvailable = 0x08
+
+type queryAttr struct {
+	name  string
+	value string
+}
+
+type queryAttrsKey struct{}
+
+// WithQueryAttrs returns a copy of ctx with query attributes which are sent
+// with the statements executed with the context, e.g. trace IDs. They are
+// available to the server via mysql_query_attribute_string() and to audit
+// plugins. Attributes of ctx with the same name are replaced.
+//
+// Sending query attributes requires the queryAttributes DSN parameter and
+// MySQL 8.0.23 or later. Otherwise ErrQueryAttrs is returned.
+func WithQueryAttrs(ctx context.Context, attrs map[string]string) context.Context {
+	parent, _ := ctx.Value(queryAttrsKey{}).([]queryAttr)
+	merged := make([]queryAttr, 0, len(parent)+len(attrs))
+	for _, a := range parent {
+		if _, ok := attrs[a.name]; !ok {
+			merged = append(merged, a)
+		}
+	}
+	for name, value := range attrs {
+		merged = append(merged, queryAttr{name, value})
+	}
+	slices.SortFunc(merged, func(a, b queryAttr) int {
+		return strings.Compare(a.name, b.name)
+	})
+	return context.WithValue(ctx, queryAttrsKey{}, merged)
+}
+
+// setQueryAttrs sets the query attributes of ctx for the next statement.
+func (mc *mysqlConn) setQueryAttrs(ctx context.Context) error {
+	attrs, _ := ctx.Value(queryAttrsKey{}).([]queryAttr)
+	if len(attrs) > 0 && !mc.sendsQueryAttrs() {
+		return ErrQueryAttrs
+	}
+	mc.queryAttrs = attrs
+	return nil
+}
+
+func (mc *mysqlConn) clearQueryAttrs() {
+	mc.queryAttrs = nil
+}
+
+// sendsQueryAttrs reports whether CLIENT_QUERY_ATTRIBUTES was negotiated.
+// COM_QUERY and COM_STMT_EXECUTE contain query attributes then.
+func (mc *mysqlConn) sendsQueryAttrs() bool {
+	return (mc.cfg.queryAttributes || mc.cfg.queryAttributeParams) && mc.flags&clientQueryAttributes != 0
+}
+
+// appendQueryAttrs appends the query attributes preceding the query of
+// COM_QUERY.
+func appendQueryAttrs(b []byte, attrs []queryAttr) []byte {
+	// parameter_count [lenenc int]
+	b = appendLengthEncodedInteger(b, uint64(len(attrs)))
+	// parameter_set_count [lenenc int], always 1
+	b = append(b, 0x01)
+	if len(attrs) == 0 {
+		return b
+	}
+
+	// NULL-bitmap [(parameter_count + 7) / 8 bytes]
+	for i := 0; i < (len(attrs)+7)/8; i++ {
+		b = append(b, 0x00)
+	}
+	// new_params_bind_flag [1 byte]
+	b = append(b, 0x01)
+	b = appendQueryAttrTypes(b, attrs)
+	return appendQueryAttrValues(b, attrs)
+}
+
+// appendQueryAttrTypes appends the type and name of each attribute.
+func appendQueryAttrTypes(b []byte, attrs []queryAttr) []byte {
+	for _, a := range attrs {
+		b = append(b, byte(fieldTypeString), 0x00)
+		b = appendLengthEncodedString(b, a.name)
+	}
+	return b
+}
+
+// queryAttrTypesLen returns the length of appendQueryAttrTypes(nil, attrs).
+func queryAttrTypesLen(attrs []queryAttr) int {
+	var lei [9]byte
+	n := 0
+	for _, a := range attrs {
+		n += 2 + len(appendLengthEncodedInteger(lei[:0], uint64(len(a.name)))) + len(a.name)
+	}
+	return n
+}
+
+// appendQueryAttrValues appends the value of each attribute.
+func appendQueryAttrValues(b []byte, attrs []queryAttr) []byte {
+	for _, a := range attrs {
+		b = appendLengthEncodedString(b, a.value)
+	}
+	return b
+}
@@ -0,0 +1,124 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestWithQueryAttrs(t *testing.T) {
+	ctx := WithQueryAttrs(context.Background(), map[string]string{"trace": "1", "span": "a"})
+	ctx = WithQueryAttrs(ctx, map[string]string{"trace": "2", "app": "x"})
+
+	expected := []queryAttr{{"app", "x"}, {"span", "a"}, {"trace", "2"}}
+	if attrs := ctx.Value(queryAttrsKey{}).([]queryAttr); !reflect.DeepEqual(attrs, expected) {
+		t.Errorf("expected %v, got %v", expected, attrs)
+	}
+}
+
+func newQueryAttrsMockConn() (*mockConn, *mysqlConn) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg = mc.cfg.Clone()
+	mc.cfg.queryAttributes = true
+	mc.flags |= clientQueryAttributes
+	return conn, mc
+}
+
+func TestQueryAttrsComQuery(t *testing.T) {
+	conn, mc := newQueryAttrsMockConn()
+	mc.queryAttrs = []queryAttr{{"trace", "abc"}}
+	if err := mc.writeCommandPacketStr(comQuery, "DO 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{
+		comQuery,
+		1,    // parameter_count
+		1,    // parameter_set_count
+		0,    // NULL-bitmap
+		1,    // new_params_bind_flag
+		0xfe, // MYSQL_TYPE_STRING
+		0, 5, 't', 'r', 'a', 'c', 'e',
+		3, 'a', 'b', 'c',
+		'D', 'O', ' ', '1',
+	}
+	if !bytes.Equal(conn.written[4:], expected) {
+		t.Errorf("expected %v, got %v", expected, conn.written[4:])
+	}
+
+	// without attributes
+	conn.written = nil
+	mc.queryAttrs = nil
+	if err := mc.writeCommandPacketStr(comQuery, "DO 1"); err != nil {
+		t.Fatal(err)
+	}
+	expected = []byte{comQuery, 0, 1, 'D', 'O', ' ', '1'}
+	if !bytes.Equal(conn.written[4:], expected) {
+		t.Errorf("expected %v, got %v", expected, conn.written[4:])
+	}
+}
+
+func TestQueryAttrsStmtExecute(t *testing.T) {
+	conn, mc := newQueryAttrsMockConn()
+	mc.queryAttrs = []queryAttr{{"t", "v"}}
+	stmt := &mysqlStmt{mc: mc, id: 1, paramCount: 1}
+	if err := stmt.writeExecutePacket([]driver.Value{int64(42)}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{
+		comStmtExecute,
+		1, 0, 0, 0, // statement_id
+		0,          // flags
+		1, 0, 0, 0, // iteration_count
+		2, // parameter_count
+		0, // NULL-bitmap
+		1, // new_params_bind_flag
+		byte(fieldTypeLongLong), 0, 0,
+		0xfe, 0, 1, 't',
+		42, 0, 0, 0, 0, 0, 0, 0,
+		1, 'v',
+	}
+	if !bytes.Equal(conn.written[4:], expected) {
+		t.Errorf("expected %v, got %v", expected, conn.written[4:])
+	}
+
+	// statement without parameters
+	conn.written = nil
+	stmt.paramCount = 0
+	if err := stmt.writeExecutePacket(nil); err != nil {
+		t.Fatal(err)
+	}
+	expected = []byte{
+		comStmtExecute,
+		1, 0, 0, 0, // statement_id
+		parameterCountAvailable,
+		1, 0, 0, 0, // iteration_count
+		1, // parameter_count
+		0, // NULL-bitmap
+		1, // new_params_bind_flag
+		0xfe, 0, 1, 't',
+		1, 'v',
+	}
+	if !bytes.Equal(conn.written[4:], expected) {
+		t.Errorf("expected %v, got %v", expected, conn.written[4:])
+	}
+}
+
+func TestQueryAttrsNotEnabled(t *testing.T) {
+	_, mc := newRWMockConn(0)
+	ctx := WithQueryAttrs(context.Background(), map[string]string{"trace": "1"})
+	if _, err := mc.QueryContext(ctx, "SELECT 1", nil); err != ErrQueryAttrs {
+		t.Errorf("expected ErrQueryAttrs, got %v", err)
+	}
+}
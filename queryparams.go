@@ -0,0 +1,110 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// attrParamPrefix is the name prefix of the query attributes carrying the
+// parameters with queryAttributeParams=true.
+const attrParamPrefix = "_param"
+
+// bindsQueryAttrParams reports whether the parameters of the next query are
+// sent as query attributes.
+func (mc *mysqlConn) bindsQueryAttrParams() bool {
+	return mc.cfg.queryAttributeParams && mc.interpolation == nil && mc.sendsQueryAttrs()
+}
+
+// bindQueryAttrParams returns query with the placeholders replaced by calls of
+// mysql_query_attribute_string() and the query attributes of the statement
+// extended by args. NULL and numbers are inserted as literals, since they can
+// not be used for injection and keep their type, e.g. for LIMIT.
+// driver.ErrSkip is returned if an arg can not be sent this way, e.g. binary
+// data, which mysql_query_attribute_string() would convert to a string.
+func (mc *mysqlConn) bindQueryAttrParams(query string, args []driver.Value) (string, []queryAttr, error) {
+	// Number of ? should be same to len(args)
+	noBackslashEscapes := mc.status&statusNoBackslashEscapes != 0
+	if countPlaceholders(query, noBackslashEscapes) != len(args) {
+		return "", nil, driver.ErrSkip
+	}
+
+	attrs := make([]queryAttr, len(mc.queryAttrs), len(mc.queryAttrs)+len(args))
+	copy(attrs, mc.queryAttrs)
+	buf := make([]byte, 0, len(query)+len(args)*48)
+	for i, arg := range args {
+		q := nextPlaceholder(query, 0, noBackslashEscapes)
+		buf = append(buf, query[:q]...)
+		query = query[q+1:]
+
+		var value string
+		switch v := mc.zeroDateArg(arg).(type) {
+		case nil:
+			buf = append(buf, "NULL"...)
+			continue
+		case int64:
+			buf = strconv.AppendInt(buf, v, 10)
+			continue
+		case uint64:
+			buf = strconv.AppendUint(buf, v, 10)
+			continue
+		case float64:
+			buf = strconv.AppendFloat(buf, v, 'g', -1, 64)
+			continue
+		case float32:
+			buf = strconv.AppendFloat(buf, float64(v), 'g', -1, 32)
+			continue
+		case Decimal:
+			buf = append(buf, v.String()...)
+			continue
+		case bool:
+			if v {
+				buf = append(buf, '1')
+			} else {
+				buf = append(buf, '0')
+			}
+			continue
+		case time.Time:
+			if v.IsZero() {
+				value = "0000-00-00"
+			} else {
+				b, err := appendDateTime(nil, v.In(mc.cfg.Loc), mc.cfg.timeTruncate)
+				if err != nil {
+					return "", nil, err
+				}
+				value = string(b)
+			}
+		case time.Duration:
+			value = string(appendTimeDuration(nil, v))
+		case json.RawMessage:
+			value = string(v)
+		case []byte:
+			if v == nil {
+				buf = append(buf, "NULL"...)
+				continue
+			}
+			return "", nil, driver.ErrSkip
+		case string:
+			value = v
+		default:
+			return "", nil, driver.ErrSkip
+		}
+
+		name := attrParamPrefix + strconv.Itoa(i+1)
+		buf = append(buf, "mysql_query_attribute_string('"...)
+		buf = append(buf, name...)
+		buf = append(buf, "')"...)
+		attrs = append(attrs, queryAttr{name, value})
+	}
+	buf = append(buf, query...)
+	return string(buf), attrs, nil
+}
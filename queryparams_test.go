@@ -0,0 +1,90 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBindQueryAttrParams(t *testing.T) {
+	_, mc := newRWMockConn(0)
+	mc.queryAttrs = []queryAttr{{"trace", "abc"}}
+	query, attrs, err := mc.bindQueryAttrParams("SELECT * FROM t WHERE a = ? AND b IN (?, ?) AND c = ? LIMIT ?", []driver.Value{
+		"x' OR 1=1 --",
+		nil,
+		true,
+		time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		int64(10),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT * FROM t WHERE a = mysql_query_attribute_string('_param1') AND b IN (NULL, 1) AND c = mysql_query_attribute_string('_param4') LIMIT 10"
+	if query != want {
+		t.Errorf("expected %q, got %q", want, query)
+	}
+	wantAttrs := []queryAttr{{"trace", "abc"}, {"_param1", "x' OR 1=1 --"}, {"_param4", "2026-01-02 03:04:05"}}
+	if !reflect.DeepEqual(attrs, wantAttrs) {
+		t.Errorf("expected %v, got %v", wantAttrs, attrs)
+	}
+	if len(mc.queryAttrs) != 1 {
+		t.Errorf("query attributes of the statement were modified: %v", mc.queryAttrs)
+	}
+
+	if _, _, err := mc.bindQueryAttrParams("SELECT ?", nil); err != driver.ErrSkip {
+		t.Errorf("expected driver.ErrSkip, got %v", err)
+	}
+	if _, _, err := mc.bindQueryAttrParams("SELECT ?", []driver.Value{struct{}{}}); err != driver.ErrSkip {
+		t.Errorf("expected driver.ErrSkip, got %v", err)
+	}
+	if _, _, err := mc.bindQueryAttrParams("SELECT ?", []driver.Value{[]byte{0xff, 0}}); err != driver.ErrSkip {
+		t.Errorf("expected driver.ErrSkip for []byte, got %v", err)
+	}
+}
+
+func TestQueryAttrParamsExec(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg = mc.cfg.Clone()
+	mc.cfg.queryAttributeParams = true
+	mc.flags |= clientQueryAttributes
+	conn.queuedReplies = [][]byte{okPacket}
+	conn.maxReads = 1
+
+	if _, err := mc.ExecContext(context.Background(), "DO ?", []driver.NamedValue{{Ordinal: 1, Value: "v"}}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{
+		comQuery,
+		1,    // parameter_count
+		1,    // parameter_set_count
+		0,    // NULL-bitmap
+		1,    // new_params_bind_flag
+		0xfe, // MYSQL_TYPE_STRING
+		0, 7, '_', 'p', 'a', 'r', 'a', 'm', '1',
+		1, 'v',
+	}
+	expected = append(expected, "DO mysql_query_attribute_string('_param1')"...)
+	if !bytes.Equal(conn.written[4:], expected) {
+		t.Errorf("expected %q, got %q", expected, conn.written[4:])
+	}
+	if mc.queryAttrs != nil {
+		t.Errorf("parameters were not cleared: %v", mc.queryAttrs)
+	}
+
+	// without server support the statement is prepared
+	mc.flags &^= clientQueryAttributes
+	if _, err := mc.Exec("DO ?", []driver.Value{"v"}); err != driver.ErrSkip {
+		t.Errorf("expected driver.ErrSkip, got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RawCommand sends the command cmd with payload on conn and returns the
+// payload of the response packet, e.g. 0x0d (COM_DEBUG) or 0x1b
+// (COM_SET_OPTION). An ERR packet is returned as *MySQLError and an OK packet
+// updates the status of the connection.
+//
+// RawCommand is a low-level escape hatch: only commands answered with a
+// single packet are supported, and the driver does not know about changes
+// to the session made by the command. For example, the driver still assumes
+// the multiStatements setting of the DSN after COM_SET_OPTION. The supported
+// commands are COM_INIT_DB, COM_REFRESH, COM_SHUTDOWN, COM_STATISTICS,
+// COM_PROCESS_KILL, COM_DEBUG, COM_PING, COM_REGISTER_SLAVE and
+// COM_SET_OPTION. Other commands are rejected, e.g. COM_QUERY or
+// COM_PROCESS_INFO whose responses are result sets, or COM_RESET_CONNECTION
+// which discards state the driver keeps. COM_CHANGE_USER is sent by
+// ChangeUser and the binlog dump commands by StreamCommand.
+func RawCommand(ctx context.Context, conn *sql.Conn, cmd byte, payload []byte) (resp []byte, err error) {
+	err = rawConn(ctx, conn, func(mc *mysqlConn) error {
+		resp, err = mc.rawCommand(cmd, payload)
+		return err
+	})
+	return resp, err
+}
+
+func (mc *mysqlConn) rawCommand(cmd byte, payload []byte) ([]byte, error) {
+	switch cmd {
+	case comInitDB, comRefresh, comShutdown, comStatistics, comProcessKill, comDebug, comPing,
+		comRegisterSlave, comSetOption:
+		// answered with a single packet
+	default:
+		return nil, fmt.Errorf("mysql: command 0x%02x is not supported by RawCommand", cmd)
+	}
+	if mc.closed.Load() {
+		return nil, driver.ErrBadConn
+	}
+
+	handleOk := mc.clearResult()
+	if err := mc.writeCommandPacketStr(cmd, string(payload)); err != nil {
+		return nil, mc.markBadConn(err)
+	}
+	data, err := mc.readPacket()
+	if err != nil {
+		return nil, err
+	}
+	switch data[0] {
+	case iERR:
+		return nil, mc.handleErrorPacket(data)
+	case iOK:
+		if err := handleOk.handleOkPacket(data); err != nil {
+			return nil, err
+		}
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// StreamCommand sends the command cmd with payload on conn and calls f with
+// the payload of every packet of the response, for commands whose response
+// is a stream of packets like COM_BINLOG_DUMP (0x12) and
+// COM_BINLOG_DUMP_GTID (0x1e). The packet passed to f is only valid until f
+// returns. f can send packets to the server with reply while the response
+// is streamed, e.g. the ACKs of semi-synchronous replication.
+//
+// StreamCommand returns nil when the server ends the stream with an EOF
+// packet, and the error of an ERR packet as *MySQLError. If f returns an
+// error or ctx is done, the stream is interrupted and the connection is
+// closed, since the server keeps sending the response. The read timeout of
+// the DSN applies to every packet of the stream.
+func StreamCommand(ctx context.Context, conn *sql.Conn, cmd byte, payload []byte, f func(packet []byte, reply func(payload []byte) error) error) error {
+	return rawConn(ctx, conn, func(mc *mysqlConn) error {
+		return mc.streamCommand(cmd, payload, f)
+	})
+}
+
+func (mc *mysqlConn) streamCommand(cmd byte, payload []byte, f func(packet []byte, reply func(payload []byte) error) error) error {
+	if mc.closed.Load() {
+		return driver.ErrBadConn
+	}
+	mc.clearResult()
+	if err := mc.writeCommandPacketStr(cmd, string(payload)); err != nil {
+		return mc.markBadConn(err)
+	}
+	for {
+		data, err := mc.readPacket()
+		if err != nil {
+			return err
+		}
+		switch {
+		case data[0] == iERR:
+			return mc.handleErrorPacket(data)
+		case data[0] == iEOF && len(data) < 9:
+			return nil
+		}
+		if err := f(data, mc.writeStreamReply); err != nil {
+			mc.cleanup()
+			return err
+		}
+	}
+}
+
+// writeStreamReply sends a packet to the server while a response is
+// streamed. The packet starts a new sequence, and the sequence of the
+// streamed response continues afterwards.
+func (mc *mysqlConn) writeStreamReply(payload []byte) error {
+	seq, compressSeq := mc.sequence, mc.compressSequence
+	mc.resetSequence()
+	// the read buffer may hold the next packets of the response
+	data := make([]byte, 4+len(payload))
+	copy(data[4:], payload)
+	err := mc.writePacket(data)
+	mc.sequence, mc.compressSequence = seq, compressSeq
+	return err
+}
+
+// ServerStatistics are the statistics returned by COM_STATISTICS, the
+// command behind mysqladmin status.
+type ServerStatistics struct {
+	Uptime              time.Duration
+	Threads             int64
+	Questions           int64
+	SlowQueries         int64
+	Opens               int64
+	FlushTables         int64
+	OpenTables          int64
+	QueriesPerSecondAvg float64
+}
+
+// Statistics returns the statistics of the server conn is connected to.
+func Statistics(ctx context.Context, conn *sql.Conn) (ServerStatistics, error) {
+	resp, err := RawCommand(ctx, conn, comStatistics, nil)
+	if err != nil {
+		return ServerStatistics{}, err
+	}
+	return parseStatistics(string(resp))
+}
+
+// parseStatistics parses the response to COM_STATISTICS, e.g.
+//
+//	Uptime: 5190  Threads: 2  Questions: 27  Slow queries: 0  Opens: 119  Flush tables: 3  Open tables: 38  Queries per second avg: 0.005
+//
+// Unknown fields are ignored.
+func parseStatistics(s string) (ServerStatistics, error) {
+	var st ServerStatistics
+	for _, field := range strings.Split(s, "  ") {
+		name, value, ok := strings.Cut(strings.TrimSpace(field), ": ")
+		if !ok {
+			continue
+		}
+		var n *int64
+		switch name {
+		case "Uptime":
+			secs, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ServerStatistics{}, fmt.Errorf("mysql: malformed statistics %q", s)
+			}
+			st.Uptime = time.Duration(secs) * time.Second
+			continue
+		case "Queries per second avg":
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return ServerStatistics{}, fmt.Errorf("mysql: malformed statistics %q", s)
+			}
+			st.QueriesPerSecondAvg = f
+			continue
+		case "Threads":
+			n = &st.Threads
+		case "Questions":
+			n = &st.Questions
+		case "Slow queries":
+			n = &st.SlowQueries
+		case "Opens":
+			n = &st.Opens
+		case "Flush tables":
+			n = &st.FlushTables
+		case "Open tables":
+			n = &st.OpenTables
+		default:
+			continue
+		}
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return ServerStatistics{}, fmt.Errorf("mysql: malformed statistics %q", s)
+		}
+		*n = v
+	}
+	return st, nil
+}
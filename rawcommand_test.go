@@ -0,0 +1,129 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRawCommand(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	stats := "Uptime: 5190  Threads: 2  Questions: 27  Slow queries: 0  Opens: 119  Flush tables: 3  Open tables: 38  Queries per second avg: 0.005"
+	conn.queuedReplies = [][]byte{
+		mockPacket(1, []byte(stats)),
+		mockPacket(1, []byte{iOK, 0, 0, 2, 0, 0, 0}),
+		mockPacket(1, []byte{iERR, 0x1e, 0x04, '#', 'H', 'Y', '0', '0', '0', 'n', 'o', 'p', 'e'}),
+	}
+
+	resp, err := mc.rawCommand(comStatistics, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp) != stats {
+		t.Errorf("unexpected response %q", resp)
+	}
+	if expected := []byte{1, 0, 0, 0, comStatistics}; !bytes.Equal(conn.written, expected) {
+		t.Errorf("unexpected packet %v, expected %v", conn.written, expected)
+	}
+
+	conn.written = nil
+	if _, err := mc.rawCommand(comSetOption, []byte{1, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []byte{3, 0, 0, 0, comSetOption, 1, 0}; !bytes.Equal(conn.written, expected) {
+		t.Errorf("unexpected packet %v, expected %v", conn.written, expected)
+	}
+	if mc.status&statusInAutocommit == 0 {
+		t.Error("status not updated from the OK packet")
+	}
+
+	var me *MySQLError
+	if _, err := mc.rawCommand(comDebug, nil); !errors.As(err, &me) || me.Number != 1054 {
+		t.Errorf("expected error 1054, got %v", err)
+	}
+
+	conn.written = nil
+	for _, cmd := range []byte{comQuery, comProcessInfo, comResetConnection, comStmtReset, 0xff} {
+		if _, err := mc.rawCommand(cmd, []byte("SELECT 1")); err == nil {
+			t.Errorf("expected command 0x%02x to be rejected", cmd)
+		}
+	}
+	if len(conn.written) != 0 {
+		t.Errorf("unexpected packet %v", conn.written)
+	}
+}
+
+func TestStreamCommand(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	conn.queuedReplies = [][]byte{append(append(
+		mockPacket(1, []byte{0, 'a'}),
+		mockPacket(2, []byte{0, 'b'})...),
+		mockPacket(3, []byte{iEOF, 0, 0, 2, 0})...),
+	}
+	var packets []string
+	err := mc.streamCommand(comBinlogDump, []byte{4, 0, 0, 0}, func(packet []byte, reply func([]byte) error) error {
+		packets = append(packets, string(packet))
+		if len(packets) == 1 {
+			conn.written = nil
+			return reply([]byte{0xef, 1})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packets) != 2 || packets[0] != "\x00a" || packets[1] != "\x00b" {
+		t.Errorf("unexpected packets %q", packets)
+	}
+	// the reply starts a new sequence without disturbing the stream
+	if expected := []byte{2, 0, 0, 0, 0xef, 1}; !bytes.Equal(conn.written, expected) {
+		t.Errorf("unexpected reply %v, expected %v", conn.written, expected)
+	}
+	if mc.closed.Load() {
+		t.Error("connection closed after the end of the stream")
+	}
+
+	// an error of the callback closes the connection
+	conn.queuedReplies = [][]byte{mockPacket(1, []byte{0, 'a'})}
+	errStop := errors.New("stop")
+	err = mc.streamCommand(comBinlogDump, nil, func(packet []byte, reply func([]byte) error) error { return errStop })
+	if err != errStop {
+		t.Errorf("expected errStop, got %v", err)
+	}
+	if !mc.closed.Load() {
+		t.Error("connection not closed")
+	}
+}
+
+func TestParseStatistics(t *testing.T) {
+	st, err := parseStatistics("Uptime: 5190  Threads: 2  Questions: 27  Slow queries: 1  Opens: 119  Flush tables: 3  Open tables: 38  Queries per second avg: 0.005")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ServerStatistics{
+		Uptime:              5190 * time.Second,
+		Threads:             2,
+		Questions:           27,
+		SlowQueries:         1,
+		Opens:               119,
+		FlushTables:         3,
+		OpenTables:          38,
+		QueriesPerSecondAvg: 0.005,
+	}
+	if st != expected {
+		t.Errorf("got %+v, expected %+v", st, expected)
+	}
+
+	if _, err := parseStatistics("Uptime: x  Threads: 2"); err == nil {
+		t.Error("expected an error for a malformed value")
+	}
+}
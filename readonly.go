@@ -0,0 +1,223 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"strings"
+	"time"
+)
+
+// readOnlyCheckInterval is the minimum time between two verifications of the
+// read-only mode of the server when a connection is reused, see
+// Config.AssertReadOnly.
+const readOnlyCheckInterval = 10 * time.Second
+
+// ReadOnlyError is returned if Config.AssertReadOnly is set and either the
+// server is not in read-only mode or a statement which may write was refused.
+type ReadOnlyError struct {
+	// Statement is the refused statement, or empty if the server is not in
+	// read-only mode.
+	Statement string
+}
+
+func (e *ReadOnlyError) Error() string {
+	if e.Statement == "" {
+		return "server is not in read-only mode, but AssertReadOnly is set"
+	}
+	keyword, _, _ := strings.Cut(strings.TrimSpace(e.Statement), " ")
+	return "statement refused by AssertReadOnly: " + keyword
+}
+
+// readOnlyKeywords are the first keywords of the statements which are sent
+// with Config.AssertReadOnly.
+var readOnlyKeywords = []string{
+	"SELECT", "WITH", "SHOW", "DESCRIBE", "DESC", "EXPLAIN", "HELP",
+	"SET", "DO", "USE",
+	"BEGIN", "START", "COMMIT", "ROLLBACK", "SAVEPOINT", "RELEASE",
+}
+
+// isReadOnlyStatement reports whether query starts with one of the
+// readOnlyKeywords after leading comments and parentheses. The statement
+// following the common table expressions of a WITH clause must be a SELECT,
+// as MySQL also allows UPDATE and DELETE statements there.
+func isReadOnlyStatement(query string, noBackslashEscapes bool) bool {
+	query = skipLeadingComments(query)
+	if i, ok := hasKeywordPrefix(query, "WITH"); ok {
+		query, ok = skipCommonTableExpressions(query[i:], noBackslashEscapes)
+		if !ok {
+			return false
+		}
+		_, ok = hasKeywordPrefix(skipLeadingComments(query), "SELECT")
+		return ok
+	}
+	for _, kw := range readOnlyKeywords {
+		if _, ok := hasKeywordPrefix(query, kw); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// skipCommonTableExpressions returns the statement following the common table
+// expressions of a WITH clause, "[RECURSIVE] name [(columns)] AS (subquery)"
+// separated by commas. ok is false if they can not be parsed.
+func skipCommonTableExpressions(query string, noBackslashEscapes bool) (rest string, ok bool) {
+	for {
+		open := indexUnquoted(query, 0, "(", noBackslashEscapes)
+		if open < 0 {
+			return "", false
+		}
+		before := strings.TrimRight(query[:open], " \t\r\n")
+		subquery := len(before) >= 2 && strings.EqualFold(before[len(before)-2:], "AS") &&
+			(len(before) == 2 || !isIdentByte(before[len(before)-3]))
+
+		// skip the column list or the subquery
+		depth := 0
+		i := open
+		for ; i >= 0; i = indexUnquoted(query, i+1, "()", noBackslashEscapes) {
+			if query[i] == '(' {
+				depth++
+			} else if depth--; depth == 0 {
+				break
+			}
+		}
+		if i < 0 {
+			return "", false
+		}
+		query = query[i+1:]
+		if !subquery {
+			continue
+		}
+		if j := skipSpace(query, 0); j < len(query) && query[j] == ',' {
+			query = query[j+1:]
+			continue
+		}
+		return query, true
+	}
+}
+
+// hasMultipleStatements reports whether query consists of several statements
+// separated by semicolons, which are executed with multiStatements=true.
+func hasMultipleStatements(query string, noBackslashEscapes bool) bool {
+	for i := indexUnquoted(query, 0, ";", noBackslashEscapes); i >= 0; i = indexUnquoted(query, i+1, ";", noBackslashEscapes) {
+		if rest := skipLeadingComments(query[i+1:]); rest != "" && rest[0] != ';' {
+			return true
+		}
+	}
+	return false
+}
+
+// skipLeadingComments returns query without leading whitespace, comments and
+// opening parentheses.
+func skipLeadingComments(query string) string {
+	for {
+		query = query[skipSpace(query, 0):]
+		switch {
+		case strings.HasPrefix(query, "("):
+			query = query[1:]
+		case strings.HasPrefix(query, "/*"):
+			end := strings.Index(query[2:], "*/")
+			if end < 0 {
+				return query
+			}
+			query = query[end+4:]
+		case strings.HasPrefix(query, "#"), strings.HasPrefix(query, "-- "):
+			end := strings.IndexByte(query, '\n')
+			if end < 0 {
+				return ""
+			}
+			query = query[end+1:]
+		default:
+			return query
+		}
+	}
+}
+
+// assertReadOnly returns a *ReadOnlyError if Config.AssertReadOnly is set and
+// query may write. With multiStatements=true, queries consisting of several
+// statements are refused, as only the first one would be verified.
+func (mc *mysqlConn) assertReadOnly(query string) error {
+	if !mc.cfg.AssertReadOnly {
+		return nil
+	}
+	noBackslashEscapes := mc.status&statusNoBackslashEscapes != 0
+	if isReadOnlyStatement(query, noBackslashEscapes) &&
+		!(mc.cfg.MultiStatements && hasMultipleStatements(query, noBackslashEscapes)) {
+		return nil
+	}
+	return &ReadOnlyError{Statement: query}
+}
+
+// checkReadOnly verifies that the server is in read-only mode if
+// Config.AssertReadOnly is set. It is called when the connection is
+// established and, at most every readOnlyCheckInterval, when it is reused,
+// so that a replica which was promoted, e.g. by a failover, is detected.
+func (mc *mysqlConn) checkReadOnly() error {
+	if !mc.cfg.AssertReadOnly {
+		return nil
+	}
+	readOnly, err := mc.getSystemVar("GLOBAL.read_only")
+	if err != nil {
+		return err
+	}
+	mc.readOnlyChecked = time.Now()
+	if v := string(readOnly); v != "1" && !strings.EqualFold(v, "ON") {
+		return &ReadOnlyError{}
+	}
+	return nil
+}
+
+// serverReadOnlyError is returned with rejectReadOnly=error for statements
+// which failed because the server is read-only.
+type serverReadOnlyError struct {
+	*MySQLError
+	retry bool // the connection was discarded and the statement may be retried
+}
+
+func (e *serverReadOnlyError) Unwrap() error {
+	return e.MySQLError
+}
+
+// Is matches ErrServerReadOnly and, if the statement may be retried,
+// driver.ErrBadConn, so that database/sql retries on another connection.
+func (e *serverReadOnlyError) Is(target error) bool {
+	return target == ErrServerReadOnly || e.retry && target == driver.ErrBadConn
+}
+
+// rejectReadOnly handles the error of a statement which failed because the
+// server is read-only, with Config.RejectReadOnly.
+func (mc *mysqlConn) rejectReadOnly(me *MySQLError) error {
+	discard := mc.cfg.ReadOnlyDiscardBudget == nil || mc.cfg.ReadOnlyDiscardBudget.allow()
+	if fn := mc.cfg.OnServerReadOnly; fn != nil {
+		fn(me, discard)
+	}
+	if !discard {
+		if mc.cfg.readOnlyError {
+			return &serverReadOnlyError{MySQLError: me}
+		}
+		return me
+	}
+
+	// Oops; we are connected to a read-only connection, and won't be able
+	// to issue any write statements. Since RejectReadOnly is configured,
+	// we throw away this connection hoping this one would have write
+	// permission. This is specifically for a possible race condition
+	// during failover (e.g. on AWS Aurora). See README.md for more.
+	//
+	// We explicitly close the connection before returning
+	// driver.ErrBadConn to ensure that `database/sql` purges this
+	// connection and initiates a new one for next statement next time.
+	mc.Close()
+	err := mc.badConn(me)
+	if mc.cfg.readOnlyError {
+		return &serverReadOnlyError{MySQLError: me, retry: err == driver.ErrBadConn}
+	}
+	return err
+}
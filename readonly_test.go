@@ -0,0 +1,141 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIsReadOnlyStatement(t *testing.T) {
+	for query, want := range map[string]bool{
+		"SELECT 1":                             true,
+		"  select * FROM t":                    true,
+		"(SELECT 1) UNION (SELECT 2)":          true,
+		"/* app */ SELECT 1":                   true,
+		"-- comment\nSHOW TABLES":              true,
+		"WITH a AS (SELECT 1) SELECT * FROM a": true,
+		"WITH RECURSIVE a (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM a WHERE n < 5), b AS (SELECT ')') (SELECT * FROM a, b)": true,
+		"WITH a AS (SELECT 1) DELETE FROM t WHERE id IN (SELECT * FROM a)":                                                     false,
+		"with a as (select 1) update t, a SET t.x = 1":                                                                         false,
+		"WITH a AS (SELECT 1":             false,
+		"SET @a = 1":                      true,
+		"INSERT INTO t VALUES (1)":        false,
+		"/* SELECT */ UPDATE t SET a = 1": false,
+		"DELETE FROM t":                   false,
+		"SELECTED":                        false,
+		"/* unterminated":                 false,
+	} {
+		if got := isReadOnlyStatement(query, false); got != want {
+			t.Errorf("%q: got %t, want %t", query, got, want)
+		}
+	}
+}
+
+func TestAssertReadOnlyStatement(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.AssertReadOnly = true
+
+	var roErr *ReadOnlyError
+	if _, err := mc.Exec("INSERT INTO t VALUES (1)", nil); !errors.As(err, &roErr) || roErr.Statement == "" {
+		t.Errorf("expected ReadOnlyError, got %v", err)
+	}
+	if _, err := mc.Query("DELETE FROM t", nil); !errors.As(err, &roErr) {
+		t.Errorf("expected ReadOnlyError, got %v", err)
+	}
+	if _, err := mc.Prepare("UPDATE t SET a = ?"); !errors.As(err, &roErr) {
+		t.Errorf("expected ReadOnlyError, got %v", err)
+	}
+
+	mc.cfg.MultiStatements = true
+	if _, err := mc.Exec("SELECT 1; DELETE FROM t", nil); !errors.As(err, &roErr) {
+		t.Errorf("expected ReadOnlyError, got %v", err)
+	}
+	if conn.writes != 0 {
+		t.Errorf("refused statements were sent: %d writes", conn.writes)
+	}
+}
+
+func TestHasMultipleStatements(t *testing.T) {
+	for query, want := range map[string]bool{
+		"SELECT 1":                   false,
+		"SELECT 1;":                  false,
+		"SELECT 1; -- end\n":         false,
+		"SELECT ';' FROM t":          false,
+		"SELECT 1 /* ; */":           false,
+		"SELECT 1; DELETE FROM t":    true,
+		"SELECT 1;; DELETE FROM t":   true,
+		"SELECT 1; /* x */ SELECT 2": true,
+	} {
+		if got := hasMultipleStatements(query, false); got != want {
+			t.Errorf("%q: got %t, want %t", query, got, want)
+		}
+	}
+}
+
+func TestCheckReadOnly(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.AssertReadOnly = true
+	conn.queuedReplies = [][]byte{mockSystemVar("GLOBAL.read_only", "1")}
+	if err := mc.checkReadOnly(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the server was promoted since the last check
+	mc.readOnlyChecked = time.Now().Add(-readOnlyCheckInterval)
+	conn.queuedReplies = [][]byte{mockSystemVar("GLOBAL.read_only", "0")}
+	if err := mc.ResetSession(context.Background()); err != driver.ErrBadConn {
+		t.Errorf("expected ErrBadConn, got %v", err)
+	}
+	if !mc.closed.Load() {
+		t.Error("connection to a writable server was not closed")
+	}
+}
+
+func TestRejectReadOnlyError(t *testing.T) {
+	var calls []bool
+	budget := NewRetryBudget(0, 1)
+	// ER_READ_ONLY_MODE
+	data := append([]byte{0xff, 0x2c, 0x07, '#', 'H', 'Y', '0', '0', '0'}, "read-only"...)
+	for i, discard := range []bool{true, false} {
+		_, mc := newRWMockConn(0)
+		mc.cfg.Apply(RejectReadOnlyError(true))
+		mc.cfg.ReadOnlyDiscardBudget = budget
+		mc.cfg.OnServerReadOnly = func(err *MySQLError, discarded bool) {
+			calls = append(calls, discarded)
+		}
+
+		err := mc.handleErrorPacket(data)
+		var me *MySQLError
+		if !errors.Is(err, ErrServerReadOnly) || !errors.As(err, &me) || me.Number != 1836 {
+			t.Errorf("%d: expected ErrServerReadOnly, got %v", i, err)
+		}
+		if errors.Is(err, driver.ErrBadConn) != discard {
+			t.Errorf("%d: expected ErrBadConn %t, got %v", i, discard, err)
+		}
+		if mc.closed.Load() != discard {
+			t.Errorf("%d: expected closed connection %t", i, discard)
+		}
+	}
+	if len(calls) != 2 || !calls[0] || calls[1] {
+		t.Errorf("unexpected callbacks %v", calls)
+	}
+
+	// rejectReadOnly=true returns ER_READ_ONLY_MODE unchanged
+	_, mc := newRWMockConn(0)
+	mc.cfg.RejectReadOnly = true
+	err := mc.handleErrorPacket(data)
+	var me *MySQLError
+	if !errors.As(err, &me) || me.Number != 1836 || errors.Is(err, driver.ErrBadConn) || mc.closed.Load() {
+		t.Errorf("expected MySQLError 1836, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// registry is a global registry of named values. It is copy-on-write:
+// lookups, which happen for every new connection, read an immutable map
+// without locking, and updates replace the map.
+type registry[V any] struct {
+	mu sync.Mutex // serializes updates
+	m  atomic.Pointer[map[string]V]
+}
+
+func (r *registry[V]) get(name string) (v V, ok bool) {
+	if m := r.m.Load(); m != nil {
+		v, ok = (*m)[name]
+	}
+	return
+}
+
+// update replaces the map with a copy modified by fn.
+func (r *registry[V]) update(fn func(m map[string]V)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var m map[string]V
+	if old := r.m.Load(); old != nil {
+		m = make(map[string]V, len(*old)+1)
+		for name, v := range *old {
+			m[name] = v
+		}
+	} else {
+		m = make(map[string]V, 1)
+	}
+	fn(m)
+	r.m.Store(&m)
+}
+
+func (r *registry[V]) set(name string, v V) {
+	r.update(func(m map[string]V) { m[name] = v })
+}
+
+func (r *registry[V]) delete(name string) {
+	if _, ok := r.get(name); !ok {
+		return
+	}
+	r.update(func(m map[string]V) { delete(m, name) })
+}
+
+// names returns the sorted names of the registered values.
+func (r *registry[V]) names() []string {
+	m := r.m.Load()
+	if m == nil {
+		return []string{}
+	}
+	names := make([]string, 0, len(*m))
+	for name := range *m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *registry[V]) reset() {
+	r.mu.Lock()
+	r.m.Store(nil)
+	r.mu.Unlock()
+}
@@ -0,0 +1,105 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"crypto/tls"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRegistry(t *testing.T) {
+	var r registry[int]
+	if v, ok := r.get("a"); ok || v != 0 {
+		t.Errorf("unexpected value %d in empty registry", v)
+	}
+	if names := r.names(); len(names) != 0 {
+		t.Errorf("unexpected names %v", names)
+	}
+	r.delete("a")
+
+	r.set("b", 2)
+	r.set("a", 1)
+	if v, ok := r.get("a"); !ok || v != 1 {
+		t.Errorf("expected 1, got %d, %v", v, ok)
+	}
+	if names := r.names(); !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+
+	// copy-on-write: a loaded map is never modified
+	m := r.m.Load()
+	r.delete("a")
+	if _, ok := (*m)["a"]; !ok {
+		t.Error("previous map was modified")
+	}
+	if _, ok := r.get("a"); ok {
+		t.Error("value was not deleted")
+	}
+
+	r.reset()
+	if names := r.names(); len(names) != 0 {
+		t.Errorf("unexpected names after reset %v", names)
+	}
+}
+
+func TestRegistryConcurrent(t *testing.T) {
+	var r registry[int]
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := strings.Repeat("x", i+1)
+			for j := 0; j < 100; j++ {
+				r.set(name, j)
+				r.get(name)
+				r.names()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if n := len(r.names()); n != 8 {
+		t.Errorf("expected 8 names, got %d", n)
+	}
+}
+
+func TestListRegistries(t *testing.T) {
+	if err := RegisterTLSConfig("list-test", &tls.Config{}); err != nil {
+		t.Fatal(err)
+	}
+	defer DeregisterTLSConfig("list-test")
+	if !slices.Contains(ListTLSConfigs(), "list-test") {
+		t.Errorf("tls config not listed: %v", ListTLSConfigs())
+	}
+
+	RegisterReaderHandler("list-reader", func() io.Reader { return nil })
+	defer DeregisterReaderHandler("list-reader")
+	RegisterReaderAtHandler("list-reader-at", func() (io.ReaderAt, int64) { return nil, 0 })
+	defer DeregisterReaderAtHandler("list-reader-at")
+	names := ListReaderHandlers()
+	if !slices.Contains(names, "list-reader") || !slices.Contains(names, "list-reader-at") || !slices.IsSorted(names) {
+		t.Errorf("unexpected reader handlers %v", names)
+	}
+
+	RegisterFS("a", fstest.MapFS{})
+	RegisterFS("b", fstest.MapFS{})
+	if names := ListFS(); !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("unexpected file systems %v", names)
+	}
+	ResetFS()
+	if names := ListFS(); len(names) != 0 {
+		t.Errorf("unexpected file systems after reset %v", names)
+	}
+}
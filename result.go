@@ -31,6 +31,90 @@ type mysqlResult struct {
 	// One entry in both slices is created for every executed statement result.
 	affectedRows []int64
 	insertIds    []int64
+
+	// Warnings of all statements, see Config.TreatWarningsAsErrors.
+	warningCount uint16
+
+	// Number of warnings of every statement, see MultiResults.
+	warningCounts []uint16
+
+	// Warnings fetched with SHOW WARNINGS, see Config.collectWarnings.
+	warnings MySQLWarnings
+
+	// Info of the last statement.
+	info string
+
+	// GTIDs of every statement, see Config.trackGTIDs.
+	gtids []string
+}
+
+// StatementResult is the result of a single statement executed by Exec, e.g.
+// of one of the statements of a query with multiStatements, see MultiResults.
+type StatementResult struct {
+	AffectedRows int64
+	LastInsertId int64
+	Warnings     uint16 // number of warnings of the statement
+	GTIDs        string // GTID set of the transaction committed by the statement, with trackGTIDs=true
+}
+
+// MultiResults returns the results of all statements executed by an Exec in
+// order, e.g. of a query with several statements and multiStatements=true.
+// Statements returning rows, e.g. SELECTs, have a result without affected
+// rows. res must be a result of this driver, e.g. returned by Exec of the
+// driver connection accessible with sql.Conn.Raw. ok is false for other types.
+func MultiResults(res any) (results []StatementResult, ok bool) {
+	var mres *mysqlResult
+	switch r := res.(type) {
+	case *mysqlResult:
+		mres = r
+	case *coalescedResult:
+		if r.wait() != nil {
+			return nil, true
+		}
+		mres = &r.res
+	default:
+		return nil, false
+	}
+	results = make([]StatementResult, len(mres.affectedRows))
+	for i := range results {
+		results[i].AffectedRows = mres.affectedRows[i]
+		if i < len(mres.insertIds) {
+			results[i].LastInsertId = mres.insertIds[i]
+		}
+		if i < len(mres.warningCounts) {
+			results[i].Warnings = mres.warningCounts[i]
+		}
+		if i < len(mres.gtids) {
+			results[i].GTIDs = mres.gtids[i]
+		}
+	}
+	return results, true
+}
+
+// ResultInfo returns the human-readable information which the server sent with
+// the result of the last statement executed by an Exec, e.g. "Records: 3
+// Duplicates: 0  Warnings: 0" for an INSERT of several rows, or "" if there is
+// none. res must be a result of this driver, see MultiResults. ok is false for
+// other types.
+func ResultInfo(res any) (info string, ok bool) {
+	switch r := res.(type) {
+	case *mysqlResult:
+		return r.info, true
+	case *coalescedResult:
+		if r.wait() != nil {
+			return "", true
+		}
+		return r.res.info, true
+	}
+	return "", false
+}
+
+// addWarnings adds n warnings of the current statement.
+func (res *mysqlResult) addWarnings(n uint16) {
+	res.warningCount += n
+	if len(res.warningCounts) > 0 {
+		res.warningCounts[len(res.warningCounts)-1] += n
+	}
 }
 
 func (res *mysqlResult) LastInsertId() (int64, error) {
@@ -0,0 +1,55 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMultiResults(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	more := byte(statusMoreResultsExists | statusInAutocommit)
+
+	// INSERT, UPDATE and SELECT with 1, 0 and 2 warnings
+	resp := mockPacket(1, []byte{iOK, 1, 5, more, 0, 1, 0})
+	resp = append(resp, mockPacket(2, []byte{iOK, 3, 0, more, 0, 0, 0})...)
+	resp = append(resp, mockPacket(3, []byte{1})...)
+	resp = append(resp, mockColumn(4, "1", fieldTypeLongLong)...)
+	resp = append(resp, mockPacket(5, []byte{iEOF, 0, 0, more, 0})...)
+	resp = append(resp, mockPacket(6, appendLengthEncodedString(nil, "1"))...)
+	resp = append(resp, mockPacket(7, []byte{iEOF, 2, 0, 2, 0})...)
+	conn.queuedReplies = [][]byte{resp}
+
+	res, err := mc.Exec("INSERT INTO t VALUES (1); UPDATE t SET a = 2; SELECT 1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, ok := MultiResults(res)
+	if !ok {
+		t.Fatalf("MultiResults not supported by %T", res)
+	}
+	want := []StatementResult{
+		{AffectedRows: 1, LastInsertId: 5, Warnings: 1},
+		{AffectedRows: 3},
+		{Warnings: 2},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("expected %+v, got %+v", want, results)
+	}
+
+	if _, ok := MultiResults(driverResult{}); ok {
+		t.Error("expected ok=false for other results")
+	}
+}
+
+type driverResult struct{}
+
+func (driverResult) LastInsertId() (int64, error) { return 0, nil }
+func (driverResult) RowsAffected() (int64, error) { return 0, nil }
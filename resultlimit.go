@@ -0,0 +1,55 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import "strconv"
+
+// ResultSetLimitError is returned by Rows.Next when a result set exceeds the
+// maxResultSetRows or maxResultSetBytes limit. The remaining rows of the
+// result set have been discarded and the connection remains usable.
+type ResultSetLimitError struct {
+	Limit string // "maxResultSetRows" or "maxResultSetBytes"
+	Max   int64  // value of the limit
+}
+
+func (e *ResultSetLimitError) Error() string {
+	return "result set exceeds " + e.Limit + "=" + strconv.FormatInt(e.Max, 10) + ", remaining rows discarded"
+}
+
+// checkLimits accounts a row of size bytes against the result set limits of
+// the configuration. If a limit is exceeded, the remaining rows are read and
+// discarded and a *ResultSetLimitError is returned.
+func (rows *mysqlRows) checkLimits(size int) error {
+	mc := rows.mc
+	maxRows, maxBytes := mc.cfg.maxResultRows, mc.cfg.maxResultBytes
+	if maxRows == 0 && maxBytes == 0 {
+		return nil
+	}
+	rows.rs.rowCount++
+	rows.rs.rowBytes += int64(size)
+
+	var err *ResultSetLimitError
+	if maxRows > 0 && rows.rs.rowCount > maxRows {
+		err = &ResultSetLimitError{Limit: "maxResultSetRows", Max: maxRows}
+	} else if maxBytes > 0 && rows.rs.rowBytes > maxBytes {
+		err = &ResultSetLimitError{Limit: "maxResultSetBytes", Max: maxBytes}
+	} else {
+		return nil
+	}
+
+	// drain the result set, the following ones are discarded by Close
+	if derr := rows.skipRow(); derr != nil {
+		return derr
+	}
+	if derr := mc.readUntilEOF(); derr != nil {
+		return derr
+	}
+	rows.rs.done = true
+	return err
+}
@@ -0,0 +1,77 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestResultSetLimits(t *testing.T) {
+	// three rows of 4 bytes each
+	resp := mockPacket(1, []byte{1})
+	resp = append(resp, mockColumn(2, "a", fieldTypeVarString)...)
+	resp = append(resp, mockPacket(3, []byte{iEOF, 0, 0, 2, 0})...)
+	resp = append(resp, mockPacket(4, []byte{3, 'a', 'b', 'c'})...)
+	resp = append(resp, mockPacket(5, []byte{3, 'd', 'e', 'f'})...)
+	resp = append(resp, mockPacket(6, []byte{3, 'g', 'h', 'i'})...)
+	resp = append(resp, mockPacket(7, []byte{iEOF, 0, 0, 2, 0})...)
+	ok := mockPacket(1, []byte{iOK, 0, 0, 2, 0, 0, 0})
+
+	for _, tc := range []struct {
+		opt   Option
+		rows  int
+		limit string
+	}{
+		{MaxResultSetRows(2), 2, "maxResultSetRows"},
+		{MaxResultSetBytes(5), 1, "maxResultSetBytes"},
+		{MaxResultSetRows(3), 3, ""},
+	} {
+		conn, mc := newRWMockConn(0)
+		if err := tc.opt(mc.cfg); err != nil {
+			t.Fatal(err)
+		}
+		conn.queuedReplies = [][]byte{resp, ok}
+
+		rows, err := mc.Query("SELECT a FROM t", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dest := make([]driver.Value, 1)
+		n := 0
+		for err = rows.Next(dest); err == nil; err = rows.Next(dest) {
+			n++
+		}
+		var le *ResultSetLimitError
+		if tc.limit == "" {
+			if err != io.EOF {
+				t.Errorf("expected EOF, got %v", err)
+			}
+		} else if !errors.As(err, &le) || le.Limit != tc.limit {
+			t.Errorf("expected ResultSetLimitError of %s, got %v", tc.limit, err)
+		}
+		if n != tc.rows {
+			t.Errorf("%s: expected %d rows, got %d", tc.limit, tc.rows, n)
+		}
+		if err := rows.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// the connection is still usable
+		if _, err := mc.Exec("DO 1", nil); err != nil {
+			t.Errorf("%s: %v", tc.limit, err)
+		}
+	}
+
+	if err := MaxResultSetRows(-1)(NewConfig()); err == nil {
+		t.Error("expected error for negative limit")
+	}
+}
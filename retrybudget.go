@@ -0,0 +1,96 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RetryBudget limits the automatic retries of the driver, so that retries do
+// not multiply the load on the server during an incident. Every retry takes a
+// token from a bucket which is refilled at a constant rate.
+//
+// Retries are driver.ErrBadConn errors returned to database/sql for broken
+// connections, RejectReadOnly and Config.ShouldRetryError, as well as
+// reconnects of transparentFailover. If the budget is exhausted, the original
+// error is returned instead.
+//
+// A RetryBudget is safe for concurrent use. It is shared by all connections
+// using the Config and its clones. Use NewRetryBudget to create one; the zero
+// value allows no retries.
+type RetryBudget struct {
+	rate  float64 // tokens per second
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+
+	denied atomic.Uint64
+	now    func() time.Time // for testing
+}
+
+// NewRetryBudget returns a RetryBudget which allows perSecond retries on
+// average and up to burst retries at once.
+func NewRetryBudget(perSecond float64, burst int) *RetryBudget {
+	return &RetryBudget{
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+// allow takes a token and reports whether the retry may be made.
+func (b *RetryBudget) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if b.now != nil {
+		now = b.now()
+	}
+	if !b.last.IsZero() {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		b.denied.Add(1)
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Denied returns the number of retries which were not made because the
+// budget was exhausted.
+func (b *RetryBudget) Denied() uint64 {
+	return b.denied.Load()
+}
+
+// RetryBudgetCollector can be implemented by a StatsCollector to be notified
+// when a retry is not made because the Config.RetryBudget is exhausted.
+type RetryBudgetCollector interface {
+	RetryDenied()
+}
+
+// retryAllowed takes a token of the Config.RetryBudget, if any.
+func (mc *mysqlConn) retryAllowed() bool {
+	b := mc.cfg.RetryBudget
+	if b == nil || b.allow() {
+		return true
+	}
+	if rc, ok := mc.cfg.StatsCollector.(RetryBudgetCollector); ok {
+		rc.RetryDenied()
+	}
+	return false
+}
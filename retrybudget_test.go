@@ -0,0 +1,122 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+func TestRetryBudget(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := NewRetryBudget(2, 3)
+	b.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if !b.allow() {
+			t.Fatalf("retry %d denied", i)
+		}
+	}
+	if b.allow() {
+		t.Fatal("retry allowed with exhausted budget")
+	}
+
+	// 2 tokens per second
+	now = now.Add(500 * time.Millisecond)
+	if !b.allow() {
+		t.Fatal("retry denied after refill")
+	}
+	if b.allow() {
+		t.Fatal("retry allowed with exhausted budget")
+	}
+
+	// the bucket holds at most 3 tokens
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if !b.allow() {
+			t.Fatalf("retry %d denied", i)
+		}
+	}
+	if b.allow() {
+		t.Fatal("retry allowed with exhausted budget")
+	}
+	if n := b.Denied(); n != 3 {
+		t.Errorf("expected 3 denied retries, got %d", n)
+	}
+}
+
+type testRetryBudgetCollector struct {
+	testStatsCollector
+	denied int
+}
+
+func (s *testRetryBudgetCollector) RetryDenied() { s.denied++ }
+
+func TestRetryBudgetZeroValue(t *testing.T) {
+	b := &RetryBudget{}
+	if b.allow() {
+		t.Error("zero value allowed a retry")
+	}
+	if b.Denied() != 1 {
+		t.Errorf("expected 1 denied retry, got %d", b.Denied())
+	}
+}
+
+func TestRetryBudgetRejectReadOnly(t *testing.T) {
+	stats := &testRetryBudgetCollector{}
+	for i, expectBadConn := range []bool{true, false} {
+		_, mc := newRWMockConn(0)
+		mc.cfg.RejectReadOnly = true
+		mc.cfg.RetryBudget = NewRetryBudget(0, 1)
+		mc.cfg.StatsCollector = stats
+		if i > 0 {
+			mc.cfg.RetryBudget.allow()
+		}
+
+		// ER_OPTION_PREVENTS_STATEMENT
+		data := append([]byte{0xff, 0x0a, 0x05, '#', 'H', 'Y', '0', '0', '0'}, "read-only"...)
+		err := mc.handleErrorPacket(data)
+		if expectBadConn {
+			if err != driver.ErrBadConn {
+				t.Errorf("expected ErrBadConn, got %v", err)
+			}
+		} else if me, ok := err.(*MySQLError); !ok || me.Number != 1290 {
+			t.Errorf("expected MySQLError 1290, got %v", err)
+		}
+		if !mc.closed.Load() {
+			t.Error("connection must be closed")
+		}
+	}
+	if stats.badConns != 1 || stats.denied != 1 {
+		t.Errorf("expected 1 bad connection and 1 denied retry, got %d and %d", stats.badConns, stats.denied)
+	}
+}
+
+func TestRetryBudgetExhaustedResetSession(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.resetWithPing = true
+	mc.cfg.RetryBudget = NewRetryBudget(0, 1)
+	mc.cfg.RetryBudget.allow()
+	conn.maxReads = 0
+
+	// database/sql only discards the connection for driver.ErrBadConn
+	if err := mc.ResetSession(context.Background()); err != driver.ErrBadConn {
+		t.Errorf("expected ErrBadConn, got %v", err)
+	}
+
+	// the internal error must not reach callers
+	_, mc = newRWMockConn(0)
+	mc.cfg.RetryBudget = NewRetryBudget(0, 1)
+	mc.cfg.RetryBudget.allow()
+	if err := mc.markBadConn(errBadConnNoWrite); err != ErrInvalidConn {
+		t.Errorf("expected ErrInvalidConn, got %v", err)
+	}
+}
@@ -13,18 +13,47 @@ import (
 	"io"
 	"math"
 	"reflect"
+	"strings"
 )
 
+// Rows exposes column metadata not available through *sql.ColumnType.
+//
+// This is accessible by executing queries using sql.Conn.Raw() and
+// downcasting the returned rows:
+//
+//	rows, err := rawConn.(driver.QueryerContext).QueryContext(...)
+//	rows.(mysql.Rows).ColumnTypeGeneratedInvisiblePrimaryKey(0)
+type Rows interface {
+	driver.Rows
+	// ColumnTypeGeneratedInvisiblePrimaryKey reports whether the column is
+	// the invisible primary key generated by the server in GIPK mode
+	// (sql_generate_invisible_primary_key, MySQL 8.0.30+). As the protocol
+	// does not mark invisible columns, it is detected by its definition
+	// while the variable, read when connecting, is enabled. Applications
+	// should not insert values into this column.
+	ColumnTypeGeneratedInvisiblePrimaryKey(index int) bool
+}
+
 type resultSet struct {
 	columns     []mysqlField
 	columnNames []string
 	done        bool
+
+	rowCount int64 // rows read, see Config.maxResultRows
+	rowBytes int64 // size of the rows read, see Config.maxResultBytes
 }
 
 type mysqlRows struct {
 	mc     *mysqlConn
 	rs     resultSet
 	finish func()
+	stmt   *mysqlStmt // closed together with the rows, if set
+
+	warnings MySQLWarnings // set after the last row, see Config.collectWarnings
+	stream   rowStream     // current row read by NextRow, see StreamRows
+
+	// Config.TypeMapper, set for the rows returned to the application only
+	mapper func(field FieldInfo, raw []byte) (driver.Value, error)
 }
 
 type binaryRows struct {
@@ -55,6 +84,19 @@ func (rows *mysqlRows) Columns() []string {
 		}
 	}
 
+	if rows.mc != nil {
+		switch rows.mc.cfg.columnNameCase {
+		case "lower":
+			for i := range columns {
+				columns[i] = strings.ToLower(columns[i])
+			}
+		case "upper":
+			for i := range columns {
+				columns[i] = strings.ToUpper(columns[i])
+			}
+		}
+	}
+
 	rows.rs.columnNames = columns
 	return columns
 }
@@ -63,9 +105,9 @@ func (rows *mysqlRows) ColumnTypeDatabaseTypeName(i int) string {
 	return rows.rs.columns[i].typeDatabaseName()
 }
 
-// func (rows *mysqlRows) ColumnTypeLength(i int) (length int64, ok bool) {
-// 	return int64(rows.rs.columns[i].length), true
-// }
+func (rows *mysqlRows) ColumnTypeLength(i int) (length int64, ok bool) {
+	return rows.rs.columns[i].typeLength()
+}
 
 func (rows *mysqlRows) ColumnTypeNullable(i int) (nullable, ok bool) {
 	return rows.rs.columns[i].flags&flagNotNULL == 0, true
@@ -97,6 +139,18 @@ func (rows *mysqlRows) ColumnTypeScanType(i int) reflect.Type {
 	return rows.rs.columns[i].scanType()
 }
 
+func (rows *mysqlRows) ColumnTypeGeneratedInvisiblePrimaryKey(i int) bool {
+	return rows.rs.columns[i].generatedInvisiblePK
+}
+
+// ColumnTypeDefault returns the default value of the table column of the
+// result set column. ok is false if the column has no default value or if it
+// is unknown, see the columnDefaults DSN parameter.
+func (rows *mysqlRows) ColumnTypeDefault(i int) (value string, ok bool) {
+	column := rows.rs.columns[i]
+	return column.defaultValue, column.hasDefault
+}
+
 func (rows *mysqlRows) Close() (err error) {
 	if f := rows.finish; f != nil {
 		f()
@@ -110,10 +164,13 @@ func (rows *mysqlRows) Close() (err error) {
 	if err := mc.error(); err != nil {
 		return err
 	}
+	defer mc.restoreSessionVars()
 
 	// Remove unread packets from stream
 	if !rows.rs.done {
-		err = mc.readUntilEOF()
+		if err = rows.skipRow(); err == nil {
+			err = mc.readUntilEOF()
+		}
 	}
 	if err == nil {
 		handleOk := mc.clearResult()
@@ -121,6 +178,12 @@ func (rows *mysqlRows) Close() (err error) {
 			return err
 		}
 	}
+	if rows.stmt != nil {
+		if cerr := rows.stmt.Close(); err == nil {
+			err = cerr
+		}
+		rows.stmt = nil
+	}
 
 	rows.mc = nil
 	return err
@@ -143,6 +206,9 @@ func (rows *mysqlRows) nextResultSet() (int, error) {
 
 	// Remove unread packets from stream
 	if !rows.rs.done {
+		if err := rows.skipRow(); err != nil {
+			return 0, err
+		}
 		if err := rows.mc.readUntilEOF(); err != nil {
 			return 0, err
 		}
@@ -150,6 +216,7 @@ func (rows *mysqlRows) nextResultSet() (int, error) {
 	}
 
 	if !rows.HasNextResultSet() {
+		rows.mc.restoreSessionVars()
 		rows.mc = nil
 		return 0, io.EOF
 	}
@@ -197,7 +264,18 @@ func (rows *binaryRows) Next(dest []driver.Value) error {
 		}
 
 		// Fetch next row from stream
-		return rows.readRow(dest)
+		err := rows.readRow(dest)
+		if rows.mc == nil {
+			// the last result set has been read
+			defer mc.restoreSessionVars()
+		}
+		if err == io.EOF && rows.mc == nil {
+			if werr := mc.checkWarnings(); werr != nil {
+				return werr
+			}
+			rows.warnings = mc.warnings
+		}
+		return err
 	}
 	return io.EOF
 }
@@ -219,7 +297,18 @@ func (rows *textRows) Next(dest []driver.Value) error {
 		}
 
 		// Fetch next row from stream
-		return rows.readRow(dest)
+		err := rows.readRow(dest)
+		if rows.mc == nil {
+			// the last result set has been read
+			defer mc.restoreSessionVars()
+		}
+		if err == io.EOF && rows.mc == nil {
+			if werr := mc.checkWarnings(); werr != nil {
+				return werr
+			}
+			rows.warnings = mc.warnings
+		}
+		return err
 	}
 	return io.EOF
 }
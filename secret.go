@@ -0,0 +1,107 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// SecretResolver resolves named secrets at connect time.
+//
+// Once a SecretResolver is registered, a password of the form {secret:NAME}
+// in the DSN or Config.Passwd is not sent to the server as-is. Instead, NAME
+// is passed to the SecretResolver each time a new connection is established
+// and the returned value is used as password. This keeps credentials out of
+// process arguments and configuration files while still allowing DSN based
+// configuration. A literal password of this form is escaped with a second
+// opening brace, i.e. {{secret:NAME} is sent as {secret:NAME}. Without a
+// registered SecretResolver, all passwords are sent as-is.
+//
+// Implementations must be safe for concurrent use.
+type SecretResolver interface {
+	ResolveSecret(ctx context.Context, name string) (string, error)
+}
+
+// SecretResolverFunc is an adapter to allow the use of ordinary functions as
+// SecretResolver.
+type SecretResolverFunc func(ctx context.Context, name string) (string, error)
+
+// ResolveSecret calls f(ctx, name).
+func (f SecretResolverFunc) ResolveSecret(ctx context.Context, name string) (string, error) {
+	return f(ctx, name)
+}
+
+const (
+	secretPrefix = "{secret:"
+	secretSuffix = "}"
+	secretEscape = "{"
+)
+
+var (
+	secretResolverLock sync.RWMutex
+	secretResolver     SecretResolver
+)
+
+// RegisterSecretResolver registers the SecretResolver used to resolve
+// passwords of the form {secret:NAME}. A previously registered resolver is
+// replaced.
+//
+//	mysql.RegisterSecretResolver(mysql.SecretResolverFunc(
+//	    func(ctx context.Context, name string) (string, error) {
+//	        return vault.Read(ctx, "secret/mysql/"+name)
+//	    }))
+//	db, err := sql.Open("mysql", "user:{secret:prod}@tcp(localhost:3306)/dbname")
+func RegisterSecretResolver(resolver SecretResolver) {
+	secretResolverLock.Lock()
+	secretResolver = resolver
+	secretResolverLock.Unlock()
+}
+
+// DeregisterSecretResolver removes the registered SecretResolver.
+func DeregisterSecretResolver() {
+	RegisterSecretResolver(nil)
+}
+
+// secretName returns the name of the secret if passwd has the form
+// {secret:NAME}.
+func secretName(passwd string) (string, bool) {
+	if !strings.HasPrefix(passwd, secretPrefix) || !strings.HasSuffix(passwd, secretSuffix) {
+		return "", false
+	}
+	name := passwd[len(secretPrefix) : len(passwd)-len(secretSuffix)]
+	if name == "" {
+		return "", false
+	}
+	return name, true
+}
+
+// resolveSecret returns the password which is sent instead of passwd, and
+// whether it differs from passwd. Passwords are only replaced if a
+// SecretResolver is registered, see SecretResolver.
+func resolveSecret(ctx context.Context, passwd string) (string, bool, error) {
+	secretResolverLock.RLock()
+	resolver := secretResolver
+	secretResolverLock.RUnlock()
+
+	if resolver == nil {
+		return passwd, false, nil
+	}
+	if name, ok := secretName(passwd); ok {
+		resolved, err := resolver.ResolveSecret(ctx, name)
+		return resolved, true, err
+	}
+	if unescaped := strings.TrimPrefix(passwd, secretEscape); unescaped != passwd {
+		if _, ok := secretName(unescaped); ok {
+			return unescaped, true, nil
+		}
+	}
+	return passwd, false, nil
+}
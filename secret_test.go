@@ -0,0 +1,97 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestSecretName(t *testing.T) {
+	for _, tc := range []struct {
+		passwd string
+		name   string
+		ok     bool
+	}{
+		{"{secret:prod}", "prod", true},
+		{"{secret:a/b:c}", "a/b:c", true},
+		{"{secret:}", "", false},
+		{"secret:prod", "", false},
+		{"{secret:prod", "", false},
+		{"pass{secret:prod}", "", false},
+		{"", "", false},
+	} {
+		name, ok := secretName(tc.passwd)
+		if name != tc.name || ok != tc.ok {
+			t.Errorf("secretName(%q) = %q, %v; want %q, %v", tc.passwd, name, ok, tc.name, tc.ok)
+		}
+	}
+}
+
+func TestConnectResolvesSecret(t *testing.T) {
+	errDial := errors.New("dial not allowed")
+
+	cfg := NewConfig()
+	cfg.Passwd = "{secret:prod}"
+	cfg.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errDial
+	}
+	if err := cfg.normalize(); err != nil {
+		t.Fatal(err)
+	}
+	connector := newConnector(cfg)
+
+	// without a resolver, the password is sent as-is
+	DeregisterSecretResolver()
+	if _, err := connector.Connect(context.Background()); err != errDial {
+		t.Fatalf("expected dial error, got %v", err)
+	}
+
+	var resolved string
+	RegisterSecretResolver(SecretResolverFunc(func(ctx context.Context, name string) (string, error) {
+		resolved = name
+		return "secret", nil
+	}))
+	defer DeregisterSecretResolver()
+
+	if _, err := connector.Connect(context.Background()); err != errDial {
+		t.Fatalf("expected dial error, got %v", err)
+	}
+	if resolved != "prod" {
+		t.Errorf("expected secret 'prod' to be resolved, got %q", resolved)
+	}
+	if cfg.Passwd != "{secret:prod}" {
+		t.Errorf("connector config was modified: %q", cfg.Passwd)
+	}
+
+	for _, tc := range []struct {
+		passwd, expected string
+		ok               bool
+	}{
+		{"{secret:prod}", "secret", true},
+		{"{{secret:prod}", "{secret:prod}", true},
+		{"{{secret:}", "{{secret:}", false},
+		{"{{x}", "{{x}", false},
+	} {
+		passwd, ok, err := resolveSecret(context.Background(), tc.passwd)
+		if err != nil || passwd != tc.expected || ok != tc.ok {
+			t.Errorf("resolveSecret(%q) = %q, %v, %v; want %q, %v", tc.passwd, passwd, ok, err, tc.expected, tc.ok)
+		}
+	}
+
+	errResolve := errors.New("vault sealed")
+	RegisterSecretResolver(SecretResolverFunc(func(ctx context.Context, name string) (string, error) {
+		return "", errResolve
+	}))
+	if _, err := connector.Connect(context.Background()); err != errResolve {
+		t.Fatalf("expected resolver error, got %v", err)
+	}
+}
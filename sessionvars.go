@@ -0,0 +1,114 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type sessionVarsKey struct{}
+
+type sessionVar struct {
+	name, value string
+}
+
+// WithSessionVars returns a copy of ctx which sets session system variables
+// for the statements executed with it, e.g. a different sql_mode or
+// optimizer_switch for a single query. The values are SQL expressions like
+// the system variables of the DSN, so strings must be quoted:
+//
+//	ctx = mysql.WithSessionVars(ctx, map[string]string{"sql_mode": "'ANSI'"})
+//
+// The variables are set before the statement and their previous values are
+// restored after it, for queries when the last row has been read or the rows
+// are closed. This costs two round trips. If the previous values can not be restored, the connection
+// is closed.
+func WithSessionVars(ctx context.Context, vars map[string]string) context.Context {
+	sv := make([]sessionVar, 0, len(vars))
+	for name, value := range vars {
+		sv = append(sv, sessionVar{name, value})
+	}
+	sort.Slice(sv, func(i, j int) bool { return sv[i].name < sv[j].name })
+	return context.WithValue(ctx, sessionVarsKey{}, sv)
+}
+
+// savedVarName returns the user variable holding the previous value of the
+// i-th session variable.
+func savedVarName(i int) string {
+	return "@__go_mysql_saved_" + strconv.Itoa(i)
+}
+
+func isVarName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; !isIdentByte(c) && c != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// setSessionVars sets the session variables of ctx, saving their previous
+// values in user variables. They must be restored with restoreSessionVars.
+func (mc *mysqlConn) setSessionVars(ctx context.Context) error {
+	vars, _ := ctx.Value(sessionVarsKey{}).([]sessionVar)
+	if len(vars) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString("SET ")
+	for i, v := range vars {
+		if !isVarName(v.name) {
+			return fmt.Errorf("mysql: invalid session variable name %q", v.name)
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(savedVarName(i) + " = @@SESSION." + v.name)
+	}
+	for _, v := range vars {
+		b.WriteString(", SESSION " + v.name + " = " + v.value)
+	}
+	if err := mc.exec(b.String()); err != nil {
+		return err
+	}
+	mc.sessionVars = vars
+	return nil
+}
+
+// restoreSessionVars restores the session variables set by setSessionVars.
+// The connection is closed if that fails.
+func (mc *mysqlConn) restoreSessionVars() {
+	vars := mc.sessionVars
+	if len(vars) == 0 {
+		return
+	}
+	mc.sessionVars = nil
+	if mc.closed.Load() {
+		return
+	}
+	var b strings.Builder
+	b.WriteString("SET ")
+	for i, v := range vars {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("SESSION " + v.name + " = " + savedVarName(i) + ", " + savedVarName(i) + " = NULL")
+	}
+	if err := mc.exec(b.String()); err != nil {
+		mc.log("restoring session variables: ", err)
+		mc.cleanup()
+	}
+}
@@ -0,0 +1,121 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// writtenQueries returns the statements of the COM_QUERY packets in b.
+func writtenQueries(b []byte) []string {
+	var queries []string
+	for len(b) >= 5 {
+		n := 4 + getUint24(b[:3])
+		if b[4] == byte(comQuery) {
+			queries = append(queries, string(b[5:n]))
+		}
+		b = b[n:]
+	}
+	return queries
+}
+
+func TestWithSessionVars(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	ok := mockPacket(1, []byte{iOK, 0, 0, 2, 0, 0, 0})
+	conn.queuedReplies = [][]byte{ok, mockPacket(1, []byte{iOK, 1, 0, 2, 0, 0, 0}), ok}
+
+	ctx := WithSessionVars(context.Background(), map[string]string{
+		"sql_mode":           "'ANSI'",
+		"max_execution_time": "1000",
+	})
+	res, err := mc.ExecContext(ctx, "UPDATE t SET a = 1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := res.RowsAffected(); n != 1 {
+		t.Errorf("expected 1 affected row, got %d", n)
+	}
+	expected := []string{
+		"SET @__go_mysql_saved_0 = @@SESSION.max_execution_time, @__go_mysql_saved_1 = @@SESSION.sql_mode, SESSION max_execution_time = 1000, SESSION sql_mode = 'ANSI'",
+		"UPDATE t SET a = 1",
+		"SET SESSION max_execution_time = @__go_mysql_saved_0, @__go_mysql_saved_0 = NULL, SESSION sql_mode = @__go_mysql_saved_1, @__go_mysql_saved_1 = NULL",
+	}
+	if queries := writtenQueries(conn.written); !reflect.DeepEqual(queries, expected) {
+		t.Errorf("unexpected queries:\n%q\nexpected:\n%q", queries, expected)
+	}
+	if mc.sessionVars != nil {
+		t.Error("session variables not cleared")
+	}
+
+	// without session variables nothing is sent
+	conn.written = nil
+	conn.queuedReplies = [][]byte{ok}
+	if _, err := mc.ExecContext(context.Background(), "DO 1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if queries := writtenQueries(conn.written); !reflect.DeepEqual(queries, []string{"DO 1"}) {
+		t.Errorf("unexpected queries %q", queries)
+	}
+
+	// queries restore the variables when the rows are closed
+	conn.written = nil
+	resp := mockPacket(1, []byte{1})
+	resp = append(resp, mockColumn(2, "1", fieldTypeLongLong)...)
+	resp = append(resp, mockPacket(3, []byte{iEOF, 0, 0, 2, 0})...)
+	resp = append(resp, mockPacket(4, []byte{1, '1'})...)
+	resp = append(resp, mockPacket(5, []byte{iEOF, 0, 0, 2, 0})...)
+	conn.queuedReplies = [][]byte{ok, resp, ok}
+	ctx = WithSessionVars(context.Background(), map[string]string{"sql_mode": "''"})
+	rows, err := mc.QueryContext(ctx, "SELECT 1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(writtenQueries(conn.written)); n != 2 {
+		t.Errorf("expected 2 queries before closing the rows, got %d", n)
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if queries := writtenQueries(conn.written); len(queries) != 3 || queries[2] != "SET SESSION sql_mode = @__go_mysql_saved_0, @__go_mysql_saved_0 = NULL" {
+		t.Errorf("unexpected queries %q", queries)
+	}
+
+	// and when the last row has been read
+	conn.written = nil
+	conn.queuedReplies = [][]byte{ok, resp, ok}
+	rows, err = mc.QueryContext(ctx, "SELECT 1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := make([]driver.Value, 1)
+	if err := rows.Next(dest); err != nil {
+		t.Fatal(err)
+	}
+	if err := rows.Next(dest); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+	if queries := writtenQueries(conn.written); len(queries) != 3 || queries[2] != "SET SESSION sql_mode = @__go_mysql_saved_0, @__go_mysql_saved_0 = NULL" {
+		t.Errorf("unexpected queries %q", queries)
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if mc.sessionVars != nil || len(writtenQueries(conn.written)) != 3 {
+		t.Error("session variables not restored exactly once")
+	}
+
+	ctx = WithSessionVars(context.Background(), map[string]string{"sql_mode = '', a": "1"})
+	if _, err := mc.ExecContext(ctx, "DO 1", nil); err == nil {
+		t.Error("expected error for invalid variable name")
+	}
+}
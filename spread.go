@@ -0,0 +1,109 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+)
+
+var (
+	errSpreadPlaceholders = errors.New("mysql: number of placeholders does not match the number of arguments")
+	errSpreadStmt         = errors.New("mysql: Spread is not supported by prepared statements")
+)
+
+// SpreadArg is a list of query arguments created by Spread.
+type SpreadArg struct {
+	values []driver.Value
+}
+
+// Spread wraps a slice so that it can be passed as a single query argument.
+// The placeholder of the argument is expanded into a comma-separated list of
+// placeholders, one for each element of the slice:
+//
+//	db.Query("SELECT * FROM users WHERE id IN (?)", mysql.Spread(ids))
+//
+// An empty slice expands to NULL, so that "IN (?)" matches no rows.
+//
+// Spread is supported by DB.Exec, DB.Query and their Tx and Conn
+// counterparts, but not by prepared statements, since the number of
+// placeholders is fixed when the statement is prepared.
+func Spread[T any](values []T) SpreadArg {
+	s := SpreadArg{values: make([]driver.Value, len(values))}
+	for i, v := range values {
+		s.values[i] = v
+	}
+	return s
+}
+
+// checkSpreadArg converts the values of s like the other arguments.
+func (c converter) checkSpreadArg(s SpreadArg) (SpreadArg, error) {
+	values := make([]driver.Value, len(s.values))
+	for i, v := range s.values {
+		cv, err := c.ConvertValue(v)
+		if err != nil {
+			return s, err
+		}
+		values[i] = cv
+	}
+	return SpreadArg{values: values}, nil
+}
+
+// expandSpread replaces the placeholder of every SpreadArg in args with one
+// placeholder per value and returns the flattened arguments. It reports
+// whether the query has been rewritten. Question marks in string literals and
+// comments are not placeholders, see nextPlaceholder.
+func expandSpread(query string, args []driver.Value, noBackslashEscapes bool) (string, []driver.Value, bool, error) {
+	n, spread := 0, false
+	for _, arg := range args {
+		if s, ok := arg.(SpreadArg); ok {
+			n += len(s.values)
+			spread = true
+		} else {
+			n++
+		}
+	}
+	if !spread {
+		return query, args, false, nil
+	}
+	if countPlaceholders(query, noBackslashEscapes) != len(args) {
+		return "", nil, false, errSpreadPlaceholders
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 2*n)
+	expanded := make([]driver.Value, 0, n)
+	argPos := 0
+	for i := 0; i < len(query); i++ {
+		q := nextPlaceholder(query, i, noBackslashEscapes)
+		if q == -1 {
+			b.WriteString(query[i:])
+			break
+		}
+		b.WriteString(query[i:q])
+		i = q
+
+		s, ok := args[argPos].(SpreadArg)
+		argPos++
+		if !ok {
+			b.WriteByte('?')
+			expanded = append(expanded, args[argPos-1])
+			continue
+		}
+		if len(s.values) == 0 {
+			b.WriteString("NULL")
+			continue
+		}
+		b.WriteByte('?')
+		b.WriteString(strings.Repeat(",?", len(s.values)-1))
+		expanded = append(expanded, s.values...)
+	}
+	return b.String(), expanded, true, nil
+}
@@ -0,0 +1,96 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExpandSpread(t *testing.T) {
+	for _, tc := range []struct {
+		query    string
+		args     []driver.Value
+		expected string
+		values   []driver.Value
+		spread   bool
+	}{
+		{"SELECT ?", []driver.Value{int64(1)}, "SELECT ?", []driver.Value{int64(1)}, false},
+		{
+			"SELECT * FROM t WHERE a = ? AND id IN (?) AND b = ?",
+			[]driver.Value{"x", Spread([]int64{1, 2, 3}), "y"},
+			"SELECT * FROM t WHERE a = ? AND id IN (?,?,?) AND b = ?",
+			[]driver.Value{"x", int64(1), int64(2), int64(3), "y"},
+			true,
+		},
+		{"SELECT ? IN (?)", []driver.Value{int64(1), Spread([]int64{2})}, "SELECT ? IN (?)", []driver.Value{int64(1), int64(2)}, true},
+		{"SELECT 1 IN (?)", []driver.Value{Spread([]string{})}, "SELECT 1 IN (NULL)", []driver.Value{}, true},
+	} {
+		query, values, spread, err := expandSpread(tc.query, tc.args, false)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.query, err)
+			continue
+		}
+		if query != tc.expected || spread != tc.spread || !reflect.DeepEqual(values, tc.values) {
+			t.Errorf("%q: got %q %v %v, want %q %v %v", tc.query, query, values, spread, tc.expected, tc.values, tc.spread)
+		}
+	}
+
+	if _, _, _, err := expandSpread("SELECT ? IN (?)", []driver.Value{Spread([]int{1, 2})}, false); err != errSpreadPlaceholders {
+		t.Errorf("expected errSpreadPlaceholders, got %v", err)
+	}
+}
+
+func TestCheckNamedValueSpread(t *testing.T) {
+	mc := &mysqlConn{cfg: NewConfig()}
+	nv := driver.NamedValue{Value: Spread([]int{1, 2})}
+	if err := mc.CheckNamedValue(&nv); err != nil {
+		t.Fatal(err)
+	}
+	expected := SpreadArg{values: []driver.Value{int64(1), int64(2)}}
+	if !reflect.DeepEqual(nv.Value, expected) {
+		t.Errorf("expected %v, got %v", expected, nv.Value)
+	}
+
+	// the values are converted with the settings of the connection
+	mc.cfg.parseDuration = true
+	nv = driver.NamedValue{Value: Spread([]time.Duration{time.Second})}
+	if err := mc.CheckNamedValue(&nv); err != nil {
+		t.Fatal(err)
+	}
+	expected = SpreadArg{values: []driver.Value{time.Second}}
+	if !reflect.DeepEqual(nv.Value, expected) {
+		t.Errorf("expected %v, got %v", expected, nv.Value)
+	}
+
+	nv = driver.NamedValue{Value: Spread([]int{1})}
+	if err := (&mysqlStmt{}).CheckNamedValue(&nv); err != errSpreadStmt {
+		t.Errorf("expected errSpreadStmt, got %v", err)
+	}
+}
+
+func TestExecContextSpreadInterpolated(t *testing.T) {
+	conn, mc := newRWMockConn(1)
+	mc.cfg.InterpolateParams = true
+	conn.data = []byte{7, 0, 0, 1, 0, 3, 0, 2, 0, 0, 0}
+	conn.maxReads = 1
+
+	args := []driver.NamedValue{{Ordinal: 1, Value: SpreadArg{values: []driver.Value{int64(1), int64(2), int64(3)}}}}
+	if _, err := mc.ExecContext(context.Background(), "DELETE FROM t WHERE id IN (?)", args); err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte("DELETE FROM t WHERE id IN (1,2,3)")
+	if !bytes.Equal(conn.written[5:], expected) {
+		t.Errorf("expected query %q, got %q", expected, conn.written[5:])
+	}
+}
@@ -9,17 +9,25 @@
 package mysql
 
 import (
+	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"reflect"
+	"time"
 )
 
 type mysqlStmt struct {
 	mc         *mysqlConn
 	id         uint32
 	paramCount int
+	sql        string
+	cached     bool         // owned by the statement cache of the connection
+	columns    []mysqlField // result set columns with their default values, see Config.columnDefaults
+	defaults   bool         // the default values of columns have been loaded
 }
 
 func (stmt *mysqlStmt) Close() error {
@@ -32,30 +40,112 @@ func (stmt *mysqlStmt) Close() error {
 		return nil
 	}
 
+	stmt.mc.openStmts--
+
+	// The statement is kept prepared for the lifetime of the connection
+	if stmt.cached {
+		stmt.mc = nil
+		return nil
+	}
+
 	err := stmt.mc.writeCommandPacketUint32(comStmtClose, stmt.id)
 	stmt.mc = nil
 	return err
 }
 
+// Errors which require a statement to be prepared again, e.g. after a table
+// was altered or after a proxy failed over to another server.
+const (
+	errUnknownStmtHandler = 1243 // ER_UNKNOWN_STMT_HANDLER
+	errNeedReprepare      = 1615 // ER_NEED_REPREPARE
+)
+
+// needsReprepare reports whether err requires the statement to be prepared
+// again.
+func needsReprepare(err error) bool {
+	var mysqlErr *MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	return mysqlErr.Number == errNeedReprepare || mysqlErr.Number == errUnknownStmtHandler
+}
+
+// reprepare prepares the statement again after the server returned err for
+// it. The statement keeps its identity for the caller, only the statement id
+// changes. The old statement is closed unless it is unknown to the server.
+func (stmt *mysqlStmt) reprepare(err error) error {
+	mc := stmt.mc
+	if mc.closed.Load() {
+		return ErrInvalidConn
+	}
+	if err := mc.writeCommandPacketStr(comStmtPrepare, mc.rewriteQuery(stmt.sql)); err != nil {
+		return err
+	}
+	fresh := &mysqlStmt{mc: mc, sql: stmt.sql}
+	if err := fresh.readPrepareResult(); err != nil {
+		return err
+	}
+	if fresh.paramCount != stmt.paramCount {
+		// the statement changed in a way the caller can not handle
+		if cerr := mc.writeCommandPacketUint32(comStmtClose, fresh.id); cerr != nil {
+			return cerr
+		}
+		return err
+	}
+
+	// the default values are loaded again before the next execution
+	oldID := stmt.id
+	stmt.id, stmt.columns, stmt.defaults = fresh.id, fresh.columns, false
+	if prewarmed := mc.stmtCache[stmt.sql]; prewarmed != nil && prewarmed.id == oldID {
+		prewarmed.id, prewarmed.columns, prewarmed.defaults = fresh.id, fresh.columns, false
+	}
+	var mysqlErr *MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errUnknownStmtHandler {
+		return nil
+	}
+	return mc.writeCommandPacketUint32(comStmtClose, oldID)
+}
+
 func (stmt *mysqlStmt) NumInput() int {
 	return stmt.paramCount
 }
 
 func (stmt *mysqlStmt) ColumnConverter(idx int) driver.ValueConverter {
-	return converter{}
+	return stmt.mc.converter()
 }
 
 func (stmt *mysqlStmt) CheckNamedValue(nv *driver.NamedValue) (err error) {
-	nv.Value, err = converter{}.ConvertValue(nv.Value)
+	if _, ok := nv.Value.(SpreadArg); ok {
+		return errSpreadStmt
+	}
+	if out, ok := nv.Value.(sql.Out); ok {
+		return stmt.mc.converter().checkOutArg(out)
+	}
+	nv.Value, err = stmt.mc.converter().ConvertValue(nv.Value)
 	return
 }
 
 func (stmt *mysqlStmt) Exec(args []driver.Value) (driver.Result, error) {
+	res, err := stmt.execOnce(args)
+	if needsReprepare(err) && stmt.reprepare(err) == nil {
+		res, err = stmt.execOnce(args)
+	}
+	return res, err
+}
+
+func (stmt *mysqlStmt) execOnce(args []driver.Value) (driver.Result, error) {
 	if stmt.mc.closed.Load() {
 		return nil, driver.ErrBadConn
 	}
+	args, outs, err := stmt.mc.converter().splitOutArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := stmt.mc.audit(stmt.sql, args); err != nil {
+		return nil, err
+	}
 	// Send command
-	err := stmt.writeExecutePacket(args)
+	err = stmt.writeExecutePacket(args)
 	if err != nil {
 		return nil, stmt.mc.markBadConn(err)
 	}
@@ -69,9 +159,13 @@ func (stmt *mysqlStmt) Exec(args []driver.Value) (driver.Result, error) {
 		return nil, err
 	}
 
-	if resLen > 0 {
+	if outs != nil {
+		if err := stmt.readExecResults(handleOk, resLen, outs); err != nil {
+			return nil, err
+		}
+	} else if resLen > 0 {
 		// Columns
-		if err = mc.readUntilEOF(); err != nil {
+		if err = mc.skipColumns(resLen); err != nil {
 			return nil, err
 		}
 
@@ -86,6 +180,10 @@ func (stmt *mysqlStmt) Exec(args []driver.Value) (driver.Result, error) {
 	}
 
 	copied := mc.result
+	if err := mc.checkWarnings(); err != nil {
+		return nil, err
+	}
+	copied.warnings = mc.warnings
 	return &copied, nil
 }
 
@@ -93,10 +191,30 @@ func (stmt *mysqlStmt) Query(args []driver.Value) (driver.Rows, error) {
 	return stmt.query(args)
 }
 
+// query executes the statement and returns its rows. The error which requires
+// the statement to be prepared again is returned before any row, so the
+// statement can be executed again transparently.
 func (stmt *mysqlStmt) query(args []driver.Value) (*binaryRows, error) {
+	rows, err := stmt.queryOnce(args)
+	if needsReprepare(err) && stmt.reprepare(err) == nil {
+		rows, err = stmt.queryOnce(args)
+	}
+	return rows, err
+}
+
+func (stmt *mysqlStmt) queryOnce(args []driver.Value) (*binaryRows, error) {
 	if stmt.mc.closed.Load() {
 		return nil, driver.ErrBadConn
 	}
+	if hasOutArgs(args) {
+		return nil, errOutQuery
+	}
+	if err := stmt.mc.audit(stmt.sql, args); err != nil {
+		return nil, err
+	}
+	if err := stmt.loadColumnDefaults(); err != nil {
+		return nil, err
+	}
 	// Send command
 	err := stmt.writeExecutePacket(args)
 	if err != nil {
@@ -113,10 +231,12 @@ func (stmt *mysqlStmt) query(args []driver.Value) (*binaryRows, error) {
 	}
 
 	rows := new(binaryRows)
+	rows.mapper = mc.cfg.TypeMapper
 
 	if resLen > 0 {
 		rows.mc = mc
 		rows.rs.columns, err = mc.readColumns(resLen)
+		stmt.copyColumnDefaults(rows.rs.columns)
 	} else {
 		rows.rs.done = true
 
@@ -133,17 +253,48 @@ func (stmt *mysqlStmt) query(args []driver.Value) (*binaryRows, error) {
 
 var jsonType = reflect.TypeOf(json.RawMessage{})
 
-type converter struct{}
+type converter struct {
+	parseJSON     bool // marshal maps, structs, arrays and slices to JSON
+	parseDuration bool // keep time.Duration, which is sent as TIME
+}
 
 // ConvertValue mirrors the reference/default converter in database/sql/driver
 // with _one_ exception.  We support uint64 with their high bit and the default
 // implementation does not.  This function should be kept in sync with
 // database/sql/driver defaultConverter.ConvertValue() except for that
-// deliberate difference.
+// deliberate difference. If parseJSON is set, values which the default
+// converter rejects are marshaled to JSON instead. If parseDuration is set,
+// time.Duration is kept instead of being converted to int64.
 func (c converter) ConvertValue(v any) (driver.Value, error) {
 	if driver.IsValue(v) {
 		return v, nil
 	}
+	if d, ok := v.(time.Duration); ok && c.parseDuration {
+		return d, nil
+	}
+	switch v := v.(type) {
+	case Decimal, float32:
+		// kept to be sent without loss of precision
+		return v, nil
+	case NullDecimal:
+		if !v.Valid {
+			return nil, nil
+		}
+		return v.Decimal, nil
+	case *big.Int:
+		if v == nil {
+			return nil, nil
+		}
+		return Decimal{s: v.String()}, nil
+	case *big.Float:
+		if v == nil {
+			return nil, nil
+		}
+		if v.IsInf() {
+			return nil, errors.New("infinite *big.Float")
+		}
+		return Decimal{s: v.Text('f', -1)}, nil
+	}
 
 	if vr, ok := v.(driver.Valuer); ok {
 		sv, err := callValuerValue(vr)
@@ -174,7 +325,9 @@ func (c converter) ConvertValue(v any) (driver.Value, error) {
 		return rv.Int(), nil
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		return rv.Uint(), nil
-	case reflect.Float32, reflect.Float64:
+	case reflect.Float32:
+		return float32(rv.Float()), nil
+	case reflect.Float64:
 		return rv.Float(), nil
 	case reflect.Bool:
 		return rv.Bool(), nil
@@ -184,15 +337,30 @@ func (c converter) ConvertValue(v any) (driver.Value, error) {
 			return v, nil
 		case t.Elem().Kind() == reflect.Uint8:
 			return rv.Bytes(), nil
+		case c.parseJSON:
+			return marshalJSON(v)
 		default:
 			return nil, fmt.Errorf("unsupported type %T, a slice of %s", v, t.Elem().Kind())
 		}
 	case reflect.String:
 		return rv.String(), nil
+	case reflect.Map, reflect.Struct, reflect.Array:
+		if c.parseJSON {
+			return marshalJSON(v)
+		}
 	}
 	return nil, fmt.Errorf("unsupported type %T, a %s", v, rv.Kind())
 }
 
+// marshalJSON marshals an argument to JSON if parseJSON is enabled.
+func marshalJSON(v any) (driver.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("can not marshal %T to JSON: %w", v, err)
+	}
+	return json.RawMessage(b), nil
+}
+
 var valuerReflectType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
 
 // callValuerValue returns vr.Value(), with one exception:
@@ -149,3 +149,87 @@ func TestConvertJSON(t *testing.T) {
 		t.Fatalf("json.RawMessage converted, got %#v %T", out, out)
 	}
 }
+
+func TestConvertParseJSON(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+	tests := []struct {
+		in   any
+		want string
+	}{
+		{map[string]int{"a": 1}, `{"a":1}`},
+		{point{1, 2}, `{"X":1,"Y":2}`},
+		{&point{3, 4}, `{"X":3,"Y":4}`},
+		{[]string{"a", "b"}, `["a","b"]`},
+		{[2]int{1, 2}, `[1,2]`},
+	}
+	for _, tt := range tests {
+		if _, err := (converter{}).ConvertValue(tt.in); err == nil {
+			t.Errorf("%T converted without parseJSON", tt.in)
+		}
+		out, err := converter{parseJSON: true}.ConvertValue(tt.in)
+		if err != nil {
+			t.Fatalf("%T: %v", tt.in, err)
+		}
+		raw, ok := out.(json.RawMessage)
+		if !ok || string(raw) != tt.want {
+			t.Errorf("%T: expected %s, got %#v", tt.in, tt.want, out)
+		}
+	}
+
+	// []byte is not marshaled
+	out, err := converter{parseJSON: true}.ConvertValue([]byte("abc"))
+	if err != nil || !bytes.Equal(out.([]byte), []byte("abc")) {
+		t.Errorf("unexpected conversion of []byte: %#v, %v", out, err)
+	}
+
+	if _, err := (converter{parseJSON: true}).ConvertValue(map[string]any{"f": func() {}}); err == nil {
+		t.Error("expected marshal error")
+	}
+}
+
+func TestStmtReprepare(t *testing.T) {
+	tests := []struct {
+		errno    uint16
+		expected []byte // commands after the first execution
+	}{
+		{errNeedReprepare, []byte{comStmtExecute, comStmtPrepare, comStmtClose, comStmtExecute}},
+		{errUnknownStmtHandler, []byte{comStmtExecute, comStmtPrepare, comStmtExecute}},
+	}
+	for _, tt := range tests {
+		conn, mc := newRWMockConn(0)
+		conn.queuedReplies = [][]byte{mockErr(1, tt.errno, "statement invalidated"), mockPrepareOK(9)}
+		if tt.errno == errNeedReprepare {
+			conn.queuedReplies = append(conn.queuedReplies, []byte{})
+		}
+		conn.queuedReplies = append(conn.queuedReplies, okPacket)
+
+		stmt := &mysqlStmt{mc: mc, id: 1, paramCount: 1, sql: "UPDATE t SET a = ?"}
+		if _, err := stmt.Exec([]driver.Value{int64(1)}); err != nil {
+			t.Fatalf("%d: %v", tt.errno, err)
+		}
+		if stmt.id != 9 {
+			t.Errorf("%d: expected the new statement id, got %d", tt.errno, stmt.id)
+		}
+		if got := writtenCommands(conn.written); !bytes.Equal(got, tt.expected) {
+			t.Errorf("%d: expected commands %v, got %v", tt.errno, tt.expected, got)
+		}
+	}
+}
+
+func TestStmtReprepareOnce(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	invalidated := mockErr(1, errUnknownStmtHandler, "Unknown prepared statement handler")
+	conn.queuedReplies = [][]byte{invalidated, mockPrepareOK(9), invalidated}
+
+	stmt := &mysqlStmt{mc: mc, id: 1, paramCount: 1, sql: "SELECT ?"}
+	_, err := stmt.query([]driver.Value{int64(1)})
+	if !needsReprepare(err) {
+		t.Fatalf("expected the error of the second execution, got %v", err)
+	}
+	expected := []byte{comStmtExecute, comStmtPrepare, comStmtExecute}
+	if got := writtenCommands(conn.written); !bytes.Equal(got, expected) {
+		t.Errorf("expected commands %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,96 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// StatsCollector receives statistics about the connections and the protocol
+// traffic of the driver, e.g. to export them as OpenTelemetry metrics.
+//
+// The methods are called synchronously by the connection code, possibly from
+// many connections at the same time. Implementations must be safe for
+// concurrent use and should return quickly.
+type StatsCollector interface {
+	// ConnOpened is called when a network connection to the server has
+	// been established.
+	ConnOpened()
+
+	// ConnClosed is called when a network connection has been closed.
+	ConnClosed()
+
+	// Handshake is called when a connection is ready to use. d is the time
+	// spent for dialing, authentication and session setup.
+	Handshake(d time.Duration)
+
+	// PacketRead is called for every packet read from the server with the
+	// size of its payload.
+	PacketRead(size int)
+
+	// PacketWritten is called for every packet written to the server with
+	// the size of its payload.
+	PacketWritten(size int)
+
+	// BytesCompressed is called for every compressed packet written to the
+	// server with the sizes before and after the compression.
+	BytesCompressed(uncompressed, compressed int)
+
+	// BadConn is called when driver.ErrBadConn is returned because the
+	// connection is broken, so that database/sql retries on a new one.
+	BadConn()
+}
+
+// CloseReasonCollector can be implemented by a StatsCollector to be notified
+// why a broken connection was closed.
+type CloseReasonCollector interface {
+	// ConnClosedReason is called after ConnClosed if the connection was
+	// closed because it was found broken, e.g. by the liveness check of
+	// CheckConnLiveness. reason is a *MySQLError (possibly wrapped) if the
+	// server sent an error while the connection was idle, e.g. 1053 (server
+	// shutdown in progress).
+	ConnClosedReason(reason error)
+}
+
+// FallbackCollector can be implemented by a StatsCollector to count the
+// statements which are prepared by database/sql because their arguments can
+// not be interpolated (see InterpolateParams) or sent as query attributes
+// (see QueryAttributeParams), e.g. because of an unsupported argument type, a
+// mismatch of placeholders and arguments or a query longer than
+// max_allowed_packet.
+type FallbackCollector interface {
+	// PrepareFallback is called when a statement falls back to a prepared
+	// statement, or fails with ErrInterpolation if strictInterpolation is
+	// set.
+	PrepareFallback()
+}
+
+// badConn reports a broken connection to the StatsCollector and returns
+// driver.ErrBadConn, so that database/sql retries. cause is returned instead
+// if the Config.RetryBudget is exhausted.
+func (mc *mysqlConn) badConn(cause error) error {
+	if !mc.retryAllowed() {
+		return cause
+	}
+	if s := mc.cfg.StatsCollector; s != nil {
+		s.BadConn()
+	}
+	return driver.ErrBadConn
+}
+
+// discardConn reports a broken connection to the StatsCollector and returns
+// driver.ErrBadConn regardless of the Config.RetryBudget. It is used by
+// ResetSession, since database/sql reuses the connection for any other error.
+func (mc *mysqlConn) discardConn() error {
+	if s := mc.cfg.StatsCollector; s != nil {
+		s.BadConn()
+	}
+	return driver.ErrBadConn
+}
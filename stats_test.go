@@ -0,0 +1,99 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+type testStatsCollector struct {
+	opened, closed, handshakes, badConns int
+	read, written                        []int
+	uncompressed, compressed             int
+}
+
+func (s *testStatsCollector) ConnOpened()               { s.opened++ }
+func (s *testStatsCollector) ConnClosed()               { s.closed++ }
+func (s *testStatsCollector) Handshake(d time.Duration) { s.handshakes++ }
+func (s *testStatsCollector) PacketRead(size int)       { s.read = append(s.read, size) }
+func (s *testStatsCollector) PacketWritten(size int)    { s.written = append(s.written, size) }
+func (s *testStatsCollector) BadConn()                  { s.badConns++ }
+func (s *testStatsCollector) BytesCompressed(uncompressed, compressed int) {
+	s.uncompressed += uncompressed
+	s.compressed += compressed
+}
+
+func TestStatsCollector(t *testing.T) {
+	stats := &testStatsCollector{}
+	conn, mc := newRWMockConn(0)
+	mc.cfg.StatsCollector = stats
+	mc.rawConn = conn
+	conn.queuedReplies = [][]byte{{7, 0, 0, 1, 0, 0, 0, 2, 0, 0, 0}}
+	conn.maxReads = 1
+
+	if err := mc.exec("DO 1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.written) != 1 || stats.written[0] != 5 {
+		t.Errorf("unexpected packets written: %v", stats.written)
+	}
+	if len(stats.read) != 1 || stats.read[0] != 7 {
+		t.Errorf("unexpected packets read: %v", stats.read)
+	}
+
+	if err := mc.markBadConn(errBadConnNoWrite); err != driver.ErrBadConn {
+		t.Errorf("expected ErrBadConn, got %v", err)
+	}
+	if stats.badConns != 1 {
+		t.Errorf("expected 1 bad connection, got %d", stats.badConns)
+	}
+
+	mc.cleanup()
+	mc.cleanup()
+	if stats.closed != 1 {
+		t.Errorf("expected 1 closed connection, got %d", stats.closed)
+	}
+}
+
+type testFallbackCollector struct {
+	testStatsCollector
+	fallbacks int
+}
+
+func (s *testFallbackCollector) PrepareFallback() { s.fallbacks++ }
+
+func TestPrepareFallback(t *testing.T) {
+	stats := &testFallbackCollector{}
+	_, mc := newRWMockConn(0)
+	mc.cfg.StatsCollector = stats
+	mc.cfg.InterpolateParams = true
+
+	// the placeholders do not match the arguments
+	if _, err := mc.Exec("SELECT ?, ?", []driver.Value{int64(1)}); err != driver.ErrSkip {
+		t.Errorf("expected ErrSkip, got %v", err)
+	}
+	mc.cfg.strictInterpolation = true
+	if _, err := mc.query("SELECT ?, ?", []driver.Value{int64(1)}); err != ErrInterpolation {
+		t.Errorf("expected ErrInterpolation, got %v", err)
+	}
+	if stats.fallbacks != 2 {
+		t.Errorf("expected 2 fallbacks, got %d", stats.fallbacks)
+	}
+
+	// statements are prepared without interpolation, which is no fallback
+	mc.cfg.InterpolateParams = false
+	if _, err := mc.Exec("SELECT ?", []driver.Value{int64(1)}); err != driver.ErrSkip {
+		t.Errorf("expected ErrSkip, got %v", err)
+	}
+	if stats.fallbacks != 2 {
+		t.Errorf("expected 2 fallbacks, got %d", stats.fallbacks)
+	}
+}
@@ -0,0 +1,181 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"container/list"
+	"database/sql/driver"
+)
+
+// maxPipelinedPrepares is the maximum number of COM_STMT_PREPARE commands
+// which are sent before reading the responses.
+const maxPipelinedPrepares = 16
+
+// prewarmStatements prepares the statements configured with PrewarmStatements
+// and keeps them in the statement cache of the connection.
+// The commands are sent in batches without waiting for the responses in
+// between, so the whole list costs only a few round trips. Nothing is
+// prepared with Config.QueryRewriter, which is called per operation.
+func (mc *mysqlConn) prewarmStatements() error {
+	mc.stmtSchema = mc.schema
+	if mc.cfg.QueryRewriter != nil {
+		return nil
+	}
+	queries := mc.cfg.prewarmStmts
+	for len(queries) > 0 {
+		n := min(len(queries), maxPipelinedPrepares)
+		if mc.compress {
+			// the compressed protocol requires the sequence numbers to be
+			// synchronized after each command
+			n = 1
+		}
+		batch := make([]string, n)
+		for i, query := range queries[:n] {
+			batch[i] = addQueryHints(query, mc.cfg.queryHints)
+			if err := mc.writeCommandPacketStr(comStmtPrepare, batch[i]); err != nil {
+				return err
+			}
+		}
+		queries = queries[n:]
+
+		for _, query := range batch {
+			// each response starts with sequence number 1
+			if !mc.compress {
+				mc.sequence = 1
+			}
+			stmt := &mysqlStmt{mc: mc}
+			if err := stmt.readPrepareResult(); err != nil {
+				if _, ok := err.(*MySQLError); ok {
+					mc.log("prewarming statement failed: ", err)
+					continue
+				}
+				return err
+			}
+			if mc.stmtCache == nil {
+				mc.stmtCache = make(map[string]*mysqlStmt)
+			}
+			stmt.cached = true
+			mc.stmtCache[query] = stmt
+		}
+	}
+	return nil
+}
+
+// closeStaleStmts closes the cached statements if the default database
+// changed since they were prepared, e.g. by a USE statement tracked with
+// Config.trackSchema or a transaction started with WithTxSchema, and prepares the statements of PrewarmStatements again.
+// A prepared statement keeps resolving unqualified names in the database it
+// was prepared in, so it would not match the query in the new database.
+// Statements returned by Prepare which shared a closed statement are prepared
+// again by the server error of their next execution, see reprepare.
+func (mc *mysqlConn) closeStaleStmts() error {
+	if mc.schema == mc.stmtSchema {
+		return nil
+	}
+	for _, stmt := range mc.stmtCache {
+		if err := mc.writeCommandPacketUint32(comStmtClose, stmt.id); err != nil {
+			return err
+		}
+	}
+	if mc.stmtLRU != nil {
+		for e := mc.stmtLRU.ll.Front(); e != nil; e = e.Next() {
+			if err := mc.writeCommandPacketUint32(comStmtClose, e.Value.(*mysqlStmt).id); err != nil {
+				return err
+			}
+		}
+	}
+	mc.stmtCache = nil
+	mc.stmtLRU = nil
+	return mc.prewarmStatements()
+}
+
+// stmtLRU caches the statements prepared for ExecContext and QueryContext
+// with arguments, see Config.stmtCacheSize. The least recently used
+// statement is closed when the cache is full.
+type stmtLRU struct {
+	ll list.List // of *mysqlStmt, most recently used first
+	m  map[string]*list.Element
+}
+
+// cachesStmts reports whether statements with arguments are executed with a
+// cached prepared statement instead of returning driver.ErrSkip. A cached
+// statement would keep the text rewritten by Config.QueryRewriter for the
+// operation which prepared it, so the cache is not used with a rewriter.
+func (mc *mysqlConn) cachesStmts() bool {
+	return mc.cfg.stmtCacheSize > 0 && mc.cfg.QueryRewriter == nil &&
+		!mc.interpolates() && !mc.bindsQueryAttrParams()
+}
+
+// cachedStmt returns the prepared statement of query from the cache and
+// prepares it if it is not cached.
+func (mc *mysqlConn) cachedStmt(query string) (*mysqlStmt, error) {
+	if err := mc.closeStaleStmts(); err != nil {
+		return nil, err
+	}
+	lru := mc.stmtLRU
+	if lru == nil {
+		lru = &stmtLRU{m: make(map[string]*list.Element)}
+		mc.stmtLRU = lru
+	}
+	if e, ok := lru.m[addQueryHints(query, mc.queryHints())]; ok {
+		lru.ll.MoveToFront(e)
+		return e.Value.(*mysqlStmt), nil
+	}
+
+	ds, err := mc.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	stmt := ds.(*mysqlStmt)
+	if stmt.cached {
+		// prepared in advance, see Config.prewarmStmts
+		return stmt, nil
+	}
+	// owned by the cache instead of the caller
+	stmt.cached = true
+	mc.openStmts--
+	lru.m[stmt.sql] = lru.ll.PushFront(stmt)
+
+	if lru.ll.Len() > mc.cfg.stmtCacheSize {
+		if err := mc.evictStmt(lru.ll.Back().Value.(*mysqlStmt)); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+// evictStmt removes stmt from the cache and closes it.
+func (mc *mysqlConn) evictStmt(stmt *mysqlStmt) error {
+	lru := mc.stmtLRU
+	e, ok := lru.m[stmt.sql]
+	if !ok {
+		return nil
+	}
+	lru.ll.Remove(e)
+	delete(lru.m, stmt.sql)
+	return mc.writeCommandPacketUint32(comStmtClose, stmt.id)
+}
+
+// execCached executes query with a cached prepared statement.
+func (mc *mysqlConn) execCached(query string, args []driver.Value) (driver.Result, error) {
+	stmt, err := mc.cachedStmt(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Exec(args)
+}
+
+// queryCached is like execCached for queries.
+func (mc *mysqlConn) queryCached(query string, args []driver.Value) (*binaryRows, error) {
+	stmt, err := mc.cachedStmt(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.query(args)
+}
@@ -0,0 +1,168 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+func TestPrewarmStatements(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.prewarmStmts = []string{"SELECT 1", "SELECT * FROM missing"}
+
+	// responses are read after both commands have been sent
+	conn.data = []byte{
+		// COM_STMT_PREPARE OK: id 7, 0 columns, 0 params
+		12, 0, 0, 1, 0, 7, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		// ERR 1146
+		16, 0, 0, 1, 0xff, 0x7a, 0x04, '#', '4', '2', 'S', '0', '2', 'm', 'i', 's', 's', 'i', 'n', 'g',
+	}
+	conn.maxReads = 1
+
+	if err := mc.prewarmStatements(); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected []byte
+	expected = append(expected, commandPacket(comStmtPrepare, "SELECT 1")...)
+	expected = append(expected, commandPacket(comStmtPrepare, "SELECT * FROM missing")...)
+	if !bytes.Equal(conn.written, expected) {
+		t.Errorf("unexpected packets written:\nexpected %q\ngot      %q", expected, conn.written)
+	}
+	if len(mc.stmtCache) != 1 {
+		t.Fatalf("expected 1 cached statement, got %d", len(mc.stmtCache))
+	}
+
+	// Prepare and Close use the cached statement without round trips
+	conn.written = nil
+	stmt, err := mc.Prepare("SELECT 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := stmt.(*mysqlStmt).id; id != 7 {
+		t.Errorf("expected statement id 7, got %d", id)
+	}
+	if err := stmt.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(conn.written) != 0 {
+		t.Errorf("expected no packets to be written, got %v", conn.written)
+	}
+	if mc.stmtCache["SELECT 1"].mc != mc {
+		t.Error("cached statement must not be closed")
+	}
+}
+
+// mockPrepareOK returns the response to COM_STMT_PREPARE for a statement with
+// one parameter and no result columns.
+func mockPrepareOK(id byte) []byte {
+	resp := mockPacket(1, []byte{iOK, id, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0})
+	resp = append(resp, mockPacket(2, []byte{3, 'd', 'e', 'f'})...)
+	return append(resp, mockPacket(3, []byte{iEOF, 0, 0, 2, 0})...)
+}
+
+// writtenCommands returns the command byte of each packet in b.
+func writtenCommands(b []byte) []byte {
+	var cmds []byte
+	for len(b) >= 5 {
+		cmds = append(cmds, b[4])
+		b = b[4+getUint24(b[:3]):]
+	}
+	return cmds
+}
+
+func TestStmtCacheLRU(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.stmtCacheSize = 1
+	conn.queuedReplies = [][]byte{
+		mockPrepareOK(1), okPacket, // first query
+		okPacket,                       // first query again
+		mockPrepareOK(2), {}, okPacket, // second query evicts the first
+	}
+
+	args := []driver.NamedValue{{Ordinal: 1, Value: int64(1)}}
+	for _, query := range []string{"UPDATE t SET a = ?", "UPDATE t SET a = ?", "UPDATE u SET a = ?"} {
+		if _, err := mc.ExecContext(context.Background(), query, args); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []byte{comStmtPrepare, comStmtExecute, comStmtExecute, comStmtPrepare, comStmtClose, comStmtExecute}
+	if got := writtenCommands(conn.written); !bytes.Equal(got, expected) {
+		t.Errorf("expected commands %v, got %v", expected, got)
+	}
+	if mc.openStmts != 0 {
+		t.Errorf("expected no open statements, got %d", mc.openStmts)
+	}
+	if _, ok := mc.stmtLRU.m["UPDATE u SET a = ?"]; !ok || mc.stmtLRU.ll.Len() != 1 {
+		t.Errorf("unexpected cache content: %v", mc.stmtLRU.m)
+	}
+}
+
+func TestStmtCacheReprepare(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.stmtCacheSize = 8
+	conn.queuedReplies = [][]byte{
+		mockPrepareOK(1), mockErr(1, errNeedReprepare, "Prepared statement needs to be re-prepared"),
+		mockPrepareOK(2), {}, okPacket,
+	}
+
+	args := []driver.NamedValue{{Ordinal: 1, Value: int64(1)}}
+	if _, err := mc.ExecContext(context.Background(), "UPDATE t SET a = ?", args); err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{comStmtPrepare, comStmtExecute, comStmtPrepare, comStmtClose, comStmtExecute}
+	if got := writtenCommands(conn.written); !bytes.Equal(got, expected) {
+		t.Errorf("expected commands %v, got %v", expected, got)
+	}
+	if stmt := mc.stmtLRU.m["UPDATE t SET a = ?"].Value.(*mysqlStmt); stmt.id != 2 {
+		t.Errorf("expected the statement to be prepared again, got id %d", stmt.id)
+	}
+}
+
+func TestStmtCacheSchemaChange(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.prewarmStmts = []string{"UPDATE p SET a = ?"}
+	mc.cfg.stmtCacheSize = 8
+	conn.queuedReplies = [][]byte{
+		mockPrepareOK(1),           // prewarmed
+		mockPrepareOK(2), okPacket, // cached by the first query
+		okPacket,                 // USE other
+		{}, {}, mockPrepareOK(3), // both closed, prewarmed again
+		mockPrepareOK(4), okPacket, // prepared again by the second query
+	}
+
+	if err := mc.prewarmStatements(); err != nil {
+		t.Fatal(err)
+	}
+	args := []driver.NamedValue{{Ordinal: 1, Value: int64(1)}}
+	if _, err := mc.ExecContext(context.Background(), "UPDATE t SET a = ?", args); err != nil {
+		t.Fatal(err)
+	}
+	if err := mc.initDB("other"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mc.ExecContext(context.Background(), "UPDATE t SET a = ?", args); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{comStmtPrepare, comStmtPrepare, comStmtExecute, comInitDB, comStmtClose, comStmtClose, comStmtPrepare, comStmtPrepare, comStmtExecute}
+	if got := writtenCommands(conn.written); !bytes.Equal(got, expected) {
+		t.Errorf("expected commands %v, got %v", expected, got)
+	}
+	if stmt := mc.stmtCache["UPDATE p SET a = ?"]; stmt == nil || stmt.id != 3 {
+		t.Errorf("expected the prewarmed statement to be prepared again, got %v", stmt)
+	}
+	if stmt := mc.stmtLRU.m["UPDATE t SET a = ?"].Value.(*mysqlStmt); stmt.id != 4 {
+		t.Errorf("expected the cached statement to be prepared again, got id %d", stmt.id)
+	}
+}
@@ -0,0 +1,293 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"io"
+)
+
+// StreamRows reads the rows of a result set column by column directly from
+// the connection, without reading the whole row into memory first. For rows
+// with very large values, e.g. several BLOB columns, this bounds the memory
+// used to the largest single value instead of the whole row.
+//
+// Like BatchRows, this is accessible by executing queries using
+// sql.Conn.Raw() and downcasting the returned rows. It is only implemented
+// by the rows of the text protocol, i.e. of queries without arguments or
+// with InterpolateParams:
+//
+//	rows, err := rawConn.(driver.QueryerContext).QueryContext(ctx, "SELECT id, doc FROM docs", nil)
+//	srows, ok := rows.(mysql.StreamRows)
+//	for {
+//		err := srows.NextRow()
+//		if err == io.EOF {
+//			break
+//		}
+//		...
+//		id, _, err := srows.NextColumn()
+//		...
+//		doc, isNull, err := srows.NextColumn()
+//		...
+//	}
+//
+// Next, NextBatch and NextRow may be mixed. The columns of a row which are
+// not read are skipped.
+type StreamRows interface {
+	driver.Rows
+	// NextRow advances to the next row of the current result set. It
+	// returns io.EOF if there are no more rows.
+	NextRow() error
+	// NextColumn returns the value of the next column of the current row as
+	// sent by the server in the text protocol, or isNull for NULL. The value
+	// is only valid until the next call. It returns io.EOF after the last
+	// column.
+	NextColumn() (value []byte, isNull bool, err error)
+}
+
+// streamSkipSize is the maximum number of bytes read at once when the rest
+// of a streamed row is skipped.
+const streamSkipSize = 64 * 1024
+
+// rowStream is the state of a row read by StreamRows.NextRow.
+type rowStream struct {
+	active bool
+	first  int    // first byte of the row which was already read, or -1
+	remain int    // unread bytes of the current packet
+	more   bool   // the current packet is continued by the next one
+	column int    // index of the next column
+	buf    []byte // values spanning packets, reused
+}
+
+var _ StreamRows = &textRows{}
+
+func (rows *textRows) NextRow() error {
+	mc := rows.mc
+	if mc == nil {
+		return io.EOF
+	}
+	if err := mc.error(); err != nil {
+		return err
+	}
+	if err := rows.skipRow(); err != nil {
+		return err
+	}
+	if rows.rs.done {
+		return io.EOF
+	}
+
+	pktLen, seq, outOfSync, err := mc.readHeader(false)
+	if err != nil {
+		return err
+	}
+	if pktLen == 0 {
+		mc.log(ErrMalformPkt)
+		mc.close()
+		return ErrInvalidConn
+	}
+	mc.buf.observe(pktLen)
+	if s := mc.cfg.StatsCollector; s != nil {
+		s.PacketRead(pktLen)
+	}
+	data, err := mc.readBytes(1)
+	if err != nil {
+		return err
+	}
+	first := data[0]
+
+	// EOF, OK and ERR packets are small, read them completely
+	if (first == iEOF || first == iERR) && pktLen < maxPacketSize {
+		rest, err := mc.readBytes(pktLen - 1)
+		if err != nil {
+			return err
+		}
+		data := append([]byte{first}, rest...)
+		if mc.trace != nil {
+			mc.traceReceivedPacket(seq, data, false)
+		}
+		if outOfSync {
+			mc.close()
+			if first != iERR {
+				return ErrPktSync
+			}
+		}
+		if first == iERR {
+			rows.mc = nil
+			defer mc.restoreSessionVars()
+			return mc.handleErrorPacket(data)
+		}
+		if err := mc.handleEOFPacket(data); err != nil {
+			rows.mc = nil
+			defer mc.restoreSessionVars()
+			return err
+		}
+		rows.rs.done = true
+		if !rows.HasNextResultSet() {
+			rows.mc = nil
+			defer mc.restoreSessionVars()
+			if werr := mc.checkWarnings(); werr != nil {
+				return werr
+			}
+			rows.warnings = mc.warnings
+		}
+		return io.EOF
+	}
+
+	if mc.trace != nil {
+		mc.trace.add(traceEvent{dir: traceReceived, seq: seq, size: pktLen, kind: "DATA"})
+	}
+	if outOfSync {
+		mc.close()
+		return ErrPktSync
+	}
+	rows.stream = rowStream{
+		active: true,
+		first:  int(first),
+		remain: pktLen - 1,
+		more:   pktLen == maxPacketSize,
+		buf:    rows.stream.buf,
+	}
+	return rows.checkLimits(pktLen)
+}
+
+func (rows *textRows) NextColumn() (value []byte, isNull bool, err error) {
+	s := &rows.stream
+	if !s.active || s.column >= len(rows.rs.columns) {
+		return nil, false, io.EOF
+	}
+	if err := rows.mc.error(); err != nil {
+		return nil, false, err
+	}
+
+	// length encoded string
+	b, err := rows.streamByte()
+	if err != nil {
+		return nil, false, err
+	}
+	n, size := uint64(b), 0
+	switch b {
+	case 0xfb:
+		s.column++
+		return nil, true, nil
+	case 0xfc:
+		size = 2
+	case 0xfd:
+		size = 3
+	case 0xfe:
+		size = 8
+	}
+	if size > 0 {
+		data, err := rows.streamBytes(size)
+		if err != nil {
+			return nil, false, err
+		}
+		var le [8]byte
+		copy(le[:], data)
+		n = binary.LittleEndian.Uint64(le[:])
+	}
+	if n > uint64(s.remain) && !s.more {
+		return nil, false, ErrMalformPkt
+	}
+	value, err = rows.streamBytes(int(n))
+	if err != nil {
+		return nil, false, err
+	}
+	s.column++
+	return value, false, nil
+}
+
+// streamByte reads the next byte of the current row.
+func (rows *textRows) streamByte() (byte, error) {
+	if s := &rows.stream; s.first >= 0 {
+		b := byte(s.first)
+		s.first = -1
+		return b, nil
+	}
+	data, err := rows.streamBytes(1)
+	if err != nil {
+		return 0, err
+	}
+	return data[0], nil
+}
+
+// streamBytes reads the next n bytes of the current row, which may span
+// several packets. The returned slice is only valid until the next read.
+func (rows *mysqlRows) streamBytes(n int) ([]byte, error) {
+	s := &rows.stream
+	mc := rows.mc
+	if n <= s.remain {
+		s.remain -= n
+		return mc.readBytes(n)
+	}
+
+	s.buf = s.buf[:0]
+	for n > 0 {
+		if s.remain == 0 {
+			if !s.more {
+				return nil, ErrMalformPkt
+			}
+			if err := rows.streamNextPacket(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		k := min(n, s.remain)
+		data, err := mc.readBytes(k)
+		if err != nil {
+			return nil, err
+		}
+		s.buf = append(s.buf, data...)
+		s.remain -= k
+		n -= k
+	}
+	return s.buf, nil
+}
+
+// streamNextPacket reads the header of the packet continuing the current row.
+func (rows *mysqlRows) streamNextPacket() error {
+	mc := rows.mc
+	pktLen, seq, _, err := mc.readHeader(true)
+	if err != nil {
+		return err
+	}
+	if s := mc.cfg.StatsCollector; s != nil {
+		s.PacketRead(pktLen)
+	}
+	if mc.trace != nil {
+		mc.trace.add(traceEvent{dir: traceReceived, seq: seq, size: pktLen, kind: "MORE"})
+	}
+	rows.stream.remain = pktLen
+	rows.stream.more = pktLen == maxPacketSize
+	return nil
+}
+
+// skipRow discards the unread rest of the row read by NextRow, if any.
+func (rows *mysqlRows) skipRow() error {
+	s := &rows.stream
+	if !s.active {
+		return nil
+	}
+	s.active = false
+	for {
+		for s.remain > 0 {
+			k := min(s.remain, streamSkipSize)
+			if _, err := rows.mc.readBytes(k); err != nil {
+				return err
+			}
+			s.remain -= k
+		}
+		if !s.more {
+			return nil
+		}
+		if err := rows.streamNextPacket(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"io"
+	"testing"
+)
+
+func TestStreamRows(t *testing.T) {
+	// a value of maxPacketSize+5 bytes, so that the row spans two packets
+	big := bytes.Repeat([]byte{'x'}, maxPacketSize+5)
+	row2 := append([]byte{0xfb}, appendLengthEncodedInteger(nil, uint64(len(big)))...)
+	row2 = append(row2, big...)
+
+	resp := mockPacket(1, []byte{2})
+	resp = append(resp, mockColumn(2, "a", fieldTypeVarString)...)
+	resp = append(resp, mockColumn(3, "b", fieldTypeVarString)...)
+	resp = append(resp, mockPacket(4, []byte{iEOF, 0, 0, 2, 0})...)
+	resp = append(resp, mockPacket(5, []byte{1, '1', 5, 'h', 'e', 'l', 'l', 'o'})...)
+	resp = append(resp, mockPacket(6, row2[:maxPacketSize])...)
+	resp = append(resp, mockPacket(7, row2[maxPacketSize:])...)
+	resp = append(resp, mockPacket(8, []byte{1, '3', 1, 'c'})...)
+	resp = append(resp, mockPacket(9, []byte{1, '4', 1, 'd'})...)
+	resp = append(resp, mockPacket(10, []byte{iEOF, 0, 0, 2, 0})...)
+
+	conn, mc := newRWMockConn(0)
+	conn.queuedReplies = [][]byte{resp, mockPacket(1, []byte{iOK, 0, 0, 2, 0, 0, 0})}
+	rows, err := mc.Query("SELECT a, b FROM t", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srows, ok := rows.(StreamRows)
+	if !ok {
+		t.Fatalf("%T does not implement StreamRows", rows)
+	}
+
+	column := func(expected string, expectNull bool) {
+		t.Helper()
+		value, isNull, err := srows.NextColumn()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if isNull != expectNull || string(value) != expected {
+			t.Errorf("expected %q (NULL %t), got %.10q (NULL %t)", expected, expectNull, value, isNull)
+		}
+	}
+
+	if err := srows.NextRow(); err != nil {
+		t.Fatal(err)
+	}
+	column("1", false)
+	column("hello", false)
+	if _, _, err := srows.NextColumn(); err != io.EOF {
+		t.Errorf("expected io.EOF after the last column, got %v", err)
+	}
+
+	if err := srows.NextRow(); err != nil {
+		t.Fatal(err)
+	}
+	column("", true)
+	column(string(big), false)
+
+	// the unread column is skipped
+	if err := srows.NextRow(); err != nil {
+		t.Fatal(err)
+	}
+	column("3", false)
+
+	// mixed with Next
+	dest := make([]driver.Value, 2)
+	if err := rows.Next(dest); err != nil {
+		t.Fatal(err)
+	}
+	if string(dest[0].([]byte)) != "4" || string(dest[1].([]byte)) != "d" {
+		t.Errorf("unexpected row %q", dest)
+	}
+	if err := srows.NextRow(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mc.Exec("DO 1", nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStreamRowsClose(t *testing.T) {
+	resp := mockPacket(1, []byte{1})
+	resp = append(resp, mockColumn(2, "a", fieldTypeVarString)...)
+	resp = append(resp, mockPacket(3, []byte{iEOF, 0, 0, 2, 0})...)
+	resp = append(resp, mockPacket(4, []byte{3, 'a', 'b', 'c'})...)
+	resp = append(resp, mockPacket(5, []byte{3, 'd', 'e', 'f'})...)
+	resp = append(resp, mockPacket(6, []byte{iEOF, 0, 0, 2, 0})...)
+
+	conn, mc := newRWMockConn(0)
+	conn.queuedReplies = [][]byte{resp, mockPacket(1, []byte{iOK, 0, 0, 2, 0, 0, 0})}
+	rows, err := mc.Query("SELECT a FROM t", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// close in the middle of a row
+	if err := rows.(StreamRows).NextRow(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mc.Exec("DO 1", nil); err != nil {
+		t.Error(err)
+	}
+}
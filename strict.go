@@ -0,0 +1,90 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Warning codes returned as a *TruncationError with strict=true.
+const (
+	warnRowTruncated   = 1262 // Row %d was truncated; it contained more data than there were input columns
+	warnDataTruncated  = 1265 // Data truncated for column '%s' at row %d
+	warnIncorrectValue = 1366 // Incorrect %s value: '%s' for column '%s' at row %d
+)
+
+// TruncatedColumn is a warning about data which was truncated or converted
+// when it was stored.
+type TruncatedColumn struct {
+	MySQLWarning
+	Column string // name of the column, empty if the warning concerns the whole row
+	Row    int64  // row of the statement, starting at 1, or 0 if unknown
+}
+
+// TruncationError is returned with strict=true by statements which stored
+// truncated or converted data, e.g. an INSERT of a string which is too long
+// for its column on a server without strict sql_mode. The statement has been
+// executed; use a transaction to roll it back.
+type TruncationError struct {
+	Columns []TruncatedColumn
+}
+
+func (e *TruncationError) Error() string {
+	var sb strings.Builder
+	for i, c := range e.Columns {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(MySQLWarnings{c.MySQLWarning}.Error())
+	}
+	return sb.String()
+}
+
+// truncationError returns the warnings about truncated data as a
+// *TruncationError, or nil if there are none.
+func truncationError(warnings MySQLWarnings) error {
+	var columns []TruncatedColumn
+	for _, w := range warnings {
+		switch w.Code {
+		case warnRowTruncated, warnDataTruncated, warnIncorrectValue:
+			column, row := parseTruncation(w.Message)
+			columns = append(columns, TruncatedColumn{MySQLWarning: w, Column: column, Row: row})
+		}
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	return &TruncationError{Columns: columns}
+}
+
+// parseTruncation returns the column and the row of a truncation warning,
+// e.g. "Data truncated for column 'a' at row 2". MariaDB and MySQL 8 quote
+// the column of warning 1366 as `schema`.`table`.`column`.
+func parseTruncation(msg string) (column string, row int64) {
+	if i := strings.LastIndex(msg, " at row "); i >= 0 {
+		row, _ = strconv.ParseInt(msg[i+len(" at row "):], 10, 64)
+		msg = msg[:i]
+	} else if strings.HasPrefix(msg, "Row ") {
+		n, _, _ := strings.Cut(msg[len("Row "):], " ")
+		row, _ = strconv.ParseInt(n, 10, 64)
+		return "", row
+	}
+	i := strings.LastIndex(msg, " for column ")
+	if i < 0 {
+		return "", row
+	}
+	column = msg[i+len(" for column "):]
+	if strings.HasSuffix(column, "`") {
+		if j := strings.LastIndex(column[:len(column)-1], "`"); j >= 0 {
+			return column[j+1 : len(column)-1], row
+		}
+	}
+	return strings.Trim(column, "'"), row
+}
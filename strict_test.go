@@ -0,0 +1,70 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseTruncation(t *testing.T) {
+	tests := []struct {
+		msg    string
+		column string
+		row    int64
+	}{
+		{"Data truncated for column 'a' at row 2", "a", 2},
+		{"Incorrect integer value: 'abc' for column 'id' at row 1", "id", 1},
+		{"Incorrect integer value: 'abc' for column `db`.`t`.`id` at row 3", "id", 3},
+		{"Row 4 was truncated; it contained more data than there were input columns", "", 4},
+		{"something else", "", 0},
+	}
+	for _, tt := range tests {
+		column, row := parseTruncation(tt.msg)
+		if column != tt.column || row != tt.row {
+			t.Errorf("%q: expected %q, %d, got %q, %d", tt.msg, tt.column, tt.row, column, row)
+		}
+	}
+}
+
+func TestExecStrict(t *testing.T) {
+	truncated := MySQLWarning{"Warning", 1265, "Data truncated for column 'a' at row 1"}
+	incorrect := MySQLWarning{"Warning", 1366, "Incorrect integer value: 'x' for column 'b' at row 2"}
+	deprecated := MySQLWarning{"Warning", 1287, "'@@tx_isolation' is deprecated"}
+
+	conn, mc := newRWMockConn(0)
+	mc.cfg.strict = true
+	conn.queuedReplies = [][]byte{
+		mockPacket(1, []byte{iOK, 2, 0, 2, 0, 3, 0}), // 3 warnings
+		mockShowWarnings(truncated, deprecated, incorrect),
+	}
+
+	_, err := mc.Exec("INSERT INTO t VALUES ('abc', 'x')", nil)
+	var terr *TruncationError
+	if !errors.As(err, &terr) {
+		t.Fatalf("expected TruncationError, got %v", err)
+	}
+	expected := []TruncatedColumn{
+		{MySQLWarning: truncated, Column: "a", Row: 1},
+		{MySQLWarning: incorrect, Column: "b", Row: 2},
+	}
+	if !reflect.DeepEqual(terr.Columns, expected) {
+		t.Errorf("expected %+v, got %+v", expected, terr.Columns)
+	}
+
+	// other warnings are not returned
+	conn.queuedReplies = [][]byte{
+		mockPacket(1, []byte{iOK, 1, 0, 2, 0, 1, 0}),
+		mockShowWarnings(deprecated),
+	}
+	if _, err := mc.Exec("SET @@tx_isolation = 'SERIALIZABLE'", nil); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,193 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+)
+
+const (
+	// protocolTraceSize is the number of the most recent events kept by the
+	// protocol trace of a connection.
+	protocolTraceSize = 1024
+	// protocolTraceHeader is the first line of a trace file. The version is
+	// incremented when the format changes incompatibly.
+	protocolTraceHeader = "# go-sql-driver/mysql protocol trace v1"
+)
+
+// Directions of trace events.
+const (
+	traceSent     byte = '>' // packet sent to the server
+	traceReceived byte = '<' // packet received from the server
+	traceFailure  byte = '!' // I/O or protocol failure
+)
+
+// traceEvent is a packet or a failure recorded by the protocol trace. The
+// payload of packets is never recorded, except for the error number of ERR
+// packets.
+type traceEvent struct {
+	n     uint64 // number of the event on the connection, starting at 1
+	dir   byte   // traceSent, traceReceived or traceFailure
+	seq   uint8  // sequence number of the packet, the expected one for failures
+	size  int    // payload length
+	kind  string // type of the packet or failure
+	errno uint16 // error number of ERR packets
+}
+
+// protocolTrace records the packets of a connection, see Config.protocolTrace.
+// The trace is written to a file if the connection fails, so that protocol
+// bugs can be reported and replayed without the network and the data.
+type protocolTrace struct {
+	mu     sync.Mutex
+	events []traceEvent // ring buffer of the most recent events
+	n      uint64       // number of recorded events
+	failed bool         // a failure was recorded which was not caused by a cancel
+}
+
+func (t *protocolTrace) add(e traceEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.n++
+	e.n = t.n
+	if len(t.events) < protocolTraceSize {
+		t.events = append(t.events, e)
+	} else {
+		t.events[(t.n-1)%protocolTraceSize] = e
+	}
+}
+
+// traceCommandNames are the names of the commands in trace files.
+var traceCommandNames = map[byte]string{
+	comQuit:             "COM_QUIT",
+	comInitDB:           "COM_INIT_DB",
+	comQuery:            "COM_QUERY",
+	comFieldList:        "COM_FIELD_LIST",
+	comStatistics:       "COM_STATISTICS",
+	comProcessKill:      "COM_PROCESS_KILL",
+	comPing:             "COM_PING",
+	comChangeUser:       "COM_CHANGE_USER",
+	comBinlogDump:       "COM_BINLOG_DUMP",
+	comRegisterSlave:    "COM_REGISTER_SLAVE",
+	comStmtPrepare:      "COM_STMT_PREPARE",
+	comStmtExecute:      "COM_STMT_EXECUTE",
+	comStmtSendLongData: "COM_STMT_SEND_LONG_DATA",
+	comStmtClose:        "COM_STMT_CLOSE",
+	comStmtReset:        "COM_STMT_RESET",
+	comSetOption:        "COM_SET_OPTION",
+	comStmtFetch:        "COM_STMT_FETCH",
+	comBinlogDumpGTID:   "COM_BINLOG_DUMP_GTID",
+	comResetConnection:  "COM_RESET_CONNECTION",
+	comStmtBulkExecute:  "COM_STMT_BULK_EXECUTE",
+}
+
+// traceSentPacket records a packet written to the server. Only the command
+// byte of packets with sequence 0 is inspected, as the other packets may
+// contain credentials.
+func (mc *mysqlConn) traceSentPacket(seq uint8, payload []byte, continued bool) {
+	kind := "DATA"
+	switch {
+	case continued:
+		kind = "MORE"
+	case seq == 0 && len(payload) > 0:
+		if name, ok := traceCommandNames[payload[0]]; ok {
+			kind = name
+		} else {
+			kind = "COM_0x" + strconv.FormatUint(uint64(payload[0]), 16)
+		}
+	}
+	mc.trace.add(traceEvent{dir: traceSent, seq: seq, size: len(payload), kind: kind})
+}
+
+// traceReceivedPacket records a packet read from the server, classified by
+// its first byte.
+func (mc *mysqlConn) traceReceivedPacket(seq uint8, payload []byte, continued bool) {
+	e := traceEvent{dir: traceReceived, seq: seq, size: len(payload), kind: "DATA"}
+	switch {
+	case continued:
+		e.kind = "MORE"
+	case len(payload) == 0:
+	case payload[0] == iOK:
+		e.kind = "OK"
+	case payload[0] == iERR:
+		e.kind = "ERR"
+		if len(payload) >= 3 {
+			e.errno = uint16(payload[1]) | uint16(payload[2])<<8
+		}
+	case payload[0] == iEOF:
+		e.kind = "EOF"
+	case payload[0] == iLocalInFile:
+		e.kind = "LOCAL_INFILE"
+	case payload[0] == iAuthMoreData:
+		e.kind = "AUTH_MORE_DATA"
+	}
+	mc.trace.add(e)
+}
+
+// traceFailed records an I/O error or a protocol failure, e.g. an
+// unexpected sequence number. I/O errors caused by canceling the context of
+// a query do not make the connection count as failed.
+func (mc *mysqlConn) traceFailed(kind string) {
+	t := mc.trace
+	t.add(traceEvent{dir: traceFailure, seq: mc.sequence, kind: kind})
+	if mc.canceled.Value() == nil {
+		t.mu.Lock()
+		t.failed = true
+		t.mu.Unlock()
+	}
+}
+
+// writeTrace writes the protocol trace to a new file in the trace directory
+// if a failure was recorded. It is called when the connection is closed after
+// an error, on the goroutine which used the connection, never by the
+// goroutine watching the context.
+func (mc *mysqlConn) writeTrace() {
+	t := mc.trace
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.failed {
+		return
+	}
+	// written once
+	t.failed = false
+
+	f, err := os.CreateTemp(mc.cfg.protocolTrace, "mysql-trace-*.txt")
+	if err != nil {
+		mc.log("writing protocol trace:", err)
+		return
+	}
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, protocolTraceHeader)
+	fmt.Fprintf(w, "# compress=%t tls=%t events=%d\n", mc.compress, mc.cfg.TLS != nil, t.n)
+	fmt.Fprintln(w, "# event direction sequence length type [errno]")
+	start := 0
+	if t.n > protocolTraceSize {
+		start = int(t.n % protocolTraceSize)
+	}
+	for i := range t.events {
+		e := t.events[(start+i)%len(t.events)]
+		fmt.Fprintf(w, "%d %c %d %d %s", e.n, e.dir, e.seq, e.size, e.kind)
+		if e.errno != 0 {
+			fmt.Fprintf(w, " %d", e.errno)
+		}
+		fmt.Fprintln(w)
+	}
+	err = w.Flush()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		mc.log("writing protocol trace:", err)
+		return
+	}
+	mc.log("protocol trace of the failed connection written to", f.Name())
+}
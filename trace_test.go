@@ -0,0 +1,178 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// parseTrace reads a protocol trace file written by writeTrace.
+func parseTrace(r io.Reader) ([]traceEvent, error) {
+	s := bufio.NewScanner(r)
+	if !s.Scan() || s.Text() != protocolTraceHeader {
+		return nil, fmt.Errorf("not a protocol trace: %q", s.Text())
+	}
+	var events []traceEvent
+	for s.Scan() {
+		line := s.Text()
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		var e traceEvent
+		var dir string
+		n, _ := fmt.Sscan(line, &e.n, &dir, &e.seq, &e.size, &e.kind, &e.errno)
+		if n < 5 || len(dir) != 1 {
+			return nil, fmt.Errorf("invalid trace event: %q", line)
+		}
+		e.dir = dir[0]
+		events = append(events, e)
+	}
+	return events, s.Err()
+}
+
+// replayTrace returns a mock connection which replies to the packets of the
+// client with packets of the same types, sequence numbers and lengths as the
+// server packets in the trace. The payloads are zero except for the type
+// byte and the error number of ERR packets.
+func replayTrace(events []traceEvent) (*mockConn, *mysqlConn) {
+	conn, mc := newRWMockConn(0)
+	reply := &conn.data
+	for _, e := range events {
+		switch e.dir {
+		case traceSent:
+			conn.queuedReplies = append(conn.queuedReplies, nil)
+			reply = &conn.queuedReplies[len(conn.queuedReplies)-1]
+		case traceReceived:
+			payload := make([]byte, e.size)
+			if e.size > 0 {
+				switch e.kind {
+				case "OK":
+					payload[0] = iOK
+				case "ERR":
+					payload[0] = iERR
+					if e.size >= 3 {
+						payload[1], payload[2] = byte(e.errno), byte(e.errno>>8)
+					}
+				case "EOF":
+					payload[0] = iEOF
+				case "LOCAL_INFILE":
+					payload[0] = iLocalInFile
+				case "AUTH_MORE_DATA":
+					payload[0] = iAuthMoreData
+				}
+			}
+			*reply = append(*reply, mockPacket(e.seq, payload)...)
+		}
+	}
+	return conn, mc
+}
+
+func TestProtocolTraceReplay(t *testing.T) {
+	dir := t.TempDir()
+	conn, mc := newRWMockConn(0)
+	mc.cfg.protocolTrace = dir
+	mc.trace = &protocolTrace{}
+	// the reply to the second query has a wrong sequence number
+	conn.queuedReplies = [][]byte{okPacket, mockPacket(3, []byte{iOK, 0, 0, 2, 0, 0, 0})}
+
+	if _, err := mc.Exec("SET @a = 1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mc.Exec("SET @b = 'secret'", nil); err != ErrPktSync {
+		t.Fatalf("expected ErrPktSync, got %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "mysql-trace-*.txt"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected one trace file, got %v, %v", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") || strings.Contains(string(data), "SET") {
+		t.Errorf("trace contains payload data:\n%s", data)
+	}
+	events, err := parseTrace(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []traceEvent{
+		{1, traceSent, 0, 11, "COM_QUERY", 0},
+		{2, traceReceived, 1, 7, "OK", 0},
+		{3, traceSent, 0, 18, "COM_QUERY", 0},
+		{4, traceFailure, 1, 0, "SYNC", 0},
+		{5, traceReceived, 3, 7, "OK", 0},
+	}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Fatalf("got events %v, want %v", events, want)
+	}
+
+	_, mc = replayTrace(events)
+	if _, err := mc.Exec("SET @a = 1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mc.Exec("SET @b = 2", nil); err != ErrPktSync {
+		t.Errorf("replay: expected ErrPktSync, got %v", err)
+	}
+}
+
+func TestProtocolTraceClose(t *testing.T) {
+	dir := t.TempDir()
+	conn, mc := newRWMockConn(0)
+	mc.cfg.protocolTrace = dir
+	mc.trace = &protocolTrace{}
+	conn.queuedReplies = [][]byte{okPacket, {}}
+
+	if _, err := mc.Exec("DO 1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := mc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("trace written for a healthy connection: %v", files)
+	}
+}
+
+func TestProtocolTraceCancel(t *testing.T) {
+	dir := t.TempDir()
+	conn, mc := newRWMockConn(0)
+	mc.cfg.protocolTrace = dir
+	mc.trace = &protocolTrace{}
+	conn.maxReads = 1
+
+	// the watcher closed the connection after the context was canceled
+	mc.canceled.Set(context.Canceled)
+	conn.closed = true
+	if _, err := mc.readPacket(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("trace written for a canceled query: %v", files)
+	}
+}
+
+func TestProtocolTraceRing(t *testing.T) {
+	tr := &protocolTrace{}
+	for i := 0; i < protocolTraceSize+10; i++ {
+		tr.add(traceEvent{dir: traceSent, kind: "COM_PING"})
+	}
+	if len(tr.events) != protocolTraceSize || tr.events[9].n != protocolTraceSize+10 {
+		t.Errorf("unexpected ring buffer: %d events, event 9 is #%d", len(tr.events), tr.events[9].n)
+	}
+}
@@ -8,15 +8,65 @@
 
 package mysql
 
+import "context"
+
 type mysqlTx struct {
 	mc *mysqlConn
+
+	// restoreSchema is the default database to switch back to when the
+	// transaction ends, if it has been changed by WithTxSchema.
+	restoreSchema  string
+	schemaSwitched bool
+}
+
+// TxHints are returned by Config.OnBeginTx to prepare a transaction, e.g.
+// for read/write splitting by a proxy like ProxySQL or MaxScale.
+type TxHints struct {
+	// Statements are executed before the transaction is started, e.g.
+	// "SET TRANSACTION READ ONLY". Settings changed with session scope
+	// persist on the connection when it is returned to the pool.
+	Statements []string
+
+	// Comment is appended to the START TRANSACTION statement, e.g. the
+	// routing hint "-- maxscale route to slave".
+	Comment string
+}
+
+type txSchemaKey struct{}
+
+// WithTxSchema returns a context which makes transactions started with it
+// (e.g. by sql.DB.BeginTx) run against the given schema.
+//
+// The default database of the connection is switched using COM_INIT_DB
+// before the transaction is started and restored when it is committed or
+// rolled back. If the connection had no default database before, it is closed
+// after the transaction instead, since this state can not be restored.
+//
+//	ctx = mysql.WithTxSchema(ctx, "tenant_42")
+//	tx, err := db.BeginTx(ctx, nil)
+func WithTxSchema(ctx context.Context, schema string) context.Context {
+	return context.WithValue(ctx, txSchemaKey{}, schema)
+}
+
+func txSchemaFromContext(ctx context.Context) string {
+	schema, _ := ctx.Value(txSchemaKey{}).(string)
+	return schema
 }
 
 func (tx *mysqlTx) Commit() (err error) {
 	if tx.mc == nil || tx.mc.closed.Load() {
 		return ErrInvalidConn
 	}
-	err = tx.mc.exec("COMMIT")
+	tx.mc.inTx = false
+	if err = tx.mc.flushCoalesced(); err != nil {
+		// a coalesced statement failed, do not commit the others
+		if !tx.mc.closed.Load() {
+			tx.mc.exec("ROLLBACK")
+		}
+	} else {
+		err = tx.mc.exec("COMMIT")
+	}
+	tx.restoreSchemaState()
 	tx.mc = nil
 	return
 }
@@ -25,7 +75,33 @@ func (tx *mysqlTx) Rollback() (err error) {
 	if tx.mc == nil || tx.mc.closed.Load() {
 		return ErrInvalidConn
 	}
+	tx.mc.inTx = false
+	// the errors of coalesced statements are irrelevant after the rollback
+	tx.mc.flushCoalesced()
+	if tx.mc.closed.Load() {
+		tx.mc = nil
+		return ErrInvalidConn
+	}
 	err = tx.mc.exec("ROLLBACK")
+	tx.restoreSchemaState()
 	tx.mc = nil
 	return
 }
+
+// restoreSchemaState switches back to the default database which was in use
+// before the transaction. The connection is closed if this is not possible, so
+// that it is not reused with the wrong default database.
+func (tx *mysqlTx) restoreSchemaState() {
+	mc := tx.mc
+	if !tx.schemaSwitched || mc.closed.Load() {
+		return
+	}
+	if tx.restoreSchema == "" {
+		mc.Close()
+		return
+	}
+	if err := mc.initDB(tx.restoreSchema); err != nil {
+		mc.log("restoring schema: ", err)
+		mc.Close()
+	}
+}
@@ -0,0 +1,142 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+var okPacket = []byte{7, 0, 0, 1, 0, 0, 0, 2, 0, 0, 0}
+
+// commandPacket returns a packet with sequence id 0 for the given command.
+func commandPacket(cmd byte, arg string) []byte {
+	pkt := []byte{byte(len(arg) + 1), 0, 0, 0, cmd}
+	return append(pkt, arg...)
+}
+
+func TestBeginTxWithSchema(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.schema = "main"
+	conn.queuedReplies = [][]byte{okPacket, okPacket, okPacket, okPacket}
+
+	ctx := WithTxSchema(context.Background(), "tenant")
+	tx, err := mc.BeginTx(ctx, driver.TxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mc.schema != "tenant" {
+		t.Errorf("expected schema 'tenant', got %q", mc.schema)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if mc.schema != "main" {
+		t.Errorf("expected schema 'main' to be restored, got %q", mc.schema)
+	}
+
+	var expected []byte
+	expected = append(expected, commandPacket(comInitDB, "tenant")...)
+	expected = append(expected, commandPacket(comQuery, "START TRANSACTION")...)
+	expected = append(expected, commandPacket(comQuery, "COMMIT")...)
+	expected = append(expected, commandPacket(comInitDB, "main")...)
+	if !bytes.Equal(conn.written, expected) {
+		t.Errorf("unexpected packets written:\nexpected %q\ngot      %q", expected, conn.written)
+	}
+}
+
+func TestBeginTxWithSameSchema(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.schema = "tenant"
+	conn.queuedReplies = [][]byte{okPacket, okPacket}
+
+	tx, err := mc.BeginTx(WithTxSchema(context.Background(), "tenant"), driver.TxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected []byte
+	expected = append(expected, commandPacket(comQuery, "START TRANSACTION")...)
+	expected = append(expected, commandPacket(comQuery, "ROLLBACK")...)
+	if !bytes.Equal(conn.written, expected) {
+		t.Errorf("unexpected packets written:\nexpected %q\ngot      %q", expected, conn.written)
+	}
+}
+
+func TestBeginTxWithSchemaNoPriorSchema(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	conn.queuedReplies = [][]byte{okPacket, okPacket, okPacket}
+
+	tx, err := mc.BeginTx(WithTxSchema(context.Background(), "tenant"), driver.TxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if mc.IsValid() {
+		t.Error("expected connection without default database to be discarded")
+	}
+}
+
+func TestBeginTxOnBeginTx(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.OnBeginTx = func(ctx context.Context, opts sql.TxOptions) (TxHints, error) {
+		if opts.ReadOnly {
+			return TxHints{
+				Statements: []string{"SET SESSION TRANSACTION READ ONLY"},
+				Comment:    "-- maxscale route to slave",
+			}, nil
+		}
+		return TxHints{Statements: []string{"SET SESSION TRANSACTION READ WRITE"}}, nil
+	}
+	conn.queuedReplies = [][]byte{okPacket, okPacket, okPacket, okPacket, okPacket, okPacket}
+
+	for _, readOnly := range []bool{true, false} {
+		tx, err := mc.BeginTx(context.Background(), driver.TxOptions{ReadOnly: readOnly})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var expected []byte
+	expected = append(expected, commandPacket(comQuery, "SET SESSION TRANSACTION READ ONLY")...)
+	expected = append(expected, commandPacket(comQuery, "START TRANSACTION READ ONLY -- maxscale route to slave")...)
+	expected = append(expected, commandPacket(comQuery, "COMMIT")...)
+	expected = append(expected, commandPacket(comQuery, "SET SESSION TRANSACTION READ WRITE")...)
+	expected = append(expected, commandPacket(comQuery, "START TRANSACTION")...)
+	expected = append(expected, commandPacket(comQuery, "COMMIT")...)
+	if !bytes.Equal(conn.written, expected) {
+		t.Errorf("unexpected packets written:\nexpected %q\ngot      %q", expected, conn.written)
+	}
+}
+
+func TestBeginTxOnBeginTxError(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	hookErr := errors.New("no replica available")
+	mc.cfg.OnBeginTx = func(ctx context.Context, opts sql.TxOptions) (TxHints, error) {
+		return TxHints{}, hookErr
+	}
+	if _, err := mc.BeginTx(context.Background(), driver.TxOptions{}); err != hookErr {
+		t.Errorf("expected hook error, got %v", err)
+	}
+	if conn.writes != 0 {
+		t.Errorf("expected no writes, got %d", conn.writes)
+	}
+}
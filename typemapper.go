@@ -0,0 +1,111 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"math"
+	"strconv"
+	"time"
+)
+
+// FieldInfo describes the column of a value passed to Config.TypeMapper.
+type FieldInfo struct {
+	Name     string // name or alias of the column
+	Table    string // name or alias of the table, if any
+	Type     string // database type name, e.g. "BIT" or "SET", see sql.ColumnType.DatabaseTypeName
+	Length   uint32 // column length
+	Decimals byte   // number of decimals of DECIMAL and fractional seconds of temporal types
+	Unsigned bool   // the column is UNSIGNED
+}
+
+// fieldInfo returns the FieldInfo of the column.
+func (mf *mysqlField) fieldInfo() FieldInfo {
+	return FieldInfo{
+		Name:     mf.name,
+		Table:    mf.tableName,
+		Type:     mf.typeDatabaseName(),
+		Length:   mf.length,
+		Decimals: mf.decimals,
+		Unsigned: mf.flags&flagUnsigned != 0,
+	}
+}
+
+// mapValue passes the value raw of column i in text representation to the
+// TypeMapper of the rows. It returns false if the mapper declined the value
+// with driver.ErrSkip.
+func (rows *mysqlRows) mapValue(dest []driver.Value, i int, raw []byte) (bool, error) {
+	v, err := rows.mapper(rows.rs.columns[i].fieldInfo(), raw)
+	if err == driver.ErrSkip {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	dest[i] = v
+	return true, nil
+}
+
+// appendTextFloat appends f like the server formats FLOAT and DOUBLE values in
+// the text protocol: the shortest representation, in exponential notation
+// without '+' and leading zeros of the exponent only for very small or large
+// values, e.g. "100000000", "0.0001", "1e-7" and "1.5e20".
+func appendTextFloat(buf []byte, f float64, bitSize int) []byte {
+	if abs := math.Abs(f); abs == 0 || abs >= 1e-4 && abs < 1e15 || math.IsInf(f, 0) || math.IsNaN(f) {
+		return strconv.AppendFloat(buf, f, 'f', -1, bitSize)
+	}
+	n := len(buf)
+	buf = strconv.AppendFloat(buf, f, 'e', -1, bitSize)
+	e := n + bytes.IndexByte(buf[n:], 'e')
+	exp := buf[e+1:]
+	sign := exp[0] == '-'
+	digits := bytes.TrimLeft(exp[1:], "0")
+	buf = buf[:e+1]
+	if sign {
+		buf = append(buf, '-')
+	}
+	return append(buf, digits...)
+}
+
+// mapBinaryValues passes the values decoded from a binary row to the
+// TypeMapper of the rows, formatted in the text representation of the text
+// protocol.
+func (rows *binaryRows) mapBinaryValues(dest []driver.Value) error {
+	var buf [64]byte
+	for i, v := range dest {
+		var raw []byte
+		switch v := v.(type) {
+		case nil:
+			continue
+		case []byte:
+			raw = v
+		case string:
+			raw = append(buf[:0], v...)
+		case int64:
+			raw = strconv.AppendInt(buf[:0], v, 10)
+		case uint64:
+			raw = strconv.AppendUint(buf[:0], v, 10)
+		case float32:
+			raw = appendTextFloat(buf[:0], float64(v), 32)
+		case float64:
+			raw = appendTextFloat(buf[:0], v, 64)
+		case time.Duration:
+			raw = appendTimeDuration(buf[:0], v)
+		case time.Time:
+			raw = appendTextDateTime(buf[:0], v, &rows.rs.columns[i])
+		default:
+			continue
+		}
+		if _, err := rows.mapValue(dest, i, raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testTypeMapper maps BIT(1) to bool, SET to []string and YEAR to string.
+func testTypeMapper(field FieldInfo, raw []byte) (driver.Value, error) {
+	switch field.Type {
+	case "BIT":
+		if field.Length == 1 {
+			return len(raw) == 1 && raw[0] == 1, nil
+		}
+	case "SET":
+		return strings.Split(string(raw), ","), nil
+	case "YEAR":
+		return "year " + string(raw), nil
+	}
+	return nil, driver.ErrSkip
+}
+
+func TestTypeMapperText(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.TypeMapper = testTypeMapper
+
+	resp := mockPacket(1, []byte{3})
+	resp = append(resp, mockColumn(2, "s", fieldTypeSet)...)
+	resp = append(resp, mockColumn(3, "y", fieldTypeYear)...)
+	resp = append(resp, mockColumn(4, "i", fieldTypeLong)...)
+	resp = append(resp, mockPacket(5, []byte{iEOF, 0, 0, 2, 0})...)
+	row := appendLengthEncodedString(nil, "a,b")
+	row = appendLengthEncodedString(row, "2024")
+	row = appendLengthEncodedString(row, "7")
+	resp = append(resp, mockPacket(6, row)...)
+	resp = append(resp, mockPacket(7, []byte{iEOF, 0, 0, 2, 0})...)
+	conn.queuedReplies = [][]byte{resp}
+
+	rows, err := mc.Query("SELECT s, y, i FROM t", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := make([]driver.Value, 3)
+	if err := rows.Next(dest); err != nil {
+		t.Fatal(err)
+	}
+	if want := []driver.Value{[]string{"a", "b"}, "year 2024", int64(7)}; !reflect.DeepEqual(dest, want) {
+		t.Errorf("expected %v, got %v", want, dest)
+	}
+}
+
+func TestTypeMapperBinary(t *testing.T) {
+	conn, mc := newRWMockConn(1)
+	mc.cfg.TypeMapper = testTypeMapper
+	rows := &binaryRows{mysqlRows{mc: mc, mapper: mc.cfg.TypeMapper}}
+	rows.rs.columns = []mysqlField{
+		{name: "b", fieldType: fieldTypeBit, length: 1},
+		{name: "y", fieldType: fieldTypeYear},
+		{name: "i", fieldType: fieldTypeLong},
+	}
+	conn.data = mockPacket(1, []byte{iOK, 0, 1, 1, 0xe8, 0x07, 7, 0, 0, 0})
+
+	dest := make([]driver.Value, 3)
+	if err := rows.readRow(dest); err != nil {
+		t.Fatal(err)
+	}
+	if want := []driver.Value{true, "year 2024", int64(7)}; !reflect.DeepEqual(dest, want) {
+		t.Errorf("expected %v, got %v", want, dest)
+	}
+
+	// errors of the mapper are returned
+	errMapper := errors.New("mapper failed")
+	rows.mapper = func(FieldInfo, []byte) (driver.Value, error) { return nil, errMapper }
+	mc.sequence = 1
+	conn.data = mockPacket(1, []byte{iOK, 0, 1, 1, 0xe8, 0x07, 7, 0, 0, 0})
+	if err := rows.readRow(dest); err != errMapper {
+		t.Errorf("expected %v, got %v", errMapper, err)
+	}
+}
+
+func TestAppendTextFloat(t *testing.T) {
+	for _, tt := range []struct {
+		f       float64
+		bitSize int
+		want    string
+	}{
+		{0, 64, "0"},
+		{1.5, 64, "1.5"},
+		{-100000000, 64, "-100000000"},
+		{0.0001, 64, "0.0001"},
+		{1e-7, 64, "1e-7"},
+		{-1.5e20, 64, "-1.5e20"},
+		{1.2345678901234567e300, 64, "1.2345678901234567e300"},
+		{float64(float32(0.1)), 32, "0.1"},
+	} {
+		if got := string(appendTextFloat(nil, tt.f, tt.bitSize)); got != tt.want {
+			t.Errorf("%v: expected %q, got %q", tt.f, tt.want, got)
+		}
+	}
+}
+
+func TestTypeMapperBinaryDateTime(t *testing.T) {
+	var got []string
+	rows := &binaryRows{}
+	rows.mapper = func(field FieldInfo, raw []byte) (driver.Value, error) {
+		got = append(got, string(raw))
+		return nil, driver.ErrSkip
+	}
+	rows.rs.columns = []mysqlField{
+		{fieldType: fieldTypeDateTime, decimals: 3},
+		{fieldType: fieldTypeDate},
+		{fieldType: fieldTypeTimestamp},
+	}
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 678900000, time.UTC)
+	if err := rows.mapBinaryValues([]driver.Value{ts, ts, ts}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"2026-01-02 03:04:05.678", "2026-01-02", "2026-01-02 03:04:05"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
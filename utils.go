@@ -10,6 +10,7 @@ package mysql
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
 	"database/sql/driver"
 	"encoding/binary"
@@ -18,16 +19,12 @@ import (
 	"io"
 	"strconv"
 	"strings"
-	"sync"
 	"sync/atomic"
 	"time"
 )
 
 // Registry for custom tls.Configs
-var (
-	tlsConfigLock     sync.RWMutex
-	tlsConfigRegistry map[string]*tls.Config
-)
+var tlsConfigRegistry registry[*tls.Config]
 
 // RegisterTLSConfig registers a custom tls.Config to be used with sql.Open.
 // Use the key as a value in the DSN where tls=value.
@@ -55,38 +52,95 @@ var (
 //	})
 //	db, err := sql.Open("mysql", "user@tcp(localhost:3306)/test?tls=custom")
 func RegisterTLSConfig(key string, config *tls.Config) error {
-	if _, isBool := readBool(key); isBool || strings.ToLower(key) == "skip-verify" || strings.ToLower(key) == "preferred" {
+	if _, isBool := readBool(key); isBool || isTLSMode(strings.ToLower(key)) {
 		return fmt.Errorf("key '%s' is reserved", key)
 	}
 
-	tlsConfigLock.Lock()
-	if tlsConfigRegistry == nil {
-		tlsConfigRegistry = make(map[string]*tls.Config)
-	}
-
-	tlsConfigRegistry[key] = config
-	tlsConfigLock.Unlock()
+	tlsConfigRegistry.set(key, config)
+	parsedDSNs.purge()
 	return nil
 }
 
 // DeregisterTLSConfig removes the tls.Config associated with key.
 func DeregisterTLSConfig(key string) {
-	tlsConfigLock.Lock()
-	if tlsConfigRegistry != nil {
-		delete(tlsConfigRegistry, key)
-	}
-	tlsConfigLock.Unlock()
+	tlsConfigRegistry.delete(key)
+	parsedDSNs.purge()
+}
+
+// ListTLSConfigs returns the sorted keys of the registered tls.Configs.
+func ListTLSConfigs() []string {
+	return tlsConfigRegistry.names()
+}
+
+// ResetTLSConfigs removes all registered tls.Configs.
+func ResetTLSConfigs() {
+	tlsConfigRegistry.reset()
+	parsedDSNs.purge()
 }
 
 func getTLSConfigClone(key string) (config *tls.Config) {
-	tlsConfigLock.RLock()
-	if v, ok := tlsConfigRegistry[key]; ok {
+	if v, ok := tlsConfigRegistry.get(key); ok {
 		config = v.Clone()
 	}
-	tlsConfigLock.RUnlock()
 	return
 }
 
+// isTLSMode reports whether the lower-case value of the tls DSN parameter is
+// one of the reserved modes instead of the name of a registered config.
+func isTLSMode(value string) bool {
+	switch value {
+	case "skip-verify", "preferred", "verify-ca", "verify-identity":
+		return true
+	}
+	return false
+}
+
+// verifyCertificateChain returns a tls.Config.VerifyConnection func which
+// verifies the certificate chain of the server against roots, or the system
+// roots if nil, without checking the host name, like the VERIFY_CA mode of the
+// mysql client. next, if not nil, is called after a successful verification.
+func verifyCertificateChain(roots *x509.CertPool, next func(tls.ConnectionState) error) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("tls: server did not send a certificate")
+		}
+		opts := x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		if _, err := cs.PeerCertificates[0].Verify(opts); err != nil {
+			return err
+		}
+		if next != nil {
+			return next(cs)
+		}
+		return nil
+	}
+}
+
+// tlsSessionCacheSize is the capacity of the default TLS session cache, see
+// Config.TLSSessionCache.
+const tlsSessionCacheSize = 64
+
+// TLSConnectionState returns the state of the TLS connection, e.g. the
+// negotiated version and cipher suite and whether the session was resumed.
+// driverConn must be a connection of this driver, which is accessible with
+// sql.Conn.Raw. ok is false if the connection does not use TLS.
+func TLSConnectionState(driverConn any) (state tls.ConnectionState, ok bool) {
+	mc, ok := driverConn.(*mysqlConn)
+	if !ok {
+		return state, false
+	}
+	tlsConn, ok := mc.netConn.(*tls.Conn)
+	if !ok {
+		return state, false
+	}
+	return tlsConn.ConnectionState(), true
+}
+
 // Returns the bool value of the input.
 // The 2nd return value indicates if the input was a valid bool value
 func readBool(input string) (value bool, valid bool) {
@@ -327,6 +381,25 @@ func appendDateTime(buf []byte, t time.Time, timeTruncate time.Duration) ([]byte
 	return append(buf, localBuf[:n]...), nil
 }
 
+// appendTextDateTime appends t as the text protocol represents the value of
+// the DATE, DATETIME or TIMESTAMP column mf, i.e. with the fractional seconds
+// of the column. The zero time is appended as the zero value of the column.
+func appendTextDateTime(buf []byte, t time.Time, mf *mysqlField) []byte {
+	length := 10
+	if mf.fieldType != fieldTypeDate && mf.fieldType != fieldTypeNewDate {
+		length = 19
+		if mf.decimals > 0 && mf.decimals <= 6 {
+			length += 1 + int(mf.decimals)
+		}
+	}
+	if t.IsZero() {
+		return append(buf, zeroDateTime[:length]...)
+	}
+	n := len(buf)
+	buf = t.AppendFormat(buf, "2006-01-02 15:04:05.000000")
+	return buf[:n+length]
+}
+
 // zeroDateTime is used in formatBinaryDateTime to avoid an allocation
 // if the DATE or DATETIME has the zero value.
 // It must never be changed.
@@ -10,9 +10,16 @@ package mysql
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"database/sql"
 	"database/sql/driver"
 	"encoding/binary"
+	"math/big"
 	"testing"
 	"time"
 )
@@ -518,3 +525,68 @@ func TestParseDateTimeFail(t *testing.T) {
 		})
 	}
 }
+
+func TestTLSConnectionStateWithoutTLS(t *testing.T) {
+	_, mc := newRWMockConn(0)
+	if _, ok := TLSConnectionState(mc); ok {
+		t.Error("TLS state of an unencrypted connection")
+	}
+	if _, ok := TLSConnectionState(nil); ok {
+		t.Error("TLS state of a nil connection")
+	}
+}
+
+// newTestCertificate creates a certificate for name, signed by parent or
+// self-signed if parent is nil.
+func newTestCertificate(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  parent == nil,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestVerifyCertificateChain(t *testing.T) {
+	ca, caKey := newTestCertificate(t, "ca", nil, nil)
+	leaf, _ := newTestCertificate(t, "other.example", ca, caKey)
+	otherCA, _ := newTestCertificate(t, "other-ca", nil, nil)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	cs := tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf}}
+
+	// the host name is not checked
+	if err := verifyCertificateChain(roots, nil)(cs); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	otherRoots := x509.NewCertPool()
+	otherRoots.AddCert(otherCA)
+	if err := verifyCertificateChain(otherRoots, nil)(cs); err == nil {
+		t.Error("certificate of an unknown authority accepted")
+	}
+	if err := verifyCertificateChain(roots, nil)(tls.ConnectionState{}); err == nil {
+		t.Error("missing certificate accepted")
+	}
+}
@@ -0,0 +1,77 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Vector is the value of a VECTOR column (MySQL 9.0+, MariaDB 11.7+), e.g. an
+// embedding. The server stores it as an array of little-endian float32 values.
+//
+// Vector implements the sql.Scanner and driver.Valuer interfaces, so it can
+// be used as a statement parameter and scanned from VECTOR columns. NULL is
+// scanned as a nil Vector. ColumnTypeScanType returns Vector for VECTOR
+// columns.
+type Vector []float32
+
+// Scan implements the sql.Scanner interface.
+func (v *Vector) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		return v.decode(src)
+	case string:
+		return v.decode([]byte(src))
+	}
+	return fmt.Errorf("mysql: can not scan %T into Vector", src)
+}
+
+func (v *Vector) decode(b []byte) error {
+	if len(b)%4 != 0 {
+		return fmt.Errorf("mysql: invalid vector length %d, must be a multiple of 4", len(b))
+	}
+	vec := make(Vector, len(b)/4)
+	for i := range vec {
+		vec[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[i*4:]))
+	}
+	*v = vec
+	return nil
+}
+
+// Value implements the driver.Valuer interface. It returns the binary
+// representation of v which the server accepts for VECTOR columns.
+func (v Vector) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b := make([]byte, 0, len(v)*4)
+	for _, f := range v {
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(f))
+	}
+	return b, nil
+}
+
+// String returns v in the format of VECTOR_TO_STRING(), e.g. "[1,2.5,3]".
+func (v Vector) String() string {
+	b := []byte{'['}
+	for i, f := range v {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = strconv.AppendFloat(b, float64(f), 'g', -1, 32)
+	}
+	return string(append(b, ']'))
+}
@@ -0,0 +1,105 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestVectorValueScan(t *testing.T) {
+	vec := Vector{1, -2.5, 0}
+	v, err := vec.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0x00, 0x00, 0x80, 0x3f, 0x00, 0x00, 0x20, 0xc0, 0x00, 0x00, 0x00, 0x00}
+	if !bytes.Equal(v.([]byte), want) {
+		t.Fatalf("expected %x, got %x", want, v)
+	}
+
+	var got Vector
+	if err := got.Scan(v); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, vec) {
+		t.Errorf("expected %v, got %v", vec, got)
+	}
+	if s := got.String(); s != "[1,-2.5,0]" {
+		t.Errorf("unexpected string %q", s)
+	}
+
+	if err := got.Scan(nil); err != nil || got != nil {
+		t.Errorf("expected nil vector, got %v, %v", got, err)
+	}
+	if v, err := Vector(nil).Value(); v != nil || err != nil {
+		t.Errorf("expected NULL, got %v, %v", v, err)
+	}
+	if err := got.Scan([]byte{1, 2, 3}); err == nil {
+		t.Error("expected error for invalid length")
+	}
+	if err := got.Scan(int64(1)); err == nil {
+		t.Error("expected error for int64")
+	}
+}
+
+func TestVectorParam(t *testing.T) {
+	out, err := converter{}.ConvertValue(Vector{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.([]byte), []byte{0x00, 0x00, 0x80, 0x3f}) {
+		t.Errorf("unexpected value %x", out)
+	}
+}
+
+func TestVectorScanType(t *testing.T) {
+	conn, mc := newRWMockConn(2)
+	resp := mockColumn(2, "embedding", fieldTypeVector)
+	resp = append(resp, mockPacket(3, []byte{iEOF, 0, 0, 2, 0})...)
+	row := appendLengthEncodedString(nil, string([]byte{0x00, 0x00, 0x80, 0x3f}))
+	resp = append(resp, mockPacket(4, row)...)
+	conn.data = append(resp, mockPacket(5, []byte{iEOF, 0, 0, 2, 0})...)
+	conn.maxReads = 1
+	mc.sequence = 2
+	mc.cfg.parseVector = true
+
+	rows := &textRows{}
+	rows.mc = mc
+	var err error
+	if rows.rs.columns, err = mc.readColumns(1); err != nil {
+		t.Fatal(err)
+	}
+	if st := rows.ColumnTypeScanType(0); st != scanTypeVector {
+		t.Errorf("expected Vector scan type, got %v", st)
+	}
+	if name := rows.ColumnTypeDatabaseTypeName(0); name != "VECTOR" {
+		t.Errorf("expected VECTOR, got %s", name)
+	}
+
+	// without parseVector, the scan type is unchanged
+	mf := mysqlField{fieldType: fieldTypeVector, charSet: binaryCollationID}
+	if st := mf.scanType(); st != scanTypeBytes {
+		t.Errorf("expected []byte scan type, got %v", st)
+	}
+
+	dest := make([]driver.Value, 1)
+	if err := rows.Next(dest); err != nil {
+		t.Fatal(err)
+	}
+	var vec Vector
+	if err := vec.Scan(dest[0]); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(vec, Vector{1}) {
+		t.Errorf("unexpected vector %v", vec)
+	}
+}
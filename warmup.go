@@ -0,0 +1,156 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxParkedTime is the time after which parked connections are closed
+// instead of being returned by Connect. The server or a proxy may have
+// closed them in the meantime, which is not detected on all platforms.
+const maxParkedTime = time.Minute
+
+// WarmUpMode specifies what Connector.WarmUp does with the connections it
+// established.
+type WarmUpMode int
+
+const (
+	// WarmUpClose closes the connections. This fills the caches of the
+	// connector, e.g. the TLS session cache, and of the server, e.g. its
+	// thread cache, so that later handshakes are faster.
+	WarmUpClose WarmUpMode = iota
+
+	// WarmUpPark keeps the connections in the connector. They are returned
+	// by the next calls of Connect, i.e. when database/sql opens connections
+	// for the first requests, unless they were closed in the meantime or
+	// were parked for more than a minute. Parked connections are closed by
+	// sql.DB.Close and when the configuration is replaced with UpdateConfig.
+	WarmUpPark
+)
+
+// WarmUp implements Connector interface. The connections are established
+// concurrently. Connections which could be established are closed or parked
+// even if others failed; the errors are joined.
+func (c *connector) WarmUp(ctx context.Context, n int, mode WarmUpMode) error {
+	if n < 0 {
+		return fmt.Errorf("mysql: invalid number of connections to warm up: %d", n)
+	}
+	conns := make([]*mysqlConn, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			mc, err := c.connect(ctx)
+			if err == nil {
+				if err = mc.Ping(ctx); err != nil {
+					mc.Close()
+					mc = nil
+				}
+			}
+			conns[i], errs[i] = mc, err
+		}(i)
+	}
+	wg.Wait()
+
+	for _, mc := range conns {
+		if mc == nil {
+			continue
+		}
+		if mode == WarmUpPark {
+			c.parked.put(mc)
+		} else {
+			mc.Close()
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes the parked connections. It is called by sql.DB.Close.
+func (c *connector) Close() error {
+	c.parked.close()
+	return nil
+}
+
+// parkedConns are the connections parked by WarmUp.
+type parkedConns struct {
+	mu     sync.Mutex
+	conns  []parkedConn
+	closed bool // connections are closed instead of being parked
+}
+
+type parkedConn struct {
+	mc    *mysqlConn
+	since time.Time
+}
+
+func (p *parkedConns) put(mc *mysqlConn) {
+	p.mu.Lock()
+	if !p.closed {
+		p.conns = append(p.conns, parkedConn{mc, time.Now()})
+		mc = nil
+	}
+	p.mu.Unlock()
+	if mc != nil {
+		mc.Close()
+	}
+}
+
+// take returns a parked connection which is still alive, or nil.
+// Connections parked for longer than maxParkedTime are closed.
+func (p *parkedConns) take() *mysqlConn {
+	for {
+		p.mu.Lock()
+		n := len(p.conns)
+		if n == 0 {
+			p.mu.Unlock()
+			return nil
+		}
+		pc := p.conns[n-1]
+		p.conns[n-1] = parkedConn{}
+		p.conns = p.conns[:n-1]
+		p.mu.Unlock()
+
+		// the server or a proxy may have closed an idle connection
+		mc := pc.mc
+		conn := mc.netConn
+		if mc.rawConn != nil {
+			conn = mc.rawConn
+		}
+		if !mc.closed.Load() && time.Since(pc.since) < maxParkedTime && connCheck(conn) == nil {
+			return mc
+		}
+		mc.Close()
+	}
+}
+
+// closeAll closes the parked connections.
+func (p *parkedConns) closeAll() {
+	p.mu.Lock()
+	conns := p.conns
+	p.conns = nil
+	p.mu.Unlock()
+	for _, pc := range conns {
+		pc.mc.Close()
+	}
+}
+
+// close closes the parked connections and connections parked later.
+func (p *parkedConns) close() {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+	p.closeAll()
+}
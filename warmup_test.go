@@ -0,0 +1,47 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWarmUpInvalidCount(t *testing.T) {
+	c, err := NewConnector(NewConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.(Connector).WarmUp(context.Background(), -1, WarmUpPark); err == nil {
+		t.Error("expected error for a negative number of connections")
+	}
+	if err := c.(Connector).WarmUp(context.Background(), 0, WarmUpPark); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParkedConnsExpire(t *testing.T) {
+	var p parkedConns
+	_, fresh := newRWMockConn(0)
+	_, expired := newRWMockConn(0)
+	p.put(fresh)
+	p.put(expired)
+	p.conns[1].since = time.Now().Add(-maxParkedTime)
+
+	if mc := p.take(); mc != fresh {
+		t.Errorf("expected the fresh connection, got %p", mc)
+	}
+	if !expired.closed.Load() {
+		t.Error("expired connection not closed")
+	}
+	if mc := p.take(); mc != nil {
+		t.Errorf("unexpected connection %p", mc)
+	}
+}
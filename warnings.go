@@ -0,0 +1,186 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MySQLWarning is a single warning reported by SHOW WARNINGS.
+type MySQLWarning struct {
+	Level   string
+	Code    uint16
+	Message string
+}
+
+// MySQLWarnings is an error type which represents a group of one or more
+// MySQL warnings promoted to an error, see Config.TreatWarningsAsErrors.
+type MySQLWarnings []MySQLWarning
+
+func (mws MySQLWarnings) Error() string {
+	var sb strings.Builder
+	for i, w := range mws {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "%s %d: %s", w.Level, w.Code, w.Message)
+	}
+	return sb.String()
+}
+
+// WarningRules selects the warnings which Config.TreatWarningsAsErrors
+// promotes to errors.
+type WarningRules struct {
+	// Promote lists the warning codes promoted to errors, e.g. 1265 (data
+	// truncated). If it is empty, every warning is promoted unless it is
+	// listed in Ignore.
+	Promote []uint16
+
+	// Ignore lists the warning codes which are never promoted to errors,
+	// e.g. 1287 (deprecated syntax).
+	Ignore []uint16
+}
+
+func (wr *WarningRules) promotes(code uint16) bool {
+	if slices.Contains(wr.Ignore, code) {
+		return false
+	}
+	return len(wr.Promote) == 0 || slices.Contains(wr.Promote, code)
+}
+
+func (wr *WarningRules) clone() *WarningRules {
+	return &WarningRules{
+		Promote: slices.Clone(wr.Promote),
+		Ignore:  slices.Clone(wr.Ignore),
+	}
+}
+
+// Warnings returns the warnings of a statement which were collected because
+// Config.collectWarnings is set. v must be a driver.Result returned by Exec or
+// a driver.Rows returned by Query of a driver connection, see sql.Conn.Raw.
+// The warnings of a query are available after the last row was read.
+//
+// It returns nil if the statement did not cause warnings, or if v is of
+// another type.
+func Warnings(v any) []MySQLWarning {
+	switch v := v.(type) {
+	case *mysqlResult:
+		return v.warnings
+	case *textRows:
+		return v.warnings
+	case *binaryRows:
+		return v.warnings
+	}
+	return nil
+}
+
+// showsWarnings reports whether SHOW WARNINGS is sent after statements which
+// caused warnings.
+func (mc *mysqlConn) showsWarnings() bool {
+	return mc.cfg.TreatWarningsAsErrors != nil || mc.cfg.collectWarnings || mc.cfg.strict
+}
+
+// checkWarnings fetches the warnings of the last statement if they are
+// collected or promoted to errors by strict=true or
+// Config.TreatWarningsAsErrors, and returns the promoted warnings.
+// SHOW WARNINGS is only sent if the server reported a non-zero warning
+// count. The collected warnings are stored in mc.warnings.
+func (mc *mysqlConn) checkWarnings() error {
+	mc.warnings = nil
+	if !mc.showsWarnings() || mc.result.warningCount == 0 {
+		return nil
+	}
+
+	warnings, err := mc.getWarnings()
+	if err != nil {
+		return err
+	}
+	if mc.cfg.collectWarnings {
+		mc.warnings = warnings
+	}
+	if mc.cfg.strict {
+		if err := truncationError(warnings); err != nil {
+			return err
+		}
+	}
+	rules := mc.cfg.TreatWarningsAsErrors
+	if rules == nil {
+		return nil
+	}
+	var promoted MySQLWarnings
+	for _, w := range warnings {
+		if rules.promotes(w.Code) {
+			promoted = append(promoted, w)
+		}
+	}
+	if len(promoted) > 0 {
+		return promoted
+	}
+	return nil
+}
+
+// getWarnings fetches the warnings of the last statement.
+func (mc *mysqlConn) getWarnings() (MySQLWarnings, error) {
+	// Send command
+	handleOk := mc.clearResult()
+	if err := mc.writeCommandPacketStr(comQuery, "SHOW WARNINGS"); err != nil {
+		return nil, err
+	}
+
+	// Read Result
+	resLen, err := handleOk.readResultSetHeaderPacket()
+	if err != nil {
+		return nil, err
+	}
+	rows := new(textRows)
+	rows.mc = mc
+	if rows.rs.columns, err = mc.readColumns(resLen); err != nil {
+		return nil, err
+	}
+
+	// Level, Code, Message
+	if resLen != 3 {
+		if err := mc.readUntilEOF(); err != nil {
+			return nil, err
+		}
+		return nil, ErrMalformPkt
+	}
+	var warnings MySQLWarnings
+	dest := make([]driver.Value, resLen)
+	for {
+		err := rows.readRow(dest)
+		if err == io.EOF {
+			return warnings, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		w := MySQLWarning{}
+		if raw, ok := dest[0].([]byte); ok {
+			w.Level = string(raw)
+		}
+		switch code := dest[1].(type) {
+		case int64:
+			w.Code = uint16(code)
+		case []byte:
+			n, _ := strconv.ParseUint(string(code), 10, 16)
+			w.Code = uint16(n)
+		}
+		if raw, ok := dest[2].([]byte); ok {
+			w.Message = string(raw)
+		}
+		warnings = append(warnings, w)
+	}
+}
@@ -0,0 +1,157 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// mockPacket returns the packet with the given sequence number and payload.
+func mockPacket(seq byte, payload []byte) []byte {
+	n := len(payload)
+	return append([]byte{byte(n), byte(n >> 8), byte(n >> 16), seq}, payload...)
+}
+
+// mockColumn returns a column definition packet.
+func mockColumn(seq byte, name string, typ fieldType) []byte {
+	payload := appendLengthEncodedString(nil, "def")
+	payload = append(payload, 0, 0, 0) // schema, table, org_table
+	payload = appendLengthEncodedString(payload, name)
+	payload = append(payload, 0)     // org_name
+	payload = append(payload, 0x0c)  // length of fixed fields
+	payload = append(payload, 33, 0) // charset
+	payload = append(payload, 0, 1, 0, 0)
+	payload = append(payload, byte(typ), 0, 0, 0, 0, 0)
+	return mockPacket(seq, payload)
+}
+
+// mockShowWarnings returns the response to SHOW WARNINGS for the given warnings.
+func mockShowWarnings(warnings ...MySQLWarning) []byte {
+	resp := mockPacket(1, []byte{3})
+	resp = append(resp, mockColumn(2, "Level", fieldTypeVarChar)...)
+	resp = append(resp, mockColumn(3, "Code", fieldTypeLong)...)
+	resp = append(resp, mockColumn(4, "Message", fieldTypeVarChar)...)
+	resp = append(resp, mockPacket(5, []byte{iEOF, 0, 0, 2, 0})...)
+	seq := byte(6)
+	for _, w := range warnings {
+		row := appendLengthEncodedString(nil, w.Level)
+		row = appendLengthEncodedString(row, string(uint64ToString(uint64(w.Code))))
+		row = appendLengthEncodedString(row, w.Message)
+		resp = append(resp, mockPacket(seq, row)...)
+		seq++
+	}
+	return append(resp, mockPacket(seq, []byte{iEOF, 0, 0, 2, 0})...)
+}
+
+func TestWarningRules(t *testing.T) {
+	rules := &WarningRules{Ignore: []uint16{1287}}
+	if !rules.promotes(1265) || rules.promotes(1287) {
+		t.Errorf("unexpected result for %+v", rules)
+	}
+	rules = &WarningRules{Promote: []uint16{1265, 1287}, Ignore: []uint16{1287}}
+	if !rules.promotes(1265) || rules.promotes(1287) || rules.promotes(1366) {
+		t.Errorf("unexpected result for %+v", rules)
+	}
+}
+
+func TestExecTreatWarningsAsErrors(t *testing.T) {
+	truncated := MySQLWarning{"Warning", 1265, "Data truncated for column 'a' at row 1"}
+	deprecated := MySQLWarning{"Warning", 1287, "'@@tx_isolation' is deprecated"}
+
+	tests := []struct {
+		rules *WarningRules
+		want  error
+	}{
+		{nil, nil},
+		{&WarningRules{}, MySQLWarnings{truncated, deprecated}},
+		{&WarningRules{Ignore: []uint16{1287}}, MySQLWarnings{truncated}},
+		{&WarningRules{Promote: []uint16{1366}}, nil},
+	}
+	for i, tt := range tests {
+		conn, mc := newRWMockConn(0)
+		mc.cfg.TreatWarningsAsErrors = tt.rules
+		conn.queuedReplies = [][]byte{
+			mockPacket(1, []byte{iOK, 1, 0, 2, 0, 2, 0}), // 2 warnings
+			mockShowWarnings(truncated, deprecated),
+		}
+		conn.maxReads = 2
+
+		res, err := mc.Exec("INSERT INTO t VALUES ('abc')", nil)
+		if tt.want == nil {
+			if err != nil {
+				t.Fatalf("%d: unexpected error: %v", i, err)
+			}
+			if n, _ := res.RowsAffected(); n != 1 {
+				t.Errorf("%d: expected 1 affected row, got %d", i, n)
+			}
+			continue
+		}
+		var warnings MySQLWarnings
+		if !errors.As(err, &warnings) {
+			t.Fatalf("%d: expected MySQLWarnings, got %v", i, err)
+		}
+		if !reflect.DeepEqual(warnings, tt.want) {
+			t.Errorf("%d: expected %v, got %v", i, tt.want, warnings)
+		}
+	}
+}
+
+func TestExecTreatWarningsAsErrorsNoWarnings(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.cfg.TreatWarningsAsErrors = &WarningRules{}
+	conn.queuedReplies = [][]byte{mockPacket(1, []byte{iOK, 1, 0, 2, 0, 0, 0})}
+	conn.maxReads = 1
+
+	// SHOW WARNINGS must not be sent
+	if _, err := mc.Exec("INSERT INTO t VALUES ('abc')", nil); err != nil {
+		t.Fatal(err)
+	}
+	if conn.writes != 1 {
+		t.Errorf("expected 1 write, got %d", conn.writes)
+	}
+}
+
+func TestCollectWarnings(t *testing.T) {
+	truncated := MySQLWarning{"Warning", 1265, "Data truncated for column 'a' at row 1"}
+	conn, mc := newRWMockConn(0)
+	mc.cfg.collectWarnings = true
+	conn.queuedReplies = [][]byte{
+		mockPacket(1, []byte{iOK, 1, 0, 2, 0, 1, 0}), // 1 warning
+		mockShowWarnings(truncated),
+	}
+
+	res, err := mc.Exec("INSERT INTO t VALUES ('abc')", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := Warnings(res); !reflect.DeepEqual(w, []MySQLWarning{truncated}) {
+		t.Errorf("expected %v, got %v", truncated, w)
+	}
+
+	resp := mockPacket(1, []byte{1})
+	resp = append(resp, mockColumn(2, "a", fieldTypeVarChar)...)
+	resp = append(resp, mockPacket(3, []byte{iEOF, 0, 0, 2, 0})...)
+	resp = append(resp, mockPacket(4, appendLengthEncodedString(nil, "abc"))...)
+	resp = append(resp, mockPacket(5, []byte{iEOF, 1, 0, 2, 0})...) // 1 warning
+	conn.queuedReplies = [][]byte{resp, mockShowWarnings(truncated)}
+
+	rows, err := mc.Query("SELECT CAST('abc' AS DOUBLE)", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := make([]driver.Value, 1)
+	for rows.Next(dest) == nil {
+	}
+	if w := Warnings(rows); !reflect.DeepEqual(w, []MySQLWarning{truncated}) {
+		t.Errorf("expected %v, got %v", truncated, w)
+	}
+}
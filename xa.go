@@ -0,0 +1,229 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrXAState is returned by the XA functions when the requested operation is
+// not allowed in the current state of the XA transaction on the connection.
+var ErrXAState = errors.New("mysql: invalid XA transaction state")
+
+// XID identifies a branch of an XA transaction.
+type XID struct {
+	GlobalID string // gtrid, at most 64 bytes
+	BranchID string // bqual, at most 64 bytes
+	FormatID int64  // identifies the format of GlobalID and BranchID, -1 for a null XID
+}
+
+// String returns the XID in the syntax of the XA statements.
+func (x XID) String() string {
+	return "X'" + hex.EncodeToString([]byte(x.GlobalID)) + "',X'" +
+		hex.EncodeToString([]byte(x.BranchID)) + "'," + strconv.FormatInt(x.FormatID, 10)
+}
+
+func (x XID) validate() error {
+	if len(x.GlobalID) == 0 || len(x.GlobalID) > 64 || len(x.BranchID) > 64 {
+		return fmt.Errorf("mysql: invalid XID %v", x)
+	}
+	return nil
+}
+
+// states of an XA transaction branch on a connection
+type xaState uint8
+
+const (
+	xaNone xaState = iota
+	xaActive
+	xaIdle
+	xaPrepared
+)
+
+// The XA functions drive a branch of an XA (distributed) transaction on a
+// dedicated connection. The driver tracks the state of the branch and rejects
+// invalid transitions with ErrXAState. A connection with an unfinished branch
+// is not returned to the connection pool; a prepared branch can be completed
+// from any connection after recovering its XID with XARecover.
+//
+// A branch is completed either with XAStart, XAEnd, XAPrepare and XACommit
+// (two-phase commit), XAStart, XAEnd and XACommit with onePhase (one-phase
+// commit), or XARollback after XAEnd or XAPrepare.
+
+// XAStart starts an XA transaction branch on conn.
+func XAStart(ctx context.Context, conn *sql.Conn, xid XID) error {
+	return rawConn(ctx, conn, func(mc *mysqlConn) error { return mc.xaStart(xid) })
+}
+
+// XAEnd ends the work of the XA transaction branch on conn.
+func XAEnd(ctx context.Context, conn *sql.Conn, xid XID) error {
+	return rawConn(ctx, conn, func(mc *mysqlConn) error { return mc.xaEnd(xid) })
+}
+
+// XAPrepare prepares the XA transaction branch for the commit.
+func XAPrepare(ctx context.Context, conn *sql.Conn, xid XID) error {
+	return rawConn(ctx, conn, func(mc *mysqlConn) error { return mc.xaPrepare(xid) })
+}
+
+// XACommit commits the XA transaction branch. If onePhase is true, the branch
+// is prepared and committed in a single step.
+// XACommit can also commit a prepared branch recovered with XARecover.
+func XACommit(ctx context.Context, conn *sql.Conn, xid XID, onePhase bool) error {
+	return rawConn(ctx, conn, func(mc *mysqlConn) error { return mc.xaCommit(xid, onePhase) })
+}
+
+// XARollback rolls back the XA transaction branch.
+// XARollback can also roll back a prepared branch recovered with XARecover.
+func XARollback(ctx context.Context, conn *sql.Conn, xid XID) error {
+	return rawConn(ctx, conn, func(mc *mysqlConn) error { return mc.xaRollback(xid) })
+}
+
+// XARecover returns the XIDs of all XA transaction branches in the prepared
+// state. q is typically a *sql.DB or *sql.Conn.
+// Since MySQL 8.0 this requires the XA_RECOVER_ADMIN privilege.
+func XARecover(ctx context.Context, q interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}) ([]XID, error) {
+	rows, err := q.QueryContext(ctx, "XA RECOVER")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var xids []XID
+	for rows.Next() {
+		var formatID int64
+		var gtridLen, bqualLen int
+		var data []byte
+		if err := rows.Scan(&formatID, &gtridLen, &bqualLen, &data); err != nil {
+			return nil, err
+		}
+		if gtridLen < 0 || bqualLen < 0 || gtridLen+bqualLen != len(data) {
+			return nil, fmt.Errorf("mysql: malformed XID data %q", data)
+		}
+		xids = append(xids, XID{
+			GlobalID: string(data[:gtridLen]),
+			BranchID: string(data[gtridLen:]),
+			FormatID: formatID,
+		})
+	}
+	return xids, rows.Err()
+}
+
+// rawConn calls f with the driver connection of conn while ctx is watched.
+func rawConn(ctx context.Context, conn *sql.Conn, f func(mc *mysqlConn) error) error {
+	return conn.Raw(func(driverConn any) error {
+		mc, ok := driverConn.(*mysqlConn)
+		if !ok {
+			return fmt.Errorf("mysql: unexpected driver connection %T", driverConn)
+		}
+		if err := mc.watchCancel(ctx); err != nil {
+			return err
+		}
+		defer mc.finish()
+		return f(mc)
+	})
+}
+
+func (mc *mysqlConn) xaExec(cmd string, xid XID, suffix string) error {
+	if mc.closed.Load() {
+		return driver.ErrBadConn
+	}
+	err := mc.exec("XA " + cmd + " " + xid.String() + suffix)
+	var mysqlErr *MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1402, 1613, 1614: // ER_XA_RBROLLBACK, ER_XA_RBTIMEOUT, ER_XA_RBDEADLOCK
+			// the branch has been rolled back by the server
+			mc.xaState = xaNone
+		}
+	}
+	return err
+}
+
+func (mc *mysqlConn) xaStart(xid XID) error {
+	if err := xid.validate(); err != nil {
+		return err
+	}
+	if mc.xaState != xaNone || mc.status&statusInTrans != 0 {
+		return ErrXAState
+	}
+	if err := mc.xaExec("START", xid, ""); err != nil {
+		return err
+	}
+	mc.xaState, mc.xaID = xaActive, xid
+	return nil
+}
+
+func (mc *mysqlConn) xaEnd(xid XID) error {
+	if mc.xaState != xaActive || mc.xaID != xid {
+		return ErrXAState
+	}
+	if err := mc.xaExec("END", xid, ""); err != nil {
+		return err
+	}
+	mc.xaState = xaIdle
+	return nil
+}
+
+func (mc *mysqlConn) xaPrepare(xid XID) error {
+	if mc.xaState != xaIdle || mc.xaID != xid {
+		return ErrXAState
+	}
+	if err := mc.xaExec("PREPARE", xid, ""); err != nil {
+		return err
+	}
+	mc.xaState = xaPrepared
+	return nil
+}
+
+func (mc *mysqlConn) xaCommit(xid XID, onePhase bool) error {
+	suffix := ""
+	switch {
+	case onePhase && mc.xaState == xaIdle && mc.xaID == xid:
+		suffix = " ONE PHASE"
+	case !onePhase && mc.xaState == xaPrepared && mc.xaID == xid:
+	case !onePhase && mc.xaState == xaNone:
+		// branch recovered with XARecover
+		if err := xid.validate(); err != nil {
+			return err
+		}
+	default:
+		return ErrXAState
+	}
+	if err := mc.xaExec("COMMIT", xid, suffix); err != nil {
+		return err
+	}
+	mc.xaState = xaNone
+	return nil
+}
+
+func (mc *mysqlConn) xaRollback(xid XID) error {
+	switch {
+	case (mc.xaState == xaIdle || mc.xaState == xaPrepared) && mc.xaID == xid:
+	case mc.xaState == xaNone:
+		// branch recovered with XARecover
+		if err := xid.validate(); err != nil {
+			return err
+		}
+	default:
+		return ErrXAState
+	}
+	if err := mc.xaExec("ROLLBACK", xid, ""); err != nil {
+		return err
+	}
+	mc.xaState = xaNone
+	return nil
+}
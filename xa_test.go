@@ -0,0 +1,103 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestXIDString(t *testing.T) {
+	xid := XID{GlobalID: "g'1", BranchID: "b", FormatID: 7}
+	if expected := "X'672731',X'62',7"; xid.String() != expected {
+		t.Errorf("expected %q, got %q", expected, xid.String())
+	}
+}
+
+func TestXATwoPhaseCommit(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	ok := []byte{7, 0, 0, 1, 0, 0, 0, 2, 0, 0, 0}
+	conn.queuedReplies = [][]byte{ok, ok, ok, ok}
+	conn.maxReads = 4
+	xid := XID{GlobalID: "g", BranchID: "b", FormatID: 1}
+
+	if err := mc.xaPrepare(xid); err != ErrXAState {
+		t.Errorf("expected ErrXAState for prepare before start, got %v", err)
+	}
+	if err := mc.xaStart(xid); err != nil {
+		t.Fatal(err)
+	}
+	if mc.IsValid() {
+		t.Error("connection with active XA branch must not be valid")
+	}
+	if err := mc.xaStart(xid); err != ErrXAState {
+		t.Errorf("expected ErrXAState for nested start, got %v", err)
+	}
+	if err := mc.xaEnd(XID{GlobalID: "other"}); err != ErrXAState {
+		t.Errorf("expected ErrXAState for different XID, got %v", err)
+	}
+	if err := mc.xaEnd(xid); err != nil {
+		t.Fatal(err)
+	}
+	if err := mc.xaPrepare(xid); err != nil {
+		t.Fatal(err)
+	}
+	if err := mc.xaCommit(xid, true); err != ErrXAState {
+		t.Errorf("expected ErrXAState for one phase commit after prepare, got %v", err)
+	}
+	if err := mc.xaCommit(xid, false); err != nil {
+		t.Fatal(err)
+	}
+	if !mc.IsValid() {
+		t.Error("connection must be valid after XA commit")
+	}
+
+	expected := []string{
+		"XA START X'67',X'62',1",
+		"XA END X'67',X'62',1",
+		"XA PREPARE X'67',X'62',1",
+		"XA COMMIT X'67',X'62',1",
+	}
+	var got []string
+	for written := conn.written; len(written) > 4; {
+		n := int(written[0]) | int(written[1])<<8 | int(written[2])<<16
+		got = append(got, string(written[5:4+n]))
+		written = written[4+n:]
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d commands, got %q", len(expected), got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], got[i])
+		}
+	}
+}
+
+func TestXARollbackOnServerRollback(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.xaState, mc.xaID = xaIdle, XID{GlobalID: "g"}
+
+	// ER_XA_RBDEADLOCK
+	errPacket := []byte{0x17, 0x00, 0x00, 0x01, 0xff, 0x4e, 0x06}
+	errPacket = append(errPacket, "#XA102deadlock"...)
+	errPacket[0] = byte(len(errPacket) - 4)
+	conn.data = errPacket
+	conn.maxReads = 1
+
+	if err := mc.xaPrepare(mc.xaID); err == nil {
+		t.Fatal("expected error")
+	}
+	if mc.xaState != xaNone {
+		t.Errorf("expected branch to be finished, got state %d", mc.xaState)
+	}
+	if !bytes.HasPrefix(conn.written[5:], []byte("XA PREPARE")) {
+		t.Errorf("unexpected command %q", conn.written[5:])
+	}
+}
@@ -0,0 +1,59 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"strings"
+	"time"
+)
+
+// Policies for the encoding of zero time.Time parameters, see ZeroDate.
+const (
+	zeroDateZero = "zero"
+	zeroDateNull = "null"
+	zeroDateAuto = "auto"
+)
+
+// setZeroDatePolicy decides how zero time.Time parameters are sent on the
+// connection, see Config.zeroDate. With "auto", they are sent as NULL if the
+// sql_mode of the session contains NO_ZERO_DATE, which is the default since
+// MySQL 5.7 and rejects '0000-00-00' in strict mode.
+func (mc *mysqlConn) setZeroDatePolicy() error {
+	switch mc.cfg.zeroDate {
+	case zeroDateNull:
+		mc.zeroDateNull = true
+	case zeroDateAuto:
+		mode, err := mc.getSystemVar("SESSION.sql_mode")
+		if err != nil {
+			return err
+		}
+		mc.zeroDateNull = sqlModeContains(string(mode), "NO_ZERO_DATE")
+	}
+	return nil
+}
+
+// sqlModeContains reports whether the comma separated sql_mode contains mode.
+func sqlModeContains(sqlMode, mode string) bool {
+	for _, m := range strings.Split(sqlMode, ",") {
+		if strings.EqualFold(strings.TrimSpace(m), mode) {
+			return true
+		}
+	}
+	return false
+}
+
+// zeroDateArg returns nil instead of the zero time.Time if the connection
+// sends zero dates as NULL.
+func (mc *mysqlConn) zeroDateArg(arg driver.Value) driver.Value {
+	if t, ok := arg.(time.Time); ok && mc.zeroDateNull && t.IsZero() {
+		return nil
+	}
+	return arg
+}
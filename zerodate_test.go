@@ -0,0 +1,89 @@
+// Go MySQL Driver - A MySQL-Driver for Go's database/sql package
+//
+// Copyright 2026 The Go-MySQL-Driver Authors. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package mysql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+// mockSystemVar returns the response to SELECT @@name.
+func mockSystemVar(name, value string) []byte {
+	resp := mockPacket(1, []byte{1})
+	resp = append(resp, mockColumn(2, "@@"+name, fieldTypeVarChar)...)
+	resp = append(resp, mockPacket(3, []byte{iEOF, 0, 0, 2, 0})...)
+	resp = append(resp, mockPacket(4, appendLengthEncodedString(nil, value))...)
+	return append(resp, mockPacket(5, []byte{iEOF, 0, 0, 2, 0})...)
+}
+
+func TestZeroDatePolicy(t *testing.T) {
+	tests := []struct {
+		policy  string
+		sqlMode string
+		null    bool
+	}{
+		{"", "", false},
+		{zeroDateNull, "", true},
+		{zeroDateAuto, "ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE", true},
+		{zeroDateAuto, "STRICT_TRANS_TABLES,NO_ZERO_IN_DATE", false},
+		{zeroDateAuto, "", false},
+	}
+	for _, tt := range tests {
+		conn, mc := newRWMockConn(0)
+		mc.cfg.zeroDate = tt.policy
+		conn.queuedReplies = [][]byte{mockSystemVar("SESSION.sql_mode", tt.sqlMode)}
+		if err := mc.setZeroDatePolicy(); err != nil {
+			t.Fatal(err)
+		}
+		if mc.zeroDateNull != tt.null {
+			t.Errorf("%q, %q: got %t, want %t", tt.policy, tt.sqlMode, mc.zeroDateNull, tt.null)
+		}
+		if queried := conn.writes > 0; queried != (tt.policy == zeroDateAuto) {
+			t.Errorf("%q: sql_mode queried: %t", tt.policy, queried)
+		}
+	}
+}
+
+func TestZeroDateInterpolation(t *testing.T) {
+	_, mc := newRWMockConn(0)
+	args := []driver.Value{time.Time{}}
+	for _, null := range []bool{false, true} {
+		mc.zeroDateNull = null
+		q, err := mc.interpolateParams("INSERT INTO t VALUES (?)", args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "INSERT INTO t VALUES ('0000-00-00')"
+		if null {
+			want = "INSERT INTO t VALUES (NULL)"
+		}
+		if q != want {
+			t.Errorf("got %q, want %q", q, want)
+		}
+	}
+}
+
+func TestZeroDateExecutePacket(t *testing.T) {
+	conn, mc := newRWMockConn(0)
+	mc.zeroDateNull = true
+	stmt := &mysqlStmt{mc: mc, id: 1, paramCount: 2}
+	if err := stmt.writeExecutePacket([]driver.Value{time.Time{}, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)}); err != nil {
+		t.Fatal(err)
+	}
+	// header, command, statement id, flags, iteration count
+	pkt := conn.written[4+1+4+1+4:]
+	if pkt[0] != 0x01 {
+		t.Errorf("expected the first parameter to be NULL, got NULL bitmap %#x", pkt[0])
+	}
+	if !bytes.HasSuffix(conn.written, []byte("2026-01-02")) {
+		t.Errorf("expected the second parameter to be sent, got %q", conn.written)
+	}
+}